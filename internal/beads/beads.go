@@ -2,17 +2,14 @@
 package beads
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/filelock"
-	"github.com/steveyegge/gastown/internal/runtime"
 )
 
 // Common errors
@@ -81,6 +78,7 @@ type ListOptions struct {
 
 // CreateOptions specifies options for creating an issue.
 type CreateOptions struct {
+	ID          string // Explicit ID (see CreateWithID); empty to generate one
 	Title       string
 	Type        string // "task", "bug", "feature", "epic"
 	Priority    int    // 0-4
@@ -100,6 +98,7 @@ type UpdateOptions struct {
 	AddLabels    []string // Labels to add
 	RemoveLabels []string // Labels to remove
 	SetLabels    []string // Labels to set (replaces all existing)
+	Notes        *string  // Replaces the issue's notes
 }
 
 // SyncStatus represents the sync status of the beads repository.
@@ -111,10 +110,13 @@ type SyncStatus struct {
 }
 
 // Beads wraps bd CLI operations for a working directory.
+// Issue operations go through a Store; raw bd commands (slots, gates, sync)
+// require the default CLIStore.
 type Beads struct {
 	workDir  string
 	beadsDir string // Optional BEADS_DIR override for cross-database access
 	isolated bool   // If true, suppress inherited beads env vars (for test isolation)
+	store    Store
 
 	// Lazy-cached town root for routing resolution.
 	// Populated on first call to getTownRoot() to avoid filesystem walk on every operation.
@@ -123,21 +125,36 @@ type Beads struct {
 }
 
 // New creates a new Beads wrapper for the given directory.
+// The storage backend is chosen by the GT_BEADS_STORE environment variable.
 func New(workDir string) *Beads {
-	return &Beads{workDir: workDir}
+	return &Beads{workDir: workDir, store: defaultStore(workDir, "")}
 }
 
 // NewIsolated creates a Beads wrapper for test isolation.
 // This suppresses inherited beads env vars (BD_ACTOR, BEADS_DB) to prevent
 // tests from accidentally routing to production databases.
 func NewIsolated(workDir string) *Beads {
-	return &Beads{workDir: workDir, isolated: true}
+	return &Beads{
+		workDir:  workDir,
+		isolated: true,
+		store:    &CLIStore{workDir: workDir, isolated: true},
+	}
 }
 
 // NewWithBeadsDir creates a Beads wrapper with an explicit BEADS_DIR.
 // This is needed when running from a polecat worktree but accessing town-level beads.
 func NewWithBeadsDir(workDir, beadsDir string) *Beads {
-	return &Beads{workDir: workDir, beadsDir: beadsDir}
+	return &Beads{workDir: workDir, beadsDir: beadsDir, store: defaultStore(workDir, beadsDir)}
+}
+
+// NewWithStore creates a Beads wrapper over an explicit storage backend.
+// Use NewJSONLStore to work with a .beads directory without the bd binary.
+func NewWithStore(workDir string, store Store) *Beads {
+	b := &Beads{workDir: workDir, store: store}
+	if s, ok := store.(*JSONLStore); ok {
+		b.beadsDir = s.beadsDir
+	}
+	return b
 }
 
 // getActor returns the BD_ACTOR value for this context.
@@ -178,60 +195,14 @@ func (b *Beads) Init(prefix string) error {
 }
 
 // run executes a bd command and returns stdout.
+// Raw bd commands are only available with the CLI store; other stores
+// return ErrNotSupported.
 func (b *Beads) run(args ...string) ([]byte, error) {
-	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
-	// The daemon is primarily useful for write coalescing, not reads.
-	// Use --allow-stale to prevent failures when db is out of sync with JSONL
-	// (e.g., after daemon is killed during shutdown before syncing).
-	fullArgs := append([]string{"--no-daemon", "--allow-stale"}, args...)
-
-	// Always explicitly set BEADS_DIR to prevent inherited env vars from
-	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
-	// resolve from working directory.
-	beadsDir := b.beadsDir
-	if beadsDir == "" {
-		beadsDir = ResolveBeadsDir(b.workDir)
+	cli, ok := b.store.(*CLIStore)
+	if !ok {
+		return nil, fmt.Errorf("bd %s: %w", strings.Join(args, " "), ErrNotSupported)
 	}
-
-	// In isolated mode, use --db flag to force specific database path
-	// This bypasses bd's routing logic that can redirect to .beads-planning
-	// Skip --db for init command since it creates the database
-	isInit := len(args) > 0 && args[0] == "init"
-	if b.isolated && !isInit {
-		beadsDB := filepath.Join(beadsDir, "beads.db")
-		fullArgs = append([]string{"--db", beadsDB}, fullArgs...)
-	}
-
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = b.workDir
-
-	// Build environment: filter beads env vars when in isolated mode (tests)
-	// to prevent routing to production databases.
-	var env []string
-	if b.isolated {
-		env = filterBeadsEnv(os.Environ())
-	} else {
-		env = os.Environ()
-	}
-	cmd.Env = append(env, "BEADS_DIR="+beadsDir)
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-	if err != nil {
-		return nil, b.wrapError(err, stderr.String(), args)
-	}
-
-	// Handle bd --no-daemon exit code 0 bug: when issue not found,
-	// --no-daemon exits 0 but writes error to stderr with empty stdout.
-	// Detect this case and treat as error to avoid JSON parse failures.
-	if stdout.Len() == 0 && stderr.Len() > 0 {
-		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
-	}
-
-	return stdout.Bytes(), nil
+	return cli.run(args...)
 }
 
 // Run executes a bd command and returns stdout.
@@ -241,90 +212,9 @@ func (b *Beads) Run(args ...string) ([]byte, error) {
 	return b.run(args...)
 }
 
-// wrapError wraps bd errors with context.
-// ZFC: Avoid parsing stderr to make decisions. Transport errors to agents instead.
-// Exception: ErrNotInstalled (exec.ErrNotFound) and ErrNotFound (issue lookup) are
-// acceptable as they enable basic error handling without decision-making.
-func (b *Beads) wrapError(err error, stderr string, args []string) error {
-	stderr = strings.TrimSpace(stderr)
-
-	// Check for bd not installed
-	if execErr, ok := err.(*exec.Error); ok && errors.Is(execErr.Err, exec.ErrNotFound) {
-		return ErrNotInstalled
-	}
-
-	// ErrNotFound is widely used for issue lookups - acceptable exception
-	// Match various "not found" error patterns from bd
-	if strings.Contains(stderr, "not found") || strings.Contains(stderr, "Issue not found") ||
-		strings.Contains(stderr, "no issue found") {
-		return ErrNotFound
-	}
-
-	if stderr != "" {
-		return fmt.Errorf("bd %s: %s", strings.Join(args, " "), stderr)
-	}
-	return fmt.Errorf("bd %s: %w", strings.Join(args, " "), err)
-}
-
-// filterBeadsEnv removes beads-related environment variables from the given
-// environment slice. This ensures test isolation by preventing inherited
-// BD_ACTOR, BEADS_DB, GT_ROOT, HOME etc. from routing commands to production databases.
-func filterBeadsEnv(environ []string) []string {
-	filtered := make([]string, 0, len(environ))
-	for _, env := range environ {
-		// Skip beads-related env vars that could interfere with test isolation
-		// BD_ACTOR, BEADS_* - direct beads config
-		// GT_ROOT - causes bd to find global routes file
-		// HOME - causes bd to find ~/.beads-planning routing
-		if strings.HasPrefix(env, "BD_ACTOR=") ||
-			strings.HasPrefix(env, "BEADS_") ||
-			strings.HasPrefix(env, "GT_ROOT=") ||
-			strings.HasPrefix(env, "HOME=") {
-			continue
-		}
-		filtered = append(filtered, env)
-	}
-	return filtered
-}
-
 // List returns issues matching the given options.
 func (b *Beads) List(opts ListOptions) ([]*Issue, error) {
-	args := []string{"list", "--json"}
-
-	if opts.Status != "" {
-		args = append(args, "--status="+opts.Status)
-	}
-	// Prefer Label over Type (Type is deprecated)
-	if opts.Label != "" {
-		args = append(args, "--label="+opts.Label)
-	} else if opts.Type != "" {
-		// Deprecated: convert type to label for backward compatibility
-		args = append(args, "--label=gt:"+opts.Type)
-	}
-	if opts.Priority >= 0 {
-		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
-	}
-	if opts.Parent != "" {
-		args = append(args, "--parent="+opts.Parent)
-	}
-	if opts.Assignee != "" {
-		args = append(args, "--assignee="+opts.Assignee)
-	}
-	if opts.NoAssignee {
-		args = append(args, "--no-assignee")
-	}
-
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
-	}
-
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd list output: %w", err)
-	}
-
-	return issues, nil
+	return b.store.List(opts)
 }
 
 // ListByAssignee returns all issues assigned to a specific assignee.
@@ -370,49 +260,23 @@ func (b *Beads) GetAssignedIssue(assignee string) (*Issue, error) {
 
 // Ready returns issues that are ready to work (not blocked).
 func (b *Beads) Ready() ([]*Issue, error) {
-	out, err := b.run("ready", "--json")
-	if err != nil {
-		return nil, err
-	}
-
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd ready output: %w", err)
-	}
-
-	return issues, nil
+	return b.store.Ready(ReadyOptions{})
 }
 
 // ReadyWithType returns ready issues filtered by label.
 // Uses bd ready --label flag for server-side filtering.
 // The issueType is converted to a gt:<type> label (e.g., "molecule" -> "gt:molecule").
 func (b *Beads) ReadyWithType(issueType string) ([]*Issue, error) {
-	out, err := b.run("ready", "--json", "--label", "gt:"+issueType, "-n", "100")
-	if err != nil {
-		return nil, err
-	}
-
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd ready output: %w", err)
-	}
-
-	return issues, nil
+	return b.store.Ready(ReadyOptions{Label: "gt:" + issueType, Limit: 100})
 }
 
 // Show returns detailed information about an issue.
 func (b *Beads) Show(id string) (*Issue, error) {
-	out, err := b.run("show", id, "--json")
+	issues, err := b.store.Show(id)
 	if err != nil {
 		return nil, err
 	}
 
-	// bd show --json returns an array with one element
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd show output: %w", err)
-	}
-
 	if len(issues) == 0 {
 		return nil, ErrNotFound
 	}
@@ -427,19 +291,12 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 		return make(map[string]*Issue), nil
 	}
 
-	// bd show supports multiple IDs
-	args := append([]string{"show", "--json"}, ids...)
-	out, err := b.run(args...)
+	issues, err := b.store.Show(ids...)
 	if err != nil {
 		// If bd fails, return empty map (some IDs might not exist)
 		return make(map[string]*Issue), nil
 	}
 
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd show output: %w", err)
-	}
-
 	result := make(map[string]*Issue, len(issues))
 	for _, issue := range issues {
 		result[issue.ID] = issue
@@ -450,189 +307,46 @@ func (b *Beads) ShowMultiple(ids []string) (map[string]*Issue, error) {
 
 // Blocked returns issues that are blocked by dependencies.
 func (b *Beads) Blocked() ([]*Issue, error) {
-	out, err := b.run("blocked", "--json")
-	if err != nil {
-		return nil, err
-	}
-
-	var issues []*Issue
-	if err := json.Unmarshal(out, &issues); err != nil {
-		return nil, fmt.Errorf("parsing bd blocked output: %w", err)
-	}
-
-	return issues, nil
+	return b.store.Blocked()
 }
 
 // Create creates a new issue and returns it.
 // If opts.Actor is empty, it defaults to the BD_ACTOR environment variable.
 // This ensures created_by is populated for issue provenance tracking.
 func (b *Beads) Create(opts CreateOptions) (*Issue, error) {
-	args := []string{"create", "--json"}
-
-	if opts.Title != "" {
-		args = append(args, "--title="+opts.Title)
-	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
-	}
-	if opts.Priority >= 0 {
-		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
-	}
-	if opts.Description != "" {
-		args = append(args, "--description="+opts.Description)
-	}
-	if opts.Parent != "" {
-		args = append(args, "--parent="+opts.Parent)
-	}
-	if opts.Ephemeral {
-		args = append(args, "--ephemeral")
-	}
 	// Default Actor from BD_ACTOR env var if not specified
 	// Uses getActor() to respect isolated mode (tests)
-	actor := opts.Actor
-	if actor == "" {
-		actor = b.getActor()
-	}
-	if actor != "" {
-		args = append(args, "--actor="+actor)
-	}
-
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
+	if opts.Actor == "" {
+		opts.Actor = b.getActor()
 	}
-
-	var issue Issue
-	if err := json.Unmarshal(out, &issue); err != nil {
-		return nil, fmt.Errorf("parsing bd create output: %w", err)
-	}
-
-	return &issue, nil
+	return b.store.Create(opts)
 }
 
 // CreateWithID creates an issue with a specific ID.
 // This is useful for agent beads, role beads, and other beads that need
 // deterministic IDs rather than auto-generated ones.
 func (b *Beads) CreateWithID(id string, opts CreateOptions) (*Issue, error) {
-	args := []string{"create", "--json", "--id=" + id}
-	if NeedsForceForID(id) {
-		args = append(args, "--force")
-	}
-
-	if opts.Title != "" {
-		args = append(args, "--title="+opts.Title)
-	}
-	// Type is deprecated: convert to gt:<type> label
-	if opts.Type != "" {
-		args = append(args, "--labels=gt:"+opts.Type)
-	}
-	if opts.Priority >= 0 {
-		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
-	}
-	if opts.Description != "" {
-		args = append(args, "--description="+opts.Description)
-	}
-	if opts.Parent != "" {
-		args = append(args, "--parent="+opts.Parent)
-	}
-	// Default Actor from BD_ACTOR env var if not specified
-	// Uses getActor() to respect isolated mode (tests)
-	actor := opts.Actor
-	if actor == "" {
-		actor = b.getActor()
-	}
-	if actor != "" {
-		args = append(args, "--actor="+actor)
-	}
-
-	out, err := b.run(args...)
-	if err != nil {
-		return nil, err
-	}
-
-	var issue Issue
-	if err := json.Unmarshal(out, &issue); err != nil {
-		return nil, fmt.Errorf("parsing bd create output: %w", err)
-	}
-
-	return &issue, nil
+	opts.ID = id
+	return b.Create(opts)
 }
 
 // Update updates an existing issue.
 func (b *Beads) Update(id string, opts UpdateOptions) error {
-	args := []string{"update", id}
-
-	if opts.Title != nil {
-		args = append(args, "--title="+*opts.Title)
-	}
-	if opts.Status != nil {
-		args = append(args, "--status="+*opts.Status)
-	}
-	if opts.Priority != nil {
-		args = append(args, fmt.Sprintf("--priority=%d", *opts.Priority))
-	}
-	if opts.Description != nil {
-		args = append(args, "--description="+*opts.Description)
-	}
-	if opts.Assignee != nil {
-		args = append(args, "--assignee="+*opts.Assignee)
-	}
-	// Label operations: set-labels replaces all, otherwise use add/remove
-	if len(opts.SetLabels) > 0 {
-		for _, label := range opts.SetLabels {
-			args = append(args, "--set-labels="+label)
-		}
-	} else {
-		for _, label := range opts.AddLabels {
-			args = append(args, "--add-label="+label)
-		}
-		for _, label := range opts.RemoveLabels {
-			args = append(args, "--remove-label="+label)
-		}
-	}
-
-	_, err := b.run(args...)
-	return err
+	return b.store.Update(id, opts)
 }
 
 // Close closes one or more issues.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
 func (b *Beads) Close(ids ...string) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	args := append([]string{"close"}, ids...)
-
-	// Pass session ID for work attribution if available
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		args = append(args, "--session="+sessionID)
-	}
-
-	_, err := b.run(args...)
-	return err
+	return b.store.Close("", ids...)
 }
 
 // CloseWithReason closes one or more issues with a reason.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
 func (b *Beads) CloseWithReason(reason string, ids ...string) error {
-	if len(ids) == 0 {
-		return nil
-	}
-
-	args := append([]string{"close"}, ids...)
-	args = append(args, "--reason="+reason)
-
-	// Pass session ID for work attribution if available
-	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
-		args = append(args, "--session="+sessionID)
-	}
-
-	_, err := b.run(args...)
-	return err
+	return b.store.Close(reason, ids...)
 }
 
 // Release moves an in_progress issue back to open status.
@@ -645,27 +359,37 @@ func (b *Beads) Release(id string) error {
 // ReleaseWithReason moves an in_progress issue back to open status with a reason.
 // The reason is added as a note to the issue for tracking purposes.
 func (b *Beads) ReleaseWithReason(id, reason string) error {
-	args := []string{"update", id, "--status=open", "--assignee="}
+	status := "open"
+	assignee := ""
+	opts := UpdateOptions{Status: &status, Assignee: &assignee}
 
 	// Add reason as a note if provided
 	if reason != "" {
-		args = append(args, "--notes=Released: "+reason)
+		notes := "Released: " + reason
+		opts.Notes = &notes
 	}
 
-	_, err := b.run(args...)
-	return err
+	return b.store.Update(id, opts)
+}
+
+// Reopen reopens a closed issue.
+func (b *Beads) Reopen(id, reason string) error {
+	return b.store.Reopen(id, reason)
+}
+
+// Delete permanently deletes an issue.
+func (b *Beads) Delete(id string) error {
+	return b.store.Delete(id)
 }
 
 // AddDependency adds a dependency: issue depends on dependsOn.
 func (b *Beads) AddDependency(issue, dependsOn string) error {
-	_, err := b.run("dep", "add", issue, dependsOn)
-	return err
+	return b.store.AddDependency(issue, dependsOn)
 }
 
 // RemoveDependency removes a dependency.
 func (b *Beads) RemoveDependency(issue, dependsOn string) error {
-	_, err := b.run("dep", "remove", issue, dependsOn)
-	return err
+	return b.store.RemoveDependency(issue, dependsOn)
 }
 
 // Sync syncs beads with remote.
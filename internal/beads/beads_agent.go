@@ -237,7 +237,7 @@ func (b *Beads) CreateOrReopenAgentBead(id, title string, fields *AgentFields) (
 
 	// The bead already exists (should be closed from previous polecat lifecycle)
 	// Reopen it and update its fields
-	if _, reopenErr := b.run("reopen", id, "--reason=re-spawning agent"); reopenErr != nil {
+	if reopenErr := b.Reopen(id, "re-spawning agent"); reopenErr != nil {
 		// If reopen fails, the bead might already be open - continue with update
 		if !strings.Contains(reopenErr.Error(), "already open") {
 			return nil, fmt.Errorf("reopening existing agent bead: %w (original error: %v)", reopenErr, err)
@@ -444,8 +444,7 @@ func (b *Beads) GetAgentNotificationLevel(id string) (string, error) {
 // WORKAROUND: Use CloseAndClearAgentBead instead, which allows CreateOrReopenAgentBead
 // to reopen the bead on re-spawn.
 func (b *Beads) DeleteAgentBead(id string) error {
-	_, err := b.run("delete", id, "--hard", "--force")
-	return err
+	return b.Delete(id)
 }
 
 // CloseAndClearAgentBead closes an agent bead (soft delete).
@@ -465,12 +464,7 @@ func (b *Beads) CloseAndClearAgentBead(id, reason string) error {
 	issue, err := b.Show(id)
 	if err != nil {
 		// If we can't read the issue, still attempt to close
-		args := []string{"close", id}
-		if reason != "" {
-			args = append(args, "--reason="+reason)
-		}
-		_, closeErr := b.run(args...)
-		return closeErr
+		return b.CloseWithReason(reason, id)
 	}
 
 	// Parse existing fields and clear mutable ones
@@ -491,12 +485,7 @@ func (b *Beads) CloseAndClearAgentBead(id, reason string) error {
 		// Non-fatal
 	}
 
-	args := []string{"close", id}
-	if reason != "" {
-		args = append(args, "--reason="+reason)
-	}
-	_, err = b.run(args...)
-	return err
+	return b.CloseWithReason(reason, id)
 }
 
 // GetAgentBead retrieves an agent bead by ID.
@@ -322,8 +322,7 @@ func (b *Beads) UpdateChannelStatus(name, status string) error {
 // DeleteChannelBead permanently deletes a channel bead.
 func (b *Beads) DeleteChannelBead(name string) error {
 	id := ChannelBeadID(name)
-	_, err := b.run("delete", id, "--hard", "--force")
-	return err
+	return b.Delete(id)
 }
 
 // ListChannelBeads returns all channel beads.
@@ -446,7 +445,7 @@ func (b *Beads) EnforceChannelRetention(name string) error {
 	// Delete marked messages (best-effort)
 	for id := range toDeleteIDs {
 		// Use close instead of delete for audit trail
-		_, _ = b.run("close", id, "--reason=channel retention pruning")
+		_ = b.CloseWithReason("channel retention pruning", id)
 	}
 
 	return nil
@@ -519,7 +518,7 @@ func (b *Beads) PruneAllChannels() (int, error) {
 
 		// Delete marked messages
 		for id := range toDeleteIDs {
-			if _, err := b.run("close", id, "--reason=patrol retention pruning"); err == nil {
+			if err := b.CloseWithReason("patrol retention pruning", id); err == nil {
 				pruned++
 			}
 		}
@@ -27,6 +27,20 @@ type EscalationFields struct {
 	ReescalationCount int    // Number of times this has been re-escalated
 	LastReescalatedAt string // When last re-escalated (empty if never)
 	LastReescalatedBy string // Who last re-escalated (empty if never)
+
+	Deliveries []EscalationDelivery // External notification attempts, oldest first
+}
+
+// EscalationDelivery records one external notification (email, sms) sent for
+// an escalation. Stored as a JSON "delivery:" line in the description.
+type EscalationDelivery struct {
+	Channel  string `json:"channel"`         // "email", "sms"
+	To       string `json:"to"`              // Recipient address or number
+	Status   string `json:"status"`          // "sent" or "failed"
+	Attempts int    `json:"attempts"`        // Attempts made, including retries
+	At       string `json:"at"`              // ISO 8601 timestamp of the final attempt
+	Severity string `json:"severity"`        // Severity the notification was sent at
+	Error    string `json:"error,omitempty"` // Final error when Status is "failed"
 }
 
 // EscalationState constants for bead status tracking.
@@ -103,6 +117,12 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	for _, d := range fields.Deliveries {
+		if data, err := json.Marshal(d); err == nil {
+			lines = append(lines, fmt.Sprintf("delivery: %s", data))
+		}
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -158,6 +178,11 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "delivery":
+			var d EscalationDelivery
+			if err := json.Unmarshal([]byte(value), &d); err == nil {
+				fields.Deliveries = append(fields.Deliveries, d)
+			}
 		}
 	}
 
@@ -259,8 +284,27 @@ func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
 	}
 
 	// Close the issue
-	_, err = b.run("close", id, "--reason="+reason)
-	return err
+	return b.CloseWithReason(reason, id)
+}
+
+// RecordEscalationDeliveries appends external notification records to an
+// escalation bead so acks and audits can see who was actually reached.
+func (b *Beads) RecordEscalationDeliveries(id string, deliveries ...EscalationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	issue, fields, err := b.GetEscalationBead(id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return fmt.Errorf("escalation not found: %s", id)
+	}
+
+	fields.Deliveries = append(fields.Deliveries, deliveries...)
+	description := FormatEscalationDescription(issue.Title, fields)
+	return b.Update(id, UpdateOptions{Description: &description})
 }
 
 // GetEscalationBead retrieves an escalation bead by ID.
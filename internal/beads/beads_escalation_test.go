@@ -0,0 +1,58 @@
+package beads
+
+import "testing"
+
+func TestEscalationDeliveriesRoundTrip(t *testing.T) {
+	fields := &EscalationFields{
+		Severity:    "critical",
+		EscalatedBy: "gastown/witness",
+		Deliveries: []EscalationDelivery{
+			{Channel: "email", To: "oncall@example.com", Status: "sent", Attempts: 1, At: "2026-01-01T03:00:00Z", Severity: "critical"},
+			{Channel: "sms", To: "+15551234567", Status: "failed", Attempts: 5, At: "2026-01-01T03:00:09Z", Severity: "critical", Error: "gateway returned 503: busy"},
+		},
+	}
+
+	parsed := ParseEscalationFields(FormatEscalationDescription("Deacon down", fields))
+	if len(parsed.Deliveries) != 2 {
+		t.Fatalf("parsed %d deliveries, want 2", len(parsed.Deliveries))
+	}
+	if parsed.Deliveries[0] != fields.Deliveries[0] || parsed.Deliveries[1] != fields.Deliveries[1] {
+		t.Errorf("deliveries = %+v, want %+v", parsed.Deliveries, fields.Deliveries)
+	}
+	if parsed.Severity != "critical" || parsed.EscalatedBy != "gastown/witness" {
+		t.Errorf("other fields lost: %+v", parsed)
+	}
+}
+
+func TestRecordEscalationDeliveries(t *testing.T) {
+	b, _ := newJSONLBeads(t)
+
+	description := FormatEscalationDescription("Deacon down", &EscalationFields{Severity: "high"})
+	if _, err := b.CreateWithID("hq-esc1", CreateOptions{
+		Title:       "Deacon down",
+		Type:        "escalation",
+		Description: description,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := EscalationDelivery{Channel: "email", To: "oncall@example.com", Status: "sent", Attempts: 1, Severity: "high"}
+	if err := b.RecordEscalationDeliveries("hq-esc1", sent); err != nil {
+		t.Fatalf("RecordEscalationDeliveries: %v", err)
+	}
+	paged := EscalationDelivery{Channel: "sms", To: "+15551234567", Status: "sent", Attempts: 2, Severity: "critical"}
+	if err := b.RecordEscalationDeliveries("hq-esc1", paged); err != nil {
+		t.Fatalf("RecordEscalationDeliveries: %v", err)
+	}
+
+	_, fields, err := b.GetEscalationBead("hq-esc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fields.Deliveries) != 2 || fields.Deliveries[0] != sent || fields.Deliveries[1] != paged {
+		t.Errorf("deliveries = %+v", fields.Deliveries)
+	}
+	if fields.Severity != "high" {
+		t.Errorf("severity = %q, want high", fields.Severity)
+	}
+}
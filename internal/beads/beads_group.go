@@ -252,8 +252,7 @@ func (b *Beads) RemoveGroupMember(name string, member string) error {
 // DeleteGroupBead permanently deletes a group bead.
 func (b *Beads) DeleteGroupBead(name string) error {
 	id := GroupBeadID(name)
-	_, err := b.run("delete", id, "--hard", "--force")
-	return err
+	return b.Delete(id)
 }
 
 // ListGroupBeads returns all group beads.
@@ -88,7 +88,7 @@ func TestIsBeadsRepo(t *testing.T) {
 // ZFC: Only test ErrNotFound detection. ErrNotARepo and ErrSyncConflict
 // were removed as per ZFC - agents should handle those errors directly.
 func TestWrapError(t *testing.T) {
-	b := New("/test")
+	s := NewCLIStore("/test", "")
 
 	tests := []struct {
 		stderr  string
@@ -100,7 +100,7 @@ func TestWrapError(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		err := b.wrapError(nil, tt.stderr, []string{"test"})
+		err := s.wrapError(nil, tt.stderr, []string{"test"})
 		if tt.wantNil {
 			if err != nil {
 				t.Errorf("wrapError(%q) = %v, want nil", tt.stderr, err)
@@ -568,6 +568,7 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		Rig:         "gastown",
 		MergeCommit: "abc123def789",
 		CloseReason: "merged",
+		TraceID:     "4bf92f3577b34da6a3ce929d0e0e4736",
 	}
 
 	// Format to string
@@ -885,6 +886,7 @@ func TestAttachmentFieldsRoundTrip(t *testing.T) {
 	original := &AttachmentFields{
 		AttachedMolecule: "mol-roundtrip",
 		AttachedAt:       "2025-12-21T15:30:00Z",
+		TraceID:          "4bf92f3577b34da6a3ce929d0e0e4736",
 	}
 
 	// Format to string
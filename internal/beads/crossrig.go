@@ -0,0 +1,323 @@
+// Package beads provides cross-rig dependency tracking through routes.
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExternalRefPrefix marks a dependency on an issue in another beads database.
+// Convoys use the same form to track issues across rigs.
+const ExternalRefPrefix = "external:"
+
+// ExternalRef returns the reference a rig's database records for a dependency
+// on beadID in another rig: "external:<prefix>:<id>" (e.g.,
+// "external:fe:fe-abc123").
+func ExternalRef(beadID string) string {
+	return fmt.Sprintf("%s%s:%s", ExternalRefPrefix, strings.TrimSuffix(ExtractPrefix(beadID), "-"), beadID)
+}
+
+// ParseExternalRef returns the bead ID in an external reference.
+func ParseExternalRef(ref string) (string, bool) {
+	rest, ok := strings.CutPrefix(ref, ExternalRefPrefix)
+	if !ok {
+		return "", false
+	}
+	_, id, ok := strings.Cut(rest, ":")
+	if !ok || id == "" {
+		return "", false
+	}
+	return id, true
+}
+
+// RouteDir returns the directory holding beadID's database, resolved through
+// the town's routes.jsonl by ID prefix.
+func RouteDir(townRoot, beadID string) (string, error) {
+	prefix := ExtractPrefix(beadID)
+	if prefix == "" {
+		return "", fmt.Errorf("%s: no prefix to route by", beadID)
+	}
+	dir := GetRigPathForPrefix(townRoot, prefix)
+	if dir == "" {
+		return "", fmt.Errorf("%s: no route for prefix %s", beadID, prefix)
+	}
+	return dir, nil
+}
+
+// AddCrossRigDependency records that issue depends on dependsOn, wherever
+// each lives. Both IDs are routed by prefix; when they share a database this
+// is a plain AddDependency. Otherwise dependsOn must exist in its own rig and
+// the edge is recorded in issue's database as an external reference, which
+// keeps issue out of ready work until the daemon's cross-rig dependency
+// watcher sees dependsOn close and resolves it. The edge is also added to the
+// town's cross-rig index so the watcher can find issue from dependsOn.
+func AddCrossRigDependency(townRoot, issue, dependsOn string) error {
+	issueDir, err := RouteDir(townRoot, issue)
+	if err != nil {
+		return err
+	}
+	depDir, err := RouteDir(townRoot, dependsOn)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(issueDir) == filepath.Clean(depDir) {
+		return New(issueDir).AddDependency(issue, dependsOn)
+	}
+
+	if _, err := New(depDir).Show(dependsOn); err != nil {
+		return fmt.Errorf("looking up %s: %w", dependsOn, err)
+	}
+	if err := New(issueDir).AddDependency(issue, ExternalRef(dependsOn)); err != nil {
+		return err
+	}
+	if err := indexCrossRigDependent(townRoot, issue, dependsOn); err != nil {
+		return fmt.Errorf("indexing %s -> %s: %w", issue, dependsOn, err)
+	}
+	return nil
+}
+
+// RemoveCrossRigDependency removes a dependency added with
+// AddCrossRigDependency.
+func RemoveCrossRigDependency(townRoot, issue, dependsOn string) error {
+	issueDir, err := RouteDir(townRoot, issue)
+	if err != nil {
+		return err
+	}
+	depDir, err := RouteDir(townRoot, dependsOn)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(issueDir) == filepath.Clean(depDir) {
+		return New(issueDir).RemoveDependency(issue, dependsOn)
+	}
+	if err := New(issueDir).RemoveDependency(issue, ExternalRef(dependsOn)); err != nil {
+		return err
+	}
+	if err := unindexCrossRigDependent(townRoot, issue, dependsOn); err != nil {
+		return fmt.Errorf("unindexing %s -> %s: %w", issue, dependsOn, err)
+	}
+	return nil
+}
+
+// CrossRigBlockerIDs returns the IDs of the issues in other rigs that issue
+// depends on.
+func CrossRigBlockerIDs(issue *Issue) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(ref string) {
+		if id, ok := ParseExternalRef(ref); ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, ref := range issue.DependsOn {
+		add(ref)
+	}
+	for _, ref := range issue.BlockedBy {
+		add(ref)
+	}
+	for _, dep := range issue.Dependencies {
+		if dep.DependencyType == "" || dep.DependencyType == "blocks" {
+			add(dep.ID)
+		}
+	}
+	return ids
+}
+
+// CrossRigBlocker is an issue in another rig that a blocked issue waits on.
+type CrossRigBlocker struct {
+	ID     string `json:"id"`
+	Rig    string `json:"rig"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status"` // "missing" if not found, "unknown" if its rig can't be read
+}
+
+// Closed reports whether the blocker no longer blocks.
+func (b CrossRigBlocker) Closed() bool {
+	return b.Status == "closed" || b.Status == "tombstone"
+}
+
+// CrossRigBlocked is an open issue waiting on issues in other rigs.
+type CrossRigBlocked struct {
+	Issue    *Issue            `json:"issue"`
+	Rig      string            `json:"rig"`
+	Blockers []CrossRigBlocker `json:"blockers"`
+}
+
+// FindCrossRigBlocked returns the blocked issues in every routed database
+// that depend on issues in other rigs, with each blocker's current status
+// looked up in its own rig. Databases that can't be read are skipped.
+func FindCrossRigBlocked(townRoot string) ([]CrossRigBlocked, error) {
+	routes, err := LoadRoutes(GetTownBeadsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading routes: %w", err)
+	}
+
+	var result []CrossRigBlocked
+	blockers := make(map[string]CrossRigBlocker)
+	seenDirs := make(map[string]bool)
+	for _, r := range routes {
+		dir := townRoot
+		if r.Path != "." {
+			dir = filepath.Join(townRoot, r.Path)
+		}
+		if seenDirs[filepath.Clean(dir)] {
+			continue
+		}
+		seenDirs[filepath.Clean(dir)] = true
+
+		blocked, err := New(dir).Blocked()
+		if err != nil {
+			continue
+		}
+		for _, issue := range blocked {
+			ids := CrossRigBlockerIDs(issue)
+			if len(ids) == 0 {
+				continue
+			}
+			entry := CrossRigBlocked{Issue: issue, Rig: RouteRigName(r.Path)}
+			for _, id := range ids {
+				b, ok := blockers[id]
+				if !ok {
+					b = lookupCrossRigBlocker(townRoot, id)
+					blockers[id] = b
+				}
+				entry.Blockers = append(entry.Blockers, b)
+			}
+			result = append(result, entry)
+		}
+	}
+
+	sortCrossRigBlocked(result)
+	return result, nil
+}
+
+// FindCrossRigDependents returns the open issues in other rigs that wait on
+// blockerID, listing only that blocker. Only the issues the cross-rig index
+// lists for blockerID are read, so no rig's database is scanned; edges
+// missing from the index are left to a FindCrossRigBlocked sweep.
+func FindCrossRigDependents(townRoot, blockerID string) ([]CrossRigBlocked, error) {
+	ids, err := crossRigDependentIDs(townRoot, blockerID)
+	if err != nil {
+		return nil, fmt.Errorf("reading cross-rig index: %w", err)
+	}
+
+	var result []CrossRigBlocked
+	var blocker *CrossRigBlocker
+	for _, id := range ids {
+		r := routeFor(townRoot, id)
+		if r == nil {
+			continue
+		}
+		dir := townRoot
+		if r.Path != "." {
+			dir = filepath.Join(townRoot, r.Path)
+		}
+		issue, err := New(dir).Show(id)
+		if err != nil || issue.Status == "closed" || issue.Status == "tombstone" {
+			continue
+		}
+		if len(filterIDs(CrossRigBlockerIDs(issue), blockerID)) == 0 {
+			continue // Edge removed outside gt
+		}
+		if blocker == nil {
+			b := lookupCrossRigBlocker(townRoot, blockerID)
+			blocker = &b
+		}
+		result = append(result, CrossRigBlocked{
+			Issue:    issue,
+			Rig:      RouteRigName(r.Path),
+			Blockers: []CrossRigBlocker{*blocker},
+		})
+	}
+
+	sortCrossRigBlocked(result)
+	return result, nil
+}
+
+// sortCrossRigBlocked orders blocked issues by rig, then ID.
+func sortCrossRigBlocked(result []CrossRigBlocked) {
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Rig != result[j].Rig {
+			return result[i].Rig < result[j].Rig
+		}
+		return result[i].Issue.ID < result[j].Issue.ID
+	})
+}
+
+// filterIDs returns ids reduced to id, if present.
+func filterIDs(ids []string, id string) []string {
+	for _, v := range ids {
+		if v == id {
+			return []string{id}
+		}
+	}
+	return nil
+}
+
+// lookupCrossRigBlocker finds a blocker in the rig its prefix routes to.
+func lookupCrossRigBlocker(townRoot, id string) CrossRigBlocker {
+	b := CrossRigBlocker{ID: id, Status: "unknown"}
+	if r := routeFor(townRoot, id); r != nil {
+		b.Rig = RouteRigName(r.Path)
+	}
+	dir, err := RouteDir(townRoot, id)
+	if err != nil {
+		return b
+	}
+	issue, err := New(dir).Show(id)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			b.Status = "missing"
+		}
+		return b
+	}
+	b.Title = issue.Title
+	b.Status = issue.Status
+	return b
+}
+
+// routeFor returns the route for beadID's prefix, or nil.
+func routeFor(townRoot, beadID string) *Route {
+	routes, err := LoadRoutes(GetTownBeadsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	prefix := ExtractPrefix(beadID)
+	for i := range routes {
+		if routes[i].Prefix == prefix {
+			return &routes[i]
+		}
+	}
+	return nil
+}
+
+// RouteRigName returns the rig a route path belongs to: its first path
+// element (e.g., "gastown/mayor/rig" -> "gastown"), or "town" for ".".
+func RouteRigName(path string) string {
+	if path == "." || path == "" {
+		return "town"
+	}
+	rig, _, _ := strings.Cut(filepath.ToSlash(path), "/")
+	return rig
+}
+
+// ResolveCrossRigBlockers removes the external edges of a blocked issue
+// whose blockers have closed. Once every edge is gone (and nothing in its own
+// rig blocks it) the issue is ready. Returns the blocker IDs resolved.
+func ResolveCrossRigBlockers(townRoot string, blocked CrossRigBlocked) ([]string, error) {
+	var resolved []string
+	for _, blocker := range blocked.Blockers {
+		if !blocker.Closed() {
+			continue
+		}
+		if err := RemoveCrossRigDependency(townRoot, blocked.Issue.ID, blocker.ID); err != nil {
+			return resolved, fmt.Errorf("removing %s -> %s: %w", blocked.Issue.ID, blocker.ID, err)
+		}
+		resolved = append(resolved, blocker.ID)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,108 @@
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/steveyegge/gastown/internal/filelock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// CrossRigIndexFileName is the file in the town's beads directory that maps
+// each cross-rig blocker to the issues in other rigs waiting on it, so a
+// close only has to look at those issues instead of every rig's database.
+const CrossRigIndexFileName = "crossrig-deps.json"
+
+// crossRigIndexPath returns the path of the town's cross-rig index.
+func crossRigIndexPath(townRoot string) string {
+	return filepath.Join(GetTownBeadsPath(townRoot), CrossRigIndexFileName)
+}
+
+// loadCrossRigIndex reads the index, returning an empty one if it doesn't
+// exist yet.
+func loadCrossRigIndex(path string) (map[string][]string, error) {
+	index := make(map[string][]string)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return index, nil
+}
+
+// updateCrossRigIndex applies fn to the index under a write lock and saves
+// the result.
+func updateCrossRigIndex(townRoot string, fn func(index map[string][]string)) error {
+	path := crossRigIndexPath(townRoot)
+	return filelock.WithWriteLock(path, func() error {
+		index, err := loadCrossRigIndex(path)
+		if err != nil {
+			return err
+		}
+		fn(index)
+		return util.AtomicWriteJSON(path, index)
+	})
+}
+
+// indexCrossRigDependent records that issue waits on blocker in another rig.
+func indexCrossRigDependent(townRoot, issue, blocker string) error {
+	return updateCrossRigIndex(townRoot, func(index map[string][]string) {
+		if !slices.Contains(index[blocker], issue) {
+			index[blocker] = append(index[blocker], issue)
+		}
+	})
+}
+
+// unindexCrossRigDependent drops issue from blocker's dependents.
+func unindexCrossRigDependent(townRoot, issue, blocker string) error {
+	return updateCrossRigIndex(townRoot, func(index map[string][]string) {
+		index[blocker] = slices.DeleteFunc(index[blocker], func(id string) bool { return id == issue })
+		if len(index[blocker]) == 0 {
+			delete(index, blocker)
+		}
+	})
+}
+
+// IndexCrossRigBlocked adds every edge in blocked to the cross-rig index.
+// Edges recorded with bd directly, or before the index existed, are only
+// found by a full FindCrossRigBlocked sweep; indexing its result lets later
+// closes find them with FindCrossRigDependents.
+func IndexCrossRigBlocked(townRoot string, blocked []CrossRigBlocked) error {
+	if len(blocked) == 0 {
+		return nil
+	}
+	return updateCrossRigIndex(townRoot, func(index map[string][]string) {
+		for _, entry := range blocked {
+			for _, b := range entry.Blockers {
+				if !slices.Contains(index[b.ID], entry.Issue.ID) {
+					index[b.ID] = append(index[b.ID], entry.Issue.ID)
+				}
+			}
+		}
+	})
+}
+
+// crossRigDependentIDs returns the issues the index lists as waiting on
+// blocker.
+func crossRigDependentIDs(townRoot, blocker string) ([]string, error) {
+	path := crossRigIndexPath(townRoot)
+	var ids []string
+	err := filelock.WithReadLock(path, func() error {
+		index, err := loadCrossRigIndex(path)
+		if err != nil {
+			return err
+		}
+		ids = index[blocker]
+		return nil
+	})
+	return ids, err
+}
@@ -0,0 +1,227 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupCrossRigTown creates a town with a frontend (fe-) and backend (be-)
+// rig, each with its own JSONL beads database.
+func setupCrossRigTown(t *testing.T) string {
+	t.Helper()
+	t.Setenv(EnvStore, "jsonl")
+
+	townRoot := t.TempDir()
+	for _, rig := range []struct{ name, prefix string }{{"frontend", "fe"}, {"backend", "be"}} {
+		beadsDir := filepath.Join(townRoot, rig.name, ".beads")
+		if err := os.MkdirAll(beadsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte("issue-prefix: "+rig.prefix+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	routes := []Route{{Prefix: "fe-", Path: "frontend"}, {Prefix: "be-", Path: "backend"}}
+	if err := WriteRoutes(GetTownBeadsPath(townRoot), routes); err != nil {
+		t.Fatal(err)
+	}
+
+	fe := New(filepath.Join(townRoot, "frontend"))
+	be := New(filepath.Join(townRoot, "backend"))
+	for _, c := range []struct {
+		b  *Beads
+		id string
+	}{{fe, "fe-login"}, {fe, "fe-nav"}, {be, "be-auth"}, {be, "be-db"}} {
+		if _, err := c.b.CreateWithID(c.id, CreateOptions{Title: c.id, Type: "task", Priority: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+func readyIDs(t *testing.T, b *Beads) map[string]bool {
+	t.Helper()
+	ready, err := b.Ready()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]bool)
+	for _, issue := range ready {
+		ids[issue.ID] = true
+	}
+	return ids
+}
+
+func TestExternalRef(t *testing.T) {
+	ref := ExternalRef("fe-abc.1")
+	if ref != "external:fe:fe-abc.1" {
+		t.Errorf("ExternalRef = %q", ref)
+	}
+	if id, ok := ParseExternalRef(ref); !ok || id != "fe-abc.1" {
+		t.Errorf("ParseExternalRef(%q) = %q, %v", ref, id, ok)
+	}
+	for _, bad := range []string{"fe-abc", "external:", "external:fe", "external:fe:"} {
+		if _, ok := ParseExternalRef(bad); ok {
+			t.Errorf("ParseExternalRef(%q) ok", bad)
+		}
+	}
+}
+
+func TestRouteRigName(t *testing.T) {
+	tests := map[string]string{
+		".":                 "town",
+		"gastown/mayor/rig": "gastown",
+		"frontend":          "frontend",
+	}
+	for path, want := range tests {
+		if got := RouteRigName(path); got != want {
+			t.Errorf("RouteRigName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestCrossRigDependencyLifecycle(t *testing.T) {
+	townRoot := setupCrossRigTown(t)
+	fe := New(filepath.Join(townRoot, "frontend"))
+	be := New(filepath.Join(townRoot, "backend"))
+
+	if err := AddCrossRigDependency(townRoot, "fe-login", "be-auth"); err != nil {
+		t.Fatalf("AddCrossRigDependency: %v", err)
+	}
+	if err := AddCrossRigDependency(townRoot, "fe-login", "be-db"); err != nil {
+		t.Fatalf("AddCrossRigDependency: %v", err)
+	}
+	// Same-rig dependency is a plain edge
+	if err := AddCrossRigDependency(townRoot, "fe-nav", "fe-login"); err != nil {
+		t.Fatalf("AddCrossRigDependency (same rig): %v", err)
+	}
+	if err := AddCrossRigDependency(townRoot, "fe-login", "be-missing"); err == nil {
+		t.Error("dependency on a missing bead should fail")
+	}
+	if err := AddCrossRigDependency(townRoot, "fe-login", "zz-unrouted"); err == nil {
+		t.Error("dependency on an unrouted prefix should fail")
+	}
+
+	if ready := readyIDs(t, fe); ready["fe-login"] || ready["fe-nav"] {
+		t.Errorf("frontend ready = %v, want fe-login and fe-nav blocked", ready)
+	}
+
+	blocked, err := FindCrossRigBlocked(townRoot)
+	if err != nil {
+		t.Fatalf("FindCrossRigBlocked: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].Issue.ID != "fe-login" || blocked[0].Rig != "frontend" {
+		t.Fatalf("blocked = %+v, want fe-login only (fe-nav waits in its own rig)", blocked)
+	}
+	if got := blocked[0].Blockers; len(got) != 2 || got[0].Rig != "backend" || got[0].Status != "open" {
+		t.Errorf("blockers = %+v", got)
+	}
+
+	// Dependents of one blocker list only that blocker
+	dependents, err := FindCrossRigDependents(townRoot, "be-auth")
+	if err != nil {
+		t.Fatalf("FindCrossRigDependents: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].Issue.ID != "fe-login" ||
+		len(dependents[0].Blockers) != 1 || dependents[0].Blockers[0].ID != "be-auth" {
+		t.Errorf("dependents of be-auth = %+v, want fe-login waiting on be-auth only", dependents)
+	}
+	if dependents, _ := FindCrossRigDependents(townRoot, "fe-login"); len(dependents) != 0 {
+		t.Errorf("dependents of fe-login = %+v, want none across rigs", dependents)
+	}
+
+	// Nothing closed yet: nothing to resolve
+	if resolved, err := ResolveCrossRigBlockers(townRoot, blocked[0]); err != nil || len(resolved) != 0 {
+		t.Errorf("ResolveCrossRigBlockers = %v, %v; want nothing", resolved, err)
+	}
+
+	// One blocker closes: its edge resolves, the other still blocks
+	if err := be.CloseWithReason("done", "be-auth"); err != nil {
+		t.Fatal(err)
+	}
+	blocked, _ = FindCrossRigBlocked(townRoot)
+	resolved, err := ResolveCrossRigBlockers(townRoot, blocked[0])
+	if err != nil || len(resolved) != 1 || resolved[0] != "be-auth" {
+		t.Fatalf("ResolveCrossRigBlockers = %v, %v; want [be-auth]", resolved, err)
+	}
+	if readyIDs(t, fe)["fe-login"] {
+		t.Error("fe-login ready while be-db is open")
+	}
+
+	// Last blocker closes: fe-login is ready in its own rig
+	if err := be.CloseWithReason("done", "be-db"); err != nil {
+		t.Fatal(err)
+	}
+	blocked, _ = FindCrossRigBlocked(townRoot)
+	if len(blocked) != 1 || len(blocked[0].Blockers) != 1 || !blocked[0].Blockers[0].Closed() {
+		t.Fatalf("blocked = %+v, want fe-login waiting on closed be-db", blocked)
+	}
+	if _, err := ResolveCrossRigBlockers(townRoot, blocked[0]); err != nil {
+		t.Fatal(err)
+	}
+	if !readyIDs(t, fe)["fe-login"] {
+		t.Error("fe-login not ready after its cross-rig blockers closed")
+	}
+	if blocked, _ = FindCrossRigBlocked(townRoot); len(blocked) != 0 {
+		t.Errorf("blocked after resolving = %+v", blocked)
+	}
+}
+
+func TestRemoveCrossRigDependency(t *testing.T) {
+	townRoot := setupCrossRigTown(t)
+	fe := New(filepath.Join(townRoot, "frontend"))
+
+	if err := AddCrossRigDependency(townRoot, "fe-login", "be-auth"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveCrossRigDependency(townRoot, "fe-login", "be-auth"); err != nil {
+		t.Fatalf("RemoveCrossRigDependency: %v", err)
+	}
+	if !readyIDs(t, fe)["fe-login"] {
+		t.Error("fe-login still blocked after removing its dependency")
+	}
+}
+
+func TestFindCrossRigDependents_UsesIndex(t *testing.T) {
+	townRoot := setupCrossRigTown(t)
+	fe := New(filepath.Join(townRoot, "frontend"))
+
+	// An edge recorded with bd directly is not indexed, so a close of its
+	// blocker doesn't scan frontend to find it
+	if err := fe.AddDependency("fe-nav", ExternalRef("be-auth")); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddCrossRigDependency(townRoot, "fe-login", "be-auth"); err != nil {
+		t.Fatal(err)
+	}
+	dependents, err := FindCrossRigDependents(townRoot, "be-auth")
+	if err != nil {
+		t.Fatalf("FindCrossRigDependents: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].Issue.ID != "fe-login" {
+		t.Fatalf("dependents = %+v, want indexed fe-login only", dependents)
+	}
+
+	// A full sweep indexes it
+	blocked, err := FindCrossRigBlocked(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := IndexCrossRigBlocked(townRoot, blocked); err != nil {
+		t.Fatalf("IndexCrossRigBlocked: %v", err)
+	}
+	if dependents, _ = FindCrossRigDependents(townRoot, "be-auth"); len(dependents) != 2 {
+		t.Errorf("dependents after indexing = %+v, want fe-login and fe-nav", dependents)
+	}
+
+	// Removing edges drops them from the index
+	for _, id := range []string{"fe-login", "fe-nav"} {
+		if err := RemoveCrossRigDependency(townRoot, id, "be-auth"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ids, err := crossRigDependentIDs(townRoot, "be-auth"); err != nil || len(ids) != 0 {
+		t.Errorf("index for be-auth = %v, %v; want empty", ids, err)
+	}
+}
@@ -22,6 +22,7 @@ type AttachmentFields struct {
 	AttachedArgs     string // Natural language args passed via gt sling --args (no-tmux mode)
 	DispatchedBy     string // Agent ID that dispatched this work (for completion notification)
 	NoMerge          bool   // If true, gt done skips merge queue (for upstream PRs/human review)
+	TraceID          string // Lifecycle trace minted by gt sling (see gt trace)
 }
 
 // ParseAttachmentFields extracts attachment fields from an issue's description.
@@ -69,6 +70,9 @@ func ParseAttachmentFields(issue *Issue) *AttachmentFields {
 		case "no_merge", "no-merge", "nomerge":
 			fields.NoMerge = strings.ToLower(value) == "true"
 			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
 		}
 	}
 
@@ -102,6 +106,9 @@ func FormatAttachmentFields(fields *AttachmentFields) string {
 	if fields.NoMerge {
 		lines = append(lines, "no_merge: true")
 	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -127,6 +134,9 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 		"no_merge":          true,
 		"no-merge":          true,
 		"nomerge":           true,
+		"trace_id":          true,
+		"trace-id":          true,
+		"traceid":           true,
 	}
 
 	// Collect non-attachment lines from existing description
@@ -197,6 +207,9 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Lifecycle tracing
+	TraceID string // Trace of the source issue, carried from gt sling (see gt trace)
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -271,6 +284,9 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "trace_id", "trace-id", "traceid":
+			fields.TraceID = value
+			hasFields = true
 		}
 	}
 
@@ -335,6 +351,9 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if fields.TraceID != "" {
+		lines = append(lines, "trace_id: "+fields.TraceID)
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -381,6 +400,9 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at": true,
 		"convoy-created-at": true,
 		"convoycreatedat":   true,
+		"trace_id":          true,
+		"trace-id":          true,
+		"traceid":           true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -0,0 +1,72 @@
+package beads
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrNotSupported is returned for bd features the active Store cannot provide
+// (slots, merge slots, gates, sync). Only CLIStore supports the full bd surface.
+var ErrNotSupported = errors.New("not supported by this beads store (requires the bd CLI)")
+
+// EnvStore selects the default Store for New and NewWithBeadsDir.
+// Set to "jsonl" to read and write .beads/issues.jsonl in-process instead of
+// exec'ing bd; any other value (or unset) uses the bd CLI.
+const EnvStore = "GT_BEADS_STORE"
+
+// Store is the storage backend behind a Beads wrapper.
+//
+// CLIStore execs the bd binary and is the default. JSONLStore reads and writes
+// the .beads JSONL file directly, so tests and long-running loops can work
+// with issues without the external binary.
+type Store interface {
+	// List returns issues matching opts.
+	List(opts ListOptions) ([]*Issue, error)
+
+	// Ready returns open issues with no open blockers.
+	Ready(opts ReadyOptions) ([]*Issue, error)
+
+	// Blocked returns open issues that have at least one open blocker.
+	Blocked() ([]*Issue, error)
+
+	// Show returns the issues with the given IDs. Missing IDs are omitted.
+	Show(ids ...string) ([]*Issue, error)
+
+	// Create creates an issue. If opts.ID is empty an ID is generated.
+	Create(opts CreateOptions) (*Issue, error)
+
+	// Update applies opts to an existing issue.
+	Update(id string, opts UpdateOptions) error
+
+	// Close closes issues, recording reason when non-empty.
+	Close(reason string, ids ...string) error
+
+	// Reopen moves a closed issue back to open.
+	Reopen(id, reason string) error
+
+	// Delete permanently removes an issue.
+	Delete(id string) error
+
+	// AddDependency records that issue depends on (is blocked by) dependsOn.
+	AddDependency(issue, dependsOn string) error
+
+	// RemoveDependency removes a dependency added with AddDependency.
+	RemoveDependency(issue, dependsOn string) error
+}
+
+// ReadyOptions specifies filters for ready work.
+type ReadyOptions struct {
+	Label string // Label filter (e.g., "gt:merge-request")
+	Limit int    // Maximum results, 0 for the backend default
+}
+
+// defaultStore returns the Store selected by EnvStore.
+func defaultStore(workDir, beadsDir string) Store {
+	if os.Getenv(EnvStore) == "jsonl" {
+		if beadsDir == "" {
+			beadsDir = ResolveBeadsDir(workDir)
+		}
+		return NewJSONLStore(beadsDir)
+	}
+	return NewCLIStore(workDir, beadsDir)
+}
@@ -0,0 +1,352 @@
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/runtime"
+)
+
+// CLIStore is the Store backed by the bd binary.
+type CLIStore struct {
+	workDir  string
+	beadsDir string // Optional BEADS_DIR override for cross-database access
+	isolated bool   // If true, suppress inherited beads env vars (for test isolation)
+}
+
+// NewCLIStore creates a Store that execs bd in workDir.
+// If beadsDir is empty it is resolved from workDir on every call.
+func NewCLIStore(workDir, beadsDir string) *CLIStore {
+	return &CLIStore{workDir: workDir, beadsDir: beadsDir}
+}
+
+// run executes a bd command and returns stdout.
+func (s *CLIStore) run(args ...string) ([]byte, error) {
+	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
+	// The daemon is primarily useful for write coalescing, not reads.
+	// Use --allow-stale to prevent failures when db is out of sync with JSONL
+	// (e.g., after daemon is killed during shutdown before syncing).
+	fullArgs := append([]string{"--no-daemon", "--allow-stale"}, args...)
+
+	// Always explicitly set BEADS_DIR to prevent inherited env vars from
+	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
+	// resolve from working directory.
+	beadsDir := s.beadsDir
+	if beadsDir == "" {
+		beadsDir = ResolveBeadsDir(s.workDir)
+	}
+
+	// In isolated mode, use --db flag to force specific database path
+	// This bypasses bd's routing logic that can redirect to .beads-planning
+	// Skip --db for init command since it creates the database
+	isInit := len(args) > 0 && args[0] == "init"
+	if s.isolated && !isInit {
+		beadsDB := filepath.Join(beadsDir, "beads.db")
+		fullArgs = append([]string{"--db", beadsDB}, fullArgs...)
+	}
+
+	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
+	cmd.Dir = s.workDir
+
+	// Build environment: filter beads env vars when in isolated mode (tests)
+	// to prevent routing to production databases.
+	var env []string
+	if s.isolated {
+		env = filterBeadsEnv(os.Environ())
+	} else {
+		env = os.Environ()
+	}
+	cmd.Env = append(env, "BEADS_DIR="+beadsDir)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		return nil, s.wrapError(err, stderr.String(), args)
+	}
+
+	// Handle bd --no-daemon exit code 0 bug: when issue not found,
+	// --no-daemon exits 0 but writes error to stderr with empty stdout.
+	// Detect this case and treat as error to avoid JSON parse failures.
+	if stdout.Len() == 0 && stderr.Len() > 0 {
+		return nil, s.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// wrapError wraps bd errors with context.
+// ZFC: Avoid parsing stderr to make decisions. Transport errors to agents instead.
+// Exception: ErrNotInstalled (exec.ErrNotFound) and ErrNotFound (issue lookup) are
+// acceptable as they enable basic error handling without decision-making.
+func (s *CLIStore) wrapError(err error, stderr string, args []string) error {
+	stderr = strings.TrimSpace(stderr)
+
+	// Check for bd not installed
+	if execErr, ok := err.(*exec.Error); ok && errors.Is(execErr.Err, exec.ErrNotFound) {
+		return ErrNotInstalled
+	}
+
+	// ErrNotFound is widely used for issue lookups - acceptable exception
+	// Match various "not found" error patterns from bd
+	if strings.Contains(stderr, "not found") || strings.Contains(stderr, "Issue not found") ||
+		strings.Contains(stderr, "no issue found") {
+		return ErrNotFound
+	}
+
+	if stderr != "" {
+		return fmt.Errorf("bd %s: %s", strings.Join(args, " "), stderr)
+	}
+	return fmt.Errorf("bd %s: %w", strings.Join(args, " "), err)
+}
+
+// filterBeadsEnv removes beads-related environment variables from the given
+// environment slice. This ensures test isolation by preventing inherited
+// BD_ACTOR, BEADS_DB, GT_ROOT, HOME etc. from routing commands to production databases.
+func filterBeadsEnv(environ []string) []string {
+	filtered := make([]string, 0, len(environ))
+	for _, env := range environ {
+		// Skip beads-related env vars that could interfere with test isolation
+		// BD_ACTOR, BEADS_* - direct beads config
+		// GT_ROOT - causes bd to find global routes file
+		// HOME - causes bd to find ~/.beads-planning routing
+		if strings.HasPrefix(env, "BD_ACTOR=") ||
+			strings.HasPrefix(env, "BEADS_") ||
+			strings.HasPrefix(env, "GT_ROOT=") ||
+			strings.HasPrefix(env, "HOME=") {
+			continue
+		}
+		filtered = append(filtered, env)
+	}
+	return filtered
+}
+
+// runJSON runs a bd command and decodes its JSON output into v.
+func (s *CLIStore) runJSON(v any, args ...string) error {
+	out, err := s.run(args...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("parsing bd %s output: %w", args[0], err)
+	}
+	return nil
+}
+
+// List returns issues matching the given options.
+func (s *CLIStore) List(opts ListOptions) ([]*Issue, error) {
+	args := []string{"list", "--json"}
+
+	if opts.Status != "" {
+		args = append(args, "--status="+opts.Status)
+	}
+	// Prefer Label over Type (Type is deprecated)
+	if opts.Label != "" {
+		args = append(args, "--label="+opts.Label)
+	} else if opts.Type != "" {
+		// Deprecated: convert type to label for backward compatibility
+		args = append(args, "--label=gt:"+opts.Type)
+	}
+	if opts.Priority >= 0 {
+		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
+	}
+	if opts.Parent != "" {
+		args = append(args, "--parent="+opts.Parent)
+	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
+	}
+	if opts.NoAssignee {
+		args = append(args, "--no-assignee")
+	}
+
+	var issues []*Issue
+	if err := s.runJSON(&issues, args...); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// Ready returns issues that are ready to work (not blocked).
+func (s *CLIStore) Ready(opts ReadyOptions) ([]*Issue, error) {
+	args := []string{"ready", "--json"}
+	if opts.Label != "" {
+		args = append(args, "--label", opts.Label)
+	}
+	if opts.Limit > 0 {
+		args = append(args, "-n", fmt.Sprintf("%d", opts.Limit))
+	}
+
+	var issues []*Issue
+	if err := s.runJSON(&issues, args...); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// Blocked returns issues that are blocked by dependencies.
+func (s *CLIStore) Blocked() ([]*Issue, error) {
+	var issues []*Issue
+	if err := s.runJSON(&issues, "blocked", "--json"); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// Show returns detailed information about one or more issues.
+func (s *CLIStore) Show(ids ...string) ([]*Issue, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"show", ids[0], "--json"}
+	if len(ids) > 1 {
+		// bd show supports multiple IDs
+		args = append([]string{"show", "--json"}, ids...)
+	}
+
+	// bd show --json returns an array even for a single ID
+	var issues []*Issue
+	if err := s.runJSON(&issues, args...); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// Create creates a new issue and returns it.
+func (s *CLIStore) Create(opts CreateOptions) (*Issue, error) {
+	args := []string{"create", "--json"}
+
+	if opts.ID != "" {
+		args = append(args, "--id="+opts.ID)
+		if NeedsForceForID(opts.ID) {
+			args = append(args, "--force")
+		}
+	}
+	if opts.Title != "" {
+		args = append(args, "--title="+opts.Title)
+	}
+	// Type is deprecated: convert to gt:<type> label
+	if opts.Type != "" {
+		args = append(args, "--labels=gt:"+opts.Type)
+	}
+	if opts.Priority >= 0 {
+		args = append(args, fmt.Sprintf("--priority=%d", opts.Priority))
+	}
+	if opts.Description != "" {
+		args = append(args, "--description="+opts.Description)
+	}
+	if opts.Parent != "" {
+		args = append(args, "--parent="+opts.Parent)
+	}
+	if opts.Ephemeral {
+		args = append(args, "--ephemeral")
+	}
+	if opts.Actor != "" {
+		args = append(args, "--actor="+opts.Actor)
+	}
+
+	var issue Issue
+	if err := s.runJSON(&issue, args...); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// Update updates an existing issue.
+func (s *CLIStore) Update(id string, opts UpdateOptions) error {
+	args := []string{"update", id}
+
+	if opts.Title != nil {
+		args = append(args, "--title="+*opts.Title)
+	}
+	if opts.Status != nil {
+		args = append(args, "--status="+*opts.Status)
+	}
+	if opts.Priority != nil {
+		args = append(args, fmt.Sprintf("--priority=%d", *opts.Priority))
+	}
+	if opts.Description != nil {
+		args = append(args, "--description="+*opts.Description)
+	}
+	if opts.Assignee != nil {
+		args = append(args, "--assignee="+*opts.Assignee)
+	}
+	// Label operations: set-labels replaces all, otherwise use add/remove
+	if len(opts.SetLabels) > 0 {
+		for _, label := range opts.SetLabels {
+			args = append(args, "--set-labels="+label)
+		}
+	} else {
+		for _, label := range opts.AddLabels {
+			args = append(args, "--add-label="+label)
+		}
+		for _, label := range opts.RemoveLabels {
+			args = append(args, "--remove-label="+label)
+		}
+	}
+	if opts.Notes != nil {
+		args = append(args, "--notes="+*opts.Notes)
+	}
+
+	_, err := s.run(args...)
+	return err
+}
+
+// Close closes one or more issues.
+// If a runtime session ID is set in the environment, it is passed to bd close
+// for work attribution tracking (see decision 009-session-events-architecture.md).
+func (s *CLIStore) Close(reason string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := append([]string{"close"}, ids...)
+	if reason != "" {
+		args = append(args, "--reason="+reason)
+	}
+
+	// Pass session ID for work attribution if available
+	if sessionID := runtime.SessionIDFromEnv(); sessionID != "" {
+		args = append(args, "--session="+sessionID)
+	}
+
+	_, err := s.run(args...)
+	return err
+}
+
+// Reopen reopens a closed issue.
+func (s *CLIStore) Reopen(id, reason string) error {
+	args := []string{"reopen", id}
+	if reason != "" {
+		args = append(args, "--reason="+reason)
+	}
+	_, err := s.run(args...)
+	return err
+}
+
+// Delete permanently deletes an issue.
+func (s *CLIStore) Delete(id string) error {
+	_, err := s.run("delete", id, "--hard", "--force")
+	return err
+}
+
+// AddDependency adds a dependency: issue depends on dependsOn.
+func (s *CLIStore) AddDependency(issue, dependsOn string) error {
+	_, err := s.run("dep", "add", issue, dependsOn)
+	return err
+}
+
+// RemoveDependency removes a dependency.
+func (s *CLIStore) RemoveDependency(issue, dependsOn string) error {
+	_, err := s.run("dep", "remove", issue, dependsOn)
+	return err
+}
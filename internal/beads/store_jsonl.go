@@ -0,0 +1,765 @@
+package beads
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/filelock"
+)
+
+// Dependency types understood by JSONLStore.
+const (
+	depBlocks      = "blocks"
+	depParentChild = "parent-child"
+)
+
+// JSONLStore is a Store that reads and writes <beadsDir>/issues.jsonl
+// directly, without the bd binary.
+//
+// Records use bd's JSONL export schema, and fields this store does not model
+// are carried through unchanged, so the file stays importable by bd. Reads
+// take a shared filelock on issues.jsonl; every mutation holds the exclusive
+// lock across its read-modify-write cycle and replaces the file atomically.
+//
+// JSONLStore does not see bd's SQLite database. Don't point it at a beads
+// directory that a bd daemon is also writing.
+type JSONLStore struct {
+	beadsDir string
+}
+
+// NewJSONLStore creates a Store over the issues.jsonl file in beadsDir.
+func NewJSONLStore(beadsDir string) *JSONLStore {
+	return &JSONLStore{beadsDir: beadsDir}
+}
+
+// Path returns the path of the JSONL file.
+func (s *JSONLStore) Path() string {
+	return filepath.Join(s.beadsDir, "issues.jsonl")
+}
+
+// jsonlIssue holds the issue fields JSONLStore understands.
+type jsonlIssue struct {
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
+	Status       string     `json:"status"`
+	Priority     int        `json:"priority"`
+	IssueType    string     `json:"issue_type"`
+	Assignee     string     `json:"assignee,omitempty"`
+	Labels       []string   `json:"labels,omitempty"`
+	CreatedAt    string     `json:"created_at"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	UpdatedAt    string     `json:"updated_at"`
+	ClosedAt     string     `json:"closed_at,omitempty"`
+	CloseReason  string     `json:"close_reason,omitempty"`
+	Ephemeral    bool       `json:"ephemeral,omitempty"`
+	HookBead     string     `json:"hook_bead,omitempty"`
+	AgentState   string     `json:"agent_state,omitempty"`
+	Dependencies []jsonlDep `json:"dependencies,omitempty"`
+}
+
+// jsonlDep is one dependency edge: IssueID depends on DependsOnID.
+type jsonlDep struct {
+	IssueID     string `json:"issue_id"`
+	DependsOnID string `json:"depends_on_id"`
+	Type        string `json:"type"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	CreatedBy   string `json:"created_by,omitempty"`
+}
+
+// jsonlRecord is one line of issues.jsonl. Unknown fields are kept in extra
+// so rewriting the file doesn't drop data written by newer bd versions.
+type jsonlRecord struct {
+	jsonlIssue
+	extra map[string]json.RawMessage
+}
+
+// jsonlKnownFields lists the JSON keys owned by jsonlIssue.
+var jsonlKnownFields = func() map[string]bool {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(jsonlIssue{})
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" {
+			known[name] = true
+		}
+	}
+	return known
+}()
+
+func (r *jsonlRecord) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.jsonlIssue); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	r.extra = nil
+	for k, v := range all {
+		if jsonlKnownFields[k] {
+			continue
+		}
+		if r.extra == nil {
+			r.extra = make(map[string]json.RawMessage)
+		}
+		r.extra[k] = v
+	}
+	return nil
+}
+
+func (r jsonlRecord) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(r.jsonlIssue)
+	if err != nil || len(r.extra) == 0 {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range r.extra {
+		all[k] = v
+	}
+	return json.Marshal(all)
+}
+
+// isClosedStatus reports whether status takes an issue out of open work.
+func isClosedStatus(status string) bool {
+	return status == "closed" || status == "tombstone"
+}
+
+// jsonlDB is an in-memory view of issues.jsonl.
+type jsonlDB struct {
+	records []*jsonlRecord
+	byID    map[string]*jsonlRecord
+}
+
+func (db *jsonlDB) get(id string) *jsonlRecord {
+	return db.byID[id]
+}
+
+func (db *jsonlDB) add(rec *jsonlRecord) {
+	db.records = append(db.records, rec)
+	db.byID[rec.ID] = rec
+}
+
+func (db *jsonlDB) remove(id string) {
+	kept := db.records[:0]
+	for _, rec := range db.records {
+		if rec.ID == id {
+			continue
+		}
+		deps := rec.Dependencies[:0]
+		for _, d := range rec.Dependencies {
+			if d.DependsOnID != id {
+				deps = append(deps, d)
+			}
+		}
+		rec.Dependencies = deps
+		kept = append(kept, rec)
+	}
+	db.records = kept
+	delete(db.byID, id)
+}
+
+// isOpen reports whether id names an issue that still blocks its dependents.
+// Dependencies on issues missing from this file (e.g. in another rig) are
+// treated as open.
+func (db *jsonlDB) isOpen(id string) bool {
+	rec := db.get(id)
+	return rec == nil || !isClosedStatus(rec.Status)
+}
+
+// openBlockers returns the IDs of open issues rec is blocked by.
+func (db *jsonlDB) openBlockers(rec *jsonlRecord) []string {
+	var ids []string
+	for _, d := range rec.Dependencies {
+		if d.Type == depBlocks && db.isOpen(d.DependsOnID) {
+			ids = append(ids, d.DependsOnID)
+		}
+	}
+	return ids
+}
+
+// toIssue converts a record to the Issue shape bd's JSON output uses,
+// filling in the derived relationship fields.
+func (db *jsonlDB) toIssue(rec *jsonlRecord) *Issue {
+	issue := &Issue{
+		ID:          rec.ID,
+		Title:       rec.Title,
+		Description: rec.Description,
+		Status:      rec.Status,
+		Priority:    rec.Priority,
+		Type:        rec.IssueType,
+		CreatedAt:   rec.CreatedAt,
+		CreatedBy:   rec.CreatedBy,
+		UpdatedAt:   rec.UpdatedAt,
+		ClosedAt:    rec.ClosedAt,
+		Assignee:    rec.Assignee,
+		Labels:      append([]string(nil), rec.Labels...),
+		HookBead:    rec.HookBead,
+		AgentState:  rec.AgentState,
+	}
+
+	for _, d := range rec.Dependencies {
+		switch d.Type {
+		case depParentChild:
+			issue.Parent = d.DependsOnID
+		case depBlocks:
+			issue.DependsOn = append(issue.DependsOn, d.DependsOnID)
+		}
+		issue.Dependencies = append(issue.Dependencies, db.issueDep(d.DependsOnID, d.Type))
+	}
+	issue.BlockedBy = db.openBlockers(rec)
+
+	for _, other := range db.records {
+		for _, d := range other.Dependencies {
+			if d.DependsOnID != rec.ID {
+				continue
+			}
+			switch d.Type {
+			case depParentChild:
+				issue.Children = append(issue.Children, other.ID)
+			case depBlocks:
+				issue.Blocks = append(issue.Blocks, other.ID)
+			}
+			issue.Dependents = append(issue.Dependents, db.issueDep(other.ID, d.Type))
+		}
+	}
+
+	issue.DependencyCount = len(issue.DependsOn)
+	issue.DependentCount = len(issue.Blocks)
+	issue.BlockedByCount = len(issue.BlockedBy)
+	return issue
+}
+
+func (db *jsonlDB) issueDep(id, depType string) IssueDep {
+	dep := IssueDep{ID: id, DependencyType: depType}
+	if rec := db.get(id); rec != nil {
+		dep.Title = rec.Title
+		dep.Status = rec.Status
+		dep.Priority = rec.Priority
+		dep.Type = rec.IssueType
+	}
+	return dep
+}
+
+// issues converts the records accepted by keep, sorted by priority then age.
+func (db *jsonlDB) issues(keep func(*jsonlRecord) bool) []*Issue {
+	var matched []*jsonlRecord
+	for _, rec := range db.records {
+		if keep(rec) {
+			matched = append(matched, rec)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt < b.CreatedAt
+		}
+		return a.ID < b.ID
+	})
+
+	issues := make([]*Issue, 0, len(matched))
+	for _, rec := range matched {
+		issues = append(issues, db.toIssue(rec))
+	}
+	return issues
+}
+
+// loadUnsafe reads issues.jsonl. Callers must hold the file lock.
+// A missing file is an empty database.
+func (s *JSONLStore) loadUnsafe() (*jsonlDB, error) {
+	db := &jsonlDB{byID: make(map[string]*jsonlRecord)}
+
+	f, err := os.Open(s.Path())
+	if os.IsNotExist(err) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", s.Path(), err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rec := &jsonlRecord{}
+		if err := json.Unmarshal(line, rec); err != nil {
+			return nil, fmt.Errorf("parsing %s line %d: %w", s.Path(), lineNum, err)
+		}
+		db.add(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", s.Path(), err)
+	}
+	return db, nil
+}
+
+// writeUnsafe replaces issues.jsonl atomically. Callers must hold the write lock.
+func (s *JSONLStore) writeUnsafe(db *jsonlDB) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, rec := range db.records {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("encoding %s: %w", rec.ID, err)
+		}
+	}
+
+	if err := os.MkdirAll(s.beadsDir, 0755); err != nil {
+		return fmt.Errorf("creating beads directory: %w", err)
+	}
+	tmpPath := s.Path() + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", tmpPath, err)
+	}
+	return os.Rename(tmpPath, s.Path())
+}
+
+// read runs fn against a snapshot of the file under a shared lock.
+func (s *JSONLStore) read(fn func(*jsonlDB) error) error {
+	return filelock.WithReadLock(s.Path(), func() error {
+		db, err := s.loadUnsafe()
+		if err != nil {
+			return err
+		}
+		return fn(db)
+	})
+}
+
+// mutate runs fn under the exclusive lock and writes the result back if fn
+// succeeds.
+func (s *JSONLStore) mutate(fn func(*jsonlDB) error) error {
+	return filelock.WithWriteLock(s.Path(), func() error {
+		db, err := s.loadUnsafe()
+		if err != nil {
+			return err
+		}
+		if err := fn(db); err != nil {
+			return err
+		}
+		return s.writeUnsafe(db)
+	})
+}
+
+// List returns issues matching the given options.
+func (s *JSONLStore) List(opts ListOptions) ([]*Issue, error) {
+	label := opts.Label
+	if label == "" && opts.Type != "" {
+		label = "gt:" + opts.Type
+	}
+
+	var issues []*Issue
+	err := s.read(func(db *jsonlDB) error {
+		issues = db.issues(func(rec *jsonlRecord) bool {
+			switch opts.Status {
+			case "":
+				if isClosedStatus(rec.Status) {
+					return false
+				}
+			case "all":
+				if rec.Status == "tombstone" {
+					return false
+				}
+			default:
+				if rec.Status != opts.Status {
+					return false
+				}
+			}
+			if label != "" && !hasLabel(rec.Labels, label) {
+				return false
+			}
+			if opts.Priority >= 0 && rec.Priority != opts.Priority {
+				return false
+			}
+			if opts.Parent != "" && !hasDep(rec, opts.Parent, depParentChild) {
+				return false
+			}
+			if opts.Assignee != "" && rec.Assignee != opts.Assignee {
+				return false
+			}
+			if opts.NoAssignee && rec.Assignee != "" {
+				return false
+			}
+			return true
+		})
+		return nil
+	})
+	return issues, err
+}
+
+// Ready returns open and in-progress issues with no open blockers.
+func (s *JSONLStore) Ready(opts ReadyOptions) ([]*Issue, error) {
+	var issues []*Issue
+	err := s.read(func(db *jsonlDB) error {
+		issues = db.issues(func(rec *jsonlRecord) bool {
+			if rec.Status != "open" && rec.Status != "in_progress" {
+				return false
+			}
+			if opts.Label != "" && !hasLabel(rec.Labels, opts.Label) {
+				return false
+			}
+			return len(db.openBlockers(rec)) == 0
+		})
+		return nil
+	})
+	if opts.Limit > 0 && len(issues) > opts.Limit {
+		issues = issues[:opts.Limit]
+	}
+	return issues, err
+}
+
+// Blocked returns issues that are blocked by open dependencies.
+func (s *JSONLStore) Blocked() ([]*Issue, error) {
+	var issues []*Issue
+	err := s.read(func(db *jsonlDB) error {
+		issues = db.issues(func(rec *jsonlRecord) bool {
+			return !isClosedStatus(rec.Status) && len(db.openBlockers(rec)) > 0
+		})
+		return nil
+	})
+	return issues, err
+}
+
+// Show returns the issues with the given IDs, in the order requested.
+func (s *JSONLStore) Show(ids ...string) ([]*Issue, error) {
+	var issues []*Issue
+	err := s.read(func(db *jsonlDB) error {
+		for _, id := range ids {
+			if rec := db.get(id); rec != nil && rec.Status != "tombstone" {
+				issues = append(issues, db.toIssue(rec))
+			}
+		}
+		return nil
+	})
+	return issues, err
+}
+
+// Create creates a new issue. Without opts.ID, children of opts.Parent get
+// the next <parent>.N ID and other issues get <prefix>-<random>.
+func (s *JSONLStore) Create(opts CreateOptions) (*Issue, error) {
+	var issue *Issue
+	err := s.mutate(func(db *jsonlDB) error {
+		if opts.Parent != "" && db.get(opts.Parent) == nil {
+			return fmt.Errorf("parent %s: %w", opts.Parent, ErrNotFound)
+		}
+
+		id := opts.ID
+		switch {
+		case id != "":
+			if db.get(id) != nil {
+				return fmt.Errorf("issue %s already exists", id)
+			}
+		case opts.Parent != "":
+			id = nextChildID(db, opts.Parent)
+		default:
+			var err error
+			if id, err = s.generateID(db); err != nil {
+				return err
+			}
+		}
+
+		now := jsonlNow()
+		rec := &jsonlRecord{jsonlIssue: jsonlIssue{
+			ID:          id,
+			Title:       opts.Title,
+			Description: opts.Description,
+			Status:      "open",
+			Priority:    opts.Priority,
+			IssueType:   "task",
+			CreatedAt:   now,
+			CreatedBy:   opts.Actor,
+			UpdatedAt:   now,
+			Ephemeral:   opts.Ephemeral,
+		}}
+		if rec.Priority < 0 {
+			rec.Priority = 2
+		}
+		// Type is deprecated: convert to gt:<type> label, as the CLI does
+		if opts.Type != "" {
+			rec.Labels = []string{"gt:" + opts.Type}
+		}
+		if opts.Parent != "" {
+			rec.Dependencies = []jsonlDep{{
+				IssueID:     id,
+				DependsOnID: opts.Parent,
+				Type:        depParentChild,
+				CreatedAt:   now,
+				CreatedBy:   opts.Actor,
+			}}
+		}
+
+		db.add(rec)
+		issue = db.toIssue(rec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// Update updates an existing issue.
+func (s *JSONLStore) Update(id string, opts UpdateOptions) error {
+	return s.mutate(func(db *jsonlDB) error {
+		rec := db.get(id)
+		if rec == nil {
+			return ErrNotFound
+		}
+
+		now := jsonlNow()
+		if opts.Title != nil {
+			rec.Title = *opts.Title
+		}
+		if opts.Status != nil && *opts.Status != rec.Status {
+			rec.Status = *opts.Status
+			if rec.Status == "closed" {
+				rec.ClosedAt = now
+			} else {
+				rec.ClosedAt = ""
+				rec.CloseReason = ""
+			}
+		}
+		if opts.Priority != nil {
+			rec.Priority = *opts.Priority
+		}
+		if opts.Description != nil {
+			rec.Description = *opts.Description
+		}
+		if opts.Assignee != nil {
+			rec.Assignee = *opts.Assignee
+		}
+		// Label operations: set-labels replaces all, otherwise use add/remove
+		if len(opts.SetLabels) > 0 {
+			rec.Labels = nil
+			for _, label := range opts.SetLabels {
+				rec.Labels = addLabel(rec.Labels, label)
+			}
+		} else {
+			for _, label := range opts.AddLabels {
+				rec.Labels = addLabel(rec.Labels, label)
+			}
+			for _, label := range opts.RemoveLabels {
+				rec.Labels = removeLabel(rec.Labels, label)
+			}
+		}
+		if opts.Notes != nil {
+			rec.Notes = *opts.Notes
+		}
+
+		rec.UpdatedAt = now
+		return nil
+	})
+}
+
+// Close closes one or more issues.
+func (s *JSONLStore) Close(reason string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.mutate(func(db *jsonlDB) error {
+		now := jsonlNow()
+		for _, id := range ids {
+			rec := db.get(id)
+			if rec == nil {
+				return fmt.Errorf("closing %s: %w", id, ErrNotFound)
+			}
+			rec.Status = "closed"
+			rec.ClosedAt = now
+			rec.CloseReason = reason
+			rec.UpdatedAt = now
+		}
+		return nil
+	})
+}
+
+// Reopen reopens a closed issue. Reopening an open issue is a no-op.
+func (s *JSONLStore) Reopen(id, reason string) error {
+	return s.mutate(func(db *jsonlDB) error {
+		rec := db.get(id)
+		if rec == nil {
+			return ErrNotFound
+		}
+		if !isClosedStatus(rec.Status) {
+			return nil
+		}
+		rec.Status = "open"
+		rec.ClosedAt = ""
+		rec.CloseReason = ""
+		rec.UpdatedAt = jsonlNow()
+		return nil
+	})
+}
+
+// Delete removes an issue and every dependency edge pointing at it.
+func (s *JSONLStore) Delete(id string) error {
+	return s.mutate(func(db *jsonlDB) error {
+		if db.get(id) == nil {
+			return ErrNotFound
+		}
+		db.remove(id)
+		return nil
+	})
+}
+
+// AddDependency adds a dependency: issue depends on dependsOn.
+func (s *JSONLStore) AddDependency(issue, dependsOn string) error {
+	return s.mutate(func(db *jsonlDB) error {
+		rec := db.get(issue)
+		if rec == nil {
+			return fmt.Errorf("%s: %w", issue, ErrNotFound)
+		}
+		// External references name issues in other rigs' databases
+		if _, external := ParseExternalRef(dependsOn); !external && db.get(dependsOn) == nil {
+			return fmt.Errorf("%s: %w", dependsOn, ErrNotFound)
+		}
+		if hasDep(rec, dependsOn, depBlocks) {
+			return nil
+		}
+		now := jsonlNow()
+		rec.Dependencies = append(rec.Dependencies, jsonlDep{
+			IssueID:     issue,
+			DependsOnID: dependsOn,
+			Type:        depBlocks,
+			CreatedAt:   now,
+		})
+		rec.UpdatedAt = now
+		return nil
+	})
+}
+
+// RemoveDependency removes a dependency.
+func (s *JSONLStore) RemoveDependency(issue, dependsOn string) error {
+	return s.mutate(func(db *jsonlDB) error {
+		rec := db.get(issue)
+		if rec == nil {
+			return fmt.Errorf("%s: %w", issue, ErrNotFound)
+		}
+		deps := rec.Dependencies[:0]
+		for _, d := range rec.Dependencies {
+			if d.DependsOnID == dependsOn && d.Type == depBlocks {
+				continue
+			}
+			deps = append(deps, d)
+		}
+		rec.Dependencies = deps
+		rec.UpdatedAt = jsonlNow()
+		return nil
+	})
+}
+
+// prefix returns the issue prefix for generated IDs: issue-prefix from
+// config.yaml, else the prefix of existing issues, else "bd".
+func (s *JSONLStore) prefix(db *jsonlDB) string {
+	if data, err := os.ReadFile(filepath.Join(s.beadsDir, "config.yaml")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			for _, key := range []string{"issue-prefix:", "prefix:"} {
+				if value, ok := strings.CutPrefix(line, key); ok {
+					if value = strings.Trim(strings.TrimSpace(value), `"'`); value != "" {
+						return value
+					}
+				}
+			}
+		}
+	}
+	for _, rec := range db.records {
+		if i := strings.Index(rec.ID, "-"); i > 0 {
+			return rec.ID[:i]
+		}
+	}
+	return "bd"
+}
+
+// generateID returns an unused <prefix>-<random base36> ID.
+func (s *JSONLStore) generateID(db *jsonlDB) (string, error) {
+	prefix := s.prefix(db)
+	limit := big.NewInt(36 * 36 * 36 * 36 * 36)
+	for range 100 {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("generating issue ID: %w", err)
+		}
+		suffix := strconv.FormatInt(n.Int64(), 36)
+		id := prefix + "-" + strings.Repeat("0", 5-len(suffix)) + suffix
+		if db.get(id) == nil {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("generating issue ID: too many collisions for prefix %q", prefix)
+}
+
+// nextChildID returns the next hierarchical child ID for parent (parent.N).
+func nextChildID(db *jsonlDB, parent string) string {
+	next := 1
+	for _, rec := range db.records {
+		suffix, ok := strings.CutPrefix(rec.ID, parent+".")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(suffix); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return fmt.Sprintf("%s.%d", parent, next)
+}
+
+func hasDep(rec *jsonlRecord, dependsOn, depType string) bool {
+	for _, d := range rec.Dependencies {
+		if d.DependsOnID == dependsOn && d.Type == depType {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+func addLabel(labels []string, label string) []string {
+	if hasLabel(labels, label) {
+		return labels
+	}
+	return append(labels, label)
+}
+
+func removeLabel(labels []string, label string) []string {
+	kept := labels[:0]
+	for _, l := range labels {
+		if l != label {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
+
+func jsonlNow() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
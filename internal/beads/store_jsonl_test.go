@@ -0,0 +1,283 @@
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newJSONLBeads(t *testing.T) (*Beads, *JSONLStore) {
+	t.Helper()
+	dir := t.TempDir()
+	beadsDir := filepath.Join(dir, ".beads")
+	if err := os.MkdirAll(beadsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte("issue-prefix: gt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewJSONLStore(beadsDir)
+	return NewWithStore(dir, store), store
+}
+
+func TestJSONLStore_CreateShowUpdate(t *testing.T) {
+	b, _ := newJSONLBeads(t)
+
+	issue, err := b.Create(CreateOptions{
+		Title:       "Fix the widget",
+		Type:        "bug",
+		Priority:    1,
+		Description: "It is broken",
+		Actor:       "gastown/crew/max",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(issue.ID, "gt-") {
+		t.Errorf("ID = %q, want gt- prefix from config.yaml", issue.ID)
+	}
+	if issue.Status != "open" || issue.CreatedBy != "gastown/crew/max" {
+		t.Errorf("created issue = %+v", issue)
+	}
+	if !hasLabel(issue.Labels, "gt:bug") {
+		t.Errorf("labels = %v, want gt:bug", issue.Labels)
+	}
+
+	title := "Fix the widget properly"
+	assignee := "gastown/polecats/Toast"
+	status := "in_progress"
+	if err := b.Update(issue.ID, UpdateOptions{
+		Title:     &title,
+		Assignee:  &assignee,
+		Status:    &status,
+		AddLabels: []string{"urgent"},
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := b.Show(issue.ID)
+	if err != nil {
+		t.Fatalf("Show: %v", err)
+	}
+	if got.Title != title || got.Assignee != assignee || got.Status != status {
+		t.Errorf("after update = %+v", got)
+	}
+	if !hasLabel(got.Labels, "urgent") || !hasLabel(got.Labels, "gt:bug") {
+		t.Errorf("labels = %v", got.Labels)
+	}
+
+	assigned, err := b.GetAssignedIssue(assignee)
+	if err != nil || assigned == nil || assigned.ID != issue.ID {
+		t.Errorf("GetAssignedIssue = %v, %v", assigned, err)
+	}
+
+	if err := b.ReleaseWithReason(issue.ID, "worker died"); err != nil {
+		t.Fatalf("ReleaseWithReason: %v", err)
+	}
+	got, _ = b.Show(issue.ID)
+	if got.Status != "open" || got.Assignee != "" {
+		t.Errorf("after release = %+v", got)
+	}
+
+	if _, err := b.Show("gt-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Show(missing) err = %v, want ErrNotFound", err)
+	}
+	if err := b.Update("gt-missing", UpdateOptions{Title: &title}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update(missing) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestJSONLStore_CreateWithIDAndChildren(t *testing.T) {
+	b, _ := newJSONLBeads(t)
+
+	epic, err := b.CreateWithID("gt-epic", CreateOptions{Title: "Epic", Priority: -1})
+	if err != nil {
+		t.Fatalf("CreateWithID: %v", err)
+	}
+	if epic.ID != "gt-epic" || epic.Priority != 2 {
+		t.Errorf("epic = %+v", epic)
+	}
+	if _, err := b.CreateWithID("gt-epic", CreateOptions{Title: "Dup"}); err == nil {
+		t.Error("expected duplicate ID to fail")
+	}
+
+	c1, err := b.Create(CreateOptions{Title: "Step 1", Parent: "gt-epic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c2, err := b.Create(CreateOptions{Title: "Step 2", Parent: "gt-epic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1.ID != "gt-epic.1" || c2.ID != "gt-epic.2" || c2.Parent != "gt-epic" {
+		t.Errorf("children = %s, %s (parent %q)", c1.ID, c2.ID, c2.Parent)
+	}
+
+	children, err := b.List(ListOptions{Parent: "gt-epic", Priority: -1})
+	if err != nil || len(children) != 2 {
+		t.Fatalf("List(parent) = %d issues, %v", len(children), err)
+	}
+	got, _ := b.Show("gt-epic")
+	if len(got.Children) != 2 {
+		t.Errorf("epic children = %v", got.Children)
+	}
+}
+
+func TestJSONLStore_ReadyAndBlocked(t *testing.T) {
+	b, _ := newJSONLBeads(t)
+
+	for _, id := range []string{"gt-a", "gt-b", "gt-c"} {
+		if _, err := b.CreateWithID(id, CreateOptions{Title: id, Type: "task", Priority: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// b depends on a; c is independent
+	if err := b.AddDependency("gt-b", "gt-a"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+
+	ids := func(issues []*Issue) string {
+		var out []string
+		for _, i := range issues {
+			out = append(out, i.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	ready, err := b.Ready()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(ready); got != "gt-a,gt-c" {
+		t.Errorf("Ready = %s, want gt-a,gt-c", got)
+	}
+	blocked, _ := b.Blocked()
+	if got := ids(blocked); got != "gt-b" {
+		t.Errorf("Blocked = %s, want gt-b", got)
+	}
+
+	bIssue, _ := b.Show("gt-b")
+	if len(bIssue.BlockedBy) != 1 || bIssue.BlockedBy[0] != "gt-a" || len(bIssue.Dependencies) != 1 {
+		t.Errorf("gt-b deps = %+v", bIssue)
+	}
+
+	// Closing the blocker makes b ready
+	if err := b.CloseWithReason("done", "gt-a"); err != nil {
+		t.Fatal(err)
+	}
+	ready, _ = b.ReadyWithType("task")
+	if got := ids(ready); got != "gt-b,gt-c" {
+		t.Errorf("ReadyWithType = %s, want gt-b,gt-c", got)
+	}
+
+	// Closed issues are hidden by default and visible with status=all
+	open, _ := b.List(ListOptions{Priority: -1})
+	all, _ := b.List(ListOptions{Status: "all", Priority: -1})
+	if len(open) != 2 || len(all) != 3 {
+		t.Errorf("List open=%d all=%d, want 2 and 3", len(open), len(all))
+	}
+
+	if err := b.Reopen("gt-a", ""); err != nil {
+		t.Fatal(err)
+	}
+	blocked, _ = b.Blocked()
+	if got := ids(blocked); got != "gt-b" {
+		t.Errorf("Blocked after reopen = %s, want gt-b", got)
+	}
+
+	if err := b.RemoveDependency("gt-b", "gt-a"); err != nil {
+		t.Fatal(err)
+	}
+	if blocked, _ = b.Blocked(); len(blocked) != 0 {
+		t.Errorf("Blocked after RemoveDependency = %s", ids(blocked))
+	}
+
+	if err := b.Delete("gt-c"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Show("gt-c"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Show after delete err = %v", err)
+	}
+}
+
+func TestJSONLStore_PreservesUnknownFields(t *testing.T) {
+	b, store := newJSONLBeads(t)
+
+	line := `{"id":"gt-x","title":"Old","status":"open","priority":2,"issue_type":"task",` +
+		`"created_at":"2026-01-01T00:00:00Z","updated_at":"2026-01-01T00:00:00Z",` +
+		`"external_ref":"gh-42","compaction_level":1}` + "\n"
+	if err := os.WriteFile(store.Path(), []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	title := "New"
+	if err := b.Update("gt-x", UpdateOptions{Title: &title}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	data, err := os.ReadFile(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"title":"New"`, `"external_ref":"gh-42"`, `"compaction_level":1`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("rewritten file missing %s:\n%s", want, data)
+		}
+	}
+}
+
+func TestJSONLStore_ConcurrentCreates(t *testing.T) {
+	b, _ := newJSONLBeads(t)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := b.Create(CreateOptions{Title: fmt.Sprintf("issue %d", i), Priority: 2}); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Create: %v", err)
+	}
+
+	issues, err := b.List(ListOptions{Priority: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != n {
+		t.Errorf("got %d issues, want %d (lost updates)", len(issues), n)
+	}
+}
+
+func TestJSONLStore_RawCommandsUnsupported(t *testing.T) {
+	b, _ := newJSONLBeads(t)
+
+	if _, err := b.Run("slot", "show", "gt-x"); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Run err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestDefaultStore_Env(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv(EnvStore, "jsonl")
+	if _, ok := New(dir).store.(*JSONLStore); !ok {
+		t.Errorf("%s=jsonl: store = %T, want *JSONLStore", EnvStore, New(dir).store)
+	}
+
+	t.Setenv(EnvStore, "")
+	if _, ok := New(dir).store.(*CLIStore); !ok {
+		t.Errorf("default store = %T, want *CLIStore", New(dir).store)
+	}
+}
@@ -51,7 +51,7 @@ Supported event types for witness patrol:
 
 Supported event types for refinery:
   merge_started    - When refinery starts a merge
-  merge_complete   - When merge succeeds
+  merged           - When merge succeeds (--target is the branch)
   merge_failed     - When merge fails
   queue_processed  - When refinery finishes processing queue
 
@@ -65,7 +65,8 @@ Examples:
   gt activity emit polecat_checked --rig greenplace --polecat Toast --status working --issue gp-xyz
   gt activity emit polecat_nudged --rig greenplace --polecat Toast --reason "idle for 10 minutes"
   gt activity emit escalation_sent --rig greenplace --target Toast --to mayor --reason "unresponsive"
-  gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"`,
+  gt activity emit patrol_complete --rig greenplace --count 3 --message "All polecats healthy"
+  gt activity emit merged --rig greenplace --target polecat/Toast --message gp-mr-abc`,
 	Args: cobra.ExactArgs(1),
 	RunE: runActivityEmit,
 }
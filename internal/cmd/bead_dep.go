@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var beadDepRemove bool
+
+var beadDepCmd = &cobra.Command{
+	Use:   "dep <bead-id> <depends-on>",
+	Short: "Make a bead depend on another, across rigs",
+	Long: `Record that a bead depends on (is blocked by) another bead.
+
+Both IDs are resolved through the town's routes by prefix, so the beads can
+live in different rigs. A dependency within one rig is a plain bd dependency.
+Across rigs, the blocker is recorded in the dependent's rig as an external
+reference (external:<prefix>:<id>): the bead stays out of ready work until
+the daemon's dependency watcher sees the blocker close and unblocks it.
+
+gt ready lists beads waiting on other rigs and the status of each blocker.
+
+Examples:
+  gt bead dep fe-login be-auth            # fe-login waits on be-auth
+  gt bead dep fe-login be-auth --remove   # Drop the dependency`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBeadDep,
+}
+
+func init() {
+	beadDepCmd.Flags().BoolVar(&beadDepRemove, "remove", false, "Remove the dependency instead of adding it")
+	beadCmd.AddCommand(beadDepCmd)
+}
+
+func runBeadDep(cmd *cobra.Command, args []string) error {
+	issue, dependsOn := args[0], args[1]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if beadDepRemove {
+		if err := beads.RemoveCrossRigDependency(townRoot, issue, dependsOn); err != nil {
+			return fmt.Errorf("removing dependency: %w", err)
+		}
+		fmt.Printf("%s %s no longer depends on %s\n", style.Bold.Render("✓"), issue, dependsOn)
+		return nil
+	}
+
+	if err := beads.AddCrossRigDependency(townRoot, issue, dependsOn); err != nil {
+		return fmt.Errorf("adding dependency: %w", err)
+	}
+	fmt.Printf("%s %s depends on %s\n", style.Bold.Render("✓"), issue, dependsOn)
+	if issueDir, _ := beads.RouteDir(townRoot, issue); issueDir != "" {
+		if depDir, _ := beads.RouteDir(townRoot, dependsOn); depDir != "" && depDir != issueDir {
+			fmt.Printf("  %s\n", style.Dim.Render("Cross-rig: unblocked by the daemon when "+dependsOn+" closes"))
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -275,24 +276,14 @@ func classifyCallback(subject string) CallbackType {
 // handlePolecatDone processes a POLECAT_DONE callback.
 // These come from Witnesses forwarding polecat completion notices.
 func handlePolecatDone(townRoot string, msg *mail.Message, dryRun bool) (string, error) { //nolint:unparam // error return kept for consistency with callback interface
-	matches := patternPolecatDone.FindStringSubmatch(msg.Subject)
-	polecatName := ""
-	if len(matches) > 1 {
+	// Extract info from the envelope, or the body text for older messages
+	var polecatName, exitType, issueID string
+	if payload, err := witness.ParsePolecatDone(msg.Subject, msg.Body); err == nil {
+		polecatName, exitType, issueID = payload.PolecatName, payload.Exit, payload.IssueID
+	} else if matches := patternPolecatDone.FindStringSubmatch(msg.Subject); len(matches) > 1 {
 		polecatName = matches[1]
 	}
 
-	// Extract info from body
-	var exitType, issueID string
-	for _, line := range strings.Split(msg.Body, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Exit:") {
-			exitType = strings.TrimSpace(strings.TrimPrefix(line, "Exit:"))
-		}
-		if strings.HasPrefix(line, "Issue:") {
-			issueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		}
-	}
-
 	if dryRun {
 		return fmt.Sprintf("would log completion for %s (exit=%s, issue=%s)",
 			polecatName, exitType, issueID), nil
@@ -17,6 +17,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tui/convoy"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -603,6 +604,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	}
 
 	fmt.Printf("%s Auto-closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, "All tracked issues completed", tracked)
 
 	// Send completion notification
 	notifyConvoyCompletion(townBeads, convoyID, convoy.Title)
@@ -673,6 +675,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Closed convoy 🚚 %s: %s\n", style.Bold.Render("✓"), convoyID, convoy.Title)
+	logConvoyClosed(convoyID, reason, getTrackedIssues(townBeads, convoyID))
 	if convoyCloseReason != "" {
 		fmt.Printf("  Reason: %s\n", convoyCloseReason)
 	}
@@ -688,6 +691,16 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// logConvoyClosed records a convoy closing in the activity feed. The tracked
+// issues let gt trace end each issue's lifecycle at the convoy close.
+func logConvoyClosed(convoyID, reason string, tracked []trackedIssueInfo) {
+	ids := make([]string, 0, len(tracked))
+	for _, t := range tracked {
+		ids = append(ids, t.ID)
+	}
+	_ = events.LogFeed(events.TypeConvoyClosed, detectActor(), events.ConvoyClosedPayload(convoyID, reason, ids))
+}
+
 // sendCloseNotification sends a notification about convoy closure.
 func sendCloseNotification(addr, convoyID, title, reason string) {
 	subject := fmt.Sprintf("🚚 Convoy closed: %s", title)
@@ -934,6 +947,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			}
 
 			closed = append(closed, struct{ ID, Title string }{convoy.ID, convoy.Title})
+			logConvoyClosed(convoy.ID, "All tracked issues completed", tracked)
 
 			// Check if convoy has notify address and send notification
 			notifyConvoyCompletion(townBeads, convoy.ID, convoy.Title)
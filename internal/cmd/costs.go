@@ -2,10 +2,9 @@
 package cmd
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,6 +16,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -46,11 +46,31 @@ var (
 var costsCmd = &cobra.Command{
 	Use:     "costs",
 	GroupID: GroupDiag,
-	Short:   "Show costs for running Claude sessions",
-	Long: `Display costs for Claude Code sessions in Gas Town.
+	Short:   "Show costs for running agent sessions",
+	Long: `Display costs for agent sessions in Gas Town.
 
-Costs are calculated from Claude Code transcript files at ~/.claude/projects/
-by summing token usage from assistant messages and applying model-specific pricing.
+Costs are calculated by summing token usage from each session's agent runtime
+logs and applying model-specific pricing. Each session's runtime follows the
+role_agents/default_agent settings (or GT_AGENT):
+
+  claude     ~/.claude/projects/<workdir>/*.jsonl
+  gemini     ~/.gemini/tmp/<hash>/chats/session-*.json
+  codex      ~/.codex/sessions/**/rollout-*.jsonl
+  opencode   ~/.local/share/opencode/storage/
+
+Other runtimes don't expose token usage and are shown as n/a.
+
+Prices are per million tokens. Built-in prices can be overridden or extended
+in <town>/settings/pricing.json:
+
+  {
+    "models":   {"claude-opus-4": {"input": 15, "output": 75,
+                                   "cache_read": 1.5, "cache_write": 18.75}},
+    "defaults": {"gemini": {"input": 1.25, "output": 10}}
+  }
+
+Models match exactly or by longest prefix, then fall back to the runtime's
+default price.
 
 Examples:
   gt costs              # Live costs from running sessions
@@ -63,7 +83,8 @@ Examples:
 
 Subcommands:
   gt costs record       # Record session cost to local log file (Stop hook)
-  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)`,
+  gt costs digest       # Aggregate log entries into daily digest bead (Deacon patrol)
+  gt costs budget       # Show budgets at or over their limits`,
 	RunE: runCosts,
 }
 
@@ -73,7 +94,7 @@ var costsRecordCmd = &cobra.Command{
 	Long: `Record the final cost of a session to a local log file.
 
 This command is intended to be called from a Claude Code Stop hook.
-It reads token usage from the Claude Code transcript file (~/.claude/projects/...)
+It reads token usage from the session's agent runtime logs (see 'gt costs')
 and calculates the cost based on model pricing, then appends it to
 ~/.gt/costs.jsonl. This is a simple append operation that never fails
 due to database availability.
@@ -154,12 +175,14 @@ func init() {
 
 // SessionCost represents cost info for a single session.
 type SessionCost struct {
-	Session string  `json:"session"`
-	Role    string  `json:"role"`
-	Rig     string  `json:"rig,omitempty"`
-	Worker  string  `json:"worker,omitempty"`
-	Cost    float64 `json:"cost_usd"`
-	Running bool    `json:"running"`
+	Session  string  `json:"session"`
+	Role     string  `json:"role"`
+	Rig      string  `json:"rig,omitempty"`
+	Worker   string  `json:"worker,omitempty"`
+	Runtime  string  `json:"runtime,omitempty"`
+	Cost     float64 `json:"cost_usd"`
+	Unpriced bool    `json:"unpriced,omitempty"` // Runtime doesn't expose token usage
+	Running  bool    `json:"running"`
 }
 
 // CostEntry is a ledger entry for historical cost tracking.
@@ -186,56 +209,6 @@ type CostsOutput struct {
 // costRegex matches cost patterns like "$1.23" or "$12.34"
 var costRegex = regexp.MustCompile(`\$(\d+\.\d{2})`)
 
-// TranscriptMessage represents a message from a Claude Code transcript file.
-type TranscriptMessage struct {
-	Type      string                 `json:"type"`
-	SessionID string                 `json:"sessionId"`
-	CWD       string                 `json:"cwd"`
-	Message   *TranscriptMessageBody `json:"message,omitempty"`
-}
-
-// TranscriptMessageBody contains the message content and usage info.
-type TranscriptMessageBody struct {
-	Model string          `json:"model"`
-	Role  string          `json:"role"`
-	Usage *TranscriptUsage `json:"usage,omitempty"`
-}
-
-// TranscriptUsage contains token usage information.
-type TranscriptUsage struct {
-	InputTokens              int `json:"input_tokens"`
-	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
-	OutputTokens             int `json:"output_tokens"`
-}
-
-// TokenUsage aggregates token usage across a session.
-type TokenUsage struct {
-	Model                    string
-	InputTokens              int
-	CacheCreationInputTokens int
-	CacheReadInputTokens     int
-	OutputTokens             int
-}
-
-// Model pricing per million tokens (as of Jan 2025).
-// See: https://www.anthropic.com/pricing
-var modelPricing = map[string]struct {
-	InputPerMillion       float64
-	OutputPerMillion      float64
-	CacheReadPerMillion   float64 // 90% discount on input price
-	CacheCreatePerMillion float64 // 25% premium on input price
-}{
-	// Claude Opus 4.5
-	"claude-opus-4-5-20251101": {15.0, 75.0, 1.5, 18.75},
-	// Claude Sonnet 4
-	"claude-sonnet-4-20250514": {3.0, 15.0, 0.3, 3.75},
-	// Claude Haiku 3.5
-	"claude-3-5-haiku-20241022": {1.0, 5.0, 0.1, 1.25},
-	// Fallback for unknown models (use Sonnet pricing)
-	"default": {3.0, 15.0, 0.3, 3.75},
-}
-
 func runCosts(cmd *cobra.Command, args []string) error {
 	// If querying ledger, use ledger functions
 	if costsToday || costsWeek || costsByRole || costsByRig {
@@ -257,6 +230,7 @@ func runLiveCosts() error {
 
 	var costs []SessionCost
 	var total float64
+	coster := newSessionCoster()
 
 	for _, session := range sessions {
 		// Only process Gas Town sessions (start with "gt-")
@@ -276,8 +250,10 @@ func runLiveCosts() error {
 			continue
 		}
 
-		// Extract cost from Claude transcript
-		cost, err := extractCostFromWorkDir(workDir)
+		// Extract cost from the agent runtime's session logs
+		agentOverride, _ := t.GetEnvironment(session, "GT_AGENT")
+		runtime := coster.runtime(role, rig, agentOverride)
+		cost, err := coster.cost(runtime, workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost for %s: %v\n", session, err)
@@ -290,12 +266,14 @@ func runLiveCosts() error {
 		running := t.IsAgentRunning(session)
 
 		costs = append(costs, SessionCost{
-			Session: session,
-			Role:    role,
-			Rig:     rig,
-			Worker:  worker,
-			Cost:    cost,
-			Running: running,
+			Session:  session,
+			Role:     role,
+			Rig:      rig,
+			Worker:   worker,
+			Runtime:  runtime,
+			Cost:     cost,
+			Unpriced: isUnpriced(err),
+			Running:  running,
 		})
 		total += cost
 	}
@@ -707,144 +685,57 @@ func extractCost(content string) float64 {
 	return cost
 }
 
-// getClaudeProjectDir returns the Claude Code project directory for a working directory.
-// Claude Code stores transcripts in ~/.claude/projects/<path-with-dashes-instead-of-slashes>/
-func getClaudeProjectDir(workDir string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	// Convert path to Claude's directory naming: replace / with -
-	// Keep leading slash - it becomes a leading dash in Claude's encoding
-	projectName := strings.ReplaceAll(workDir, "/", "-")
-	return filepath.Join(home, ".claude", "projects", projectName), nil
+// sessionCoster prices sessions by the agent runtime each one runs.
+type sessionCoster struct {
+	townRoot string
+	pricing  *costs.PricingTable
 }
 
-// findLatestTranscript finds the most recently modified .jsonl file in a directory.
-func findLatestTranscript(projectDir string) (string, error) {
-	var latestPath string
-	var latestTime time.Time
-
-	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() && path != projectDir {
-			return fs.SkipDir // Don't recurse into subdirectories
-		}
-		if !d.IsDir() && strings.HasSuffix(path, ".jsonl") {
-			info, err := d.Info()
-			if err != nil {
-				return nil // Skip files we can't stat
-			}
-			if info.ModTime().After(latestTime) {
-				latestTime = info.ModTime()
-				latestPath = path
-			}
-		}
-		return nil
-	})
-
+// newSessionCoster loads the town's pricing table, falling back to the
+// built-in prices outside a town or when settings/pricing.json is invalid.
+func newSessionCoster() *sessionCoster {
+	townRoot, _ := workspace.FindFromCwd()
+	pricing, err := costs.LoadPricing(townRoot)
 	if err != nil {
-		return "", err
-	}
-	if latestPath == "" {
-		return "", fmt.Errorf("no transcript files found in %s", projectDir)
+		fmt.Fprintf(os.Stderr, "warning: %v, using built-in pricing\n", err)
+		pricing = costs.DefaultPricing()
 	}
-	return latestPath, nil
+	return &sessionCoster{townRoot: townRoot, pricing: pricing}
 }
 
-// parseTranscriptUsage reads a transcript file and sums token usage from assistant messages.
-func parseTranscriptUsage(transcriptPath string) (*TokenUsage, error) {
-	file, err := os.Open(transcriptPath)
-	if err != nil {
-		return nil, err
+// runtime returns the cost parser runtime for a session's role, honoring a
+// GT_AGENT override. Returns "" if the agent isn't one we can parse.
+func (c *sessionCoster) runtime(role, rig, agentOverride string) string {
+	if c.townRoot == "" {
+		return "claude"
 	}
-	defer file.Close()
-
-	usage := &TokenUsage{}
-	scanner := bufio.NewScanner(file)
-	// Increase buffer for potentially large JSON lines
-	buf := make([]byte, 0, 256*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
-		}
-
-		var msg TranscriptMessage
-		if err := json.Unmarshal(line, &msg); err != nil {
-			continue // Skip malformed lines
-		}
-
-		// Only process assistant messages with usage info
-		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
-			continue
-		}
-
-		// Capture the model (use first one found, they should all be the same)
-		if usage.Model == "" && msg.Message.Model != "" {
-			usage.Model = msg.Message.Model
-		}
-
-		// Sum token usage
-		u := msg.Message.Usage
-		usage.InputTokens += u.InputTokens
-		usage.CacheCreationInputTokens += u.CacheCreationInputTokens
-		usage.CacheReadInputTokens += u.CacheReadInputTokens
-		usage.OutputTokens += u.OutputTokens
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
+	rigPath := ""
+	if rig != "" {
+		rigPath = filepath.Join(c.townRoot, rig)
 	}
 
-	return usage, nil
-}
-
-// calculateCost converts token usage to USD cost based on model pricing.
-func calculateCost(usage *TokenUsage) float64 {
-	if usage == nil {
-		return 0.0
+	var rc *config.RuntimeConfig
+	if agentOverride != "" {
+		rc, _, _ = config.ResolveAgentConfigWithOverride(c.townRoot, rigPath, agentOverride)
 	}
-
-	// Look up pricing for the model
-	pricing, ok := modelPricing[usage.Model]
-	if !ok {
-		pricing = modelPricing["default"]
+	if rc == nil {
+		rc = config.ResolveRoleAgentConfig(role, c.townRoot, rigPath)
 	}
-
-	// Calculate cost (prices are per million tokens)
-	inputCost := float64(usage.InputTokens) / 1_000_000 * pricing.InputPerMillion
-	cacheReadCost := float64(usage.CacheReadInputTokens) / 1_000_000 * pricing.CacheReadPerMillion
-	cacheCreateCost := float64(usage.CacheCreationInputTokens) / 1_000_000 * pricing.CacheCreatePerMillion
-	outputCost := float64(usage.OutputTokens) / 1_000_000 * pricing.OutputPerMillion
-
-	return inputCost + cacheReadCost + cacheCreateCost + outputCost
+	return costs.RuntimeForCommand(rc.Command)
 }
 
-// extractCostFromWorkDir extracts cost from Claude Code transcript for a working directory.
-// This reads the most recent transcript file and sums all token usage.
-func extractCostFromWorkDir(workDir string) (float64, error) {
-	projectDir, err := getClaudeProjectDir(workDir)
-	if err != nil {
-		return 0, fmt.Errorf("getting project dir: %w", err)
-	}
-
-	transcriptPath, err := findLatestTranscript(projectDir)
-	if err != nil {
-		return 0, fmt.Errorf("finding transcript: %w", err)
-	}
-
-	usage, err := parseTranscriptUsage(transcriptPath)
-	if err != nil {
-		return 0, fmt.Errorf("parsing transcript: %w", err)
+// cost prices the most recent session runtime ran in workDir.
+func (c *sessionCoster) cost(runtime, workDir string) (float64, error) {
+	if runtime == "" {
+		return 0, costs.ErrUnsupportedRuntime
 	}
+	return costs.SessionCost(runtime, workDir, c.pricing)
+}
 
-	return calculateCost(usage), nil
+// isUnpriced reports whether a cost error means the runtime can't be priced,
+// as opposed to its logs being missing or unreadable.
+func isUnpriced(err error) bool {
+	return errors.Is(err, costs.ErrUnsupportedRuntime)
 }
 
 // getTmuxSessionWorkDir gets the current working directory of a tmux session.
@@ -892,11 +783,16 @@ func outputCostsHuman(costs []SessionCost, total float64) error {
 			}
 		}
 
+		cost := fmt.Sprintf("$%.2f", c.Cost)
+		if c.Unpriced {
+			cost = "n/a"
+		}
+
 		fmt.Printf("%-25s %-10s %-15s %10s %8s\n",
 			c.Session,
 			c.Role,
 			rigWorker,
-			fmt.Sprintf("$%.2f", c.Cost),
+			cost,
 			statusIcon)
 	}
 
@@ -941,26 +837,6 @@ func outputLedgerHuman(output CostsOutput, entries []CostEntry) error {
 	return nil
 }
 
-// CostLogEntry represents a single entry in the costs.jsonl log file.
-type CostLogEntry struct {
-	SessionID string    `json:"session_id"`
-	Role      string    `json:"role"`
-	Rig       string    `json:"rig,omitempty"`
-	Worker    string    `json:"worker,omitempty"`
-	CostUSD   float64   `json:"cost_usd"`
-	EndedAt   time.Time `json:"ended_at"`
-	WorkItem  string    `json:"work_item,omitempty"`
-}
-
-// getCostsLogPath returns the path to the costs log file (~/.gt/costs.jsonl).
-func getCostsLogPath() string {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "/tmp/gt-costs.jsonl" // Fallback
-	}
-	return filepath.Join(home, ".gt", "costs.jsonl")
-}
-
 // runCostsRecord captures the final cost from a session and appends it to a local log file.
 // This is called by the Claude Code Stop hook. It's designed to never fail due to
 // database availability - it's a simple file append operation.
@@ -995,11 +871,15 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Extract cost from Claude transcript
+	// Parse session name
+	role, rig, worker := parseSessionName(session)
+
+	// Extract cost from the agent runtime's session logs
 	var cost float64
 	if workDir != "" {
+		coster := newSessionCoster()
 		var err error
-		cost, err = extractCostFromWorkDir(workDir)
+		cost, err = coster.cost(coster.runtime(role, rig, os.Getenv("GT_AGENT")), workDir)
 		if err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] could not extract cost from transcript: %v\n", err)
@@ -1008,11 +888,8 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Parse session name
-	role, rig, worker := parseSessionName(session)
-
 	// Build log entry
-	entry := CostLogEntry{
+	entry := costs.LogEntry{
 		SessionID: session,
 		Role:      role,
 		Rig:       rig,
@@ -1029,7 +906,7 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	}
 
 	// Append to log file
-	logPath := getCostsLogPath()
+	logPath := costs.LogPath()
 
 	// Ensure directory exists
 	logDir := filepath.Dir(logPath)
@@ -1220,7 +1097,7 @@ func runCostsDigest(cmd *cobra.Command, args []string) error {
 
 // querySessionCostEntries reads session cost entries from the local log file for a target date.
 func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
-	logPath := getCostsLogPath()
+	logPath := costs.LogPath()
 
 	// Read log file
 	data, err := os.ReadFile(logPath)
@@ -1234,7 +1111,7 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 	targetDay := targetDate.Format("2006-01-02")
 	var entries []CostEntry
 
-	// Parse each line as a CostLogEntry
+	// Parse each line as a costs.LogEntry
 	lines := strings.Split(string(data), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -1242,7 +1119,7 @@ func querySessionCostEntries(targetDate time.Time) ([]CostEntry, error) {
 			continue
 		}
 
-		var logEntry CostLogEntry
+		var logEntry costs.LogEntry
 		if err := json.Unmarshal([]byte(line), &logEntry); err != nil {
 			if costsVerbose {
 				fmt.Fprintf(os.Stderr, "[costs] failed to parse log entry: %v\n", err)
@@ -1339,7 +1216,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 // deleteSessionCostEntries removes entries for a target date from the costs log file.
 // It rewrites the file without the entries for that date.
 func deleteSessionCostEntries(targetDate time.Time) (int, error) {
-	logPath := getCostsLogPath()
+	logPath := costs.LogPath()
 
 	// Read log file
 	data, err := os.ReadFile(logPath)
@@ -1362,7 +1239,7 @@ func deleteSessionCostEntries(targetDate time.Time) (int, error) {
 			continue
 		}
 
-		var logEntry CostLogEntry
+		var logEntry costs.LogEntry
 		if err := json.Unmarshal([]byte(line), &logEntry); err != nil {
 			// Keep unparseable lines (shouldn't happen but be safe)
 			keepLines = append(keepLines, line)
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var costsBudgetJSON bool
+
+var costsBudgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Show budgets at or over their limits",
+	Long: `Show spend budgets that have reached their soft or hard limit.
+
+Budgets are daily and weekly USD caps in the "budget" section of town and
+rig settings (settings/config.json):
+
+  {
+    "budget": {
+      "soft_percent": 80,
+      "total":   {"daily_usd": 200, "weekly_usd": 800},
+      "roles":   {"polecat": {"daily_usd": 120}},
+      "convoys": {"hq-cv-abc": {"weekly_usd": 50}}
+    }
+  }
+
+In rig settings "total" caps the rig and "roles" caps roles within the rig.
+
+The daemon evaluates budgets every few minutes against ~/.gt/costs.jsonl and
+running sessions. At the soft limit it escalates a warning. At a hard limit
+only what the budget covers is paused, until the day or week rolls over or
+the cap is raised: gt sling refuses to dispatch into it, the daemon stops
+auto-starting its agents, and scheduled plugins wait. The Deacon is paused
+only by the town total or a "deacon" role cap.
+
+Examples:
+  gt costs budget          # Show breached budgets
+  gt costs budget --json   # Output as JSON`,
+	RunE: runCostsBudget,
+}
+
+func init() {
+	costsCmd.AddCommand(costsBudgetCmd)
+	costsBudgetCmd.Flags().BoolVar(&costsBudgetJSON, "json", false, "Output as JSON")
+}
+
+func runCostsBudget(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	breaches, err := costs.CurrentBreaches(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking budgets: %w", err)
+	}
+
+	if costsBudgetJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(breaches)
+	}
+
+	if len(breaches) == 0 {
+		fmt.Printf("%s All budgets under their limits\n", style.Success.Render("✓"))
+		return nil
+	}
+
+	for _, b := range breaches {
+		icon := style.Warning.Render("⚠")
+		if b.Level == costs.LevelHard {
+			icon = style.Error.Render("✗")
+		}
+		fmt.Printf("%s %s\n", icon, b)
+	}
+
+	if paused, state, _ := deacon.IsPaused(townRoot); paused && state != nil && state.PausedBy == "budget" {
+		fmt.Printf("\n%s\n", style.Dim.Render("Deacon paused by budget watcher; it resumes once spend is under its caps."))
+	}
+	return nil
+}
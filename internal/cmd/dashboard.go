@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/metrics"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -27,12 +28,28 @@ The dashboard shows real-time convoy status with:
 - Convoy list with status indicators
 - Progress tracking for each convoy
 - Last activity indicator (green/yellow/red)
-- Auto-refresh every 30 seconds via htmx
+- Live updates: workers, convoys and the merge queue are pushed over
+  server-sent events (/events) as .events.jsonl changes, with a slow
+  full-page refresh via htmx as a fallback
+
+The same data is served as JSON under /api/v1/ for scripts and tooling:
+convoys, workers, merge-queue, mail, rigs, dogs, escalations, health,
+hooks and sessions. Add ?rig=<name> to filter, and send If-None-Match
+with the returned ETag to skip unchanged responses.
+
+/metrics serves the daemon's latest town sample in OpenMetrics format for
+Prometheus: polecats by state, merge-queue depth, MR cycle time, open
+escalations, session deaths, cost per hour and mail backlog. Samples are
+recorded by the daemon every minute into daemon/metrics.jsonl (set
+patrols.metrics in mayor/daemon.json to change the interval or retention,
+or to serve /metrics from the daemon itself).
 
 Example:
   gt dashboard              # Start on default port 8080
   gt dashboard --port 3000  # Start on port 3000
-  gt dashboard --open       # Start and open browser`,
+  gt dashboard --open       # Start and open browser
+  curl localhost:8080/api/v1/workers?rig=gastown
+  curl localhost:8080/metrics`,
 	RunE: runDashboard,
 }
 
@@ -44,7 +61,8 @@ func init() {
 
 func runDashboard(cmd *cobra.Command, args []string) error {
 	// Verify we're in a workspace
-	if _, err := workspace.FindFromCwdOrError(); err != nil {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
@@ -60,6 +78,22 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating convoy handler: %w", err)
 	}
 
+	// Push live panel updates as events arrive
+	stream, err := web.NewEventStream(townRoot, fetcher)
+	if err != nil {
+		return fmt.Errorf("creating event stream: %w", err)
+	}
+	if err := stream.Start(); err != nil {
+		return fmt.Errorf("starting event stream: %w", err)
+	}
+	defer stream.Stop()
+
+	mux := http.NewServeMux()
+	mux.Handle(web.APIPrefix, web.NewAPIHandler(fetcher))
+	mux.Handle(web.StreamPath, stream)
+	mux.Handle(metrics.Path, metrics.NewHandler(metrics.NewStore(metrics.StorePath(townRoot), 0)))
+	mux.Handle("/", handler)
+
 	// Build the URL
 	url := fmt.Sprintf("http://localhost:%d", dashboardPort)
 
@@ -74,7 +108,7 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf(":%d", dashboardPort),
-		Handler:           handler,
+		Handler:           mux,
 		ReadHeaderTimeout: 10 * time.Second,
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      60 * time.Second,
@@ -142,6 +142,8 @@ This command is used by the Deacon during health rounds to detect stuck sessions
 It tracks consecutive failures and determines when force-kill is warranted.
 
 The detection protocol:
+0. If the daemon's agent monitor saw the agent's pane change within the
+   timeout (and it isn't blocked, erroring or idle), count that as a response
 1. Send HEALTH_CHECK nudge to the agent
 2. Wait for agent to update their bead (configurable timeout, default 30s)
 3. If no activity update, increment failure counter
@@ -734,6 +736,17 @@ func runDeaconHealthCheck(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	// The daemon's agent monitor watches panes; recent output is a response
+	if status, lastActivity, ok := deacon.MonitoredResponse(townRoot, agent, healthCheckTimeout); ok {
+		agentState.RecordResponse()
+		if err := deacon.SaveHealthCheckState(townRoot, state); err != nil {
+			style.PrintWarning("failed to save health check state: %v", err)
+		}
+		fmt.Printf("%s Agent %s is %s (pane output %s ago), no HEALTH_CHECK needed\n",
+			style.Bold.Render("✓"), agent, status, time.Since(lastActivity).Round(time.Second))
+		return nil
+	}
+
 	// Get current bead update time
 	baselineTime, err := getAgentBeadUpdateTime(townRoot, beadID)
 	if err != nil {
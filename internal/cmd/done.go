@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -386,6 +387,9 @@ func runDone(cmd *cobra.Command, args []string) error {
 			if agentBeadID != "" {
 				description += fmt.Sprintf("\nagent_bead: %s", agentBeadID)
 			}
+			if traceID := getTraceFromBead(cwd, issueID); traceID != "" {
+				description += fmt.Sprintf("\ntrace_id: %s", traceID)
+			}
 
 			// Add conflict resolution tracking fields (initialized, updated by Refinery)
 			description += "\nretry_count: 0"
@@ -459,24 +463,23 @@ notifyWitness:
 	witnessAddr := fmt.Sprintf("%s/witness", rigName)
 
 	// Build notification body
-	var bodyLines []string
-	bodyLines = append(bodyLines, fmt.Sprintf("Exit: %s", exitType))
-	if issueID != "" {
-		bodyLines = append(bodyLines, fmt.Sprintf("Issue: %s", issueID))
-	}
-	if mrID != "" {
-		bodyLines = append(bodyLines, fmt.Sprintf("MR: %s", mrID))
-	}
-	if doneGate != "" {
-		bodyLines = append(bodyLines, fmt.Sprintf("Gate: %s", doneGate))
-	}
-	bodyLines = append(bodyLines, fmt.Sprintf("Branch: %s", branch))
+	traceID := getTraceFromBead(cwd, issueID)
+	donePayload := &witness.PolecatDonePayload{
+		PolecatName: polecatName,
+		Exit:        exitType,
+		IssueID:     issueID,
+		MRID:        mrID,
+		Branch:      branch,
+		Gate:        doneGate,
+		TraceID:     traceID,
+	}
+	doneBody := witness.FormatPolecatDone(donePayload)
 
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
 		From:    sender,
 		Subject: fmt.Sprintf("POLECAT_DONE %s", polecatName),
-		Body:    strings.Join(bodyLines, "\n"),
+		Body:    doneBody,
 	}
 
 	fmt.Printf("\nNotifying Witness...\n")
@@ -493,7 +496,7 @@ notifyWitness:
 				To:      dispatcher,
 				From:    sender,
 				Subject: fmt.Sprintf("WORK_DONE: %s", issueID),
-				Body:    strings.Join(bodyLines, "\n"),
+				Body:    witness.FormatPolecatDoneText(donePayload),
 			}
 			if err := townRouter.Send(dispatcherNotification); err != nil {
 				style.PrintWarning("could not notify dispatcher %s: %v", dispatcher, err)
@@ -505,7 +508,7 @@ notifyWitness:
 
 	// Log done event (townlog and activity feed)
 	_ = LogDone(townRoot, sender, issueID)
-	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
+	_ = events.LogFeed(events.TypeDone, sender, events.WithTrace(events.DonePayload(issueID, branch), traceID))
 
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
@@ -729,6 +732,27 @@ func getDispatcherFromBead(cwd, issueID string) string {
 	return fields.DispatchedBy
 }
 
+// getTraceFromBead reads the lifecycle trace ID gt sling stored on an issue.
+// Returns empty string if the issue wasn't slung with a trace.
+func getTraceFromBead(cwd, issueID string) string {
+	if issueID == "" {
+		return ""
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(cwd))
+	issue, err := bd.Show(issueID)
+	if err != nil {
+		return ""
+	}
+
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil {
+		return ""
+	}
+
+	return fields.TraceID
+}
+
 // parseCleanupStatus converts a string flag value to a CleanupStatus.
 // ZFC: Agent observes git state and passes the appropriate status.
 func parseCleanupStatus(s string) polecat.CleanupStatus {
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -114,8 +116,14 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	// Process external notification actions (email:, sms:, slack, webhook:)
+	executeExternalActions(bd, actions, escalationConfig, notify.Message{
+		Subject:  fmt.Sprintf("[%s] %s", strings.ToUpper(severity), description),
+		Body:     formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+		ID:       issue.ID,
+		Severity: severity,
+		Actor:    agentID,
+	})
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -379,6 +387,15 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Page humans on the new route (email:, sms:, slack, webhook:)
+			executeExternalActions(bd, actions, escalationConfig, notify.Message{
+				Subject:  fmt.Sprintf("[%s→%s] Re-escalated: %s", strings.ToUpper(result.OldSeverity), strings.ToUpper(result.NewSeverity), result.Title),
+				Body:     formatReescalationMailBody(result, reescalatedBy),
+				ID:       result.ID,
+				Severity: result.NewSeverity,
+				Actor:    reescalatedBy,
+			})
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -490,6 +507,7 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 			"closedBy":    fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
 			"relatedBead": fields.RelatedBead,
+			"deliveries":  fields.Deliveries,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -516,6 +534,15 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if len(fields.Deliveries) > 0 {
+		fmt.Printf("  Deliveries:\n")
+		for _, d := range fields.Deliveries {
+			fmt.Printf("    %s %s → %s (%s, %d attempts, %s)\n", d.At, d.Channel, d.To, d.Status, d.Attempts, d.Severity)
+			if d.Error != "" {
+				fmt.Printf("      %s\n", strings.SplitN(d.Error, "\n", 2)[0])
+			}
+		}
+	}
 
 	return nil
 }
@@ -538,34 +565,86 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// executeExternalActions processes external notification actions (email:, sms:, slack, webhook:).
+// Each is delivered through the transports and webhooks in settings/escalation.json,
+// with retry, and every attempt is recorded on the escalation bead msg.ID.
+func executeExternalActions(bd *beads.Beads, actions []string, cfg *config.EscalationConfig, msg notify.Message) {
+	msg.Kind = "escalation"
+	msg.Type = "escalation"
+	msg.Time = time.Now().Format(time.RFC3339)
+	var deliveries []beads.EscalationDelivery
+
+	deliver := func(n notify.Notifier, to, icon string) {
+		res := notify.Send(context.Background(), n, to, msg, notify.DefaultRetry())
+		d := beads.EscalationDelivery{
+			Channel:  res.Channel,
+			To:       to,
+			Status:   "sent",
+			Attempts: res.Attempts,
+			At:       time.Now().Format(time.RFC3339),
+			Severity: msg.Severity,
+		}
+		if res.OK() {
+			fmt.Printf("  %s Sent %s to %s\n", icon, res.Channel, to)
+		} else {
+			d.Status = "failed"
+			d.Error = res.Err.Error()
+			style.PrintWarning("%s to %s failed after %d attempts: %v", res.Channel, to, res.Attempts, res.Err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
 	for _, action := range actions {
 		switch {
 		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
+			switch {
+			case cfg.Contacts.HumanEmail == "":
 				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
+			case cfg.Transports.SMTP == nil:
+				style.PrintWarning("email action '%s' skipped: transports.smtp not configured in settings/escalation.json", action)
+			default:
+				deliver(notify.NewEmailNotifier(cfg.Transports.SMTP), cfg.Contacts.HumanEmail, "📧")
 			}
 
 		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
+			switch {
+			case cfg.Contacts.HumanSMS == "":
 				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
+			case cfg.Transports.SMS == nil:
+				style.PrintWarning("sms action '%s' skipped: transports.sms not configured in settings/escalation.json", action)
+			default:
+				deliver(notify.NewSMSNotifier(cfg.Transports.SMS), cfg.Contacts.HumanSMS, "📱")
 			}
 
 		case action == "slack":
 			if cfg.Contacts.SlackWebhook == "" {
 				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
+				continue
+			}
+			// Slack incoming webhooks are a plain webhook with a {"text": ...} payload
+			slack, err := notify.NewWebhook("slack", &config.WebhookEndpoint{
+				URL:     cfg.Contacts.SlackWebhook,
+				Payload: notify.SlackPayload,
+			})
+			if err != nil {
+				style.PrintWarning("slack action skipped: %v", err)
+				continue
+			}
+			deliver(slack, "slack", "💬")
+
+		case strings.HasPrefix(action, "webhook:"):
+			name := strings.TrimPrefix(action, "webhook:")
+			endpoint := cfg.Webhooks[name]
+			if endpoint == nil {
+				style.PrintWarning("webhook action '%s' skipped: webhooks.%s not configured in settings/escalation.json", action, name)
+				continue
+			}
+			hook, err := notify.NewWebhook(name, endpoint)
+			if err != nil {
+				style.PrintWarning("webhook action '%s' skipped: %v", action, err)
+				continue
 			}
+			deliver(hook, name, "🔗")
 
 		case action == "log":
 			// Log action always succeeds - writes to escalation log file
@@ -573,6 +652,10 @@ func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _
 			fmt.Printf("  📝 Logged to escalation log\n")
 		}
 	}
+
+	if err := bd.RecordEscalationDeliveries(msg.ID, deliveries...); err != nil {
+		style.PrintWarning("failed to record deliveries on %s: %v", msg.ID, err)
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
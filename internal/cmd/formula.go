@@ -46,6 +46,7 @@ Commands:
   show    Display formula details (steps, variables, composition)
   run     Execute a formula (pour and dispatch)
   create  Create a new formula template
+  test    Run formula test fixtures in a simulator
 
 Search paths (in order):
   1. .beads/formulas/ (project)
@@ -56,7 +57,8 @@ Examples:
   gt formula list                    # List all formulas
   gt formula show shiny              # Show formula details
   gt formula run shiny --pr=123      # Run formula on PR #123
-  gt formula create my-workflow      # Create new formula template`,
+  gt formula create my-workflow      # Create new formula template
+  gt formula test                    # Run formula test fixtures`,
 }
 
 var formulaListCmd = &cobra.Command{
@@ -264,6 +266,9 @@ func runFormulaRun(cmd *cobra.Command, args []string) error {
 
 	// Currently only convoy formulas are supported for execution
 	if f.Type != "convoy" {
+		if err := checkFormulaExpansion(formulaName); err != nil {
+			return err
+		}
 		fmt.Printf("%s Formula type '%s' not yet supported for execution.\n",
 			style.Dim.Render("Note:"), f.Type)
 		fmt.Printf("Currently only 'convoy' formulas can be run.\n")
@@ -517,7 +522,22 @@ type formulaSynthesis struct {
 
 // findFormulaFile searches for a formula file by name
 func findFormulaFile(name string) (string, error) {
-	// Search paths in order
+	// Try each path with common extensions
+	extensions := []string{".formula.toml", ".formula.json"}
+	for _, basePath := range formulaSearchPaths() {
+		for _, ext := range extensions {
+			path := filepath.Join(basePath, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("formula '%s' not found in search paths", name)
+}
+
+// formulaSearchPaths returns the formula directories, in search order.
+func formulaSearchPaths() []string {
 	searchPaths := []string{}
 
 	// 1. Project .beads/formulas/
@@ -535,18 +555,7 @@ func findFormulaFile(name string) (string, error) {
 		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
 	}
 
-	// Try each path with common extensions
-	extensions := []string{".formula.toml", ".formula.json"}
-	for _, basePath := range searchPaths {
-		for _, ext := range extensions {
-			path := filepath.Join(basePath, name+ext)
-			if _, err := os.Stat(path); err == nil {
-				return path, nil
-			}
-		}
-	}
-
-	return "", fmt.Errorf("formula '%s' not found in search paths", name)
+	return searchPaths
 }
 
 // parseFormulaFile parses a formula file into formulaData
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// fixtureExt is the suffix of formula test fixtures.
+const fixtureExt = ".test.toml"
+
+var (
+	formulaTestJSON    bool
+	formulaTestVerbose bool
+)
+
+var formulaTestCmd = &cobra.Command{
+	Use:   "test [fixture|dir...]",
+	Short: "Run formula test fixtures in a simulator",
+	Long: `Run a formula end to end in memory, with scripted step outcomes.
+
+Each fixture (<name>.test.toml) names a formula and lists cases. A case
+scripts step outcomes (pass, fail, skip) and outputs, then asserts on the
+final state. Nothing is poured and no agents are spawned: steps are walked
+in dependency order, a wave of ready steps at a time.
+
+Fixture format:
+  formula = "mol-refinery-patrol"
+
+  [vars]                      # vars for every case
+  rig = "gastown"
+
+  [[cases]]
+  name = "tests fail"
+  [cases.vars]                # per-case vars
+  [cases.steps.run-tests]
+  outcome = "fail"            # pass (default), fail or skip
+  output = "2 failures"       # seen by when conditions as run-tests.output
+  [cases.expect]
+  status = "failed"           # done, failed or stuck
+  order = ["inbox-check", "queue-scan", "process-branch", "run-tests"]
+  before = [["queue-scan", "run-tests"]]
+  done = [...]                # also: failed, skipped, blocked
+  synthesis = [...]           # legs a convoy's synthesis received
+
+Formulas are looked up next to the fixture, then in the formula search
+paths, then among the embedded formulas. With no arguments, runs every
+fixture in .beads/formulas/. Exits non-zero if any case fails.
+
+Examples:
+  gt formula test                                   # All fixtures in .beads/formulas/
+  gt formula test refinery.test.toml -v             # Show waves for each case
+  gt formula test .beads/formulas/tests --json`,
+	RunE: runFormulaTest,
+}
+
+func init() {
+	formulaTestCmd.Flags().BoolVar(&formulaTestJSON, "json", false, "Output as JSON")
+	formulaTestCmd.Flags().BoolVarP(&formulaTestVerbose, "verbose", "v", false, "Show the waves each case ran")
+	formulaCmd.AddCommand(formulaTestCmd)
+}
+
+// FormulaTestResult is the outcome of one fixture case.
+type FormulaTestResult struct {
+	Fixture  string     `json:"fixture"`
+	Formula  string     `json:"formula"`
+	Case     string     `json:"case"`
+	Passed   bool       `json:"passed"`
+	Failures []string   `json:"failures,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	Waves    [][]string `json:"waves,omitempty"`
+}
+
+func runFormulaTest(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		args = []string{filepath.Join(".beads", "formulas")}
+	}
+	fixtures, err := findFixtures(args)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no %s fixtures found in %s", fixtureExt, strings.Join(args, ", "))
+	}
+
+	var results []FormulaTestResult
+	for _, path := range fixtures {
+		results = append(results, runFixture(path)...)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+
+	if formulaTestJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printFormulaTestResults(results)
+	}
+
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// findFixtures expands directories in paths to the fixtures they contain.
+func findFixtures(paths []string) ([]string, error) {
+	var fixtures []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			fixtures = append(fixtures, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*"+fixtureExt))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		fixtures = append(fixtures, matches...)
+	}
+	return fixtures, nil
+}
+
+// runFixture runs every case in a fixture file. Errors loading the fixture
+// or its formula are reported as a failed result.
+func runFixture(path string) []FormulaTestResult {
+	failure := func(name, err string) []FormulaTestResult {
+		return []FormulaTestResult{{Fixture: path, Formula: name, Case: "(load)", Error: err}}
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is a fixture the user asked to run
+	if err != nil {
+		return failure("", err.Error())
+	}
+	fx, err := formula.ParseFixture(data)
+	if err != nil {
+		return failure("", err.Error())
+	}
+	dirs := append([]string{filepath.Dir(path)}, formulaSearchPaths()...)
+	f, err := formula.NewResolver(dirs...).Load(fx.Formula)
+	if err != nil {
+		return failure(fx.Formula, err.Error())
+	}
+
+	var results []FormulaTestResult
+	for i := range fx.Cases {
+		c := &fx.Cases[i]
+		r := FormulaTestResult{Fixture: path, Formula: fx.Formula, Case: c.Name}
+		sim, failures, err := c.Run(f, fx.Vars)
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Failures = failures
+			r.Passed = len(failures) == 0
+			r.Waves = sim.Waves
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+func printFormulaTestResults(results []FormulaTestResult) {
+	passed := 0
+	lastFixture := ""
+	for _, r := range results {
+		if r.Fixture != lastFixture {
+			if lastFixture != "" {
+				fmt.Println()
+			}
+			fmt.Printf("%s %s\n", style.Bold.Render(r.Formula), style.Dim.Render("("+r.Fixture+")"))
+			lastFixture = r.Fixture
+		}
+		if r.Passed {
+			passed++
+			fmt.Printf("  %s %s\n", style.SuccessPrefix, r.Case)
+		} else {
+			fmt.Printf("  %s %s\n", style.ErrorPrefix, r.Case)
+		}
+		if r.Error != "" {
+			fmt.Printf("      %s\n", r.Error)
+		}
+		for _, f := range r.Failures {
+			fmt.Printf("      %s\n", f)
+		}
+		if formulaTestVerbose {
+			for i, wave := range r.Waves {
+				fmt.Printf("      %s %s\n", style.Dim.Render(fmt.Sprintf("wave %d:", i+1)), strings.Join(wave, ", "))
+			}
+		}
+	}
+
+	fmt.Println()
+	summary := fmt.Sprintf("%d passed, %d failed", passed, len(results)-passed)
+	if passed == len(results) {
+		fmt.Println(style.Success.Render(summary))
+	} else {
+		fmt.Println(style.Error.Render(summary))
+	}
+}
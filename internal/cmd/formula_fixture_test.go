@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunFixture(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// The formula lives next to the fixture and extends an embedded one
+	writeTestFile("local-shiny.formula.toml", `
+formula = "local-shiny"
+extends = "shiny"
+
+[[steps]]
+id = "lint"
+needs = ["implement"]
+
+[[steps]]
+id = "review"
+needs = ["lint"]
+`)
+	fixture := writeTestFile("local-shiny"+fixtureExt, `
+formula = "local-shiny"
+
+[vars]
+feature = "widgets"
+
+[[cases]]
+name = "lint fails"
+[cases.steps.lint]
+outcome = "fail"
+[cases.expect]
+status = "failed"
+order = ["design", "implement", "lint"]
+blocked = ["review", "test", "submit"]
+
+[[cases]]
+name = "bad expectation"
+[cases.expect]
+status = "failed"
+`)
+
+	fixtures, err := findFixtures([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) != 1 || fixtures[0] != fixture {
+		t.Fatalf("findFixtures = %v, want [%s]", fixtures, fixture)
+	}
+
+	results := runFixture(fixture)
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want 2", results)
+	}
+	if !results[0].Passed || results[0].Error != "" {
+		t.Errorf("lint fails: %+v", results[0])
+	}
+	if results[1].Passed || len(results[1].Failures) != 1 {
+		t.Errorf("bad expectation: %+v", results[1])
+	}
+}
+
+func TestRunFixture_MissingFormula(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nope"+fixtureExt)
+	if err := os.WriteFile(path, []byte("formula = \"nope\"\n[[cases]]\nname = \"x\""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results := runFixture(path)
+	if len(results) != 1 || results[0].Passed || results[0].Error == "" {
+		t.Errorf("results = %+v, want one load error", results)
+	}
+}
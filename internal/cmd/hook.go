@@ -260,8 +260,12 @@ func runHook(_ *cobra.Command, args []string) error {
 	fmt.Printf("  Use 'gt handoff' to restart with this work\n")
 	fmt.Printf("  Use 'gt hook' to see hook status\n")
 
-	// Log hook event to activity feed (non-fatal)
-	if err := events.LogFeed(events.TypeHook, agentID, events.HookPayload(beadID)); err != nil {
+	// Log hook event to activity feed (non-fatal), joining the bead's trace if it was slung
+	payload := events.HookPayload(beadID)
+	if info, err := getBeadInfo(beadID); err == nil {
+		payload = events.WithTrace(payload, storedTraceID(info))
+	}
+	if err := events.LogFeed(events.TypeHook, agentID, payload); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Warning: failed to log hook event: %v\n", style.Dim.Render("⚠"), err)
 	}
 
@@ -18,6 +18,9 @@ var (
 	mailNotify        bool
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailSendAt        string
+	mailSendIn        string
+	mailExpiresIn     string
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -35,6 +38,9 @@ var (
 	mailSearchSubject bool
 	mailSearchBody    bool
 	mailSearchArchive bool
+	mailSearchAll     bool
+	mailSearchReindex bool
+	mailSearchLimit   int
 	mailSearchJSON    bool
 
 	// Announces flags
@@ -121,6 +127,16 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Scheduling:
+  --at 09:00            Deliver at a time (today, or tomorrow if already past)
+  --at "2026-03-16 09:00"
+  --in 2h               Deliver after a delay (Go duration, or Nd for days)
+  --expires-in 4h       Drop the message if still unread this long after delivery
+
+Scheduled messages wait in the mail orchestrator's deferred queue and are
+delivered by the daemon. Messages that expire before delivery are moved to
+the dead letter queue; expired messages are removed from inboxes.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -130,7 +146,9 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send gastown/witness -s "Re-check Toast" -m "CI should be done" --in 2h
+  gt mail send mayor/ -s "Standup" -m "Morning summary" --at 09:00 --expires-in 4h`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -385,28 +403,43 @@ Examples:
 var mailSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search messages by content",
-	Long: `Search inbox for messages matching a pattern.
+	Long: `Search mail using the town's full-text index.
 
 SYNTAX:
   gt mail search <query> [flags]
 
-The query is a regular expression pattern. Search is case-insensitive by default.
+The index covers every mail bead in the town, open and archived, plus the
+.jsonl mail archives. It is built on first use and kept current as mail is
+sent. Results are ranked by relevance (subject matches count more), newest
+first for ties.
+
+QUERY:
+  Words must all appear; matching is case-insensitive on whole words.
+  A trailing * matches a prefix ("deploy*"). Field filters:
+    from:<addr>         Sender contains (e.g. from:witness)
+    to:<addr>           Recipient or CC contains
+    thread:<id>         Messages in a thread
+    priority:<level>    low, normal, high or urgent
+    before:<date>       Sent before (YYYY-MM-DD, RFC 3339, or an age: 7d, 12h)
+    after:<date>        Sent on or after
+    subject:<word>      Word appears in the subject
 
 FLAGS:
-  --from <sender>   Filter by sender address (substring match)
+  --from <sender>   Filter by sender address (same as from:)
   --subject         Only search subject lines
   --body            Only search message body
-  --archive         Include archived (closed) messages
+  --all             Search every agent's mail (overseer and mayor only)
+  --reindex         Rebuild the index before searching
+  --limit <n>       Maximum results (default 50, 0 for all)
   --json            Output as JSON
 
-By default, searches both subject and body text.
-
 Examples:
-  gt mail search "urgent"                    # Find messages with "urgent"
-  gt mail search "status.*check" --subject   # Regex in subjects only
-  gt mail search "error" --from witness      # From witness, containing "error"
-  gt mail search "handoff" --archive         # Include archived messages
-  gt mail search "" --from mayor/            # All messages from mayor`,
+  gt mail search "urgent"                          # Your mail mentioning "urgent"
+  gt mail search "status check" --subject          # Both words in the subject
+  gt mail search "error from:witness after:7d"     # Witness errors this week
+  gt mail search "merge* priority:high" --all      # Across all mailboxes
+  gt mail search "thread:thread-abc123" --all      # A whole thread
+  gt mail search "" --from mayor/                  # All messages from mayor`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailSearch,
 }
@@ -458,6 +491,9 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailSendAt, "at", "", "Deliver at a time (15:04, 2006-01-02 15:04, or RFC3339)")
+	mailSendCmd.Flags().StringVar(&mailSendIn, "in", "", "Deliver after a delay (e.g., 30m, 2h, 1d)")
+	mailSendCmd.Flags().StringVar(&mailExpiresIn, "expires-in", "", "Expire this long after delivery (e.g., 4h, 1d)")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -489,6 +525,10 @@ func init() {
 	mailSearchCmd.Flags().BoolVar(&mailSearchSubject, "subject", false, "Only search subject lines")
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
 	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
+	_ = mailSearchCmd.Flags().MarkDeprecated("archive", "archived messages are always searched")
+	mailSearchCmd.Flags().BoolVar(&mailSearchAll, "all", false, "Search every agent's mail (overseer and mayor only)")
+	mailSearchCmd.Flags().BoolVar(&mailSearchReindex, "reindex", false, "Rebuild the search index first")
+	mailSearchCmd.Flags().IntVar(&mailSearchLimit, "limit", 50, "Maximum results (0 for all)")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
 
 	// Announces flags
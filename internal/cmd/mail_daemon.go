@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -56,7 +57,8 @@ var mailDaemonLogsCmd = &cobra.Command{
 var mailDaemonQueueCmd = &cobra.Command{
 	Use:   "queue",
 	Short: "Show mail queue status",
-	Long:  `Show the current status of mail queues (inbound, outbound, dead letter).`,
+	Long:  `Show the current status of mail queues (inbound, outbound, deferred, dead letter),
+and how many requests are awaiting a reply.`,
 	RunE:  runMailDaemonQueue,
 }
 
@@ -229,11 +231,15 @@ func runMailDaemonQueue(cmd *cobra.Command, args []string) error {
 	inbound := loadQueueSize(filepath.Join(queueDir, "inbound.json"))
 	outbound := loadQueueSize(filepath.Join(queueDir, "outbound.json"))
 	deadLetter := loadQueueSize(filepath.Join(queueDir, "dead-letter.json"))
+	deferred := loadQueueSize(mail.DeferredQueuePath(townRoot))
+	requests := loadQueueSize(mail.PendingRequestsPath(townRoot))
 
 	fmt.Println(style.Bold.Render("Mail Queue Status:"))
 	fmt.Printf("  Inbound:     %s\n", formatQueueSize(inbound))
 	fmt.Printf("  Outbound:    %s\n", formatQueueSize(outbound))
+	fmt.Printf("  Deferred:    %s\n", formatQueueSize(deferred))
 	fmt.Printf("  Dead Letter: %s\n", formatQueueSize(deadLetter))
+	fmt.Printf("  Requests:    %s\n", formatQueueSize(requests))
 
 	if deadLetter > 0 {
 		fmt.Printf("\n%s Dead letter queue has %d messages requiring attention\n",
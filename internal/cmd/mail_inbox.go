@@ -260,13 +260,16 @@ func runMailDelete(cmd *cobra.Command, args []string) error {
 	// Delete all specified messages
 	deleted := 0
 	var errors []string
+	var deletedIDs []string
 	for _, msgID := range args {
 		if err := mailbox.Delete(msgID); err != nil {
 			errors = append(errors, fmt.Sprintf("%s: %v", msgID, err))
 		} else {
 			deleted++
+			deletedIDs = append(deletedIDs, msgID)
 		}
 	}
+	unindexMail(deletedIDs)
 
 	// Report results
 	if len(errors) > 0 {
@@ -526,6 +529,7 @@ func runMailClear(cmd *cobra.Command, args []string) error {
 	// Delete each message
 	deleted := 0
 	var errors []string
+	var deletedIDs []string
 	for _, msg := range messages {
 		if err := mailbox.Delete(msg.ID); err != nil {
 			// If file is already gone (race condition), ignore it and count as success
@@ -535,8 +539,10 @@ func runMailClear(cmd *cobra.Command, args []string) error {
 			errors = append(errors, fmt.Sprintf("%s: %v", msg.ID, err))
 		} else {
 			deleted++
+			deletedIDs = append(deletedIDs, msg.ID)
 		}
 	}
+	unindexMail(deletedIDs)
 
 	// Report results
 	if len(errors) > 0 {
@@ -552,3 +558,15 @@ func runMailClear(cmd *cobra.Command, args []string) error {
 		style.Bold.Render("✓"), deleted, address)
 	return nil
 }
+
+// unindexMail drops deleted messages from the town's search index
+// (best-effort). Deleting a mail bead only closes it, which archiving does
+// too, so the index has to be told.
+func unindexMail(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	if townRoot, err := findMailWorkDir(); err == nil {
+		_ = mail.RemoveFromIndex(townRoot, ids...)
+	}
+}
@@ -10,80 +10,95 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 )
 
-// runMailSearch searches for messages matching a pattern.
+// runMailSearch searches the town's mail index.
 func runMailSearch(cmd *cobra.Command, args []string) error {
-	query := args[0]
+	query, err := mail.ParseQuery(args[0])
+	if err != nil {
+		return err
+	}
+	if mailSearchFrom != "" {
+		query.From = mailSearchFrom
+	}
+	if mailSearchSubject {
+		query.Subject = append(query.Subject, query.Terms...)
+		query.Terms = nil
+	}
+	query.BodyOnly = mailSearchBody
 
-	// Determine which inbox to search
+	// Search your own inbox unless --all, which only the overseer and
+	// mayor may use: other agents have no business reading each other's mail
 	address := detectSender()
+	if mailSearchAll {
+		if id := mail.AddressToIdentity(address); id != "overseer" && id != "mayor/" {
+			return fmt.Errorf("--all is only available to the overseer and mayor (you are %s)", address)
+		}
+	} else {
+		query.Recipient = address
+	}
 
 	// Get workspace for mail operations
-	workDir, err := findMailWorkDir()
+	townRoot, err := findMailWorkDir()
 	if err != nil {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	// Get mailbox
-	router := mail.NewRouter(workDir)
-	mailbox, err := router.GetMailbox(address)
-	if err != nil {
-		return fmt.Errorf("getting mailbox: %w", err)
+	var index *mail.Index
+	if mailSearchReindex {
+		index, err = mail.RebuildIndex(townRoot)
+	} else {
+		index, err = mail.OpenIndex(townRoot)
 	}
-
-	// Build search options
-	opts := mail.SearchOptions{
-		Query:       query,
-		FromFilter:  mailSearchFrom,
-		SubjectOnly: mailSearchSubject,
-		BodyOnly:    mailSearchBody,
-	}
-
-	// Execute search
-	messages, err := mailbox.Search(opts)
 	if err != nil {
-		return fmt.Errorf("searching messages: %w", err)
+		return fmt.Errorf("opening mail index: %w", err)
 	}
 
+	results := index.Search(query, mailSearchLimit)
+
 	// JSON output
 	if mailSearchJSON {
+		if results == nil {
+			results = []mail.SearchResult{}
+		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(messages)
+		return enc.Encode(results)
 	}
 
 	// Human-readable output
+	scope := address
+	if mailSearchAll {
+		scope = "all mailboxes"
+	}
 	fmt.Printf("%s Search results for %s: %d message(s)\n\n",
-		style.Bold.Render("🔍"), address, len(messages))
+		style.Bold.Render("🔍"), scope, len(results))
 
-	if len(messages) == 0 {
-		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
+	if len(results) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("(no matches in %d indexed messages)", index.Len())))
 		return nil
 	}
 
-	for _, msg := range messages {
-		readMarker := "●"
-		if msg.Read {
-			readMarker = "○"
-		}
+	for _, r := range results {
 		typeMarker := ""
-		if msg.Type != "" && msg.Type != mail.TypeNotification {
-			typeMarker = fmt.Sprintf(" [%s]", msg.Type)
+		if r.Type != "" && r.Type != mail.TypeNotification {
+			typeMarker = fmt.Sprintf(" [%s]", r.Type)
 		}
 		priorityMarker := ""
-		if msg.Priority == mail.PriorityHigh || msg.Priority == mail.PriorityUrgent {
+		if r.Priority == mail.PriorityHigh || r.Priority == mail.PriorityUrgent {
 			priorityMarker = " " + style.Bold.Render("!")
 		}
-		wispMarker := ""
-		if msg.Wisp {
-			wispMarker = " " + style.Dim.Render("(wisp)")
+		archiveMarker := ""
+		if r.Archive != "" {
+			archiveMarker = " " + style.Dim.Render("(archive)")
 		}
 
-		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
-		fmt.Printf("    %s from %s\n",
-			style.Dim.Render(msg.ID),
-			msg.From)
+		fmt.Printf("  %s%s%s%s\n", r.Subject, typeMarker, priorityMarker, archiveMarker)
+		if mailSearchAll {
+			fmt.Printf("    %s from %s to %s\n", style.Dim.Render(r.ID), r.From, r.To)
+		} else {
+			fmt.Printf("    %s from %s\n", style.Dim.Render(r.ID), r.From)
+		}
 		fmt.Printf("    %s\n",
-			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
+			style.Dim.Render(r.Timestamp.Format("2006-01-02 15:04")))
 	}
 
 	return nil
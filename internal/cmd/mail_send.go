@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
@@ -49,6 +51,12 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("address required (or use --self)")
 	}
 
+	// Resolve scheduling flags before anything is sent
+	deliverAt, expiresAt, err := parseMailSchedule(mailSendAt, mailSendIn, mailExpiresIn, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// All mail uses town beads (two-level architecture)
 	workDir, err := findMailWorkDir()
 	if err != nil {
@@ -88,6 +96,10 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Set delivery schedule
+	msg.DeliverAt = deliverAt
+	msg.ExpiresAt = expiresAt
+
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
 		msg.ReplyTo = mailReplyTo
@@ -123,8 +135,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("sending message: %w", err)
 		}
 		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
-		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
-		fmt.Printf("  Subject: %s\n", mailSubject)
+		printMailSent(msg, to, workDir)
 		return nil
 	}
 
@@ -164,8 +175,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Log mail event to activity feed
 	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
 
-	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
-	fmt.Printf("  Subject: %s\n", mailSubject)
+	printMailSent(msg, to, workDir)
 
 	// Show resolved recipients if fan-out occurred
 	if len(recipientAddrs) > 1 || (len(recipientAddrs) == 1 && recipientAddrs[0] != to) {
@@ -182,6 +192,98 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printMailSent reports a sent or scheduled message.
+func printMailSent(msg *mail.Message, to, townRoot string) {
+	if msg.DeliverAt == nil || !msg.DeliverAt.After(time.Now()) {
+		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
+		fmt.Printf("  Subject: %s\n", msg.Subject)
+	} else {
+		fmt.Printf("%s Message to %s scheduled for %s\n", style.Bold.Render("✓"), to,
+			msg.DeliverAt.Format("2006-01-02 15:04 MST"))
+		fmt.Printf("  Subject: %s\n", msg.Subject)
+		warnIfMailOrchestratorDown(townRoot)
+	}
+	if msg.ExpiresAt != nil {
+		fmt.Printf("  Expires: %s\n", msg.ExpiresAt.Format("2006-01-02 15:04 MST"))
+	}
+}
+
+// warnIfMailOrchestratorDown warns when nothing will release deferred mail.
+func warnIfMailOrchestratorDown(townRoot string) {
+	if running, _, err := daemon.IsRunning(townRoot); err == nil && !running {
+		fmt.Printf("  %s Daemon is not running; start it with %s to deliver scheduled mail\n",
+			style.Bold.Render("!"), style.Dim.Render("gt daemon start"))
+		return
+	}
+	if !daemon.IsPatrolEnabled(daemon.LoadPatrolConfig(townRoot), "mail-orchestrator") {
+		fmt.Printf("  %s Mail orchestrator is disabled; enable it with %s to deliver scheduled mail\n",
+			style.Bold.Render("!"), style.Dim.Render("gt mail daemon start"))
+	}
+}
+
+// parseMailSchedule resolves --at, --in and --expires-in into delivery and
+// expiry times. --expires-in counts from delivery, or from now if unscheduled.
+func parseMailSchedule(at, in, expiresIn string, now time.Time) (deliverAt, expiresAt *time.Time, err error) {
+	if at != "" && in != "" {
+		return nil, nil, fmt.Errorf("--at and --in are mutually exclusive")
+	}
+
+	switch {
+	case at != "":
+		t, err := parseDeliveryTime(at, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !t.After(now) {
+			return nil, nil, fmt.Errorf("--at %s is in the past", at)
+		}
+		deliverAt = &t
+	case in != "":
+		d, err := parseDuration(in)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid --in %q: use a positive duration like 30m, 2h or 1d", in)
+		}
+		t := now.Add(d)
+		deliverAt = &t
+	}
+
+	if expiresIn != "" {
+		d, err := parseDuration(expiresIn)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid --expires-in %q: use a positive duration like 30m, 2h or 1d", expiresIn)
+		}
+		from := now
+		if deliverAt != nil {
+			from = *deliverAt
+		}
+		t := from.Add(d)
+		expiresAt = &t
+	}
+
+	return deliverAt, expiresAt, nil
+}
+
+// parseDeliveryTime parses an --at value. A bare clock time means its next
+// occurrence: today, or tomorrow if it has already passed.
+func parseDeliveryTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: use 15:04, \"2006-01-02 15:04\", or RFC3339", s)
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
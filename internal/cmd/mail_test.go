@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
@@ -296,3 +297,69 @@ func TestAnnounceMessageParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMailSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		at, in, exp string
+		wantDeliver time.Time
+		wantExpires time.Time
+		wantErr     bool
+	}{
+		{name: "unscheduled"},
+		{name: "clock later today", at: "16:00", wantDeliver: time.Date(2026, 3, 15, 16, 0, 0, 0, time.UTC)},
+		{name: "clock already passed", at: "09:00", wantDeliver: time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{name: "date and time", at: "2026-03-20 08:15", wantDeliver: time.Date(2026, 3, 20, 8, 15, 0, 0, time.UTC)},
+		{name: "rfc3339", at: "2026-03-20T08:15:00Z", wantDeliver: time.Date(2026, 3, 20, 8, 15, 0, 0, time.UTC)},
+		{name: "delay", in: "2h", wantDeliver: now.Add(2 * time.Hour)},
+		{name: "delay in days", in: "1d", wantDeliver: now.Add(24 * time.Hour)},
+		{name: "expiry counts from delivery", in: "2h", exp: "4h", wantDeliver: now.Add(2 * time.Hour), wantExpires: now.Add(6 * time.Hour)},
+		{name: "expiry without schedule", exp: "30m", wantExpires: now.Add(30 * time.Minute)},
+		{name: "at and in", at: "16:00", in: "2h", wantErr: true},
+		{name: "past date", at: "2026-03-01 09:00", wantErr: true},
+		{name: "bad clock", at: "9am", wantErr: true},
+		{name: "negative delay", in: "-1h", wantErr: true},
+		{name: "bad expiry", exp: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliverAt, expiresAt, err := parseMailSchedule(tt.at, tt.in, tt.exp, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMailSchedule: %v", err)
+			}
+			if got := derefTime(deliverAt); !got.Equal(tt.wantDeliver) {
+				t.Errorf("deliverAt = %v, want %v", got, tt.wantDeliver)
+			}
+			if got := derefTime(expiresAt); !got.Equal(tt.wantExpires) {
+				t.Errorf("expiresAt = %v, want %v", got, tt.wantExpires)
+			}
+		})
+	}
+}
+
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func TestMailSearchAllRestricted(t *testing.T) {
+	old := mailSearchAll
+	mailSearchAll = true
+	defer func() { mailSearchAll = old }()
+
+	t.Setenv("GT_ROLE", "gastown/polecats/nux")
+	err := runMailSearch(nil, []string{"deploy"})
+	if err == nil || !strings.Contains(err.Error(), "overseer and mayor") {
+		t.Errorf("polecat --all search: err = %v, want refusal", err)
+	}
+}
@@ -137,6 +137,8 @@ func runMailReply(cmd *cobra.Command, args []string) error {
 		Priority: mail.PriorityNormal,
 		ReplyTo:  msgID,
 		ThreadID: original.ThreadID,
+
+		CorrelationID: original.CorrelationID, // Answers the original if it was a request
 	}
 
 	// If original has no thread ID, create one
@@ -9,6 +9,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -183,6 +184,18 @@ func runNudge(cmd *cobra.Command, args []string) error {
 
 		var sessionName string
 
+		// Rigs on remote machines are reached through the rig's connection
+		var remoteMgr *polecat.SessionManager
+		if mgr, r, err := getSessionManager(rigName); err == nil && r.IsRemote() {
+			remoteMgr = mgr
+		}
+		hasSession := t.HasSession
+		nudgeSession := t.NudgeSession
+		if remoteMgr != nil {
+			hasSession = remoteMgr.HasSession
+			nudgeSession = remoteMgr.NudgeSession
+		}
+
 		// Check if this is a crew address (polecatName starts with "crew/")
 		if strings.HasPrefix(polecatName, "crew/") {
 			// Extract crew name and use crew session naming
@@ -193,7 +206,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 			// Try crew first (matches mail system's addressToSessionIDs pattern),
 			// then fall back to polecat.
 			crewSession := crewSessionName(rigName, polecatName)
-			if exists, _ := t.HasSession(crewSession); exists {
+			if exists, _ := hasSession(crewSession); exists {
 				sessionName = crewSession
 			} else {
 				mgr, _, err := getSessionManager(rigName)
@@ -205,7 +218,7 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		}
 
 		// Send nudge using the reliable NudgeSession
-		if err := t.NudgeSession(sessionName, message); err != nil {
+		if err := nudgeSession(sessionName, message); err != nil {
 			return fmt.Errorf("nudging session: %w", err)
 		}
 
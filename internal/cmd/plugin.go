@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -22,6 +23,7 @@ var (
 	pluginShowJSON    bool
 	pluginRunForce    bool
 	pluginRunDryRun   bool
+	pluginRunExec     bool
 	pluginHistoryJSON bool
 	pluginHistoryLimit int
 	pluginStatusJSON  bool
@@ -43,9 +45,12 @@ GATE TYPES:
   cooldown    Run if enough time has passed (e.g., 1h)
   cron        Run on a schedule (e.g., "0 9 * * *")
   condition   Run if a check command returns exit 0
-  event       Run on events (e.g., startup)
+  event       Run on events (e.g., startup, merged)
   manual      Never auto-run, trigger explicitly
 
+The daemon's plugin scheduler evaluates gates every minute and dispatches
+due plugins to an idle dog (patrols.plugin_scheduler in mayor/daemon.json).
+
 Examples:
   gt plugin list                    # List all discovered plugins
   gt plugin show <name>             # Show plugin details
@@ -107,10 +112,21 @@ var pluginRunCmd = &cobra.Command{
 By default, checks if the gate would allow execution and informs you
 if it wouldn't. Use --force to bypass gate checks.
 
+Without --exec, the plugin's instructions are printed for you (or the
+calling agent) to carry out. With --exec, the plugin is executed here:
+its execution.run command if set, otherwise its instructions are handed
+to the headless agent command set in plugins.agent_command of
+settings/config.json (there is no default). The run is killed after
+execution.timeout (default 10m), each process is limited to
+execution.max_memory (default 4GiB) and execution.max_cpu of CPU time
+(default the timeout), its output is logged under logs/plugins/<name>/, and
+a failure is escalated at execution.severity when notify_on_failure is set.
+
 Examples:
   gt plugin run rebuild-gt              # Run if gate allows
   gt plugin run rebuild-gt --force      # Bypass gate check
-  gt plugin run rebuild-gt --dry-run    # Show what would happen`,
+  gt plugin run rebuild-gt --dry-run    # Show what would happen
+  gt plugin run rebuild-gt --exec       # Execute and record the result`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPluginRun,
 }
@@ -143,6 +159,7 @@ func init() {
 	// Run subcommand flags
 	pluginRunCmd.Flags().BoolVar(&pluginRunForce, "force", false, "Bypass gate check")
 	pluginRunCmd.Flags().BoolVar(&pluginRunDryRun, "dry-run", false, "Show what would happen without executing")
+	pluginRunCmd.Flags().BoolVar(&pluginRunExec, "exec", false, "Execute the plugin with a timeout and record the result")
 
 	// History subcommand flags
 	pluginHistoryCmd.Flags().BoolVar(&pluginHistoryJSON, "json", false, "Output as JSON")
@@ -365,6 +382,12 @@ func outputPluginShowText(p *plugin.Plugin) error {
 		if p.Execution.Timeout != "" {
 			fmt.Printf("  Timeout: %s\n", p.Execution.Timeout)
 		}
+		if p.Execution.MaxMemory != "" {
+			fmt.Printf("  Max memory: %s\n", p.Execution.MaxMemory)
+		}
+		if p.Execution.MaxCPU != "" {
+			fmt.Printf("  Max CPU: %s\n", p.Execution.MaxCPU)
+		}
 		fmt.Printf("  Notify on failure: %v\n", p.Execution.NotifyOnFailure)
 		if p.Execution.Severity != "" {
 			fmt.Printf("  Severity: %s\n", p.Execution.Severity)
@@ -443,6 +466,10 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if pluginRunExec {
+		return execPlugin(townRoot, p)
+	}
+
 	// Execute the plugin
 	// For manual runs, we print the instructions for the agent/user to execute
 	// Automatic execution via dogs is handled by gt-n08ix.2
@@ -471,6 +498,34 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// execPlugin runs a plugin through plugin.Runner and reports the result.
+func execPlugin(townRoot string, p *plugin.Plugin) error {
+	fmt.Printf("%s Executing plugin: %s\n", style.Success.Render("●"), p.Name)
+
+	run, err := plugin.NewRunner(townRoot).Run(context.Background(), p)
+	if run == nil {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if run.Result == string(plugin.ResultSuccess) {
+		fmt.Printf("%s %s %s\n", style.Success.Render("✓"), p.Name, run.Message)
+	} else {
+		fmt.Printf("%s %s %s\n", style.Error.Render("✗"), p.Name, run.Message)
+	}
+	fmt.Printf("  Log: %s\n", run.LogPath)
+	if run.RunID != "" {
+		fmt.Printf("  Recorded run: %s\n", style.Dim.Render(run.RunID))
+	}
+
+	if run.Result != string(plugin.ResultSuccess) {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
 func runPluginHistory(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
@@ -516,6 +571,9 @@ func runPluginHistory(cmd *cobra.Command, args []string) error {
 		} else if run.Result == plugin.ResultSkipped {
 			resultStyle = style.Dim
 			resultIcon = "○"
+		} else if run.Result == plugin.ResultDispatched {
+			resultStyle = style.Dim
+			resultIcon = "→"
 		}
 
 		fmt.Printf("  %s %s  %s\n",
@@ -571,29 +629,21 @@ func runPluginStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Check gate status for cooldown gates
+	// Check gate status for time-based gates (condition and event gates
+	// depend on checks and events only the daemon's scheduler sees)
 	gateOpen := true
 	gateReason := ""
 	var nextRunTime time.Time
-	if p.Gate != nil && p.Gate.Type == plugin.GateCooldown {
-		duration := p.Gate.Duration
-		if duration == "" {
-			duration = "1h"
-		}
-		count, err := recorder.CountRunsSince(p.Name, duration)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: checking gate status: %v\n", err)
-		} else if count > 0 {
-			gateOpen = false
-			gateReason = fmt.Sprintf("ran %d time(s) within %s cooldown", count, duration)
-			if lastRun != nil {
-				// Estimate next run time based on cooldown
-				parsedDuration, parseErr := time.ParseDuration(duration)
-				if parseErr == nil {
-					nextRunTime = lastRun.CreatedAt.Add(parsedDuration)
-				}
-			}
+	if p.Gate != nil && (p.Gate.Type == plugin.GateCooldown || p.Gate.Type == plugin.GateCron) {
+		var lastRunTime time.Time
+		if lastRun != nil {
+			lastRunTime = lastRun.CreatedAt
 		}
+		now := time.Now()
+		status := p.Gate.Evaluate(p.Path, plugin.GateInput{Now: now, LastRun: lastRunTime, Since: now})
+		gateOpen = status.Open
+		gateReason = status.Reason
+		nextRunTime = status.Next
 	}
 
 	if pluginStatusJSON {
@@ -712,9 +762,10 @@ func outputPluginStatusText(p *plugin.Plugin, lastRun *plugin.PluginRunBead, rec
 			fmt.Printf("  Skipped: %d\n", skippedCount)
 		}
 		
-		// Calculate success rate
-		if len(recentRuns) > 0 {
-			successRate := float64(successCount) / float64(len(recentRuns)) * 100
+		// Calculate success rate over finished runs (scheduler dispatch
+		// records are followed by the dog's own result)
+		if finished := successCount + failureCount + skippedCount; finished > 0 {
+			successRate := float64(successCount) / float64(finished) * 100
 			fmt.Printf("  Success rate: %.1f%%\n", successRate)
 		}
 	}
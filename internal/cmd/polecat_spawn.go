@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -23,7 +24,9 @@ type SpawnedPolecatInfo struct {
 	PolecatName string // Polecat name (e.g., "Toast")
 	ClonePath   string // Path to polecat's git worktree
 	SessionName string // Tmux session name (e.g., "gt-gastown-p-Toast")
-	Pane        string // Tmux pane ID
+	Pane        string // Tmux pane ID (empty for remote rigs)
+	Machine     string // Machine hosting the session (empty for local rigs)
+	RigPath     string // Local rig directory
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -31,6 +34,21 @@ func (s *SpawnedPolecatInfo) AgentID() string {
 	return fmt.Sprintf("%s/polecats/%s", s.RigName, s.PolecatName)
 }
 
+// IsRemote returns true if the polecat runs on another machine.
+func (s *SpawnedPolecatInfo) IsRemote() bool {
+	return s.Machine != ""
+}
+
+// HookWorkDir returns the local directory to run bd commands from.
+// Local polecats use their worktree (for redirect-based routing); remote
+// worktrees aren't reachable locally, so the rig directory is used instead.
+func (s *SpawnedPolecatInfo) HookWorkDir() string {
+	if s.IsRemote() {
+		return s.RigPath
+	}
+	return s.ClonePath
+}
+
 // SlingSpawnOptions contains options for spawning a polecat via sling.
 type SlingSpawnOptions struct {
 	Force    bool   // Force spawn even if polecat has uncommitted work
@@ -38,6 +56,7 @@ type SlingSpawnOptions struct {
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent    string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
+	TraceID  string // Lifecycle trace of HookBead, recorded on the spawn event
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -86,8 +105,8 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 
 	if err == nil {
 		// Stale state: polecat exists despite fresh name allocation - repair it
-		// Check for uncommitted work first
-		if !opts.Force {
+		// Check for uncommitted work first (remote rigs check during repair)
+		if !opts.Force && !r.IsRemote() {
 			pGit := git.NewGit(existingPolecat.ClonePath)
 			workStatus, checkErr := pGit.CheckUncommittedWork()
 			if checkErr == nil && !workStatus.Clean() {
@@ -136,7 +155,19 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			RuntimeConfigDir: claudeConfigDir,
 		}
 		if opts.Agent != "" {
-			cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, r.Path, "", opts.Agent)
+			var cmd string
+			if r.IsRemote() {
+				// Resolve the agent locally but point GT_ROOT at the remote town
+				envVars := config.AgentEnv(config.AgentEnvConfig{
+					Role:      "polecat",
+					Rig:       rigName,
+					AgentName: polecatName,
+					TownRoot:  filepath.Dir(r.RemotePath),
+				})
+				cmd, err = config.BuildStartupCommandWithAgentOverride(envVars, r.Path, "", opts.Agent)
+			} else {
+				cmd, err = config.BuildPolecatStartupCommandWithAgentOverride(rigName, polecatName, r.Path, "", opts.Agent)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -147,17 +178,20 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		}
 	}
 
-	// Get session name and pane
+	// Get session name and pane (remote panes aren't addressable locally)
 	sessionName := polecatSessMgr.SessionName(polecatName)
-	pane, err := getSessionPane(sessionName)
-	if err != nil {
-		return nil, fmt.Errorf("getting pane for %s: %w", sessionName, err)
+	var pane string
+	if !r.IsRemote() {
+		pane, err = getSessionPane(sessionName)
+		if err != nil {
+			return nil, fmt.Errorf("getting pane for %s: %w", sessionName, err)
+		}
 	}
 
 	fmt.Printf("%s Polecat %s spawned\n", style.Bold.Render("✓"), polecatName)
 
 	// Log spawn event to activity feed
-	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
+	_ = events.LogFeed(events.TypeSpawn, "gt", events.WithTrace(events.SpawnPayload(rigName, polecatName), opts.TraceID))
 
 	return &SpawnedPolecatInfo{
 		RigName:     rigName,
@@ -165,9 +199,24 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		ClonePath:   polecatObj.ClonePath,
 		SessionName: sessionName,
 		Pane:        pane,
+		Machine:     r.Machine,
+		RigPath:     r.Path,
 	}, nil
 }
 
+// nudgeRemoteSpawn delivers a prompt to a polecat session on a remote
+// machine through the rig's connection.
+func nudgeRemoteSpawn(info *SpawnedPolecatInfo, prompt string) error {
+	if os.Getenv("GT_TEST_NO_NUDGE") != "" {
+		return nil
+	}
+	mgr, _, err := getSessionManager(info.RigName)
+	if err != nil {
+		return err
+	}
+	return mgr.NudgeSession(info.SessionName, prompt)
+}
+
 // IsRigName checks if a target string is a rig name (not a role or path).
 // Returns the rig name and true if it's a valid rig.
 func IsRigName(target string) (string, bool) {
@@ -36,6 +36,10 @@ Aggregates ready issues from:
 Ready items have no blockers and can be worked immediately.
 Results are sorted by priority (highest first) then by source.
 
+Beads waiting on beads in other rigs (see gt bead dep) are listed after the
+ready work, with each cross-rig blocker's rig and status. The daemon unblocks
+them when their blockers close.
+
 Examples:
   gt ready              # Show all ready work
   gt ready --json       # Output as JSON
@@ -58,9 +62,10 @@ type ReadySource struct {
 
 // ReadyResult is the aggregated result of gt ready.
 type ReadyResult struct {
-	Sources  []ReadySource `json:"sources"`
-	Summary  ReadySummary  `json:"summary"`
-	TownRoot string        `json:"town_root,omitempty"`
+	Sources         []ReadySource           `json:"sources"`
+	Summary         ReadySummary            `json:"summary"`
+	CrossRigBlocked []beads.CrossRigBlocked `json:"cross_rig_blocked,omitempty"`
+	TownRoot        string                  `json:"town_root,omitempty"`
 }
 
 // ReadySummary provides counts for the ready report.
@@ -169,6 +174,22 @@ func runReady(cmd *cobra.Command, args []string) error {
 		}(r)
 	}
 
+	// Find beads waiting on other rigs
+	var crossRig []beads.CrossRigBlocked
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		blocked, err := beads.FindCrossRigBlocked(townRoot)
+		if err != nil {
+			return // Cross-rig blockers are informational
+		}
+		for _, b := range blocked {
+			if readyRig == "" || b.Rig == readyRig {
+				crossRig = append(crossRig, b)
+			}
+		}
+	}()
+
 	wg.Wait()
 
 	// Sort sources: town first, then rigs alphabetically
@@ -214,9 +235,10 @@ func runReady(cmd *cobra.Command, args []string) error {
 	}
 
 	result := ReadyResult{
-		Sources:  sources,
-		Summary:  summary,
-		TownRoot: townRoot,
+		Sources:         sources,
+		Summary:         summary,
+		CrossRigBlocked: crossRig,
+		TownRoot:        townRoot,
 	}
 
 	// Output
@@ -232,6 +254,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 func printReadyHuman(result ReadyResult) error {
 	if result.Summary.Total == 0 {
 		fmt.Println("No ready work across town.")
+		printCrossRigBlocked(result.CrossRigBlocked)
 		return nil
 	}
 
@@ -299,9 +322,41 @@ func printReadyHuman(result ReadyResult) error {
 		fmt.Printf("Total: %d items ready\n", result.Summary.Total)
 	}
 
+	printCrossRigBlocked(result.CrossRigBlocked)
 	return nil
 }
 
+// printCrossRigBlocked lists beads waiting on beads in other rigs.
+func printCrossRigBlocked(blocked []beads.CrossRigBlocked) {
+	if len(blocked) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s Waiting on other rigs (%d items):\n", style.Bold.Render("🔗"), len(blocked))
+	for _, b := range blocked {
+		title := b.Issue.Title
+		if len(title) > 60 {
+			title = title[:57] + "..."
+		}
+		fmt.Printf("  %s %s %s\n", style.Dim.Render(b.Rig+"/"), style.Dim.Render(b.Issue.ID), title)
+		for _, blocker := range b.Blockers {
+			rig := blocker.Rig
+			if rig == "" {
+				rig = "unrouted"
+			}
+			line := fmt.Sprintf("%s (%s) %s", blocker.ID, rig, blocker.Status)
+			if blocker.Title != "" {
+				line += " - " + blocker.Title
+			}
+			if blocker.Closed() {
+				fmt.Printf("      %s %s %s\n", style.Success.Render("✓"), line, style.Dim.Render("(unblocks on the daemon's next sweep)"))
+			} else {
+				fmt.Printf("      %s %s\n", style.Warning.Render("⏳"), line)
+			}
+		}
+	}
+}
+
 // getFormulaNames reads the formulas directory and returns a set of formula names.
 // Formula names are derived from filenames by removing the ".formula.toml" suffix.
 func getFormulaNames(beadsPath string) map[string]bool {
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 
@@ -217,6 +219,43 @@ Examples:
 
 var refineryBlockedJSON bool
 
+var refineryProcessCmd = &cobra.Command{
+	Use:   "process [rig]",
+	Short: "Merge all ready MRs, verifying up to max_concurrent in parallel",
+	Long: `Claim and process every ready merge request.
+
+Up to merge_queue.max_concurrent MRs are verified at once, each squash merged
+and tested in its own scratch worktree under refinery/scratch/. Merges to the
+target branch remain serialized; an MR whose base moved while it waited is
+re-verified against the new tip before it lands.
+
+Merged MRs are closed along with their source issues. Failed MRs are released
+back to the queue and the witness is notified, as with manual processing.
+
+With --batch N (or merge_queue.batch_size > 1), the next N ready MRs in queue
+order are instead merged as one speculative train: they are stacked on the
+target, tested once, and fast-forwarded together. A failing train is bisected
+so only the MR that broke it is sent back.
+
+The refinery patrol runs this with --if-enabled each cycle: when neither
+max_concurrent nor batch_size is above 1 it does nothing, and the patrol
+merges MRs one at a time itself.
+
+Examples:
+  gt refinery process
+  gt refinery process gastown --max-concurrent 4
+  gt refinery process --batch 8
+  gt refinery process gastown --if-enabled`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryProcess,
+}
+
+var (
+	refineryProcessMaxConcurrent int
+	refineryProcessBatch         int
+	refineryProcessIfEnabled     bool
+)
+
 func init() {
 	// Start flags
 	refineryStartCmd.Flags().BoolVar(&refineryForeground, "foreground", false, "Run in foreground (default: background)")
@@ -243,6 +282,11 @@ func init() {
 	// Blocked flags
 	refineryBlockedCmd.Flags().BoolVar(&refineryBlockedJSON, "json", false, "Output as JSON")
 
+	// Process flags
+	refineryProcessCmd.Flags().IntVar(&refineryProcessMaxConcurrent, "max-concurrent", 0, "Override merge_queue.max_concurrent for this run")
+	refineryProcessCmd.Flags().IntVar(&refineryProcessBatch, "batch", 0, "Merge the next N ready MRs as one speculative train (overrides merge_queue.batch_size)")
+	refineryProcessCmd.Flags().BoolVar(&refineryProcessIfEnabled, "if-enabled", false, "Do nothing unless max_concurrent or batch_size is above 1")
+
 	// Add subcommands
 	refineryCmd.AddCommand(refineryStartCmd)
 	refineryCmd.AddCommand(refineryStopCmd)
@@ -255,6 +299,7 @@ func init() {
 	refineryCmd.AddCommand(refineryUnclaimedCmd)
 	refineryCmd.AddCommand(refineryReadyCmd)
 	refineryCmd.AddCommand(refineryBlockedCmd)
+	refineryCmd.AddCommand(refineryProcessCmd)
 
 	rootCmd.AddCommand(refineryCmd)
 }
@@ -758,3 +803,100 @@ func runRefineryBlocked(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runRefineryProcess(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+
+	mgr, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if refineryProcessIfEnabled && !parallelMergeEnabled(eng.Config()) {
+		fmt.Printf("%s Parallel merging is disabled for '%s' (max_concurrent=%d, batch_size=%d); merge MRs one at a time\n",
+			style.Dim.Render("○"), rigName, eng.Config().MaxConcurrent, eng.Config().BatchSize)
+		return nil
+	}
+	if refineryProcessBatch > 1 || (refineryProcessBatch == 0 && eng.Config().BatchSize > 1) {
+		return runRefineryTrain(mgr, rigName, refineryProcessBatch)
+	}
+	if refineryProcessMaxConcurrent > 0 {
+		eng.Config().MaxConcurrent = refineryProcessMaxConcurrent
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return fmt.Errorf("listing ready MRs: %w", err)
+	}
+	if len(ready) == 0 {
+		fmt.Printf("%s No ready MRs for '%s'\n", style.Dim.Render("○"), rigName)
+		return nil
+	}
+
+	// Claim before processing so parallel workers don't double-process
+	workerID := getWorkerID()
+	var claimed []*refinery.MRInfo
+	for _, mr := range ready {
+		if err := eng.ClaimMR(mr.ID, workerID); err != nil {
+			fmt.Printf("%s Could not claim %s: %v\n", style.Dim.Render("Warning:"), mr.ID, err)
+			continue
+		}
+		claimed = append(claimed, mr)
+	}
+
+	results := eng.ProcessMRInfos(context.Background(), claimed)
+
+	var merged int
+	for i, mr := range claimed {
+		if results[i].Success {
+			eng.HandleMRInfoSuccess(mr, results[i])
+			merged++
+			continue
+		}
+		eng.HandleMRInfoFailure(mr, results[i])
+		if err := eng.ReleaseMR(mr.ID); err != nil {
+			fmt.Printf("%s Could not release %s: %v\n", style.Dim.Render("Warning:"), mr.ID, err)
+		}
+	}
+
+	fmt.Printf("%s Merged %d/%d MRs for '%s'\n", style.Bold.Render("✓"), merged, len(claimed), rigName)
+	return nil
+}
+
+// parallelMergeEnabled reports whether gt refinery process should take over
+// from the patrol's one-at-a-time merging, counting flag overrides.
+func parallelMergeEnabled(cfg *refinery.MergeQueueConfig) bool {
+	return refineryProcessBatch > 1 || refineryProcessMaxConcurrent > 1 ||
+		cfg.BatchSize > 1 || cfg.MaxConcurrent > 1
+}
+
+// runRefineryTrain merges the next batch of ready MRs as a speculative train.
+func runRefineryTrain(mgr *refinery.Manager, rigName string, batchSize int) error {
+	batch, results, err := mgr.ProcessBatch(context.Background(), batchSize, getWorkerID())
+	if err != nil {
+		if errors.Is(err, refinery.ErrNoQueue) {
+			fmt.Printf("%s No ready MRs for '%s'\n", style.Dim.Render("○"), rigName)
+			return nil
+		}
+		return fmt.Errorf("processing merge train: %w", err)
+	}
+
+	var merged int
+	for i, mr := range batch {
+		if results[i].Success {
+			merged++
+			continue
+		}
+		fmt.Printf("  %s %s: %s\n", style.Dim.Render("✗"), mr.ID, results[i].Error)
+	}
+
+	fmt.Printf("%s Merged %d/%d MRs for '%s' in one train\n", style.Bold.Render("✓"), merged, len(batch), rigName)
+	return nil
+}
@@ -49,6 +49,7 @@ var beadsExemptCommands = map[string]bool{
 	"handoff":    true,
 	"costs":      true,
 	"feed":       true,
+	"trace":      true,
 	"rig":        true,
 	"config":     true,
 	"install":    true,
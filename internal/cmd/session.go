@@ -12,6 +12,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -216,6 +217,11 @@ func init() {
 // parseAddress parses "rig/polecat" format.
 // If no "/" is present, attempts to infer rig from current directory.
 func parseAddress(addr string) (rigName, polecatName string, err error) {
+	// machine:rig/polecat - the machine must match the rig's configured machine
+	if colon := strings.Index(addr, ":"); colon >= 0 && colon < strings.Index(addr, "/") {
+		return parseMachineAddress(addr)
+	}
+
 	parts := strings.SplitN(addr, "/", 2)
 	if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
 		return parts[0], parts[1], nil
@@ -235,6 +241,32 @@ func parseAddress(addr string) (rigName, polecatName string, err error) {
 	return "", "", fmt.Errorf("invalid address format: expected 'rig/polecat', got '%s'", addr)
 }
 
+// parseMachineAddress parses a machine-qualified address (machine:rig/polecat)
+// and checks that the rig is registered on that machine in rigs.json.
+func parseMachineAddress(addr string) (rigName, polecatName string, err error) {
+	parsed, err := connection.ParseAddress(addr)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if parsed.Polecat == "" {
+		return "", "", fmt.Errorf("invalid address format: expected 'machine:rig/polecat', got '%s'", addr)
+	}
+
+	_, r, err := getRig(parsed.Rig)
+	if err != nil {
+		return "", "", err
+	}
+	rigMachine := r.Machine
+	if rigMachine == "" {
+		rigMachine = "local"
+	}
+	if parsed.Machine != rigMachine {
+		return "", "", fmt.Errorf("rig '%s' is on machine '%s', not '%s'", parsed.Rig, rigMachine, parsed.Machine)
+	}
+
+	return parsed.Rig, parsed.Polecat, nil
+}
+
 // getSessionManager creates a session manager for the given rig.
 func getSessionManager(rigName string) (*polecat.SessionManager, *rig.Rig, error) {
 	_, r, err := getRig(rigName)
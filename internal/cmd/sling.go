@@ -76,7 +76,12 @@ Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
 
   When multiple beads are provided with a rig target, each bead gets its own
-  polecat. This parallelizes work dispatch without running gt sling N times.`,
+  polecat. This parallelizes work dispatch without running gt sling N times.
+
+Budgets:
+  Sling refuses to dispatch when a hard spend cap in the "budget" section of
+  town or rig settings covers the target's rig, role or convoy. The daemon
+  evaluates caps; see 'gt costs'.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSling,
 }
@@ -185,11 +190,24 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Refuse to dispatch past a hard budget cap
+	slingTarget := ""
+	if len(args) > 1 {
+		slingTarget = args[1]
+	}
+	if err := checkSlingBudget(townRoot, beadID, slingTarget); err != nil {
+		return err
+	}
+
+	// Start (or continue) the bead's lifecycle trace; see gt trace
+	traceID := slingTraceID(beadID)
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
-	var hookWorkDir string     // Working directory for running bd hook commands
-	var hookSetAtomically bool // True if hook was set during polecat spawn (skip redundant update)
+	var hookWorkDir string              // Working directory for running bd hook commands
+	var hookSetAtomically bool          // True if hook was set during polecat spawn (skip redundant update)
+	var remoteSpawn *SpawnedPolecatInfo // Set when the spawned polecat runs on a remote machine
 
 	if len(args) > 1 {
 		target := args[1]
@@ -238,6 +256,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 					Create:   slingCreate,
 					HookBead: beadID, // Set atomically at spawn time
 					Agent:    slingAgent,
+					TraceID:  traceID,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -245,8 +264,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 				}
 				targetAgent = spawnInfo.AgentID()
 				targetPane = spawnInfo.Pane
-				hookWorkDir = spawnInfo.ClonePath // Run bd commands from polecat's worktree
-				hookSetAtomically = true          // Hook was set during spawn (GH #gt-mzyk5)
+				hookWorkDir = spawnInfo.HookWorkDir() // Run bd commands from polecat's worktree
+				hookSetAtomically = true              // Hook was set during spawn (GH #gt-mzyk5)
+				if spawnInfo.IsRemote() {
+					remoteSpawn = spawnInfo
+				}
 
 				// Wake witness and refinery to monitor the new polecat
 				wakeRigAgents(rigName)
@@ -270,6 +292,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 							Create:   slingCreate,
 							HookBead: beadID,
 							Agent:    slingAgent,
+							TraceID:  traceID,
 						}
 						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 						if spawnErr != nil {
@@ -277,8 +300,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 						}
 						targetAgent = spawnInfo.AgentID()
 						targetPane = spawnInfo.Pane
-						hookWorkDir = spawnInfo.ClonePath
+						hookWorkDir = spawnInfo.HookWorkDir()
 						hookSetAtomically = true // Hook was set during spawn (GH #gt-mzyk5)
+						if spawnInfo.IsRemote() {
+							remoteSpawn = spawnInfo
+						}
 
 						// Wake witness and refinery to monitor the new polecat
 						wakeRigAgents(rigName)
@@ -470,7 +496,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	_ = events.LogFeed(events.TypeSling, actor, events.WithTrace(events.SlingPayload(beadID, targetAgent), traceID))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -486,6 +512,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Could not store dispatcher in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
 
+	// Store the trace in bead description (gt done carries it to the MR and refinery)
+	if err := storeTraceInBead(beadID, traceID); err != nil {
+		fmt.Printf("%s Could not store trace in bead: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Store args in bead description (no-tmux mode: beads as data plane)
 	if slingArgs != "" {
 		if err := storeArgsInBead(beadID, slingArgs); err != nil {
@@ -518,7 +549,14 @@ func runSling(cmd *cobra.Command, args []string) error {
 	}
 
 	// Try to inject the "start now" prompt (graceful if no tmux)
-	if targetPane == "" {
+	if remoteSpawn != nil {
+		if err := nudgeRemoteSpawn(remoteSpawn, buildStartPrompt(beadID, slingSubject, slingArgs)); err != nil {
+			fmt.Printf("%s Could not nudge on %s: %v\n", style.Dim.Render("○"), remoteSpawn.Machine, err)
+			fmt.Printf("  Agent will discover work via gt prime / bd show\n")
+		} else {
+			fmt.Printf("%s Start prompt sent to %s\n", style.Bold.Render("▶"), remoteSpawn.Machine)
+		}
+	} else if targetPane == "" {
 		fmt.Printf("%s No pane to nudge (agent will discover work via gt prime)\n", style.Dim.Render("○"))
 	} else {
 		// Ensure agent is ready before nudging (prevents race condition where
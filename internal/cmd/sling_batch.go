@@ -65,6 +65,15 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			continue
 		}
 
+		if err := checkSlingBudget(townRoot, beadID, rigName); err != nil {
+			results = append(results, slingResult{beadID: beadID, success: false, errMsg: "budget exceeded"})
+			fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			continue
+		}
+
+		// Start (or continue) the bead's lifecycle trace
+		traceID := beadTraceID(info)
+
 		// Spawn a fresh polecat
 		spawnOpts := SlingSpawnOptions{
 			Force:    slingForce,
@@ -72,6 +81,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Create:   slingCreate,
 			HookBead: beadID, // Set atomically at spawn time
 			Agent:    slingAgent,
+			TraceID:  traceID,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -81,7 +91,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 
 		targetAgent := spawnInfo.AgentID()
-		hookWorkDir := spawnInfo.ClonePath
+		hookWorkDir := spawnInfo.HookWorkDir()
 
 		// Auto-convoy: check if issue is already tracked
 		if !slingNoConvoy {
@@ -137,7 +147,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		_ = events.LogFeed(events.TypeSling, actor, events.WithTrace(events.SlingPayload(beadToHook, targetAgent), traceID))
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -149,6 +159,17 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			}
 		}
 
+		// Store the trace in the base bead and, if a formula was applied, the hooked compound
+		traceBeads := []string{beadID}
+		if beadToHook != beadID {
+			traceBeads = append(traceBeads, beadToHook)
+		}
+		for _, id := range traceBeads {
+			if err := storeTraceInBead(id, traceID); err != nil {
+				fmt.Printf("  %s Could not store trace: %v\n", style.Dim.Render("Warning:"), err)
+			}
+		}
+
 		// Store args if provided
 		if slingArgs != "" {
 			if err := storeArgsInBead(beadID, slingArgs); err != nil {
@@ -157,7 +178,13 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 
 		// Nudge the polecat
-		if spawnInfo.Pane != "" {
+		if spawnInfo.IsRemote() {
+			if err := nudgeRemoteSpawn(spawnInfo, buildStartPrompt(beadID, slingSubject, slingArgs)); err != nil {
+				fmt.Printf("  %s Could not nudge on %s (agent will discover via gt prime)\n", style.Dim.Render("○"), spawnInfo.Machine)
+			} else {
+				fmt.Printf("  %s Start prompt sent\n", style.Bold.Render("▶"))
+			}
+		} else if spawnInfo.Pane != "" {
 			if err := injectStartPrompt(spawnInfo.Pane, beadID, slingSubject, slingArgs); err != nil {
 				fmt.Printf("  %s Could not nudge (agent will discover via gt prime)\n", style.Dim.Render("○"))
 			} else {
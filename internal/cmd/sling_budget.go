@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// slingDispatchTarget works out the rig and role a sling target resolves to,
+// for budget checks. Unknown targets return an empty target, which only
+// town-wide budgets cover.
+func slingDispatchTarget(target string) costs.DispatchTarget {
+	if target == "" || target == "." {
+		roleInfo, err := GetRole()
+		if err != nil {
+			return costs.DispatchTarget{}
+		}
+		return costs.DispatchTarget{Rig: roleInfo.Rig, Role: string(roleInfo.Role)}
+	}
+	if _, isDog := IsDogTarget(target); isDog {
+		return costs.DispatchTarget{Role: "dog"}
+	}
+	if rigName, isRig := IsRigName(target); isRig {
+		return costs.DispatchTarget{Rig: rigName, Role: string(session.RolePolecat)}
+	}
+	identity, err := session.ParseAddress(target)
+	if err != nil {
+		return costs.DispatchTarget{}
+	}
+	return costs.DispatchTarget{Rig: identity.Rig, Role: string(identity.Role)}
+}
+
+// checkSlingBudget refuses to dispatch when a hard budget cap covering the
+// target (or the convoy tracking beadID) has been reached.
+func checkSlingBudget(townRoot, beadID, target string) error {
+	breaches, err := costs.CurrentBreaches(townRoot)
+	if err != nil {
+		// Budgets are a guard rail; don't block dispatch on a broken ledger
+		fmt.Printf("Warning: could not check budgets: %v\n", err)
+		return nil
+	}
+	if len(breaches) == 0 {
+		return nil
+	}
+
+	dispatch := slingDispatchTarget(target)
+	for _, b := range breaches {
+		if b.Level == costs.LevelHard && b.Convoy != "" && beadID != "" {
+			dispatch.Convoy = isTrackedByConvoy(beadID)
+			break
+		}
+	}
+
+	blocking := costs.Blocking(breaches, dispatch)
+	if len(blocking) == 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(blocking))
+	for _, b := range blocking {
+		reasons = append(reasons, b.String())
+	}
+	return fmt.Errorf("budget exceeded, refusing to dispatch:\n  %s\nRaise the cap in settings/config.json or wait for the period to reset",
+		strings.Join(reasons, "\n  "))
+}
@@ -10,8 +10,10 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -69,6 +71,28 @@ func verifyFormulaExists(formulaName string) error {
 	return fmt.Errorf("formula '%s' not found (check 'bd formula list')", formulaName)
 }
 
+// checkFormulaExpansion refuses formulas whose steps use when or foreach.
+// bd cooks and pours every step as written, so such a formula would run
+// steps its conditions exclude and leave {{item}} unexpanded. Formulas gt
+// cannot find locally are left for bd to resolve.
+func checkFormulaExpansion(formulaName string) error {
+	path, err := findFormulaFile(formulaName)
+	if err != nil {
+		if path, err = findFormulaFile("mol-" + formulaName); err != nil {
+			return nil
+		}
+	}
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return nil
+	}
+	if ids := f.ExpansionSteps(); len(ids) > 0 {
+		return fmt.Errorf("formula '%s' uses when/foreach on step(s) %s, which bd does not expand yet",
+			formulaName, strings.Join(ids, ", "))
+	}
+	return nil
+}
+
 // runSlingFormula handles standalone formula slinging.
 // Flow: cook → wisp → attach to hook → nudge
 func runSlingFormula(args []string) error {
@@ -81,15 +105,29 @@ func runSlingFormula(args []string) error {
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
 
+	// Refuse constructs bd would silently ignore
+	if err := checkFormulaExpansion(formulaName); err != nil {
+		return err
+	}
+
 	// Determine target (self or specified)
 	var target string
 	if len(args) > 1 {
 		target = args[1]
 	}
 
+	// Refuse to dispatch past a hard budget cap
+	if err := checkSlingBudget(townRoot, "", target); err != nil {
+		return err
+	}
+
+	// The wisp is new work, so it starts a new lifecycle trace
+	traceID := trace.NewID()
+
 	// Resolve target agent and pane
 	var targetAgent string
 	var targetPane string
+	var remoteSpawn *SpawnedPolecatInfo // Set when the spawned polecat runs on a remote machine
 
 	if target != "" {
 		// Resolve "." to current agent identity (like git's "." meaning current directory)
@@ -135,6 +173,7 @@ func runSlingFormula(args []string) error {
 					Account: slingAccount,
 					Create:  slingCreate,
 					Agent:   slingAgent,
+					TraceID: traceID,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -142,6 +181,9 @@ func runSlingFormula(args []string) error {
 				}
 				targetAgent = spawnInfo.AgentID()
 				targetPane = spawnInfo.Pane
+				if spawnInfo.IsRemote() {
+					remoteSpawn = spawnInfo
+				}
 
 				// Wake witness and refinery to monitor the new polecat
 				wakeRigAgents(rigName)
@@ -223,7 +265,7 @@ func runSlingFormula(args []string) error {
 
 	// Log sling event to activity feed (formula slinging)
 	actor := detectActor()
-	payload := events.SlingPayload(wispRootID, targetAgent)
+	payload := events.WithTrace(events.SlingPayload(wispRootID, targetAgent), traceID)
 	payload["formula"] = formulaName
 	_ = events.LogFeed(events.TypeSling, actor, payload)
 
@@ -237,6 +279,11 @@ func runSlingFormula(args []string) error {
 		fmt.Printf("%s Could not store dispatcher in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
 
+	// Store the trace in the wisp (gt done carries it to the MR and refinery)
+	if err := storeTraceInBead(wispRootID, traceID); err != nil {
+		fmt.Printf("%s Could not store trace in bead: %v\n", style.Dim.Render("Warning:"), err)
+	}
+
 	// Store args in wisp bead if provided (no-tmux mode: beads as data plane)
 	if slingArgs != "" {
 		if err := storeArgsInBead(wispRootID, slingArgs); err != nil {
@@ -255,7 +302,7 @@ func runSlingFormula(args []string) error {
 	}
 
 	// Step 4: Nudge to start (graceful if no tmux)
-	if targetPane == "" {
+	if targetPane == "" && remoteSpawn == nil {
 		fmt.Printf("%s No pane to nudge (agent will discover work via gt prime)\n", style.Dim.Render("○"))
 		return nil
 	}
@@ -271,10 +318,15 @@ func runSlingFormula(args []string) error {
 	} else {
 		prompt = fmt.Sprintf("Formula %s slung. Run `gt hook` to see your hook, then execute the steps.", formulaName)
 	}
-	t := tmux.NewTmux()
-	if err := t.NudgePane(targetPane, prompt); err != nil {
+	var nudgeErr error
+	if remoteSpawn != nil {
+		nudgeErr = nudgeRemoteSpawn(remoteSpawn, prompt)
+	} else {
+		nudgeErr = tmux.NewTmux().NudgePane(targetPane, prompt)
+	}
+	if nudgeErr != nil {
 		// Graceful fallback for no-tmux mode
-		fmt.Printf("%s Could not nudge (no tmux?): %v\n", style.Dim.Render("○"), err)
+		fmt.Printf("%s Could not nudge (no tmux?): %v\n", style.Dim.Render("○"), nudgeErr)
 		fmt.Printf("  Agent will discover work via gt prime / bd show\n")
 	} else {
 		fmt.Printf("%s Nudged to start\n", style.Bold.Render("▶"))
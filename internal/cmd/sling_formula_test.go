@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckFormulaExpansion(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	formulas := filepath.Join(dir, ".beads", "formulas")
+	if err := os.MkdirAll(formulas, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, body string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(formulas, name+".formula.toml"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("mol-plain", `
+formula = "mol-plain"
+type = "workflow"
+
+[[steps]]
+id = "build"
+title = "Build {{version}}"
+`)
+	write("mol-fanout", `
+formula = "mol-fanout"
+type = "workflow"
+
+[vars.platforms]
+default = "linux,darwin"
+
+[[steps]]
+id = "build"
+title = "Build for {{item}}"
+foreach = "platforms"
+
+[[steps]]
+id = "sign"
+title = "Sign"
+when = "platforms != ''"
+needs = ["build"]
+`)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkFormulaExpansion("plain"); err != nil {
+		t.Errorf("plain formula: %v", err)
+	}
+	if err := checkFormulaExpansion("missing"); err != nil {
+		t.Errorf("unknown formula should be left to bd: %v", err)
+	}
+	err := checkFormulaExpansion("fanout")
+	if err == nil || !strings.Contains(err.Error(), "build, sign") {
+		t.Errorf("fanout formula: err = %v, want when/foreach error naming build, sign", err)
+	}
+}
@@ -11,14 +11,16 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/trace"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // beadInfo holds status and assignee for a bead.
 type beadInfo struct {
-	Title    string `json:"title"`
-	Status   string `json:"status"`
-	Assignee string `json:"assignee"`
+	Title       string `json:"title"`
+	Status      string `json:"status"`
+	Assignee    string `json:"assignee"`
+	Description string `json:"description"`
 }
 
 // verifyBeadExists checks that the bead exists using bd show.
@@ -282,6 +284,83 @@ func storeNoMergeInBead(beadID string, noMerge bool) error {
 	return nil
 }
 
+// slingTraceID returns the trace for a bead's lifecycle: the one already
+// stored on the bead if it was slung before (so a re-sling continues the
+// same trace), otherwise a new one.
+func slingTraceID(beadID string) string {
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return trace.NewID()
+	}
+	return beadTraceID(info)
+}
+
+// beadTraceID returns the trace stored on a bead, or a new one.
+func beadTraceID(info *beadInfo) string {
+	if id := storedTraceID(info); id != "" {
+		return id
+	}
+	return trace.NewID()
+}
+
+// storedTraceID returns the trace stored on a bead, if any.
+func storedTraceID(info *beadInfo) string {
+	fields := beads.ParseAttachmentFields(&beads.Issue{Description: info.Description})
+	if fields != nil && trace.ValidID(fields.TraceID) {
+		return fields.TraceID
+	}
+	return ""
+}
+
+// storeTraceInBead stores the lifecycle trace ID in the bead's description.
+// gt done reads it back so the polecat's events, MR and mail join the trace.
+func storeTraceInBead(beadID, traceID string) error {
+	if traceID == "" {
+		return nil
+	}
+
+	// Get the bead to preserve existing description content
+	showCmd := exec.Command("bd", "show", beadID, "--json")
+	out, err := showCmd.Output()
+	if err != nil {
+		return fmt.Errorf("fetching bead: %w", err)
+	}
+
+	// Parse the bead
+	var issues []beads.Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return fmt.Errorf("parsing bead: %w", err)
+	}
+	if len(issues) == 0 {
+		return fmt.Errorf("bead not found")
+	}
+	issue := &issues[0]
+
+	// Get or create attachment fields
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil {
+		fields = &beads.AttachmentFields{}
+	}
+	if fields.TraceID == traceID {
+		return nil
+	}
+
+	// Set the trace
+	fields.TraceID = traceID
+
+	// Update the description
+	newDesc := beads.SetAttachmentFields(issue, fields)
+
+	// Update the bead
+	updateCmd := exec.Command("bd", "update", beadID, "--description="+newDesc)
+	updateCmd.Stderr = os.Stderr
+	if err := updateCmd.Run(); err != nil {
+		return fmt.Errorf("updating bead description: %w", err)
+	}
+
+	return nil
+}
+
 // injectStartPrompt sends a prompt to the target pane to start working.
 // Uses the reliable nudge pattern: literal mode + 500ms debounce + separate Enter.
 func injectStartPrompt(pane, beadID, subject, args string) error {
@@ -294,7 +373,13 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 		return nil
 	}
 
-	// Build the prompt to inject
+	// Use the reliable nudge pattern (same as gt nudge / tmux.NudgeSession)
+	t := tmux.NewTmux()
+	return t.NudgePane(pane, buildStartPrompt(beadID, subject, args))
+}
+
+// buildStartPrompt builds the "start working" prompt injected after slinging.
+func buildStartPrompt(beadID, subject, args string) string {
 	var prompt string
 	if args != "" {
 		// Args provided - include them prominently in the prompt
@@ -308,10 +393,7 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 	} else {
 		prompt = fmt.Sprintf("Work slung: %s. Start working on it now - run `gt hook` to see the hook, then begin.", beadID)
 	}
-
-	// Use the reliable nudge pattern (same as gt nudge / tmux.NudgeSession)
-	t := tmux.NewTmux()
-	return t.NudgePane(pane, prompt)
+	return prompt
 }
 
 // getSessionFromPane extracts session name from a pane target.
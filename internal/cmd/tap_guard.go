@@ -18,6 +18,7 @@ is violated. They're called before the tool runs, preventing the
 forbidden operation entirely.
 
 Available guards:
+  policy        - Evaluate the town and rig guard policy files
   pr-workflow   - Block PR creation and feature branches
 
 Example hook configuration:
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/guard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var tapGuardPolicyInit bool
+
+var tapGuardPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Evaluate the town and rig guard policies",
+	Long: `Evaluate a PreToolUse payload (read from stdin) against guard policies.
+
+Policies are JSON files at settings/guard-policy.json in the town and in
+each rig. Rules allow or deny tool calls by tool name, Bash command pattern
+and the paths a tool touches, optionally scoped to roles (from GT_ROLE) and
+rigs. The rig's rules are checked before the town's; the first matching rule
+decides, and calls no rule matches are allowed. Edit the files to add a
+guard - no new gt build needed.
+
+  {
+    "type": "guard-policy",
+    "version": 1,
+    "rules": [{
+      "name": "no-force-push",
+      "action": "deny",
+      "message": "Rebase and push normally.",
+      "tools": ["Bash"],
+      "commands": ["git push *--force*"],
+      "roles": ["polecat"]
+    }]
+  }
+
+Patterns are globs (* matches anything; in paths * stays within a
+directory and ** crosses them) or regular expressions prefixed "re:".
+Path patterns may use {town}, {rig}, {worktree} and {home}, and a leading
+"!" matches paths outside the pattern ("!{worktree}/**").
+
+Blocks are logged as guard_blocked audit events.
+
+A policy that can't be loaded, or a payload that can't be parsed, blocks
+the call: a broken policy fails closed rather than silently letting
+everything through. Fix the file reported on stderr to unblock.
+
+Exit codes:
+  0 - Tool call allowed
+  1 - Usage error (e.g. --init failed)
+  2 - Tool call BLOCKED, by a rule or by a policy/payload error
+
+Use --init to write a starter town policy: polecats may not force push,
+touch .beads/ directly, or rm -r outside their worktree.
+
+Example hook configuration:
+  {
+    "PreToolUse": [{
+      "matcher": "Bash|Edit|MultiEdit|Write|NotebookEdit",
+      "hooks": [{"command": "gt tap guard policy"}]
+    }]
+  }`,
+	RunE: runTapGuardPolicy,
+}
+
+func init() {
+	tapGuardPolicyCmd.Flags().BoolVar(&tapGuardPolicyInit, "init", false, "Write a starter town policy and exit")
+	tapGuardCmd.AddCommand(tapGuardPolicyCmd)
+}
+
+func runTapGuardPolicy(cmd *cobra.Command, args []string) error {
+	if tapGuardPolicyInit {
+		return initGuardPolicy()
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		blockOnGuardError(fmt.Errorf("reading hook input: %w", err))
+	}
+	if len(data) == 0 {
+		return nil // Nothing to evaluate
+	}
+	in, err := guard.ParseHookInput(data)
+	if err != nil {
+		blockOnGuardError(err)
+	}
+
+	cwd := in.Cwd
+	if cwd == "" {
+		if cwd, err = os.Getwd(); err != nil {
+			blockOnGuardError(fmt.Errorf("getting current directory: %w", err))
+		}
+	}
+	townRoot, err := workspace.Find(cwd)
+	if err != nil || townRoot == "" {
+		return nil // Not in a Gas Town workspace: no policies apply
+	}
+
+	ctx := guard.Context{
+		TownRoot: townRoot,
+		Cwd:      cwd,
+		Worktree: guard.WorktreeFor(cwd),
+	}
+	ctx.Home, _ = os.UserHomeDir()
+	roleInfo, roleErr := GetRoleWithContext(cwd, townRoot)
+	if roleErr == nil {
+		if roleInfo.Role != RoleUnknown {
+			ctx.Role = string(roleInfo.Role)
+		}
+		ctx.Rig = roleInfo.Rig
+	}
+	if ctx.Rig != "" {
+		ctx.RigPath = filepath.Join(townRoot, ctx.Rig)
+	}
+
+	policies, err := guard.LoadPolicies(townRoot, ctx.RigPath)
+	if err != nil {
+		blockOnGuardError(err)
+	}
+	decision := guard.Evaluate(policies, in, ctx)
+	if decision.Allowed {
+		return nil
+	}
+
+	rule := decision.Rule.Name
+	if rule == "" {
+		rule = "(unnamed rule)"
+	}
+	actor := "unknown"
+	if roleErr == nil {
+		actor = roleInfo.ActorString()
+	}
+	_ = events.LogAudit(events.TypeGuardBlocked, actor,
+		events.GuardBlockedPayload(rule, in.ToolName, decision.Match, decision.Source))
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintf(os.Stderr, "❌ BLOCKED by guard policy: %s\n", rule)
+	if decision.Rule.Message != "" {
+		fmt.Fprintf(os.Stderr, "   %s\n", decision.Rule.Message)
+	}
+	fmt.Fprintf(os.Stderr, "   Matched: %s\n", decision.Match)
+	fmt.Fprintf(os.Stderr, "   Policy:  %s\n", decision.Source)
+	fmt.Fprintln(os.Stderr, "")
+	os.Exit(2) // Exit 2 = BLOCK in Claude Code hooks
+
+	return nil
+}
+
+// blockOnGuardError blocks the tool call when policies can't be evaluated.
+// Exiting 1 would let the call through, so a typo in a policy file would
+// quietly disable every guard.
+func blockOnGuardError(err error) {
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "❌ BLOCKED: guard policy could not be evaluated")
+	fmt.Fprintf(os.Stderr, "   %v\n", err)
+	fmt.Fprintln(os.Stderr, "   Guarded tool calls stay blocked until it is fixed; ask the overseer to")
+	fmt.Fprintln(os.Stderr, "   repair settings/guard-policy.json.")
+	fmt.Fprintln(os.Stderr, "")
+	os.Exit(2) // Exit 2 = BLOCK in Claude Code hooks
+}
+
+// initGuardPolicy writes the starter policy to the town's settings.
+func initGuardPolicy() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	path := guard.TownPolicyPath(townRoot)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("guard policy already exists: %s", path)
+	}
+	if err := guard.SavePolicy(path, guard.DefaultPolicy()); err != nil {
+		return err
+	}
+	fmt.Printf("%s Wrote starter guard policy to %s\n", style.Bold.Render("✓"), path)
+	return nil
+}
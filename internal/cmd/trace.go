@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/trace"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Trace command flags
+var (
+	traceJSON bool
+	traceOTLP string
+)
+
+var traceCmd = &cobra.Command{
+	Use:     "trace <bead>",
+	GroupID: GroupDiag,
+	Short:   "Show a bead's lifecycle as a span waterfall",
+	Long: `Show how a bead moved through Gas Town, phase by phase.
+
+gt sling mints a trace ID for the bead (stored as trace_id in its
+description). The spawn, done, merge and convoy events that follow carry it,
+as do the MR bead and the protocol mail about the work, so the lifecycle can
+be stitched back together from the activity log:
+
+  dispatch   spawn → sling            (polecat spawned and hooked)
+  work       sling/hook → done        (polecat working)
+  queue      done → merge_started     (waiting in the merge queue)
+  merge      merge_started → merged   (refinery merging; failed attempts
+                                       show as failed merge spans)
+  convoy     merged → convoy_closed   (only if a convoy tracks the bead)
+
+Beads slung before tracing existed are matched by bead ID and branch.
+
+With --otlp the trace is also written as OTLP/JSON (one
+ExportTraceServiceRequest per line, the OpenTelemetry Collector file
+exporter format) for loading into Jaeger, Tempo or any OTLP backend later.
+Use --otlp - for stdout.
+
+Examples:
+  gt trace gt-abc                      # Waterfall
+  gt trace gt-abc --json               # Spans and events as JSON
+  gt trace gt-abc --otlp gt-abc.json   # Export for an OTLP backend`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrace,
+}
+
+func init() {
+	traceCmd.Flags().BoolVar(&traceJSON, "json", false, "Output as JSON")
+	traceCmd.Flags().StringVar(&traceOTLP, "otlp", "", "Write the trace as OTLP/JSON to this file (- for stdout)")
+
+	rootCmd.AddCommand(traceCmd)
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	t, err := trace.Load(filepath.Join(townRoot, events.EventsFile), beadID)
+	if err != nil {
+		return err
+	}
+	if t.Root() == nil {
+		return fmt.Errorf("no activity recorded for %s", beadID)
+	}
+	now := time.Now()
+
+	if traceOTLP != "" {
+		if err := writeTraceOTLP(traceOTLP, t, now); err != nil {
+			return err
+		}
+		if traceOTLP == "-" {
+			return nil
+		}
+	}
+
+	if traceJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t)
+	}
+
+	root := t.Root()
+	status := "in progress"
+	switch {
+	case !root.InProgress() && root.Status == trace.StatusOK:
+		status = "merged"
+	case !root.InProgress():
+		status = "ended"
+	}
+	fmt.Printf("%s Trace %s for %s (%s, %s)\n", style.Bold.Render("🔭"), t.ID, beadID, status, trace.FormatDuration(root.Duration(now)))
+	if t.Derived {
+		fmt.Printf("  %s\n", style.Dim.Render("No trace ID recorded; events matched by bead and branch"))
+	}
+	fmt.Println()
+	if err := trace.WriteWaterfall(os.Stdout, t, now); err != nil {
+		return err
+	}
+	if traceOTLP != "" {
+		fmt.Printf("\n%s OTLP trace written to %s\n", style.Bold.Render("✓"), traceOTLP)
+	}
+	return nil
+}
+
+// writeTraceOTLP writes the trace as OTLP/JSON to path, or stdout for "-".
+func writeTraceOTLP(path string, t *trace.Trace, now time.Time) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path) //nolint:gosec // G304: path is user-provided output file
+		if err != nil {
+			return fmt.Errorf("creating OTLP file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := trace.WriteOTLP(w, t, now); err != nil {
+		return fmt.Errorf("writing OTLP trace: %w", err)
+	}
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -17,6 +19,10 @@ import (
 var (
 	witnessForeground    bool
 	witnessStatusJSON    bool
+	witnessSurveyJSON    bool
+	witnessMergeBranch   string
+	witnessMergeIssue    string
+	witnessMergeWait     time.Duration
 	witnessAgentOverride string
 	witnessEnvOverrides  []string
 )
@@ -85,6 +91,48 @@ Displays running state, monitored polecats, and statistics.`,
 	RunE: runWitnessStatus,
 }
 
+var witnessSurveyCmd = &cobra.Command{
+	Use:   "survey <rig>",
+	Short: "Show polecat liveness from the agent monitor",
+	Long: `Show each polecat's liveness as seen by the daemon's agent monitor.
+
+The monitor samples every agent pane on an interval, so the Witness doesn't
+have to read panes and time silences itself. Each polecat gets an action:
+
+  none       Making progress
+  nudge      No pane output for 5 minutes
+  deadline   No pane output for 15 minutes: nudge with a deadline
+  help       Monitor saw the polecat blocked or erroring
+  stalled    No running session
+
+Fails if the daemon's agent monitor isn't running.
+
+Examples:
+  gt witness survey greenplace
+  gt witness survey greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessSurvey,
+}
+
+var witnessMergeReadyCmd = &cobra.Command{
+	Use:   "merge-ready <rig> <polecat>",
+	Short: "Ask the Refinery to merge a verified polecat branch",
+	Long: `Send MERGE_READY for a verified polecat branch to the rig's Refinery.
+
+The message is sent as a tracked request: it is settled when the Refinery
+answers with MERGED, MERGE_FAILED or REWORK_REQUEST for the polecat. If no
+answer arrives in time the mail orchestrator resends it, then escalates.
+
+With --wait, block until the answer arrives in the witness inbox (it is
+left unread there) and print it.
+
+Examples:
+  gt witness merge-ready greenplace Toast --branch polecat/Toast --issue gp-abc
+  gt witness merge-ready greenplace Toast --branch polecat/Toast --issue gp-abc --wait 30m`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWitnessMergeReady,
+}
+
 var witnessAttachCmd = &cobra.Command{
 	Use:     "attach [rig]",
 	Aliases: []string{"at"},
@@ -128,6 +176,16 @@ func init() {
 	// Status flags
 	witnessStatusCmd.Flags().BoolVar(&witnessStatusJSON, "json", false, "Output as JSON")
 
+	// Survey flags
+	witnessSurveyCmd.Flags().BoolVar(&witnessSurveyJSON, "json", false, "Output as JSON")
+
+	// Merge-ready flags
+	witnessMergeReadyCmd.Flags().StringVar(&witnessMergeBranch, "branch", "", "Polecat branch to merge (required)")
+	witnessMergeReadyCmd.Flags().StringVar(&witnessMergeIssue, "issue", "", "Issue the polecat completed (required)")
+	witnessMergeReadyCmd.Flags().DurationVar(&witnessMergeWait, "wait", 0, "Wait up to this long for the Refinery's answer")
+	_ = witnessMergeReadyCmd.MarkFlagRequired("branch")
+	_ = witnessMergeReadyCmd.MarkFlagRequired("issue")
+
 	// Restart flags
 	witnessRestartCmd.Flags().StringVar(&witnessAgentOverride, "agent", "", "Agent alias to run the Witness with (overrides town default)")
 	witnessRestartCmd.Flags().StringArrayVar(&witnessEnvOverrides, "env", nil, "Environment variable override (KEY=VALUE, can be repeated)")
@@ -137,6 +195,8 @@ func init() {
 	witnessCmd.AddCommand(witnessStopCmd)
 	witnessCmd.AddCommand(witnessRestartCmd)
 	witnessCmd.AddCommand(witnessStatusCmd)
+	witnessCmd.AddCommand(witnessSurveyCmd)
+	witnessCmd.AddCommand(witnessMergeReadyCmd)
 	witnessCmd.AddCommand(witnessAttachCmd)
 
 	rootCmd.AddCommand(witnessCmd)
@@ -285,6 +345,77 @@ func runWitnessStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runWitnessSurvey(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	survey, err := witness.CheckLiveness(townRoot, rigName, r.Polecats, time.Now())
+	if err != nil {
+		return fmt.Errorf("reading agent monitor: %w", err)
+	}
+
+	if witnessSurveyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(survey)
+	}
+
+	fmt.Printf("%s Polecat liveness: %s\n\n", style.Bold.Render(AgentTypeIcons[AgentWitness]), rigName)
+	if len(survey) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no polecats)"))
+		return nil
+	}
+	for _, l := range survey {
+		quiet := ""
+		if l.Quiet > 0 {
+			quiet = style.Dim.Render(fmt.Sprintf(" (quiet %s)", l.Quiet.Round(time.Second)))
+		}
+		action := string(l.Action)
+		if l.Action != witness.ActionNone {
+			action = style.Bold.Render(action)
+		}
+		fmt.Printf("  %-20s %-10s %s%s\n", l.Polecat, l.Status, action, quiet)
+	}
+	return nil
+}
+
+func runWitnessMergeReady(cmd *cobra.Command, args []string) error {
+	rigName, polecat := args[0], args[1]
+
+	townRoot, _, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	handler := protocol.NewWitnessHandler(rigName, townRoot)
+	req, err := handler.SendMergeReady(polecat, witnessMergeBranch, witnessMergeIssue)
+	if err != nil {
+		return fmt.Errorf("sending MERGE_READY: %w", err)
+	}
+
+	fmt.Printf("%s Sent MERGE_READY %s to %s/refinery\n", style.Bold.Render("✓"), polecat, rigName)
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Request %s, answer due by %s",
+		req.CorrelationID, req.Deadline.Local().Format("15:04"))))
+
+	if witnessMergeWait <= 0 {
+		return nil
+	}
+	reply, err := handler.AwaitMergeOutcome(req, witnessMergeWait)
+	if err != nil {
+		return fmt.Errorf("waiting for answer: %w", err)
+	}
+	if reply == nil {
+		fmt.Printf("%s No answer within %s; the request stays pending\n", style.Dim.Render("○"), witnessMergeWait)
+		return nil
+	}
+	fmt.Printf("%s Refinery answered: %s\n", style.Bold.Render("✓"), reply.Subject)
+	return nil
+}
+
 // witnessSessionName returns the tmux session name for a rig's witness.
 func witnessSessionName(rigName string) string {
 	return fmt.Sprintf("gt-%s-witness", rigName)
@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Workers command flags
@@ -237,6 +238,8 @@ func runWorkersStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	applyMonitorStatus([]*WorkerInfo{worker})
+
 	// Get detailed git status
 	gitStatus, err := getWorkerGitStatus(worker)
 	if err == nil {
@@ -369,9 +372,34 @@ func collectWorkers(rigs []*rig.Rig) ([]*WorkerInfo, error) {
 		}
 	}
 
+	applyMonitorStatus(workers)
 	return workers, nil
 }
 
+// applyMonitorStatus fills in each worker's Status from the daemon's agent
+// monitor. Leaves Status empty when the monitor isn't running.
+func applyMonitorStatus(workers []*WorkerInfo) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	file, err := monitoring.LoadStatusFile(monitoring.StatusFile(townRoot))
+	if err != nil || !file.Fresh(monitoring.StatusFileMaxAge) {
+		return
+	}
+
+	idleTimeout := monitoring.DefaultIdleConfig().Timeout
+	for _, w := range workers {
+		address := fmt.Sprintf("%s/polecats/%s", w.Rig, w.Name)
+		if w.Type == "crew" {
+			address = fmt.Sprintf("%s/crew/%s", w.Rig, w.Name)
+		}
+		if status, ok := file.Status(address, idleTimeout); ok {
+			w.Status = status
+		}
+	}
+}
+
 // getWorkerInfo finds a specific worker by name.
 func getWorkerInfo(r *rig.Rig, name string) (*WorkerInfo, error) {
 	t := tmux.NewTmux()
@@ -507,6 +535,21 @@ func calculateHealth(workers []*WorkerInfo) *WorkerHealth {
 			health.ActiveWorkers++
 		}
 
+		// The agent monitor reads the pane; a blocked or erroring agent
+		// needs attention whatever its bead state says.
+		switch w.Status {
+		case monitoring.StatusBlocked:
+			health.StalledWorkers++
+			health.Problems = append(health.Problems,
+				fmt.Sprintf("%s/%s is blocked", w.Rig, w.Name))
+			continue
+		case monitoring.StatusError:
+			health.ErrorWorkers++
+			health.Problems = append(health.Problems,
+				fmt.Sprintf("%s/%s is reporting errors", w.Rig, w.Name))
+			continue
+		}
+
 		switch w.State {
 		case "idle", "available":
 			health.IdleWorkers++
@@ -573,6 +616,10 @@ func displayWorkerList(workers []*WorkerInfo, title string) {
 			typeBadge = "polecat"
 		}
 
+		if w.Status != "" {
+			stateStr += style.Dim.Render(" · " + string(w.Status))
+		}
+
 		fmt.Printf("  %s %s/%s [%s]  %s\n",
 			sessionStatus, w.Rig, w.Name, typeBadge, stateStr)
 
@@ -626,6 +673,9 @@ func displayWorkerDetail(worker *WorkerInfo) {
 	fmt.Printf("%s\n", style.Bold.Render("Session"))
 	if worker.SessionRunning {
 		fmt.Printf("  Status:        %s\n", style.Success.Render("running"))
+		if worker.Status != "" {
+			fmt.Printf("  Agent:         %s\n", worker.Status)
+		}
 		if worker.SessionID != "" {
 			fmt.Printf("  Session ID:    %s\n", style.Dim.Render(worker.SessionID))
 		}
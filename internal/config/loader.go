@@ -240,6 +240,9 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 	if c.MaxConcurrent < 0 {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("%w: batch_size must be non-negative", ErrMissingField)
+	}
 
 	return nil
 }
@@ -1341,7 +1344,9 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 		resolvedEnv[k] = v
 	}
 	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
-	if townRoot != "" {
+	// A caller-supplied GT_ROOT wins: agents on remote machines see the
+	// remote town root, not the local one used to resolve runtime config.
+	if _, ok := resolvedEnv["GT_ROOT"]; !ok && townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
 	}
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
@@ -1451,7 +1456,9 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		resolvedEnv[k] = v
 	}
 	// Add GT_ROOT so agents can find town-level resources (formulas, etc.)
-	if townRoot != "" {
+	// A caller-supplied GT_ROOT wins: agents on remote machines see the
+	// remote town root, not the local one used to resolve runtime config.
+	if _, ok := resolvedEnv["GT_ROOT"]; !ok && townRoot != "" {
 		resolvedEnv["GT_ROOT"] = townRoot
 	}
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
@@ -1722,6 +1729,38 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	// Validate transports if specified
+	if smtp := c.Transports.SMTP; smtp != nil {
+		if smtp.Host == "" || smtp.From == "" {
+			return fmt.Errorf("%w: transports.smtp requires host and from", ErrMissingField)
+		}
+		if smtp.Port < 0 {
+			return fmt.Errorf("%w: transports.smtp.port must be non-negative", ErrMissingField)
+		}
+	}
+	if sms := c.Transports.SMS; sms != nil {
+		if sms.URL == "" {
+			return fmt.Errorf("%w: transports.sms requires url", ErrMissingField)
+		}
+		if sms.Format != "" && sms.Format != "json" && sms.Format != "form" {
+			return fmt.Errorf("%w: transports.sms.format must be 'json' or 'form', got '%s'", ErrMissingField, sms.Format)
+		}
+	}
+
+	// Validate webhook endpoints and the actions that reference them
+	for name, wh := range c.Webhooks {
+		if wh == nil || wh.URL == "" {
+			return fmt.Errorf("%w: webhooks.%s requires url", ErrMissingField, name)
+		}
+	}
+	for severity, actions := range c.Routes {
+		for _, action := range actions {
+			if name, ok := strings.CutPrefix(action, "webhook:"); ok && c.Webhooks[name] == nil {
+				return fmt.Errorf("%w: routes.%s action '%s' references undefined webhook '%s'", ErrMissingField, severity, action, name)
+			}
+		}
+	}
+
 	return nil
 }
 
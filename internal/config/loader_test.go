@@ -1255,6 +1255,21 @@ func TestBuildStartupCommand_UsesRigAgentWhenRigPathProvided(t *testing.T) {
 	}
 }
 
+func TestBuildStartupCommand_CallerGTRootWins(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	// Remote rigs resolve runtime config locally but run under a remote town root
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "polecat", "GT_ROOT": "/srv/gt"}, rigPath, "")
+	if !strings.Contains(cmd, "GT_ROOT=/srv/gt") {
+		t.Fatalf("expected caller GT_ROOT in command: %q", cmd)
+	}
+	if strings.Contains(cmd, "GT_ROOT="+townRoot) {
+		t.Fatalf("local town root should not override caller GT_ROOT: %q", cmd)
+	}
+}
+
 func TestBuildStartupCommand_UsesRoleAgentsFromTownSettings(t *testing.T) {
 	townRoot := t.TempDir()
 	rigPath := filepath.Join(townRoot, "testrig")
@@ -3126,6 +3141,51 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "smtp transport missing from",
+			config: &EscalationConfig{
+				Type:       "escalation",
+				Version:    1,
+				Transports: EscalationTransports{SMTP: &SMTPTransport{Host: "smtp.example.com"}},
+			},
+			wantErr: true,
+			errMsg:  "transports.smtp requires host and from",
+		},
+		{
+			name: "sms transport bad format",
+			config: &EscalationConfig{
+				Type:       "escalation",
+				Version:    1,
+				Transports: EscalationTransports{SMS: &SMSGatewayTransport{URL: "https://sms.example.com", Format: "xml"}},
+			},
+			wantErr: true,
+			errMsg:  "transports.sms.format",
+		},
+		{
+			name: "route references undefined webhook",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				Routes: map[string][]string{
+					SeverityCritical: {"bead", "webhook:pagerduty"},
+				},
+				Webhooks: map[string]*WebhookEndpoint{
+					"discord": {URL: "https://discord.example.com/hook"},
+				},
+			},
+			wantErr: true,
+			errMsg:  "undefined webhook 'pagerduty'",
+		},
+		{
+			name: "webhook missing url",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Webhooks: map[string]*WebhookEndpoint{"bot": {}},
+			},
+			wantErr: true,
+			errMsg:  "webhooks.bot requires url",
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,6 +68,24 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Budget caps agent spend across the town, per role and per convoy.
+	// Rig settings add per-rig caps.
+	Budget *BudgetConfig `json:"budget,omitempty"`
+
+	// Plugins configures how plugins are executed.
+	Plugins *PluginSettings `json:"plugins,omitempty"`
+}
+
+// PluginSettings configures plugin execution (the "plugins" section of town
+// settings).
+type PluginSettings struct {
+	// AgentCommand runs plugins that have no execution.run command, with the
+	// plugin's instructions appended as the prompt (e.g., ["claude", "-p"]).
+	// Unset means such plugins can't be executed headlessly. The agent gets
+	// whatever tool access the command grants, so flags like
+	// --dangerously-skip-permissions are an explicit choice.
+	AgentCommand []string `json:"agent_command,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -167,6 +185,10 @@ type RigEntry struct {
 	LocalRepo   string       `json:"local_repo,omitempty"`
 	AddedAt     time.Time    `json:"added_at"`
 	BeadsConfig *BeadsConfig `json:"beads,omitempty"`
+
+	// Machine names the machine (from mayor/machines.json) hosting the rig's
+	// worktrees and sessions. Empty or "local" means this machine.
+	Machine string `json:"machine,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for a rig.
@@ -236,6 +258,42 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Budget caps this rig's spend in total and per role.
+	// SoftPercent and Convoys are town settings and are ignored here.
+	Budget *BudgetConfig `json:"budget,omitempty"`
+}
+
+// BudgetConfig caps agent spend in USD (the "budget" section of town and rig
+// settings). The daemon evaluates caps against the cost ledger
+// (~/.gt/costs.jsonl) plus live session usage. At SoftPercent of a cap it
+// escalates a warning; at the cap, dispatch and agent auto-starts covered by
+// the cap are paused (the Deacon only for the town total or a deacon cap).
+type BudgetConfig struct {
+	// SoftPercent is the share of a cap at which a warning is escalated.
+	// Default: 80.
+	SoftPercent int `json:"soft_percent,omitempty"`
+
+	// Total caps all spend in scope: the whole town in town settings,
+	// the rig in rig settings.
+	Total *BudgetLimit `json:"total,omitempty"`
+
+	// Roles caps spend per role ("polecat", "crew", "witness", ...).
+	// In town settings a role cap covers that role in every rig.
+	Roles map[string]*BudgetLimit `json:"roles,omitempty"`
+
+	// Convoys caps spend on work tracked by a convoy, keyed by convoy ID.
+	Convoys map[string]*BudgetLimit `json:"convoys,omitempty"`
+}
+
+// DefaultBudgetSoftPercent is the soft limit used when SoftPercent is unset.
+const DefaultBudgetSoftPercent = 80
+
+// BudgetLimit is a pair of spend caps in USD. Zero means uncapped.
+// Days start at local midnight and weeks on Monday.
+type BudgetLimit struct {
+	DailyUSD  float64 `json:"daily_usd,omitempty"`
+	WeeklyUSD float64 `json:"weekly_usd,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -716,6 +774,9 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// BatchSize is how many MRs are merged together as one speculative train.
+	BatchSize int `json:"batch_size,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -737,6 +798,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		RetryFlakyTests:      1,
 		PollInterval:         "30s",
 		MaxConcurrent:        1,
+		BatchSize:            1,
 	}
 }
 
@@ -863,12 +925,22 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "webhook:<name>" → POST to the named endpoint in Webhooks
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// Transports configures delivery for the email: and sms: actions.
+	// Without a transport those actions are skipped with a warning.
+	Transports EscalationTransports `json:"transports,omitempty"`
+
+	// Webhooks defines named outbound HTTP endpoints for "webhook:<name>"
+	// actions. Endpoints with Events set also receive matching activity
+	// events (e.g., "done", "merge_failed", "mass_death") from the daemon.
+	Webhooks map[string]*WebhookEndpoint `json:"webhooks,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -887,6 +959,51 @@ type EscalationContacts struct {
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
 }
 
+// EscalationTransports configures outbound delivery for external escalation actions.
+type EscalationTransports struct {
+	SMTP *SMTPTransport       `json:"smtp,omitempty"` // email:human
+	SMS  *SMSGatewayTransport `json:"sms,omitempty"`  // sms:human
+}
+
+// SMTPTransport configures the SMTP server used for escalation email.
+// STARTTLS is used when the server offers it; port 465 uses implicit TLS.
+type SMTPTransport struct {
+	Host        string `json:"host"`
+	Port        int    `json:"port,omitempty"`         // default 587
+	Username    string `json:"username,omitempty"`     // enables PLAIN auth
+	PasswordEnv string `json:"password_env,omitempty"` // env var holding the SMTP password
+	From        string `json:"from"`                   // envelope and header sender
+}
+
+// SMSGatewayTransport configures the HTTP gateway used for escalation SMS.
+// The gateway receives a POST with the recipient, sender and message text.
+type SMSGatewayTransport struct {
+	URL         string            `json:"url"`
+	Format      string            `json:"format,omitempty"`       // "json" (default): {"to","from","message"}; "form": Twilio-style To/From/Body
+	From        string            `json:"from,omitempty"`         // sender number or ID
+	Username    string            `json:"username,omitempty"`     // basic auth user (e.g., Twilio account SID)
+	PasswordEnv string            `json:"password_env,omitempty"` // env var holding the basic auth password or token
+	Headers     map[string]string `json:"headers,omitempty"`      // extra request headers; values are $ENV-expanded
+}
+
+// WebhookEndpoint is a named outbound webhook (PagerDuty, Discord, an internal bot).
+type WebhookEndpoint struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`  // default POST
+	Headers map[string]string `json:"headers,omitempty"` // extra request headers; values are $ENV-expanded
+
+	// Secret signs each request body with HMAC-SHA256, sent as
+	// "X-Gastown-Signature: sha256=<hex>". $ENV-expanded.
+	Secret string `json:"secret,omitempty"`
+
+	// Payload is a Go text/template rendering the JSON request body.
+	// Empty sends the notification as JSON. See notify.Message for fields.
+	Payload string `json:"payload,omitempty"`
+
+	// Events lists activity event types forwarded to this endpoint.
+	Events []string `json:"events,omitempty"`
+}
+
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
 const CurrentEscalationVersion = 1
 
@@ -248,8 +248,7 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		return NewSSHConnection(m), nil
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
@@ -0,0 +1,480 @@
+package connection
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	pathpkg "path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Exit codes used by the remote helper scripts to report well-known
+// failures back through ssh. They are chosen from the sysexits range so
+// they don't collide with ordinary command failures or ssh's own 255.
+const (
+	sshExitNotFound   = 66 // EX_NOINPUT
+	sshExitPermission = 77 // EX_NOPERM
+	sshExitTransport  = 255
+)
+
+// DefaultSSHConnectTimeout bounds how long ssh waits to establish a connection.
+const DefaultSSHConnectTimeout = 10 * time.Second
+
+// SSHConnection implements Connection by running commands on a remote
+// machine through the system ssh client. File operations are expressed as
+// small POSIX shell scripts so the remote side needs nothing beyond sh,
+// coreutils and tmux.
+type SSHConnection struct {
+	machine *Machine
+
+	// Binary is the ssh client to invoke. Defaults to "ssh"; tests may
+	// point it at a stand-in that executes the remote command locally.
+	Binary string
+
+	// ConnectTimeout bounds connection establishment.
+	ConnectTimeout time.Duration
+
+	// ExtraArgs are passed to ssh before the host argument.
+	ExtraArgs []string
+}
+
+// NewSSHConnection creates a connection to the given ssh machine.
+func NewSSHConnection(m *Machine) *SSHConnection {
+	return &SSHConnection{
+		machine:        m,
+		Binary:         "ssh",
+		ConnectTimeout: DefaultSSHConnectTimeout,
+	}
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.machine.Name
+}
+
+// IsLocal returns false for ssh connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Machine returns the machine this connection targets.
+func (c *SSHConnection) Machine() *Machine {
+	return c.machine
+}
+
+// sshArgs builds the ssh argument list for running script on the remote host.
+func (c *SSHConnection) sshArgs(script string) []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=" + strconv.Itoa(int(c.ConnectTimeout.Seconds())),
+	}
+	if c.machine.KeyPath != "" {
+		args = append(args, "-i", c.machine.KeyPath)
+	}
+	args = append(args, c.ExtraArgs...)
+	args = append(args, c.machine.Host, "--", script)
+	return args
+}
+
+// run executes script on the remote host, feeding stdin if non-nil.
+// Returns stdout, stderr and the remote exit code (-1 if the process
+// could not be started).
+func (c *SSHConnection) run(script string, stdin []byte) ([]byte, []byte, int, error) {
+	cmd := exec.Command(c.Binary, c.sshArgs(script)...) //nolint:gosec // G204: binary and host come from machine registry
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), stderr.Bytes(), 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code == sshExitTransport {
+			return stdout.Bytes(), stderr.Bytes(), code, c.connErr("ssh", fmt.Errorf("%s", strings.TrimSpace(stderr.String())))
+		}
+		return stdout.Bytes(), stderr.Bytes(), code, nil
+	}
+	return nil, nil, -1, c.connErr("ssh", err)
+}
+
+// runFile runs a file-oriented script, translating the helper exit codes
+// into NotFoundError / PermissionError for path.
+func (c *SSHConnection) runFile(op, path, script string, stdin []byte) ([]byte, error) {
+	stdout, stderr, code, err := c.run(script, stdin)
+	if err != nil {
+		return nil, err
+	}
+	switch code {
+	case 0:
+		return stdout, nil
+	case sshExitNotFound:
+		return nil, &NotFoundError{Path: path}
+	case sshExitPermission:
+		return nil, &PermissionError{Path: path, Op: op}
+	default:
+		return nil, fmt.Errorf("%s %s on %s: %s", op, path, c.machine.Name, strings.TrimSpace(string(stderr)))
+	}
+}
+
+func (c *SSHConnection) connErr(op string, err error) error {
+	return &ConnectionError{Op: op, Machine: c.machine.Name, Err: err}
+}
+
+// shellQuote quotes s for the remote POSIX shell.
+// Unlike config.ShellQuote, the empty string is quoted so it survives as an argument.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	return config.ShellQuote(s)
+}
+
+// globQuote backslash-escapes every character of pattern except the glob
+// metacharacters, so the remote shell expands the glob but nothing else.
+func globQuote(pattern string) string {
+	var sb strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*', '?', '[', ']':
+		default:
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// shellJoin quotes and joins a command line for the remote shell.
+func shellJoin(cmd string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ReadFile reads the named file on the remote machine.
+func (c *SSHConnection) ReadFile(path string) ([]byte, error) {
+	p := shellQuote(path)
+	script := fmt.Sprintf(`[ -e %s ] || exit %d; [ -r %s ] || exit %d; cat %s`,
+		p, sshExitNotFound, p, sshExitPermission, p)
+	return c.runFile("read", path, script, nil)
+}
+
+// WriteFile writes data to the named file on the remote machine.
+// The data is streamed over stdin and written to a temp file that is
+// renamed into place, so readers never observe a partial file. A missing
+// parent directory is a NotFoundError and an unwritable one a
+// PermissionError; other failures (a full disk, say) report the remote
+// stderr. The temp file is removed if any step fails.
+func (c *SSHConnection) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	p := shellQuote(path)
+	tmp := shellQuote(path + ".gt-tmp")
+	script := fmt.Sprintf(`d=$(dirname -- %s); [ -d "$d" ] || exit %d; [ -w "$d" ] || exit %d; `+
+		`if cat > %s && chmod %o %s && mv -f %s %s; then exit 0; fi; rm -f %s; exit 1`,
+		p, sshExitNotFound, sshExitPermission,
+		tmp, perm.Perm(), tmp, tmp, p, tmp)
+	if data == nil {
+		data = []byte{}
+	}
+	_, err := c.runFile("write", path, script, data)
+	return err
+}
+
+// MkdirAll creates a directory and all parent directories.
+func (c *SSHConnection) MkdirAll(path string, perm fs.FileMode) error {
+	script := fmt.Sprintf(`mkdir -p -m %o %s 2>/dev/null || exit %d`,
+		perm.Perm(), shellQuote(path), sshExitPermission)
+	_, err := c.runFile("mkdir", path, script, nil)
+	return err
+}
+
+// Remove removes the named file or empty directory.
+// Removing a path that doesn't exist is not an error, matching LocalConnection.
+func (c *SSHConnection) Remove(path string) error {
+	p := shellQuote(path)
+	script := fmt.Sprintf(`[ -e %s ] || [ -L %s ] || exit 0; if [ -d %s ] && [ ! -L %s ]; then rmdir %s; else rm -f %s; fi 2>/dev/null || exit %d`,
+		p, p, p, p, p, p, sshExitPermission)
+	_, err := c.runFile("remove", path, script, nil)
+	return err
+}
+
+// RemoveAll removes the named file or directory and any children.
+func (c *SSHConnection) RemoveAll(path string) error {
+	script := fmt.Sprintf(`rm -rf %s 2>/dev/null || exit %d`, shellQuote(path), sshExitPermission)
+	_, err := c.runFile("remove", path, script, nil)
+	return err
+}
+
+// Stat returns file info for the named file.
+// Requires GNU or busybox stat on the remote host.
+func (c *SSHConnection) Stat(path string) (FileInfo, error) {
+	p := shellQuote(path)
+	script := fmt.Sprintf(`[ -e %s ] || exit %d; stat -L -c '%%s %%f %%Y' %s`, p, sshExitNotFound, p)
+	out, err := c.runFile("stat", path, script, nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseRemoteStat(path, strings.TrimSpace(string(out)))
+}
+
+// parseRemoteStat parses "size rawmode-hex mtime" as printed by stat -c '%s %f %Y'.
+func parseRemoteStat(path, line string) (FileInfo, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected stat output for %s: %q", path, line)
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing size for %s: %w", path, err)
+	}
+	raw, err := strconv.ParseUint(fields[1], 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mode for %s: %w", path, err)
+	}
+	mtime, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing mtime for %s: %w", path, err)
+	}
+
+	// Translate the POSIX st_mode into fs.FileMode.
+	const sIFMT, sIFDIR, sIFLNK = 0o170000, 0o040000, 0o120000
+	mode := fs.FileMode(raw & 0o777)
+	switch raw & sIFMT {
+	case sIFDIR:
+		mode |= fs.ModeDir
+	case sIFLNK:
+		mode |= fs.ModeSymlink
+	}
+
+	return BasicFileInfo{
+		FileName:    pathpkg.Base(path),
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// Glob returns the names of all files matching the pattern.
+// Only the glob metacharacters * ? [ ] are left for the remote shell to
+// expand; everything else in the pattern is escaped.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	if strings.Contains(pattern, "\n") {
+		return nil, fmt.Errorf("unsupported glob pattern: %q", pattern)
+	}
+	script := fmt.Sprintf(`for f in %s; do [ -e "$f" ] && printf '%%s\n' "$f"; done; exit 0`, globQuote(pattern))
+	stdout, stderr, code, err := c.run(script, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code != 0 {
+		return nil, fmt.Errorf("glob %s on %s: %s", pattern, c.machine.Name, strings.TrimSpace(string(stderr)))
+	}
+	matches := splitLines(string(stdout))
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Exists returns true if the path exists.
+func (c *SSHConnection) Exists(path string) (bool, error) {
+	_, _, code, err := c.run(fmt.Sprintf(`[ -e %s ]`, shellQuote(path)), nil)
+	if err != nil {
+		return false, err
+	}
+	return code == 0, nil
+}
+
+// execScript runs a command script and returns combined output,
+// with an *exec.ExitError-like error on non-zero exit.
+func (c *SSHConnection) execScript(script string) ([]byte, error) {
+	stdout, stderr, code, err := c.run(script, nil)
+	combined := append(stdout, stderr...)
+	if err != nil {
+		return combined, err
+	}
+	if code != 0 {
+		return combined, &RemoteExitError{Machine: c.machine.Name, Code: code}
+	}
+	return combined, nil
+}
+
+// Exec runs a command on the remote machine and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.execScript(shellJoin(cmd, args...))
+}
+
+// ExecDir runs a command in the specified remote directory.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.execScript("cd " + shellQuote(dir) + " && " + shellJoin(cmd, args...))
+}
+
+// ExecEnv runs a command with additional environment variables.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{"env"}
+	for _, k := range keys {
+		parts = append(parts, shellQuote(k+"="+env[k]))
+	}
+	return c.execScript(strings.Join(parts, " ") + " " + shellJoin(cmd, args...))
+}
+
+// tmux runs a tmux command on the remote host and returns trimmed stdout.
+// Errors are classified the same way the local tmux wrapper does.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	stdout, stderr, code, err := c.run(shellJoin("tmux", args...), nil)
+	if err != nil {
+		return "", err
+	}
+	if code != 0 {
+		return "", classifyTmuxError(string(stderr), args)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// TmuxNewSession creates a new detached tmux session on the remote machine.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	_, err := c.tmux(args...)
+	return err
+}
+
+// TmuxKillSession terminates a remote tmux session.
+// Like the local implementation, descendant processes of every pane are
+// signalled first so agents don't survive as orphans.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	target := shellQuote("=" + name)
+	script := fmt.Sprintf(`kill_tree() { for c in $(pgrep -P "$1" 2>/dev/null); do kill_tree "$c"; done; kill -TERM "$1" 2>/dev/null; }
+for p in $(tmux list-panes -s -t %s -F '#{pane_pid}' 2>/dev/null); do kill_tree "$p"; done
+tmux kill-session -t %s`, target, target)
+	_, stderr, code, err := c.run(script, nil)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return classifyTmuxError(string(stderr), []string{"kill-session"})
+	}
+	return nil
+}
+
+// TmuxSendKeys sends keys to a remote tmux session followed by Enter.
+// Mirrors tmux.SendKeys: literal paste, debounce, then a separate Enter.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	debounce := fmt.Sprintf("%.3f", float64(constants.DefaultDebounceMs)/1000)
+	script := shellJoin("tmux", "send-keys", "-t", session, "-l", keys) +
+		" && sleep " + debounce + " && " +
+		shellJoin("tmux", "send-keys", "-t", session, "Enter")
+	_, stderr, code, err := c.run(script, nil)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return classifyTmuxError(string(stderr), []string{"send-keys"})
+	}
+	return nil
+}
+
+// TmuxCapturePane captures the last N lines from a remote tmux pane.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	return c.tmux("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+}
+
+// TmuxHasSession returns true if the remote session exists.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, err := c.tmux("has-session", "-t", "="+name)
+	if err != nil {
+		if errors.Is(err, tmux.ErrSessionNotFound) || errors.Is(err, tmux.ErrNoServer) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all tmux session names on the remote machine.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.tmux("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if errors.Is(err, tmux.ErrNoServer) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+// RemoteExitError reports a remote command that ran but exited non-zero.
+type RemoteExitError struct {
+	Machine string
+	Code    int
+}
+
+func (e *RemoteExitError) Error() string {
+	return "remote command on " + e.Machine + " exited with status " + strconv.Itoa(e.Code)
+}
+
+// ExitCode returns the remote exit status, matching *exec.ExitError.
+func (e *RemoteExitError) ExitCode() int {
+	return e.Code
+}
+
+// classifyTmuxError maps remote tmux stderr to the tmux package's sentinel
+// errors so callers can use errors.Is regardless of connection type.
+// Kept in sync with tmux.wrapError.
+func classifyTmuxError(stderr string, args []string) error {
+	stderr = strings.TrimSpace(stderr)
+	switch {
+	case strings.Contains(stderr, "no server running"),
+		strings.Contains(stderr, "error connecting to"),
+		strings.Contains(stderr, "no current target"):
+		return tmux.ErrNoServer
+	case strings.Contains(stderr, "duplicate session"):
+		return tmux.ErrSessionExists
+	case strings.Contains(stderr, "session not found"),
+		strings.Contains(stderr, "can't find session"):
+		return tmux.ErrSessionNotFound
+	}
+	if stderr == "" {
+		stderr = "exited non-zero"
+	}
+	return fmt.Errorf("tmux %s: %s", args[0], stderr)
+}
+
+// splitLines splits output into non-empty lines.
+func splitLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
@@ -0,0 +1,258 @@
+package connection
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeSSH writes an ssh stand-in that drops client options and runs the
+// remote script through the local shell, so SSHConnection can be exercised
+// without an sshd.
+func fakeSSH(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh requires a POSIX shell")
+	}
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  if [ "$1" = "--" ]; then shift; break; fi
+  shift
+done
+exec sh -c "$1"
+`
+	path := filepath.Join(t.TempDir(), "ssh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("writing fake ssh: %v", err)
+	}
+	return path
+}
+
+func newTestSSHConnection(t *testing.T) *SSHConnection {
+	t.Helper()
+	c := NewSSHConnection(&Machine{Name: "box", Type: "ssh", Host: "gt@box"})
+	c.Binary = fakeSSH(t)
+	return c
+}
+
+func TestSSHConnectionFileOps(t *testing.T) {
+	c := newTestSSHConnection(t)
+	dir := filepath.Join(t.TempDir(), "it's a dir")
+
+	if err := c.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	path := filepath.Join(dir, "hello.txt")
+	want := "line one\n$HOME `x` 'quoted'\n"
+	if err := c.WriteFile(path, []byte(want), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, err := c.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("ReadFile = %q, want %q", got, want)
+	}
+
+	fi, err := c.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Name() != "hello.txt" || fi.Size() != int64(len(want)) || fi.IsDir() || fi.Mode().Perm() != 0600 {
+		t.Errorf("Stat = name %q size %d dir %v mode %v", fi.Name(), fi.Size(), fi.IsDir(), fi.Mode())
+	}
+
+	di, err := c.Stat(dir)
+	if err != nil {
+		t.Fatalf("Stat dir: %v", err)
+	}
+	if !di.IsDir() {
+		t.Errorf("Stat(dir).IsDir() = false")
+	}
+
+	matches, err := c.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 || matches[0] != path {
+		t.Errorf("Glob = %v, want [%s]", matches, path)
+	}
+
+	if ok, err := c.Exists(path); err != nil || !ok {
+		t.Errorf("Exists = %v, %v; want true", ok, err)
+	}
+
+	if err := c.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if ok, _ := c.Exists(path); ok {
+		t.Errorf("file still exists after Remove")
+	}
+	if err := c.Remove(path); err != nil {
+		t.Errorf("Remove of missing file should succeed, got %v", err)
+	}
+
+	if err := c.RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if ok, _ := c.Exists(dir); ok {
+		t.Errorf("dir still exists after RemoveAll")
+	}
+}
+
+func TestSSHConnectionNotFound(t *testing.T) {
+	c := newTestSSHConnection(t)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	_, err := c.ReadFile(missing)
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("ReadFile missing: got %v, want NotFoundError", err)
+	}
+
+	_, err = c.Stat(missing)
+	if !errors.As(err, &nf) {
+		t.Errorf("Stat missing: got %v, want NotFoundError", err)
+	}
+
+	err = c.WriteFile(filepath.Join(missing, "file.txt"), []byte("x"), 0644)
+	if !errors.As(err, &nf) {
+		t.Errorf("WriteFile into missing dir: got %v, want NotFoundError", err)
+	}
+}
+
+func TestSSHConnectionWriteFileFailure(t *testing.T) {
+	c := newTestSSHConnection(t)
+
+	// An mv that fails stands in for a rename error after the temp file
+	// is written
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "mv"), []byte("#!/bin/sh\necho 'mv: disk full' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	path := filepath.Join(t.TempDir(), "target")
+	err := c.WriteFile(path, []byte("data"), 0644)
+	if err == nil {
+		t.Fatal("WriteFile succeeded with a failing mv")
+	}
+	var pe *PermissionError
+	if errors.As(err, &pe) {
+		t.Errorf("WriteFile failure reported as PermissionError: %v", err)
+	}
+	if !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("WriteFile error = %v, want remote stderr", err)
+	}
+	if _, statErr := os.Stat(path + ".gt-tmp"); !os.IsNotExist(statErr) {
+		t.Errorf("temp file left behind after failed write")
+	}
+}
+
+func TestSSHConnectionExec(t *testing.T) {
+	c := newTestSSHConnection(t)
+
+	out, err := c.Exec("echo", "hello world", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "hello world it's" {
+		t.Errorf("Exec output = %q", out)
+	}
+
+	dir := t.TempDir()
+	out, err = c.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != mustEvalSymlinks(t, dir) {
+		t.Errorf("ExecDir pwd = %q, want %q", got, dir)
+	}
+
+	out, err = c.ExecEnv(map[string]string{"GT_TEST_VAR": "a b"}, "sh", "-c", "echo $GT_TEST_VAR")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "a b" {
+		t.Errorf("ExecEnv output = %q", out)
+	}
+
+	_, err = c.Exec("sh", "-c", "exit 3")
+	var exitErr *RemoteExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Exec failing command: got %v, want RemoteExitError code 3", err)
+	}
+}
+
+func TestSSHConnectionTransportError(t *testing.T) {
+	c := NewSSHConnection(&Machine{Name: "down", Type: "ssh", Host: "gt@down"})
+	path := filepath.Join(t.TempDir(), "ssh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho 'connection refused' >&2\nexit 255\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c.Binary = path
+
+	_, err := c.Exec("true")
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("got %v, want ConnectionError", err)
+	}
+	if connErr.Machine != "down" || !strings.Contains(connErr.Error(), "connection refused") {
+		t.Errorf("unexpected ConnectionError: %v", connErr)
+	}
+}
+
+func TestSSHConnectionTmuxMissingSession(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	c := newTestSSHConnection(t)
+
+	has, err := c.TmuxHasSession("gt-ssh-test-does-not-exist")
+	if err != nil {
+		t.Fatalf("TmuxHasSession: %v", err)
+	}
+	if has {
+		t.Errorf("TmuxHasSession = true for missing session")
+	}
+}
+
+func TestMachineRegistrySSHConnection(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("NewMachineRegistry: %v", err)
+	}
+	if err := registry.Add(&Machine{Name: "box", Type: "ssh", Host: "gt@box", KeyPath: "/tmp/id"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	conn, err := registry.Connection("box")
+	if err != nil {
+		t.Fatalf("Connection: %v", err)
+	}
+	if conn.IsLocal() || conn.Name() != "box" {
+		t.Errorf("Connection = %s (local=%v), want remote box", conn.Name(), conn.IsLocal())
+	}
+
+	args := conn.(*SSHConnection).sshArgs("true")
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-i /tmp/id") || !strings.HasSuffix(joined, "gt@box -- true") {
+		t.Errorf("sshArgs = %v", args)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, p string) string {
+	t.Helper()
+	r, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
@@ -72,6 +72,9 @@ const (
 	// FileAccountsJSON is the accounts configuration file in mayor/.
 	FileAccountsJSON = "accounts.json"
 
+	// FileMachinesJSON is the machine registry file in mayor/.
+	FileMachinesJSON = "machines.json"
+
 	// FileHandoffMarker is the marker file indicating a handoff just occurred.
 	// Written by gt handoff before respawn, cleared by gt prime after detection.
 	// This prevents the handoff loop bug where agents re-run /handoff from context.
@@ -254,3 +257,8 @@ func RigSettingsPath(rigPath string) string {
 func MayorAccountsPath(townRoot string) string {
 	return townRoot + "/" + DirMayor + "/" + FileAccountsJSON
 }
+
+// MayorMachinesPath returns the path to mayor/machines.json within a town root.
+func MayorMachinesPath(townRoot string) string {
+	return townRoot + "/" + DirMayor + "/" + FileMachinesJSON
+}
@@ -0,0 +1,471 @@
+package costs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Period is the window a budget cap applies to.
+type Period string
+
+const (
+	PeriodDaily  Period = "daily"
+	PeriodWeekly Period = "weekly"
+)
+
+// Level is how far over a budget spend has gone.
+type Level string
+
+const (
+	LevelSoft Level = "soft" // At or over SoftPercent of the cap: warn
+	LevelHard Level = "hard" // At or over the cap: stop dispatching
+)
+
+// Usage is spend attributed to one session.
+type Usage struct {
+	Session  string
+	Rig      string
+	Role     string
+	WorkItem string
+	CostUSD  float64
+	At       time.Time
+}
+
+// LedgerLookback is how far before a spend window to read the ledger, so a
+// session that started before the window has an earlier entry to measure
+// its in-window spend from.
+const LedgerLookback = 24 * time.Hour
+
+// UsageFromLog converts ledger entries to usage. The Stop hook records each
+// session's cumulative cost after every turn, so an entry contributes only
+// what its session spent since the session's previous entry. A session's
+// first entry counts in full, as does one whose cost dropped (a restarted
+// transcript). Entries without a session are counted as they are.
+func UsageFromLog(entries []LogEntry) []Usage {
+	sorted := append([]LogEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EndedAt.Before(sorted[j].EndedAt)
+	})
+
+	last := make(map[string]float64)
+	usage := make([]Usage, 0, len(sorted))
+	for _, e := range sorted {
+		cost := e.CostUSD
+		if e.SessionID != "" {
+			if prev, ok := last[e.SessionID]; ok && cost >= prev {
+				cost -= prev
+			}
+			last[e.SessionID] = e.CostUSD
+		}
+		if cost <= 0 {
+			continue
+		}
+		usage = append(usage, Usage{
+			Session:  e.SessionID,
+			Rig:      e.Rig,
+			Role:     e.Role,
+			WorkItem: e.WorkItem,
+			CostUSD:  cost,
+			At:       e.EndedAt,
+		})
+	}
+	return usage
+}
+
+// Unrecorded returns the part of live session usage the ledger hasn't
+// recorded yet. Live costs are cumulative per session, so a session's most
+// recent ledger entry is subtracted from its live cost.
+func Unrecorded(live []Usage, entries []LogEntry) []Usage {
+	recorded := make(map[string]LogEntry)
+	for _, e := range entries {
+		if last, ok := recorded[e.SessionID]; !ok || e.EndedAt.After(last.EndedAt) {
+			recorded[e.SessionID] = e
+		}
+	}
+
+	var usage []Usage
+	for _, u := range live {
+		if last, ok := recorded[u.Session]; ok {
+			u.CostUSD -= last.CostUSD
+			if u.WorkItem == "" {
+				u.WorkItem = last.WorkItem
+			}
+		}
+		if u.CostUSD > 0 {
+			usage = append(usage, u)
+		}
+	}
+	return usage
+}
+
+// Breach is a budget whose spend has reached its soft or hard limit.
+// Rig, Role and Convoy identify the budget; all empty is the town total.
+type Breach struct {
+	Rig      string  `json:"rig,omitempty"`
+	Role     string  `json:"role,omitempty"`
+	Convoy   string  `json:"convoy,omitempty"`
+	Period   Period  `json:"period"`
+	Level    Level   `json:"level"`
+	SpentUSD float64 `json:"spent_usd"`
+	CapUSD   float64 `json:"cap_usd"`
+}
+
+// Budget names the budget, e.g. "rig gastown" or "gastown polecat".
+func (b Breach) Budget() string {
+	switch {
+	case b.Convoy != "":
+		return "convoy " + b.Convoy
+	case b.Rig != "" && b.Role != "":
+		return b.Rig + " " + b.Role
+	case b.Rig != "":
+		return "rig " + b.Rig
+	case b.Role != "":
+		return "role " + b.Role
+	default:
+		return "town"
+	}
+}
+
+// Key identifies the breach across evaluations, for deduplicating warnings.
+func (b Breach) Key() string {
+	return fmt.Sprintf("%s/%s/%s", b.Budget(), b.Period, b.Level)
+}
+
+// String describes the breach for logs and escalations.
+func (b Breach) String() string {
+	return fmt.Sprintf("%s: $%.2f of $%.2f %s cap", b.Budget(), b.SpentUSD, b.CapUSD, b.Period)
+}
+
+// DispatchTarget describes where work is about to be sent.
+type DispatchTarget struct {
+	Rig    string
+	Role   string
+	Convoy string // Convoy tracking the work, if any
+}
+
+// Covers reports whether the breached budget applies to work sent to target.
+func (b Breach) Covers(target DispatchTarget) bool {
+	if b.Rig != "" && b.Rig != target.Rig {
+		return false
+	}
+	if b.Role != "" && b.Role != target.Role {
+		return false
+	}
+	if b.Convoy != "" && b.Convoy != target.Convoy {
+		return false
+	}
+	return true
+}
+
+// Blocking returns the hard breaches that forbid dispatching to target.
+func Blocking(breaches []Breach, target DispatchTarget) []Breach {
+	var blocking []Breach
+	for _, b := range breaches {
+		if b.Level == LevelHard && b.Covers(target) {
+			blocking = append(blocking, b)
+		}
+	}
+	return blocking
+}
+
+// Budgets is the budget configuration of a town and its rigs.
+type Budgets struct {
+	SoftPercent int
+	Town        *config.BudgetConfig
+	Rigs        map[string]*config.BudgetConfig
+}
+
+// LoadBudgets reads the budget sections of town settings and every
+// registered rig's settings.
+func LoadBudgets(townRoot string) (*Budgets, error) {
+	town, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	budgets := &Budgets{
+		SoftPercent: config.DefaultBudgetSoftPercent,
+		Town:        town.Budget,
+		Rigs:        make(map[string]*config.BudgetConfig),
+	}
+	if town.Budget != nil && town.Budget.SoftPercent > 0 {
+		budgets.SoftPercent = town.Budget.SoftPercent
+	}
+
+	rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return budgets, nil
+		}
+		return nil, fmt.Errorf("loading rigs config: %w", err)
+	}
+	for name := range rigs.Rigs {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)))
+		if err != nil {
+			if errors.Is(err, config.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("loading settings for rig %s: %w", name, err)
+		}
+		if settings.Budget != nil {
+			budgets.Rigs[name] = settings.Budget
+		}
+	}
+	return budgets, nil
+}
+
+// budget is one configured cap pair and the spend it covers.
+type budget struct {
+	scope Breach // Rig, Role and Convoy set; the rest is filled on breach
+	limit *config.BudgetLimit
+}
+
+// list flattens the configuration into individual budgets.
+func (b *Budgets) list() []budget {
+	var list []budget
+	add := func(scope Breach, limit *config.BudgetLimit) {
+		if limit != nil && (limit.DailyUSD > 0 || limit.WeeklyUSD > 0) {
+			list = append(list, budget{scope: scope, limit: limit})
+		}
+	}
+	if b.Town != nil {
+		add(Breach{}, b.Town.Total)
+		for role, limit := range b.Town.Roles {
+			add(Breach{Role: role}, limit)
+		}
+		for convoy, limit := range b.Town.Convoys {
+			add(Breach{Convoy: convoy}, limit)
+		}
+	}
+	for rig, cfg := range b.Rigs {
+		add(Breach{Rig: rig}, cfg.Total)
+		for role, limit := range cfg.Roles {
+			add(Breach{Rig: rig, Role: role}, limit)
+		}
+	}
+	return list
+}
+
+// Convoys returns the IDs of convoys with budgets.
+func (b *Budgets) Convoys() []string {
+	if b.Town == nil {
+		return nil
+	}
+	convoys := make([]string, 0, len(b.Town.Convoys))
+	for id := range b.Town.Convoys {
+		convoys = append(convoys, id)
+	}
+	sort.Strings(convoys)
+	return convoys
+}
+
+// IsEmpty reports whether no caps are configured.
+func (b *Budgets) IsEmpty() bool {
+	return len(b.list()) == 0
+}
+
+// Evaluate returns every budget whose daily or weekly spend has reached its
+// soft or hard limit. tracked maps convoy IDs to the work items they track.
+func (b *Budgets) Evaluate(usage []Usage, tracked map[string][]string, now time.Time) []Breach {
+	dayStart, weekStart := periodStarts(now)
+
+	var breaches []Breach
+	for _, bud := range b.list() {
+		var items map[string]bool
+		if bud.scope.Convoy != "" {
+			items = make(map[string]bool)
+			for _, id := range tracked[bud.scope.Convoy] {
+				items[id] = true
+			}
+		}
+
+		var daily, weekly float64
+		for _, u := range usage {
+			if bud.scope.Rig != "" && u.Rig != bud.scope.Rig {
+				continue
+			}
+			if bud.scope.Role != "" && u.Role != bud.scope.Role {
+				continue
+			}
+			if items != nil && !items[u.WorkItem] {
+				continue
+			}
+			if !u.At.Before(weekStart) {
+				weekly += u.CostUSD
+			}
+			if !u.At.Before(dayStart) {
+				daily += u.CostUSD
+			}
+		}
+
+		if breach, ok := b.check(bud.scope, PeriodDaily, daily, bud.limit.DailyUSD); ok {
+			breaches = append(breaches, breach)
+		}
+		if breach, ok := b.check(bud.scope, PeriodWeekly, weekly, bud.limit.WeeklyUSD); ok {
+			breaches = append(breaches, breach)
+		}
+	}
+
+	sort.Slice(breaches, func(i, j int) bool {
+		return breaches[i].Key() < breaches[j].Key()
+	})
+	return breaches
+}
+
+// check compares spend against a single cap.
+func (b *Budgets) check(scope Breach, period Period, spent, capUSD float64) (Breach, bool) {
+	if capUSD <= 0 {
+		return Breach{}, false
+	}
+	scope.Period = period
+	scope.SpentUSD = spent
+	scope.CapUSD = capUSD
+	switch {
+	case spent >= capUSD:
+		scope.Level = LevelHard
+	case spent >= capUSD*float64(b.SoftPercent)/100:
+		scope.Level = LevelSoft
+	default:
+		return Breach{}, false
+	}
+	return scope, true
+}
+
+// periodStarts returns the start of the local day and week (Monday) for now.
+func periodStarts(now time.Time) (day, week time.Time) {
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sinceMonday := (int(now.Weekday()) + 6) % 7
+	week = day.AddDate(0, 0, -sinceMonday)
+	return day, week
+}
+
+// Check evaluates the town's budgets against the ledger at ledgerPath plus
+// the unrecorded part of live session usage.
+func Check(townRoot, ledgerPath string, live []Usage, now time.Time) ([]Breach, error) {
+	budgets, err := LoadBudgets(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if budgets.IsEmpty() {
+		return nil, nil
+	}
+
+	_, weekStart := periodStarts(now)
+	entries, err := ReadLog(ledgerPath, weekStart.Add(-LedgerLookback))
+	if err != nil {
+		return nil, err
+	}
+	usage := append(UsageFromLog(entries), Unrecorded(live, entries)...)
+
+	tracked := make(map[string][]string)
+	for _, convoyID := range budgets.Convoys() {
+		tracked[convoyID] = trackedIssues(townRoot, convoyID)
+	}
+
+	return budgets.Evaluate(usage, tracked, now), nil
+}
+
+// trackedIssues returns the issue IDs a convoy tracks.
+func trackedIssues(townRoot, convoyID string) []string {
+	dbPath := filepath.Join(townRoot, ".beads", "beads.db")
+	safeConvoyID := strings.ReplaceAll(convoyID, "'", "''")
+	query := fmt.Sprintf(`SELECT depends_on_id FROM dependencies WHERE issue_id = '%s' AND type = 'tracks'`, safeConvoyID)
+
+	cmd := exec.Command("sqlite3", "-json", dbPath, query) //nolint:gosec // G204: query is escaped
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return nil
+	}
+
+	var deps []struct {
+		DependsOnID string `json:"depends_on_id"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &deps); err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		id := dep.DependsOnID
+		// Normalize external refs (external:<prefix>:<id>)
+		if strings.HasPrefix(id, "external:") {
+			if parts := strings.SplitN(id, ":", 3); len(parts) == 3 {
+				id = parts[2]
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// StateMaxAge is how old the budget state can be before readers stop
+// trusting it and evaluate the ledger themselves.
+const StateMaxAge = 15 * time.Minute
+
+// State is the daemon's last budget evaluation (daemon/budget-state.json).
+type State struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Breaches  []Breach  `json:"breaches,omitempty"`
+}
+
+// StateFile returns the path to the persisted budget state.
+func StateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "budget-state.json")
+}
+
+// LoadState reads the budget state. A missing file returns empty state.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("reading budget state: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing budget state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveState atomically writes the budget state.
+func SaveState(path string, state *State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, state)
+}
+
+// BlockingNow returns the hard breaches in the daemon's latest budget state
+// that cover target. Missing or stale state blocks nothing, so agents aren't
+// held back by a budget watcher that has stopped running.
+func BlockingNow(townRoot string, target DispatchTarget) []Breach {
+	state, err := LoadState(StateFile(townRoot))
+	if err != nil || state.UpdatedAt.IsZero() || time.Since(state.UpdatedAt) > StateMaxAge {
+		return nil
+	}
+	return Blocking(state.Breaches, target)
+}
+
+// CurrentBreaches returns the daemon's latest breaches, or evaluates the
+// ledger directly when the daemon hasn't written state recently.
+func CurrentBreaches(townRoot string) ([]Breach, error) {
+	state, err := LoadState(StateFile(townRoot))
+	if err == nil && !state.UpdatedAt.IsZero() && time.Since(state.UpdatedAt) <= StateMaxAge {
+		return state.Breaches, nil
+	}
+	return Check(townRoot, LogPath(), nil, time.Now())
+}
@@ -0,0 +1,191 @@
+package costs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Wednesday noon, so both the day and the week have started
+var testNow = time.Date(2026, 3, 18, 12, 0, 0, 0, time.Local)
+
+func TestEvaluate(t *testing.T) {
+	budgets := &Budgets{
+		SoftPercent: 80,
+		Town: &config.BudgetConfig{
+			Total:   &config.BudgetLimit{WeeklyUSD: 100},
+			Roles:   map[string]*config.BudgetLimit{"polecat": {DailyUSD: 50}},
+			Convoys: map[string]*config.BudgetLimit{"hq-cv-1": {DailyUSD: 10}},
+		},
+		Rigs: map[string]*config.BudgetConfig{
+			"gastown": {Total: &config.BudgetLimit{DailyUSD: 40}},
+		},
+	}
+	usage := []Usage{
+		{Rig: "gastown", Role: "polecat", WorkItem: "gt-1", CostUSD: 30, At: testNow.Add(-time.Hour)},
+		{Rig: "beads", Role: "polecat", CostUSD: 12, At: testNow.Add(-2 * time.Hour)},
+		{Rig: "beads", Role: "crew", CostUSD: 20, At: testNow.AddDate(0, 0, -2)},  // Monday
+		{Rig: "beads", Role: "crew", CostUSD: 500, At: testNow.AddDate(0, 0, -3)}, // Last week
+	}
+	tracked := map[string][]string{"hq-cv-1": {"gt-1"}}
+
+	got := map[string]Breach{}
+	for _, b := range budgets.Evaluate(usage, tracked, testNow) {
+		got[b.Budget()+" "+string(b.Period)] = b
+	}
+
+	// gastown daily is 30 of 40 (75%) and town weekly 62 of 100: both under soft
+	want := map[string]Level{
+		"convoy hq-cv-1 daily": LevelHard, // 30 of 10
+		"role polecat daily":   LevelSoft, // 42 of 50
+	}
+	for name, level := range want {
+		if got[name].Level != level {
+			t.Errorf("%s = %+v, want %s", name, got[name], level)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d breaches, want %d: %+v", len(got), len(want), got)
+	}
+}
+
+func TestBlocking(t *testing.T) {
+	breaches := []Breach{
+		{Rig: "gastown", Level: LevelHard, Period: PeriodDaily},
+		{Role: "crew", Level: LevelHard, Period: PeriodDaily},
+		{Convoy: "hq-cv-1", Level: LevelHard, Period: PeriodWeekly},
+		{Role: "polecat", Level: LevelSoft, Period: PeriodDaily},
+	}
+
+	tests := []struct {
+		target DispatchTarget
+		want   int
+	}{
+		{DispatchTarget{Rig: "gastown", Role: "polecat"}, 1},
+		{DispatchTarget{Rig: "beads", Role: "polecat"}, 0}, // Soft limits don't block
+		{DispatchTarget{Rig: "beads", Role: "crew"}, 1},
+		{DispatchTarget{Rig: "beads", Role: "polecat", Convoy: "hq-cv-1"}, 1},
+		{DispatchTarget{Rig: "gastown", Role: "crew", Convoy: "hq-cv-1"}, 3},
+	}
+	for _, tt := range tests {
+		if got := Blocking(breaches, tt.target); len(got) != tt.want {
+			t.Errorf("Blocking(%+v) = %+v, want %d", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestUsageFromLog_CumulativeSessions(t *testing.T) {
+	entries := []LogEntry{
+		{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 5, EndedAt: testNow.Add(-time.Hour)},
+		{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 2, EndedAt: testNow.Add(-2 * time.Hour)},
+		{SessionID: "gt-gastown-nux", Rig: "gastown", CostUSD: 1, EndedAt: testNow.Add(-3 * time.Hour)},
+		{SessionID: "gt-gastown-nux", Rig: "gastown", CostUSD: 1, EndedAt: testNow.Add(-90 * time.Minute)},
+		{SessionID: "gt-gastown-nux", Rig: "gastown", CostUSD: 4, EndedAt: testNow.Add(-30 * time.Minute)},
+	}
+
+	var total float64
+	for _, u := range UsageFromLog(entries) {
+		total += u.CostUSD
+	}
+	if total != 9 {
+		t.Errorf("total = $%.2f, want $9 (toast's $5 plus nux's $4, each counted once)", total)
+	}
+
+	// Summing raw entries would be $13 and trip the hard cap
+	budgets := &Budgets{
+		SoftPercent: 95,
+		Rigs:        map[string]*config.BudgetConfig{"gastown": {Total: &config.BudgetLimit{DailyUSD: 10}}},
+	}
+	if got := budgets.Evaluate(UsageFromLog(entries), nil, testNow); len(got) != 0 {
+		t.Errorf("Evaluate = %+v, want no breach for $9 of a $10 cap", got)
+	}
+}
+
+func TestUnrecorded(t *testing.T) {
+	entries := []LogEntry{
+		{SessionID: "gt-gastown-toast", CostUSD: 2, EndedAt: testNow.Add(-2 * time.Hour)},
+		{SessionID: "gt-gastown-toast", CostUSD: 5, EndedAt: testNow.Add(-time.Hour), WorkItem: "gt-1"},
+	}
+	live := []Usage{
+		{Session: "gt-gastown-toast", CostUSD: 8},
+		{Session: "gt-gastown-nux", CostUSD: 3},
+		{Session: "gt-gastown-idle", CostUSD: 0},
+	}
+
+	got := Unrecorded(live, entries)
+	if len(got) != 2 {
+		t.Fatalf("Unrecorded = %+v, want toast and nux", got)
+	}
+	if got[0].CostUSD != 3 || got[0].WorkItem != "gt-1" {
+		t.Errorf("toast = %+v, want $3 beyond last record, attributed to gt-1", got[0])
+	}
+	if got[1].CostUSD != 3 {
+		t.Errorf("nux = %+v, want all $3 unrecorded", got[1])
+	}
+}
+
+func TestCheck_ReadsTownAndRigSettings(t *testing.T) {
+	townRoot := t.TempDir()
+	writeJSON(t, config.TownSettingsPath(townRoot), map[string]interface{}{
+		"type":    "town-settings",
+		"version": 1,
+		"budget":  map[string]interface{}{"soft_percent": 50},
+	})
+	writeJSON(t, filepath.Join(townRoot, "mayor", "rigs.json"), map[string]interface{}{
+		"version": 1,
+		"rigs":    map[string]interface{}{"gastown": map[string]interface{}{}},
+	})
+	writeJSON(t, config.RigSettingsPath(filepath.Join(townRoot, "gastown")), map[string]interface{}{
+		"type":    "rig-settings",
+		"version": 1,
+		"budget":  map[string]interface{}{"total": map[string]interface{}{"daily_usd": 10}},
+	})
+
+	ledger := filepath.Join(t.TempDir(), "costs.jsonl")
+	lines := ""
+	for _, e := range []LogEntry{
+		{SessionID: "gt-gastown-toast", Rig: "gastown", Role: "polecat", CostUSD: 6, EndedAt: testNow.Add(-time.Hour)},
+		{SessionID: "gt-gastown-nux", Rig: "gastown", Role: "polecat", CostUSD: 9, EndedAt: testNow.AddDate(0, 0, -1)},
+	} {
+		data, _ := json.Marshal(e)
+		lines += string(data) + "\n"
+	}
+	if err := os.WriteFile(ledger, []byte(lines+"not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	breaches, err := Check(townRoot, ledger, nil, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breaches) != 1 || breaches[0].Rig != "gastown" || breaches[0].Level != LevelSoft || breaches[0].SpentUSD != 6 {
+		t.Fatalf("breaches = %+v, want gastown soft at $6 (town soft_percent 50)", breaches)
+	}
+
+	live := []Usage{{Session: "gt-gastown-toast", Rig: "gastown", Role: "polecat", CostUSD: 11, At: testNow}}
+	breaches, err = Check(townRoot, ledger, live, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breaches) != 1 || breaches[0].Level != LevelHard || breaches[0].SpentUSD != 11 {
+		t.Errorf("breaches = %+v, want gastown hard at $11", breaches)
+	}
+}
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
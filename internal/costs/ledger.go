@@ -0,0 +1,66 @@
+// Package costs reads the agent cost ledger and enforces spend budgets.
+package costs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogEntry represents a single entry in the costs.jsonl ledger,
+// appended by `gt costs record` from the Stop hook.
+type LogEntry struct {
+	SessionID string    `json:"session_id"`
+	Role      string    `json:"role"`
+	Rig       string    `json:"rig,omitempty"`
+	Worker    string    `json:"worker,omitempty"`
+	CostUSD   float64   `json:"cost_usd"`
+	EndedAt   time.Time `json:"ended_at"`
+	WorkItem  string    `json:"work_item,omitempty"`
+}
+
+// LogPath returns the path to the cost ledger (~/.gt/costs.jsonl).
+func LogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "/tmp/gt-costs.jsonl" // Fallback
+	}
+	return filepath.Join(home, ".gt", "costs.jsonl")
+}
+
+// ReadLog returns the ledger entries that ended at or after since.
+// Malformed lines are skipped. A missing ledger has no entries.
+func ReadLog(path string, since time.Time) ([]LogEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the ledger location, not user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening costs log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry LogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if entry.EndedAt.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading costs log: %w", err)
+	}
+	return entries, nil
+}
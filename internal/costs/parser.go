@@ -0,0 +1,174 @@
+package costs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// ErrNoTranscript is returned when a runtime has no session log for a
+// working directory.
+var ErrNoTranscript = errors.New("no transcript found")
+
+// ErrUnsupportedRuntime is returned for runtimes whose usage can't be read.
+var ErrUnsupportedRuntime = errors.New("runtime does not expose token usage")
+
+// TokenUsage aggregates a session's token usage for one model.
+// InputTokens excludes cached input, which is counted separately.
+type TokenUsage struct {
+	Model                    string
+	InputTokens              int
+	CacheCreationInputTokens int
+	CacheReadInputTokens     int
+	OutputTokens             int
+}
+
+// CostParser reads token usage from a runtime's local session logs.
+type CostParser interface {
+	// Runtime returns the agent preset the parser reads, e.g. "claude".
+	Runtime() string
+
+	// Usage returns the token usage of the most recent session run in
+	// workDir, one entry per model. Returns ErrNoTranscript if there is none.
+	Usage(workDir string) ([]TokenUsage, error)
+}
+
+// Parsers returns a parser for each supported runtime, reading session logs
+// under the given home directory.
+func Parsers(home string) []CostParser {
+	return []CostParser{
+		&ClaudeParser{Home: home},
+		&GeminiParser{Home: home},
+		&CodexParser{Home: home},
+		&OpenCodeParser{DataDir: openCodeDataDir(home)},
+	}
+}
+
+// ParserFor returns the parser for runtime, or ErrUnsupportedRuntime.
+func ParserFor(runtime, home string) (CostParser, error) {
+	for _, p := range Parsers(home) {
+		if p.Runtime() == runtime {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedRuntime, runtime)
+}
+
+// RuntimeForCommand returns the supported runtime whose preset binary is
+// command (a name or path), or "" if none is.
+func RuntimeForCommand(command string) string {
+	base := filepath.Base(command)
+	for _, p := range Parsers("") {
+		info := config.GetAgentPreset(config.AgentPreset(p.Runtime()))
+		if info != nil && filepath.Base(info.Command) == base {
+			return p.Runtime()
+		}
+	}
+	return ""
+}
+
+// SessionCost prices the most recent session runtime ran in workDir.
+func SessionCost(runtime, workDir string, pricing *PricingTable) (float64, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return 0, err
+	}
+	parser, err := ParserFor(runtime, home)
+	if err != nil {
+		return 0, err
+	}
+	usage, err := parser.Usage(workDir)
+	if err != nil {
+		return 0, err
+	}
+	return pricing.Cost(runtime, usage), nil
+}
+
+// usageByModel accumulates per-model usage in first-seen order.
+type usageByModel struct {
+	order []string
+	byKey map[string]*TokenUsage
+}
+
+func (u *usageByModel) get(model string) *TokenUsage {
+	if u.byKey == nil {
+		u.byKey = make(map[string]*TokenUsage)
+	}
+	if t, ok := u.byKey[model]; ok {
+		return t
+	}
+	t := &TokenUsage{Model: model}
+	u.byKey[model] = t
+	u.order = append(u.order, model)
+	return t
+}
+
+func (u *usageByModel) list() []TokenUsage {
+	list := make([]TokenUsage, 0, len(u.order))
+	for _, model := range u.order {
+		list = append(list, *u.byKey[model])
+	}
+	return list
+}
+
+// latestFile returns the most recently modified file directly in dir whose
+// name matches the pattern.
+func latestFile(dir, pattern string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return "", err
+	}
+	var latestPath string
+	var latestTime time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if info.ModTime().After(latestTime) {
+			latestTime = info.ModTime()
+			latestPath = path
+		}
+	}
+	if latestPath == "" {
+		return "", fmt.Errorf("%w in %s", ErrNoTranscript, dir)
+	}
+	return latestPath, nil
+}
+
+// filesNewestFirst walks root for files with the given suffix, newest first.
+func filesNewestFirst(root, suffix string) []string {
+	type file struct {
+		path string
+		mod  time.Time
+	}
+	var files []file
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if d.IsDir() || !strings.HasSuffix(path, suffix) {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files = append(files, file{path, info.ModTime()})
+		}
+		return nil
+	})
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].mod.After(files[j].mod)
+	})
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths
+}
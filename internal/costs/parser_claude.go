@@ -0,0 +1,87 @@
+package costs
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ClaudeParser reads Claude Code transcripts from
+// ~/.claude/projects/<workdir-with-dashes>/*.jsonl.
+type ClaudeParser struct {
+	Home string
+}
+
+// claudeMessage is a line of a Claude Code transcript.
+type claudeMessage struct {
+	Type    string `json:"type"`
+	Message *struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int `json:"input_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+		} `json:"usage,omitempty"`
+	} `json:"message,omitempty"`
+}
+
+// Runtime implements CostParser.
+func (p *ClaudeParser) Runtime() string { return "claude" }
+
+// ProjectDir returns the transcript directory for a working directory.
+// Claude Code replaces each / in the path with -, keeping the leading one.
+func (p *ClaudeParser) ProjectDir(workDir string) string {
+	return filepath.Join(p.Home, ".claude", "projects", strings.ReplaceAll(workDir, "/", "-"))
+}
+
+// Usage implements CostParser by summing assistant message usage in the most
+// recently modified transcript.
+func (p *ClaudeParser) Usage(workDir string) ([]TokenUsage, error) {
+	path, err := latestFile(p.ProjectDir(workDir), "*.jsonl")
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path) //nolint:gosec // G304: path is under the runtime's own log directory
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var usage usageByModel
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	// Increase buffer for potentially large JSON lines
+	scanner.Buffer(make([]byte, 0, 256*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var msg claudeMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue // Skip malformed lines
+		}
+		if msg.Type != "assistant" || msg.Message == nil || msg.Message.Usage == nil {
+			continue
+		}
+		// A response with several content blocks is logged once per block,
+		// each line repeating the same usage.
+		if id := msg.Message.ID; id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+
+		u := usage.get(msg.Message.Model)
+		u.InputTokens += msg.Message.Usage.InputTokens
+		u.CacheCreationInputTokens += msg.Message.Usage.CacheCreationInputTokens
+		u.CacheReadInputTokens += msg.Message.Usage.CacheReadInputTokens
+		u.OutputTokens += msg.Message.Usage.OutputTokens
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return usage.list(), nil
+}
@@ -0,0 +1,93 @@
+package costs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CodexParser reads Codex CLI rollouts from
+// ~/.codex/sessions/YYYY/MM/DD/rollout-*.jsonl.
+type CodexParser struct {
+	Home string
+}
+
+// codexLine is a line of a Codex rollout.
+type codexLine struct {
+	Type    string `json:"type"`
+	Payload struct {
+		Type  string `json:"type"`
+		CWD   string `json:"cwd"`
+		Model string `json:"model"`
+		Info  *struct {
+			TotalTokenUsage struct {
+				InputTokens       int `json:"input_tokens"`
+				CachedInputTokens int `json:"cached_input_tokens"`
+				OutputTokens      int `json:"output_tokens"`
+			} `json:"total_token_usage"`
+		} `json:"info,omitempty"`
+	} `json:"payload"`
+}
+
+// Runtime implements CostParser.
+func (p *CodexParser) Runtime() string { return "codex" }
+
+// Usage implements CostParser. Rollouts aren't grouped by directory, so the
+// newest rollout whose session_meta cwd is workDir is used. Codex reports
+// running totals; the last one is the session's usage.
+func (p *CodexParser) Usage(workDir string) ([]TokenUsage, error) {
+	for _, path := range filesNewestFirst(filepath.Join(p.Home, ".codex", "sessions"), ".jsonl") {
+		usage, ok, err := p.readRollout(path, workDir)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return usage, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", ErrNoTranscript, workDir)
+}
+
+// readRollout returns the rollout's usage if it ran in workDir.
+func (p *CodexParser) readRollout(path, workDir string) ([]TokenUsage, bool, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is under the runtime's own log directory
+	if err != nil {
+		return nil, false, nil
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 256*1024), 4*1024*1024)
+
+	matched := false
+	var total TokenUsage
+	for scanner.Scan() {
+		var line codexLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		switch {
+		case line.Type == "session_meta":
+			if line.Payload.CWD != workDir {
+				return nil, false, nil
+			}
+			matched = true
+		case line.Type == "turn_context" && line.Payload.Model != "":
+			total.Model = line.Payload.Model
+		case line.Type == "event_msg" && line.Payload.Type == "token_count" && line.Payload.Info != nil:
+			t := line.Payload.Info.TotalTokenUsage
+			total.InputTokens = t.InputTokens - t.CachedInputTokens
+			total.CacheReadInputTokens = t.CachedInputTokens
+			total.OutputTokens = t.OutputTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+	if !matched {
+		return nil, false, nil
+	}
+	return []TokenUsage{total}, true, nil
+}
@@ -0,0 +1,67 @@
+package costs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// GeminiParser reads Gemini CLI chat recordings from
+// ~/.gemini/tmp/<sha256(workdir)>/chats/session-*.json.
+type GeminiParser struct {
+	Home string
+}
+
+// geminiSession is a Gemini CLI chat recording.
+type geminiSession struct {
+	Messages []struct {
+		Type   string `json:"type"`
+		Model  string `json:"model"`
+		Tokens *struct {
+			Input    int `json:"input"`
+			Output   int `json:"output"`
+			Cached   int `json:"cached"`
+			Thoughts int `json:"thoughts"`
+		} `json:"tokens,omitempty"`
+	} `json:"messages"`
+}
+
+// Runtime implements CostParser.
+func (p *GeminiParser) Runtime() string { return "gemini" }
+
+// ChatsDir returns the chat recording directory for a working directory.
+func (p *GeminiParser) ChatsDir(workDir string) string {
+	sum := sha256.Sum256([]byte(workDir))
+	return filepath.Join(p.Home, ".gemini", "tmp", hex.EncodeToString(sum[:]), "chats")
+}
+
+// Usage implements CostParser. Gemini counts cached tokens inside input and
+// bills thinking tokens as output.
+func (p *GeminiParser) Usage(workDir string) ([]TokenUsage, error) {
+	path, err := latestFile(p.ChatsDir(workDir), "session-*.json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the runtime's own log directory
+	if err != nil {
+		return nil, err
+	}
+	var session geminiSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+
+	var usage usageByModel
+	for _, msg := range session.Messages {
+		if msg.Tokens == nil {
+			continue
+		}
+		u := usage.get(msg.Model)
+		u.InputTokens += msg.Tokens.Input - msg.Tokens.Cached
+		u.CacheReadInputTokens += msg.Tokens.Cached
+		u.OutputTokens += msg.Tokens.Output + msg.Tokens.Thoughts
+	}
+	return usage.list(), nil
+}
@@ -0,0 +1,97 @@
+package costs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// OpenCodeParser reads OpenCode session storage from
+// $XDG_DATA_HOME/opencode/storage (default ~/.local/share/opencode/storage).
+type OpenCodeParser struct {
+	DataDir string
+}
+
+// openCodeDataDir returns OpenCode's data directory.
+func openCodeDataDir(home string) string {
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+		return filepath.Join(xdg, "opencode")
+	}
+	return filepath.Join(home, ".local", "share", "opencode")
+}
+
+// openCodeSession is storage/session/<project>/<session>.json.
+type openCodeSession struct {
+	ID        string `json:"id"`
+	Directory string `json:"directory"`
+	Time      struct {
+		Updated int64 `json:"updated"`
+	} `json:"time"`
+}
+
+// openCodeMessage is storage/message/<session>/<message>.json.
+type openCodeMessage struct {
+	Role    string `json:"role"`
+	ModelID string `json:"modelID"`
+	Tokens  *struct {
+		Input     int `json:"input"`
+		Output    int `json:"output"`
+		Reasoning int `json:"reasoning"`
+		Cache     struct {
+			Read  int `json:"read"`
+			Write int `json:"write"`
+		} `json:"cache"`
+	} `json:"tokens,omitempty"`
+}
+
+// Runtime implements CostParser.
+func (p *OpenCodeParser) Runtime() string { return "opencode" }
+
+// Usage implements CostParser using the most recently updated session whose
+// directory is workDir. OpenCode sessions can switch models, so usage is
+// summed per model.
+func (p *OpenCodeParser) Usage(workDir string) ([]TokenUsage, error) {
+	storage := filepath.Join(p.DataDir, "storage")
+
+	var latest *openCodeSession
+	for _, path := range filesNewestFirst(filepath.Join(storage, "session"), ".json") {
+		var s openCodeSession
+		if !readJSON(path, &s) || s.Directory != workDir {
+			continue
+		}
+		if latest == nil || s.Time.Updated > latest.Time.Updated {
+			latest = &s
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("%w for %s", ErrNoTranscript, workDir)
+	}
+
+	messages, err := filepath.Glob(filepath.Join(storage, "message", latest.ID, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var usage usageByModel
+	for _, path := range messages {
+		var msg openCodeMessage
+		if !readJSON(path, &msg) || msg.Role != "assistant" || msg.Tokens == nil {
+			continue
+		}
+		u := usage.get(msg.ModelID)
+		u.InputTokens += msg.Tokens.Input
+		u.CacheReadInputTokens += msg.Tokens.Cache.Read
+		u.CacheCreationInputTokens += msg.Tokens.Cache.Write
+		u.OutputTokens += msg.Tokens.Output + msg.Tokens.Reasoning
+	}
+	return usage.list(), nil
+}
+
+// readJSON decodes a JSON file, reporting whether it succeeded.
+func readJSON(path string, v interface{}) bool {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the runtime's own log directory
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
@@ -0,0 +1,159 @@
+package costs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWorkDir = "/home/gt/gastown/polecats/toast"
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClaudeParser(t *testing.T) {
+	p := &ClaudeParser{Home: t.TempDir()}
+	dir := p.ProjectDir(testWorkDir)
+	if !strings.HasSuffix(dir, "-home-gt-gastown-polecats-toast") {
+		t.Errorf("ProjectDir = %s", dir)
+	}
+
+	writeFile(t, filepath.Join(dir, "old.jsonl"),
+		`{"type":"assistant","message":{"id":"x","model":"claude-opus-4-5","usage":{"input_tokens":999}}}`+"\n")
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old.jsonl"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "new.jsonl"), strings.Join([]string{
+		`{"type":"user","message":{"role":"user"}}`,
+		`{"type":"assistant","message":{"id":"a","model":"claude-sonnet-4-5","usage":{"input_tokens":10,"cache_creation_input_tokens":5,"cache_read_input_tokens":100,"output_tokens":20}}}`,
+		`{"type":"assistant","message":{"id":"a","model":"claude-sonnet-4-5","usage":{"input_tokens":10,"cache_creation_input_tokens":5,"cache_read_input_tokens":100,"output_tokens":20}}}`,
+		`not json`,
+		`{"type":"assistant","message":{"id":"b","model":"claude-haiku-4-5","usage":{"input_tokens":1,"output_tokens":2}}}`,
+	}, "\n"))
+
+	got, err := p.Usage(testWorkDir)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := []TokenUsage{
+		{Model: "claude-sonnet-4-5", InputTokens: 10, CacheCreationInputTokens: 5, CacheReadInputTokens: 100, OutputTokens: 20},
+		{Model: "claude-haiku-4-5", InputTokens: 1, OutputTokens: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+}
+
+func TestGeminiParser(t *testing.T) {
+	p := &GeminiParser{Home: t.TempDir()}
+	writeFile(t, filepath.Join(p.ChatsDir(testWorkDir), "session-2026-03-18T12-00-abc.json"), `{
+		"sessionId": "abc",
+		"messages": [
+			{"type": "user", "content": "hi"},
+			{"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 1000, "output": 50, "cached": 400, "thoughts": 30, "total": 1080}},
+			{"type": "gemini", "model": "gemini-2.5-pro", "tokens": {"input": 200, "output": 10, "cached": 0, "thoughts": 0, "total": 210}}
+		]
+	}`)
+
+	got, err := p.Usage(testWorkDir)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := []TokenUsage{{Model: "gemini-2.5-pro", InputTokens: 800, CacheReadInputTokens: 400, OutputTokens: 90}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+}
+
+func TestCodexParser(t *testing.T) {
+	home := t.TempDir()
+	p := &CodexParser{Home: home}
+	day := filepath.Join(home, ".codex", "sessions", "2026", "03", "18")
+
+	writeFile(t, filepath.Join(day, "rollout-other.jsonl"), strings.Join([]string{
+		`{"type":"session_meta","payload":{"cwd":"/somewhere/else"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":9999}}}}`,
+	}, "\n"))
+	writeFile(t, filepath.Join(day, "rollout-ours.jsonl"), strings.Join([]string{
+		`{"type":"session_meta","payload":{"cwd":"` + testWorkDir + `"}}`,
+		`{"type":"turn_context","payload":{"model":"gpt-5-codex"}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":100,"cached_input_tokens":40,"output_tokens":10}}}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":null}}`,
+		`{"type":"event_msg","payload":{"type":"token_count","info":{"total_token_usage":{"input_tokens":300,"cached_input_tokens":200,"output_tokens":25}}}}`,
+	}, "\n"))
+
+	got, err := p.Usage(testWorkDir)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := []TokenUsage{{Model: "gpt-5-codex", InputTokens: 100, CacheReadInputTokens: 200, OutputTokens: 25}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+
+	if _, err := p.Usage("/not/run/here"); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("Usage for unknown dir: err = %v, want ErrNoTranscript", err)
+	}
+}
+
+func TestOpenCodeParser(t *testing.T) {
+	p := &OpenCodeParser{DataDir: t.TempDir()}
+	storage := filepath.Join(p.DataDir, "storage")
+
+	writeFile(t, filepath.Join(storage, "session", "proj", "ses_old.json"),
+		`{"id":"ses_old","directory":"`+testWorkDir+`","time":{"updated":100}}`)
+	writeFile(t, filepath.Join(storage, "session", "proj", "ses_new.json"),
+		`{"id":"ses_new","directory":"`+testWorkDir+`","time":{"updated":200}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_old", "msg_1.json"),
+		`{"role":"assistant","modelID":"claude-sonnet-4","tokens":{"input":999}}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_1.json"),
+		`{"role":"user"}`)
+	writeFile(t, filepath.Join(storage, "message", "ses_new", "msg_2.json"),
+		`{"role":"assistant","modelID":"claude-sonnet-4","tokens":{"input":10,"output":5,"reasoning":3,"cache":{"read":100,"write":7}}}`)
+
+	got, err := p.Usage(testWorkDir)
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	want := []TokenUsage{{Model: "claude-sonnet-4", InputTokens: 10, CacheReadInputTokens: 100, CacheCreationInputTokens: 7, OutputTokens: 8}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Usage = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsers_NoTranscript(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "")
+	for _, p := range Parsers(t.TempDir()) {
+		if _, err := p.Usage(testWorkDir); !errors.Is(err, ErrNoTranscript) {
+			t.Errorf("%s: err = %v, want ErrNoTranscript", p.Runtime(), err)
+		}
+	}
+}
+
+func TestRuntimeForCommand(t *testing.T) {
+	tests := map[string]string{
+		"claude":                  "claude",
+		"/usr/local/bin/gemini":   "gemini",
+		"codex":                   "codex",
+		"opencode":                "opencode",
+		"cursor-agent":            "",
+		"/opt/custom/claude-wrap": "",
+	}
+	for command, want := range tests {
+		if got := RuntimeForCommand(command); got != want {
+			t.Errorf("RuntimeForCommand(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
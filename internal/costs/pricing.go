@@ -0,0 +1,111 @@
+package costs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed pricing.json
+var defaultPricingJSON []byte
+
+// ModelPrice is a model's price in USD per million tokens.
+type ModelPrice struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cache_read,omitempty"`
+	CacheWrite float64 `json:"cache_write,omitempty"`
+}
+
+// PricingTable maps models to prices.
+//
+// Keys in Models match a model exactly or as a prefix, so "claude-sonnet-4"
+// prices "claude-sonnet-4-20250514"; the longest matching key wins. Models
+// with no match fall back to Defaults for their runtime, then Defaults["default"].
+type PricingTable struct {
+	Models   map[string]ModelPrice `json:"models"`
+	Defaults map[string]ModelPrice `json:"defaults,omitempty"`
+}
+
+// PricingFile returns the path to the town's pricing overrides.
+func PricingFile(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "pricing.json")
+}
+
+// DefaultPricing returns the built-in pricing table.
+func DefaultPricing() *PricingTable {
+	var table PricingTable
+	if err := json.Unmarshal(defaultPricingJSON, &table); err != nil {
+		panic(fmt.Sprintf("parsing built-in pricing: %v", err)) // Embedded at build time
+	}
+	return &table
+}
+
+// LoadPricing returns the built-in pricing table with the town's
+// settings/pricing.json applied on top. Entries in the file replace built-in
+// entries of the same name. townRoot may be empty to use built-ins only.
+func LoadPricing(townRoot string) (*PricingTable, error) {
+	table := DefaultPricing()
+	if townRoot == "" {
+		return table, nil
+	}
+
+	path := PricingFile(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return table, nil
+		}
+		return nil, fmt.Errorf("reading pricing: %w", err)
+	}
+
+	var override PricingTable
+	if err := json.Unmarshal(data, &override); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for model, price := range override.Models {
+		table.Models[model] = price
+	}
+	for runtime, price := range override.Defaults {
+		table.Defaults[runtime] = price
+	}
+	return table, nil
+}
+
+// Lookup returns the price for a model run by runtime.
+func (p *PricingTable) Lookup(runtime, model string) ModelPrice {
+	if price, ok := p.Models[model]; ok {
+		return price
+	}
+
+	best := ""
+	for key := range p.Models {
+		if strings.HasPrefix(model, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best != "" {
+		return p.Models[best]
+	}
+
+	if price, ok := p.Defaults[runtime]; ok {
+		return price
+	}
+	return p.Defaults["default"]
+}
+
+// Cost converts token usage to USD.
+func (p *PricingTable) Cost(runtime string, usage []TokenUsage) float64 {
+	var total float64
+	for _, u := range usage {
+		price := p.Lookup(runtime, u.Model)
+		total += float64(u.InputTokens) / 1_000_000 * price.Input
+		total += float64(u.CacheReadInputTokens) / 1_000_000 * price.CacheRead
+		total += float64(u.CacheCreationInputTokens) / 1_000_000 * price.CacheWrite
+		total += float64(u.OutputTokens) / 1_000_000 * price.Output
+	}
+	return total
+}
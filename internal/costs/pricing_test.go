@@ -0,0 +1,80 @@
+package costs
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	table := DefaultPricing()
+
+	tests := []struct {
+		runtime, model string
+		wantInput      float64
+	}{
+		{"claude", "claude-opus-4-5-20251101", 15},
+		{"claude", "claude-sonnet-4-20250514", 3},
+		{"gemini", "gemini-2.5-flash-lite", 0.10}, // Longer prefix beats gemini-2.5-flash
+		{"gemini", "gemini-2.5-flash-001", 0.30},
+		{"codex", "gpt-5-mini-2025-08-07", 0.25},
+		{"gemini", "gemini-9-ultra", 1.25}, // Runtime default
+		{"opencode", "mystery-model", 3},   // Global default
+	}
+	for _, tt := range tests {
+		if got := table.Lookup(tt.runtime, tt.model).Input; got != tt.wantInput {
+			t.Errorf("Lookup(%q, %q).Input = %v, want %v", tt.runtime, tt.model, got, tt.wantInput)
+		}
+	}
+}
+
+func TestLoadPricing_Overrides(t *testing.T) {
+	townRoot := t.TempDir()
+	writeJSON(t, PricingFile(townRoot), PricingTable{
+		Models:   map[string]ModelPrice{"claude-sonnet-4": {Input: 2, Output: 10}, "local-llama": {}},
+		Defaults: map[string]ModelPrice{"codex": {Input: 1, Output: 1}},
+	})
+
+	table, err := LoadPricing(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPricing: %v", err)
+	}
+	if got := table.Lookup("claude", "claude-sonnet-4-5").Input; got != 2 {
+		t.Errorf("overridden model input = %v, want 2", got)
+	}
+	if got := table.Lookup("claude", "claude-opus-4-1").Input; got != 15 {
+		t.Errorf("built-in model input = %v, want 15", got)
+	}
+	if got := table.Lookup("opencode", "local-llama-70b").Output; got != 0 {
+		t.Errorf("added model output = %v, want 0", got)
+	}
+	if got := table.Lookup("codex", "unknown").Input; got != 1 {
+		t.Errorf("overridden default input = %v, want 1", got)
+	}
+
+	// Missing file means built-ins
+	table, err = LoadPricing(filepath.Join(townRoot, "elsewhere"))
+	if err != nil {
+		t.Fatalf("LoadPricing without file: %v", err)
+	}
+	if got := table.Lookup("claude", "claude-sonnet-4-5").Input; got != 3 {
+		t.Errorf("built-in input = %v, want 3", got)
+	}
+}
+
+func TestCost(t *testing.T) {
+	table := &PricingTable{Models: map[string]ModelPrice{
+		"m": {Input: 1, Output: 2, CacheRead: 0.5, CacheWrite: 4},
+	}}
+	usage := []TokenUsage{{
+		Model:                    "m",
+		InputTokens:              1_000_000,
+		CacheReadInputTokens:     2_000_000,
+		CacheCreationInputTokens: 500_000,
+		OutputTokens:             250_000,
+	}}
+	// 1 + 1 + 2 + 0.5
+	if got := table.Cost("claude", usage); math.Abs(got-4.5) > 1e-9 {
+		t.Errorf("Cost = %v, want 4.5", got)
+	}
+}
@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/monitoring"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// defaultAgentMonitorInterval is how often agent panes are sampled when
+// patrols.agent_monitor.interval isn't set in mayor/daemon.json.
+const defaultAgentMonitorInterval = 30 * time.Second
+
+// agentStatusEvents maps the statuses worth announcing to their event types.
+var agentStatusEvents = map[monitoring.AgentStatus]string{
+	monitoring.StatusBlocked: events.TypeAgentBlocked,
+	monitoring.StatusError:   events.TypeAgentError,
+	monitoring.StatusIdle:    events.TypeAgentIdle,
+}
+
+// AgentMonitor samples every Gas Town session's pane on an interval and feeds
+// a monitoring.StatusTracker. Status history is persisted to
+// daemon/agent-status.json, and agents going blocked, errored or idle are
+// announced on the event feed.
+type AgentMonitor struct {
+	townRoot   string
+	interval   time.Duration
+	statusFile string
+	tracker    *monitoring.StatusTracker
+	sampler    *monitoring.Sampler
+	logger     func(format string, args ...interface{})
+	emit       func(eventType, actor string, payload map[string]interface{}) error
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewAgentMonitor creates an agent monitor reading panes from source.
+// History from a previous run is restored so restarts don't re-announce
+// agents that were already blocked or idle.
+func NewAgentMonitor(townRoot string, interval time.Duration, source monitoring.PaneSource, logger func(format string, args ...interface{})) *AgentMonitor {
+	if interval <= 0 {
+		interval = defaultAgentMonitorInterval
+	}
+
+	statusFile := monitoring.StatusFile(townRoot)
+	tracker := monitoring.NewStatusTracker()
+	if saved, err := monitoring.LoadStatusFile(statusFile); err != nil {
+		logger("Warning: agent monitor starting without history: %v", err)
+	} else {
+		tracker.Restore(saved.Agents)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &AgentMonitor{
+		townRoot:   townRoot,
+		interval:   interval,
+		statusFile: statusFile,
+		tracker:    tracker,
+		sampler:    monitoring.NewSampler(tracker, source, agentForSession),
+		logger:     logger,
+		emit:       events.LogFeed,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// agentForSession maps a tmux session name to the agent's address.
+// Non-Gas Town sessions are skipped.
+func agentForSession(sess string) (string, bool) {
+	identity, err := session.ParseSessionName(sess)
+	if err != nil {
+		return "", false
+	}
+	return identity.Address(), true
+}
+
+// Start begins the monitor goroutine.
+func (m *AgentMonitor) Start() error {
+	m.wg.Add(1)
+	go m.run()
+	return nil
+}
+
+// Stop gracefully stops the monitor.
+func (m *AgentMonitor) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// run is the main monitor loop.
+func (m *AgentMonitor) run() {
+	defer m.wg.Done()
+
+	m.sample()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.sample()
+		}
+	}
+}
+
+// sample runs one sampling pass, announces transitions and saves history.
+func (m *AgentMonitor) sample() {
+	transitions, err := m.sampler.Sample()
+	if err != nil {
+		m.logger("Agent monitor: listing sessions failed: %v", err)
+		return
+	}
+
+	for _, t := range transitions {
+		eventType, ok := agentStatusEvents[t.To]
+		if !ok {
+			continue
+		}
+		m.logger("Agent monitor: %s is %s (was %s): %s", t.AgentID, t.To, t.From, t.Message)
+		_ = m.emit(eventType, "daemon",
+			events.AgentStatusPayload(t.AgentID, t.Session, string(t.From), string(t.To), t.Message))
+	}
+
+	if err := monitoring.SaveStatusFile(m.statusFile, m.tracker); err != nil {
+		m.logger("Agent monitor: saving status history failed: %v", err)
+	}
+}
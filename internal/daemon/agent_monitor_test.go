@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/monitoring"
+)
+
+type fakePaneSource map[string][]string
+
+func (f fakePaneSource) ListSessions() ([]string, error) {
+	var sessions []string
+	for s := range f {
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (f fakePaneSource) CapturePaneLines(session string, _ int) ([]string, error) {
+	return f[session], nil
+}
+
+type emitted struct {
+	eventType string
+	payload   map[string]interface{}
+}
+
+func newTestAgentMonitor(t *testing.T, townRoot string, panes fakePaneSource) (*AgentMonitor, *[]emitted) {
+	t.Helper()
+	m := NewAgentMonitor(townRoot, 0, panes, t.Logf)
+	var got []emitted
+	m.emit = func(eventType, _ string, payload map[string]interface{}) error {
+		got = append(got, emitted{eventType, payload})
+		return nil
+	}
+	return m, &got
+}
+
+func TestAgentMonitor_AnnouncesBlockedAndError(t *testing.T) {
+	townRoot := t.TempDir()
+	panes := fakePaneSource{
+		"gt-gastown-nux":     {"Editing internal/foo.go"},
+		"gt-gastown-witness": {"Running patrol"},
+		"scratch":            {"ERROR: someone else's shell"},
+	}
+	m, got := newTestAgentMonitor(t, townRoot, panes)
+
+	m.sample()
+	if len(*got) != 0 {
+		t.Fatalf("working agents announced: %+v", *got)
+	}
+
+	panes["gt-gastown-nux"] = []string{"Editing internal/foo.go", "BLOCKED: need gt-abc merged first"}
+	panes["gt-gastown-witness"] = []string{"Error: bd list failed"}
+	m.sample()
+
+	byAgent := map[string]emitted{}
+	for _, e := range *got {
+		byAgent[e.payload["agent"].(string)] = e
+	}
+	if e := byAgent["gastown/polecats/nux"]; e.eventType != events.TypeAgentBlocked || e.payload["from"] != "working" {
+		t.Errorf("nux event = %+v", e)
+	}
+	if e := byAgent["gastown/witness"]; e.eventType != events.TypeAgentError || e.payload["session"] != "gt-gastown-witness" {
+		t.Errorf("witness event = %+v", e)
+	}
+	if len(*got) != 2 {
+		t.Errorf("got %d events, want 2: %+v", len(*got), *got)
+	}
+
+	// History persisted for readers like gt workers and deacon health-check
+	file, err := monitoring.LoadStatusFile(monitoring.StatusFile(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := file.Status("gastown/polecats/nux", monitoring.DefaultIdleConfig().Timeout); status != monitoring.StatusBlocked {
+		t.Errorf("persisted nux status = %s, want blocked", status)
+	}
+	if _, ok := file.Agents["scratch"]; ok {
+		t.Error("non-Gas Town session was tracked")
+	}
+}
+
+func TestAgentMonitor_RestartDoesNotReannounce(t *testing.T) {
+	townRoot := t.TempDir()
+	panes := fakePaneSource{"gt-gastown-nux": {"BLOCKED: waiting on review"}}
+
+	first, got := newTestAgentMonitor(t, townRoot, panes)
+	first.sample()
+	if len(*got) != 1 {
+		t.Fatalf("first run announced %d events, want 1", len(*got))
+	}
+
+	second, got := newTestAgentMonitor(t, townRoot, panes)
+	second.sample()
+	if len(*got) != 0 {
+		t.Errorf("restarted monitor re-announced: %+v", *got)
+	}
+}
@@ -0,0 +1,238 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// defaultBudgetWatcherInterval is how often budgets are evaluated when
+// patrols.budget_watcher.interval isn't set in mayor/daemon.json.
+const defaultBudgetWatcherInterval = 5 * time.Minute
+
+// budgetPausedBy marks Deacon pauses made by the budget watcher, so it only
+// lifts pauses it made itself.
+const budgetPausedBy = "budget"
+
+// BudgetWatcher evaluates the spend caps in town and rig settings against the
+// cost ledger and live session usage. Budgets reaching their soft limit are
+// escalated. The result is saved to daemon/budget-state.json, and a hard
+// limit pauses only what the budget covers: gt sling refuses to dispatch into
+// a capped rig, role or convoy, the daemon stops auto-starting agents in a
+// capped rig or role, and the plugin scheduler holds plugins whose dogs are
+// capped. The Deacon itself is paused only by caps that cover it, the town
+// total or the deacon role, until spend is back under them.
+type BudgetWatcher struct {
+	townRoot   string
+	interval   time.Duration
+	ledgerPath string
+	stateFile  string
+	logger     func(format string, args ...interface{})
+	live       func() ([]costs.Usage, error)
+	escalate   func(b costs.Breach) error
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewBudgetWatcher creates a budget watcher for the town.
+func NewBudgetWatcher(townRoot string, interval time.Duration, logger func(format string, args ...interface{})) *BudgetWatcher {
+	if interval <= 0 {
+		interval = defaultBudgetWatcherInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &BudgetWatcher{
+		townRoot:   townRoot,
+		interval:   interval,
+		ledgerPath: costs.LogPath(),
+		stateFile:  costs.StateFile(townRoot),
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	w.live = w.liveUsage
+	w.escalate = w.escalateBreach
+	return w
+}
+
+// Start begins the watcher goroutine.
+func (w *BudgetWatcher) Start() error {
+	w.wg.Add(1)
+	go w.run()
+	return nil
+}
+
+// Stop gracefully stops the watcher.
+func (w *BudgetWatcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// run is the main watcher loop.
+func (w *BudgetWatcher) run() {
+	defer w.wg.Done()
+
+	w.check()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check evaluates budgets once, announces new breaches, pauses or resumes
+// the Deacon, and saves the state.
+func (w *BudgetWatcher) check() {
+	live, err := w.live()
+	if err != nil {
+		w.logger("Budget watcher: live usage unavailable, using ledger only: %v", err)
+	}
+
+	breaches, err := costs.Check(w.townRoot, w.ledgerPath, live, time.Now())
+	if err != nil {
+		w.logger("Budget watcher: evaluating budgets failed: %v", err)
+		return
+	}
+
+	// Announce each breach once; state from the last check survives restarts
+	announced := make(map[string]bool)
+	if prev, err := costs.LoadState(w.stateFile); err == nil {
+		for _, b := range prev.Breaches {
+			announced[b.Key()] = true
+		}
+	}
+	for _, b := range breaches {
+		if announced[b.Key()] {
+			continue
+		}
+		w.logger("Budget watcher: %s budget %s", b.Level, b)
+		if err := w.escalate(b); err != nil {
+			w.logger("Budget watcher: escalating %s failed: %v", b.Budget(), err)
+		}
+	}
+
+	w.applyPause(breaches)
+
+	state := &costs.State{UpdatedAt: time.Now().UTC(), Breaches: breaches}
+	if err := costs.SaveState(w.stateFile, state); err != nil {
+		w.logger("Budget watcher: saving state failed: %v", err)
+	}
+}
+
+// applyPause pauses the Deacon while a hard cap covering it is reached, and
+// resumes it once none are, unless someone else paused it. Caps on a rig,
+// role or convoy are enforced by the readers of the saved state instead.
+func (w *BudgetWatcher) applyPause(breaches []costs.Breach) {
+	var hard []string
+	for _, b := range costs.Blocking(breaches, costs.DispatchTarget{Role: string(session.RoleDeacon)}) {
+		hard = append(hard, b.String())
+	}
+
+	paused, state, err := deacon.IsPaused(w.townRoot)
+	if err != nil {
+		w.logger("Budget watcher: checking Deacon pause failed: %v", err)
+		return
+	}
+
+	if len(hard) > 0 {
+		if paused {
+			return
+		}
+		reason := "budget exceeded: " + strings.Join(hard, "; ")
+		if err := deacon.Pause(w.townRoot, reason, budgetPausedBy); err != nil {
+			w.logger("Budget watcher: pausing Deacon failed: %v", err)
+			return
+		}
+		w.logger("Budget watcher: paused Deacon (%s)", reason)
+		return
+	}
+
+	if paused && state != nil && state.PausedBy == budgetPausedBy {
+		if err := deacon.Resume(w.townRoot); err != nil {
+			w.logger("Budget watcher: resuming Deacon failed: %v", err)
+			return
+		}
+		w.logger("Budget watcher: spend back under the Deacon's caps, resumed Deacon")
+	}
+}
+
+// liveUsage reads running sessions' costs from `gt costs --json`.
+func (w *BudgetWatcher) liveUsage() ([]costs.Usage, error) {
+	cmd := exec.Command("gt", "costs", "--json")
+	cmd.Dir = w.townRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed struct {
+		Sessions []struct {
+			Session string  `json:"session"`
+			Role    string  `json:"role"`
+			Rig     string  `json:"rig"`
+			Cost    float64 `json:"cost_usd"`
+		} `json:"sessions"`
+	}
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing gt costs output: %w", err)
+	}
+
+	now := time.Now()
+	usage := make([]costs.Usage, 0, len(parsed.Sessions))
+	for _, s := range parsed.Sessions {
+		usage = append(usage, costs.Usage{
+			Session: s.Session,
+			Rig:     s.Rig,
+			Role:    s.Role,
+			CostUSD: s.Cost,
+			At:      now,
+		})
+	}
+	return usage, nil
+}
+
+// escalateBreach routes a breach through gt escalate. Soft limits are
+// warnings; hard limits are high severity since dispatch has stopped.
+func (w *BudgetWatcher) escalateBreach(b costs.Breach) error {
+	severity := config.SeverityMedium
+	title := "Budget warning: " + b.String()
+	reason := fmt.Sprintf("Spend on %s has reached %.0f%% of its %s cap.", b.Budget(), b.SpentUSD/b.CapUSD*100, b.Period)
+	if b.Level == costs.LevelHard {
+		severity = config.SeverityHigh
+		title = "Budget exceeded: " + b.String()
+		reason = fmt.Sprintf("Spend on %s has reached its %s cap. Dispatch into it and auto-starts of its agents are paused until the period resets or the cap is raised.", b.Budget(), b.Period)
+	}
+
+	cmd := exec.Command("gt", "escalate", title, "--severity", severity, "--reason", reason, "--source", "daemon:budget") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = w.townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// budgetPaused reports whether a hard budget cap covers an agent of role in
+// rigName, in which case the daemon doesn't auto-start it.
+func (d *Daemon) budgetPaused(rigName string, role session.Role) (bool, string) {
+	blocking := costs.BlockingNow(d.config.TownRoot, costs.DispatchTarget{Rig: rigName, Role: string(role)})
+	if len(blocking) == 0 {
+		return false, ""
+	}
+	return true, "budget exceeded: " + blocking[0].String()
+}
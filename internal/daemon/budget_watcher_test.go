@@ -0,0 +1,138 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/deacon"
+)
+
+func newTestBudgetWatcher(t *testing.T, dailyCap float64) (*BudgetWatcher, *[]costs.Usage, *[]costs.Breach) {
+	t.Helper()
+	townRoot := t.TempDir()
+
+	settings := config.NewTownSettings()
+	settings.Budget = &config.BudgetConfig{Total: &config.BudgetLimit{DailyUSD: dailyCap}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewBudgetWatcher(townRoot, 0, t.Logf)
+	w.ledgerPath = filepath.Join(townRoot, "costs.jsonl")
+
+	var live []costs.Usage
+	var escalated []costs.Breach
+	w.live = func() ([]costs.Usage, error) { return live, nil }
+	w.escalate = func(b costs.Breach) error {
+		escalated = append(escalated, b)
+		return nil
+	}
+	return w, &live, &escalated
+}
+
+func TestBudgetWatcher_PausesAndResumes(t *testing.T) {
+	w, live, escalated := newTestBudgetWatcher(t, 10)
+	usage := func(cost float64) []costs.Usage {
+		return []costs.Usage{{Session: "gt-gastown-toast", Rig: "gastown", Role: "polecat", CostUSD: cost, At: time.Now()}}
+	}
+
+	*live = usage(9)
+	w.check()
+	if len(*escalated) != 1 || (*escalated)[0].Level != costs.LevelSoft {
+		t.Fatalf("escalated = %+v, want one soft warning", *escalated)
+	}
+	if paused, _, _ := deacon.IsPaused(w.townRoot); paused {
+		t.Fatal("soft limit paused the Deacon")
+	}
+
+	// Same breach again: not re-escalated
+	w.check()
+	if len(*escalated) != 1 {
+		t.Errorf("soft warning escalated %d times", len(*escalated))
+	}
+
+	*live = usage(12)
+	w.check()
+	if len(*escalated) != 2 || (*escalated)[1].Level != costs.LevelHard {
+		t.Fatalf("escalated = %+v, want a hard breach after the warning", *escalated)
+	}
+	paused, state, _ := deacon.IsPaused(w.townRoot)
+	if !paused || state.PausedBy != budgetPausedBy {
+		t.Fatalf("Deacon pause = %v %+v, want paused by budget", paused, state)
+	}
+
+	saved, err := costs.LoadState(w.stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(costs.Blocking(saved.Breaches, costs.DispatchTarget{Rig: "gastown", Role: "polecat"})) != 1 {
+		t.Errorf("saved state %+v doesn't block dispatch", saved.Breaches)
+	}
+
+	// Cap raised: spend is under every limit again
+	settings := config.NewTownSettings()
+	settings.Budget = &config.BudgetConfig{Total: &config.BudgetLimit{DailyUSD: 100}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(w.townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	w.check()
+	if paused, _, _ := deacon.IsPaused(w.townRoot); paused {
+		t.Error("Deacon still paused after spend dropped under the cap")
+	}
+}
+
+func TestBudgetWatcher_LeavesOtherPausesAlone(t *testing.T) {
+	w, live, _ := newTestBudgetWatcher(t, 10)
+	if err := deacon.Pause(w.townRoot, "maintenance", "human"); err != nil {
+		t.Fatal(err)
+	}
+
+	*live = []costs.Usage{{Session: "gt-mayor", Role: "mayor", CostUSD: 1, At: time.Now()}}
+	w.check()
+
+	paused, state, _ := deacon.IsPaused(w.townRoot)
+	if !paused || state.PausedBy != "human" {
+		t.Errorf("Deacon pause = %v %+v, want the human pause kept", paused, state)
+	}
+}
+
+func TestBudgetWatcher_CountsLedger(t *testing.T) {
+	w, _, escalated := newTestBudgetWatcher(t, 10)
+
+	entry, _ := json.Marshal(costs.LogEntry{SessionID: "gt-gastown-nux", Rig: "gastown", Role: "polecat", CostUSD: 15, EndedAt: time.Now()})
+	if err := os.WriteFile(w.ledgerPath, append(entry, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w.check()
+	if len(*escalated) != 1 || (*escalated)[0].Level != costs.LevelHard || (*escalated)[0].SpentUSD != 15 {
+		t.Errorf("escalated = %+v, want hard breach at $15 from the ledger", *escalated)
+	}
+}
+
+func TestBudgetWatcher_RoleCapPausesOnlyThatRole(t *testing.T) {
+	w, live, _ := newTestBudgetWatcher(t, 0)
+	settings := config.NewTownSettings()
+	settings.Budget = &config.BudgetConfig{Roles: map[string]*config.BudgetLimit{"polecat": {DailyUSD: 10}}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(w.townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	*live = []costs.Usage{{Session: "gt-gastown-toast", Rig: "gastown", Role: "polecat", CostUSD: 12, At: time.Now()}}
+	w.check()
+
+	if paused, _, _ := deacon.IsPaused(w.townRoot); paused {
+		t.Error("polecat cap paused the whole town")
+	}
+	if len(costs.BlockingNow(w.townRoot, costs.DispatchTarget{Rig: "gastown", Role: "polecat"})) != 1 {
+		t.Error("polecat cap doesn't hold polecats")
+	}
+	if blocking := costs.BlockingNow(w.townRoot, costs.DispatchTarget{Rig: "gastown", Role: "witness"}); len(blocking) != 0 {
+		t.Errorf("polecat cap holds the witness: %+v", blocking)
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/steveyegge/gastown/internal/errors"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/metrics"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -46,10 +48,16 @@ type Daemon struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	curator          *feed.Curator
+	eventSink        *notify.EventSink
 	convoyWatcher    *ConvoyWatcher
+	depWatcher       *DependencyWatcher
 	doltServer       *DoltServerManager
 	krcPruner        *KRCPruner
 	mailOrchestrator *MailOrchestrator
+	agentMonitor     *AgentMonitor
+	budgetWatcher    *BudgetWatcher
+	pluginScheduler  *PluginScheduler
+	metricsRecorder  *MetricsRecorder
 
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
@@ -178,6 +186,9 @@ func (d *Daemon) Run() error {
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
+	d.startEventSink()
+	d.startPluginScheduler()
+	d.startMetricsRecorder()
 	if err := d.curator.Start(); err != nil {
 		d.logger.Printf("Warning: failed to start feed curator: %v", err)
 	} else {
@@ -192,6 +203,18 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Convoy watcher started")
 	}
 
+	// Start dependency watcher: unblocks beads when their cross-rig blockers close
+	if IsPatrolEnabled(d.patrolConfig, "dependency-watcher") {
+		d.depWatcher = NewDependencyWatcher(d.config.TownRoot, d.logger.Printf)
+		if err := d.depWatcher.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start dependency watcher: %v", err)
+		} else {
+			d.logger.Println("Dependency watcher started")
+		}
+	} else {
+		d.logger.Printf("Dependency watcher disabled in config, skipping")
+	}
+
 	// Start KRC pruner for automatic ephemeral data cleanup
 	krcPruner, err := NewKRCPruner(d.config.TownRoot, d.logger.Printf)
 	if err != nil {
@@ -219,6 +242,30 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Mail orchestrator disabled in config, skipping")
 	}
 
+	// Start agent monitor: samples panes into monitoring.StatusTracker
+	if IsPatrolEnabled(d.patrolConfig, "agent-monitor") {
+		d.agentMonitor = NewAgentMonitor(d.config.TownRoot, d.patrolInterval("agent_monitor", defaultAgentMonitorInterval), d.tmux, d.logger.Printf)
+		if err := d.agentMonitor.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start agent monitor: %v", err)
+		} else {
+			d.logger.Printf("Agent monitor started (interval %v)", d.agentMonitor.interval)
+		}
+	} else {
+		d.logger.Printf("Agent monitor disabled in config, skipping")
+	}
+
+	// Start budget watcher: enforces spend caps from town and rig settings
+	if IsPatrolEnabled(d.patrolConfig, "budget-watcher") {
+		d.budgetWatcher = NewBudgetWatcher(d.config.TownRoot, d.patrolInterval("budget_watcher", defaultBudgetWatcherInterval), d.logger.Printf)
+		if err := d.budgetWatcher.Start(); err != nil {
+			d.logger.Printf("Warning: failed to start budget watcher: %v", err)
+		} else {
+			d.logger.Printf("Budget watcher started (interval %v)", d.budgetWatcher.interval)
+		}
+	} else {
+		d.logger.Printf("Budget watcher disabled in config, skipping")
+	}
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -541,6 +588,10 @@ func (d *Daemon) ensureWitnessRunning(rigName string) {
 		d.logger.Printf("Skipping witness auto-start for %s: %s", rigName, reason)
 		return
 	}
+	if paused, reason := d.budgetPaused(rigName, session.RoleWitness); paused {
+		d.logger.Printf("Skipping witness auto-start for %s: %s", rigName, reason)
+		return
+	}
 
 	// Manager.Start() handles: zombie detection, session creation, env vars, theming,
 	// startup readiness waits, and crucially - startup/propulsion nudges (GUPP).
@@ -581,6 +632,10 @@ func (d *Daemon) ensureRefineryRunning(rigName string) {
 		d.logger.Printf("Skipping refinery auto-start for %s: %s", rigName, reason)
 		return
 	}
+	if paused, reason := d.budgetPaused(rigName, session.RoleRefinery); paused {
+		d.logger.Printf("Skipping refinery auto-start for %s: %s", rigName, reason)
+		return
+	}
 
 	// Manager.Start() handles: zombie detection, session creation, env vars, theming,
 	// WaitForClaudeReady, and crucially - startup/propulsion nudges (GUPP).
@@ -737,6 +792,118 @@ func (d *Daemon) processLifecycleRequests() {
 	d.ProcessLifecycleRequests()
 }
 
+// startEventSink forwards activity events to the webhooks in
+// settings/escalation.json that subscribe to them. Must run before the
+// curator starts.
+func (d *Daemon) startEventSink() {
+	cfg, err := config.LoadEscalationConfig(config.EscalationConfigPath(d.config.TownRoot))
+	if err != nil {
+		if !errors.Is(err, config.ErrNotFound) {
+			d.logger.Printf("Warning: failed to load escalation config for webhooks: %v", err)
+		}
+		return
+	}
+
+	sink, err := notify.NewEventSink(cfg, d.logger.Printf)
+	if err != nil {
+		d.logger.Printf("Warning: failed to configure webhook event sink: %v", err)
+		return
+	}
+	if sink == nil {
+		return
+	}
+
+	d.eventSink = sink
+	d.eventSink.Start()
+	d.curator.AddSink(d.eventSink.Handle)
+	d.logger.Println("Webhook event sink started")
+}
+
+// startPluginScheduler starts dispatching gated plugins to dogs. It
+// subscribes to the curator for event gates, so must run before the curator
+// starts.
+func (d *Daemon) startPluginScheduler() {
+	if !IsPatrolEnabled(d.patrolConfig, "plugin-scheduler") {
+		d.logger.Printf("Plugin scheduler disabled in config, skipping")
+		return
+	}
+
+	d.pluginScheduler = NewPluginScheduler(d.config.TownRoot, d.patrolInterval("plugin_scheduler", defaultPluginSchedulerInterval), d.logger.Printf)
+	d.curator.AddSink(d.pluginScheduler.HandleEvent)
+	if err := d.pluginScheduler.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start plugin scheduler: %v", err)
+		return
+	}
+	d.logger.Printf("Plugin scheduler started (interval %v)", d.pluginScheduler.interval)
+}
+
+// startMetricsRecorder starts sampling town health into the metrics store.
+// It subscribes to the curator for event counters, so must run before the
+// curator starts.
+func (d *Daemon) startMetricsRecorder() {
+	if !IsPatrolEnabled(d.patrolConfig, "metrics") {
+		d.logger.Printf("Metrics recorder disabled in config, skipping")
+		return
+	}
+
+	var retention time.Duration
+	var listen string
+	if d.patrolConfig != nil && d.patrolConfig.Patrols != nil && d.patrolConfig.Patrols.Metrics != nil {
+		cfg := d.patrolConfig.Patrols.Metrics
+		listen = cfg.Listen
+		if cfg.Retention != "" {
+			r, err := time.ParseDuration(cfg.Retention)
+			if err != nil || r <= 0 {
+				d.logger.Printf("Warning: invalid metrics retention %q, using %v", cfg.Retention, metrics.DefaultRetention)
+			} else {
+				retention = r
+			}
+		}
+	}
+
+	d.metricsRecorder = NewMetricsRecorder(d.config.TownRoot, d.patrolInterval("metrics", defaultMetricsInterval), retention, listen, d.logger.Printf)
+	d.curator.AddSink(d.metricsRecorder.HandleEvent)
+	if err := d.metricsRecorder.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start metrics recorder: %v", err)
+		return
+	}
+	if listen != "" {
+		d.logger.Printf("Metrics recorder started (interval %v, serving %s on %s)", d.metricsRecorder.interval, metrics.Path, listen)
+	} else {
+		d.logger.Printf("Metrics recorder started (interval %v)", d.metricsRecorder.interval)
+	}
+}
+
+// patrolInterval returns patrols.<name>.interval from mayor/daemon.json,
+// or def if unset or invalid.
+func (d *Daemon) patrolInterval(name string, def time.Duration) time.Duration {
+	if d.patrolConfig == nil || d.patrolConfig.Patrols == nil {
+		return def
+	}
+	var patrol *PatrolConfig
+	switch name {
+	case "agent_monitor":
+		patrol = d.patrolConfig.Patrols.AgentMonitor
+	case "budget_watcher":
+		patrol = d.patrolConfig.Patrols.BudgetWatcher
+	case "plugin_scheduler":
+		patrol = d.patrolConfig.Patrols.PluginScheduler
+	case "metrics":
+		if d.patrolConfig.Patrols.Metrics != nil {
+			patrol = &d.patrolConfig.Patrols.Metrics.PatrolConfig
+		}
+	}
+	if patrol == nil || patrol.Interval == "" {
+		return def
+	}
+	interval, err := time.ParseDuration(patrol.Interval)
+	if err != nil || interval <= 0 {
+		d.logger.Printf("Warning: invalid %s interval %q, using %v", name, patrol.Interval, def)
+		return def
+	}
+	return interval
+}
+
 // shutdown performs graceful shutdown.
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
@@ -747,12 +914,24 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Feed curator stopped")
 	}
 
+	// Stop webhook event sink (after the curator, which feeds it)
+	if d.eventSink != nil {
+		d.eventSink.Stop()
+		d.logger.Println("Webhook event sink stopped")
+	}
+
 	// Stop convoy watcher
 	if d.convoyWatcher != nil {
 		d.convoyWatcher.Stop()
 		d.logger.Println("Convoy watcher stopped")
 	}
 
+	// Stop dependency watcher
+	if d.depWatcher != nil {
+		d.depWatcher.Stop()
+		d.logger.Println("Dependency watcher stopped")
+	}
+
 	// Stop KRC pruner
 	if d.krcPruner != nil {
 		d.krcPruner.Stop()
@@ -765,6 +944,30 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Mail orchestrator stopped")
 	}
 
+	// Stop agent monitor
+	if d.agentMonitor != nil {
+		d.agentMonitor.Stop()
+		d.logger.Println("Agent monitor stopped")
+	}
+
+	// Stop budget watcher
+	if d.budgetWatcher != nil {
+		d.budgetWatcher.Stop()
+		d.logger.Println("Budget watcher stopped")
+	}
+
+	// Stop plugin scheduler
+	if d.pluginScheduler != nil {
+		d.pluginScheduler.Stop()
+		d.logger.Println("Plugin scheduler stopped")
+	}
+
+	// Stop metrics recorder
+	if d.metricsRecorder != nil {
+		d.metricsRecorder.Stop()
+		d.logger.Println("Metrics recorder stopped")
+	}
+
 	// Stop Dolt server if we're managing it
 	if d.doltServer != nil && d.doltServer.IsEnabled() && !d.doltServer.IsExternal() {
 		if err := d.doltServer.Stop(); err != nil {
@@ -1150,6 +1353,12 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 			WithContext("polecat", polecatName).
 			WithContext("reason", reason)
 	}
+	if paused, reason := d.budgetPaused(rigName, session.RolePolecat); paused {
+		return errors.Permanent("daemon.polecat-restart", fmt.Errorf("cannot restart polecat: %s", reason)).
+			WithHint("Raise the cap in settings/config.json or wait for the budget period to reset").
+			WithContext("rig", rigName).
+			WithContext("polecat", polecatName)
+	}
 
 	// Calculate rig path for agent config resolution
 	rigPath := filepath.Join(d.config.TownRoot, rigName)
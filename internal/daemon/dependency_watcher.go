@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DependencyWatcher resolves cross-rig dependencies. A bead that depends on a
+// bead in another rig carries an external reference in its own rig's
+// database (see beads.AddCrossRigDependency), which keeps it out of ready
+// work. Like the ConvoyWatcher, this follows bd activity across the town;
+// when a bead closes, the beads the town's cross-rig index lists as waiting
+// on it have the edge removed, so they become ready in their own rigs.
+// Closes missed while the daemon was down, and edges missing from the index,
+// are caught by a full sweep each time the activity stream starts, which
+// also indexes what it finds.
+type DependencyWatcher struct {
+	townRoot string
+	logger   func(format string, args ...interface{})
+	find     func(closedID string) ([]beads.CrossRigBlocked, error)
+	resolve  func(blocked beads.CrossRigBlocked) ([]string, error)
+	emit     func(eventType, actor string, payload map[string]interface{}) error
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewDependencyWatcher creates a cross-rig dependency watcher.
+func NewDependencyWatcher(townRoot string, logger func(format string, args ...interface{})) *DependencyWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &DependencyWatcher{
+		townRoot: townRoot,
+		logger:   logger,
+		find: func(closedID string) ([]beads.CrossRigBlocked, error) {
+			if closedID != "" {
+				return beads.FindCrossRigDependents(townRoot, closedID)
+			}
+			blocked, err := beads.FindCrossRigBlocked(townRoot)
+			if err == nil {
+				if err := beads.IndexCrossRigBlocked(townRoot, blocked); err != nil {
+					logger("dependency watcher: indexing cross-rig dependents: %v", err)
+				}
+			}
+			return blocked, err
+		},
+		resolve: func(blocked beads.CrossRigBlocked) ([]string, error) {
+			return beads.ResolveCrossRigBlockers(townRoot, blocked)
+		},
+		emit:   events.LogFeed,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start begins the dependency watcher goroutine.
+func (w *DependencyWatcher) Start() error {
+	w.wg.Add(1)
+	go w.run()
+	return nil
+}
+
+// Stop gracefully stops the dependency watcher.
+func (w *DependencyWatcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// run is the main watcher loop.
+func (w *DependencyWatcher) run() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		default:
+			w.sweep("")
+			if err := w.watchActivity(); err != nil {
+				w.logger("dependency watcher: bd activity error: %v, restarting in 5s", err)
+				select {
+				case <-w.ctx.Done():
+					return
+				case <-time.After(5 * time.Second):
+				}
+			}
+		}
+	}
+}
+
+// watchActivity follows bd activity until error or context cancellation.
+func (w *DependencyWatcher) watchActivity() error {
+	cmd := exec.CommandContext(w.ctx, "bd", "activity", "--follow", "--town", "--json")
+	cmd.Dir = w.townRoot
+	cmd.Env = os.Environ() // Inherit PATH to find bd executable
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errors.Transient("daemon.dependency-watcher", err).
+			WithHint("Failed to create stdout pipe. Check system resources and permissions")
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.Transient("daemon.dependency-watcher", err).
+			WithHint("Failed to start 'bd activity' command. Check bd is installed and accessible").
+			WithContext("command", "bd activity --follow")
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		w.processLine(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Transient("daemon.dependency-watcher", err).
+			WithHint("Error reading from 'bd activity' stream. The command may have failed")
+	}
+	return cmd.Wait()
+}
+
+// processLine handles one bd activity event, resolving dependents of a
+// closed bead.
+func (w *DependencyWatcher) processLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	var event bdActivityEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return // Skip malformed lines
+	}
+	if event.Type != "status" || event.NewStatus != "closed" || event.IssueID == "" {
+		return
+	}
+	w.sweep(event.IssueID)
+}
+
+// sweep resolves cross-rig edges whose blocker has closed. With a closedID
+// only beads waiting on it are looked up and touched; otherwise every closed
+// blocker in town is resolved.
+func (w *DependencyWatcher) sweep(closedID string) {
+	blocked, err := w.find(closedID)
+	if err != nil {
+		w.logger("dependency watcher: finding cross-rig blockers: %v", err)
+		return
+	}
+
+	for _, b := range blocked {
+		resolved, err := w.resolve(b)
+		for _, blocker := range resolved {
+			w.logger("dependency watcher: %s (%s) unblocked: %s closed", b.Issue.ID, b.Rig, blocker)
+			_ = w.emit(events.TypeUnblocked, "daemon", events.UnblockedPayload(b.Issue.ID, b.Rig, blocker))
+		}
+		if err != nil {
+			w.logger("dependency watcher: resolving %s: %v", b.Issue.ID, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func newTestDependencyWatcher(t *testing.T, blocked []beads.CrossRigBlocked) (*DependencyWatcher, *[]string, *[]emitted) {
+	t.Helper()
+	w := NewDependencyWatcher(t.TempDir(), t.Logf)
+	var resolvedIssues []string
+	var sent []emitted
+	w.find = func(closedID string) ([]beads.CrossRigBlocked, error) {
+		if closedID == "" {
+			return blocked, nil
+		}
+		var dependents []beads.CrossRigBlocked
+		for _, b := range blocked {
+			for _, blocker := range b.Blockers {
+				if blocker.ID == closedID {
+					dependents = append(dependents, beads.CrossRigBlocked{Issue: b.Issue, Rig: b.Rig, Blockers: []beads.CrossRigBlocker{blocker}})
+				}
+			}
+		}
+		return dependents, nil
+	}
+	w.resolve = func(b beads.CrossRigBlocked) ([]string, error) {
+		resolvedIssues = append(resolvedIssues, b.Issue.ID)
+		var ids []string
+		for _, blocker := range b.Blockers {
+			if blocker.Closed() {
+				ids = append(ids, blocker.ID)
+			}
+		}
+		return ids, nil
+	}
+	w.emit = func(eventType, actor string, payload map[string]interface{}) error {
+		sent = append(sent, emitted{eventType, payload})
+		return nil
+	}
+	return w, &resolvedIssues, &sent
+}
+
+func testBlocked() []beads.CrossRigBlocked {
+	return []beads.CrossRigBlocked{
+		{
+			Issue:    &beads.Issue{ID: "fe-login"},
+			Rig:      "frontend",
+			Blockers: []beads.CrossRigBlocker{{ID: "be-auth", Rig: "backend", Status: "closed"}},
+		},
+		{
+			Issue:    &beads.Issue{ID: "fe-nav"},
+			Rig:      "frontend",
+			Blockers: []beads.CrossRigBlocker{{ID: "be-db", Rig: "backend", Status: "closed"}},
+		},
+	}
+}
+
+func TestDependencyWatcherProcessLine(t *testing.T) {
+	w, resolved, sent := newTestDependencyWatcher(t, testBlocked())
+
+	// Not a close: ignored
+	w.processLine(`{"type":"status","issue_id":"be-auth","new_status":"in_progress"}`)
+	w.processLine(`{"type":"create","issue_id":"be-auth"}`)
+	w.processLine(`not json`)
+	if len(*resolved) != 0 {
+		t.Fatalf("resolved %v on non-close events", *resolved)
+	}
+
+	// Closing be-auth only touches the bead waiting on it
+	w.processLine(`{"type":"status","issue_id":"be-auth","new_status":"closed"}`)
+	if strings.Join(*resolved, ",") != "fe-login" {
+		t.Errorf("resolved = %v, want [fe-login]", *resolved)
+	}
+	if len(*sent) != 1 || (*sent)[0].eventType != events.TypeUnblocked {
+		t.Fatalf("emitted = %+v, want one unblocked event", *sent)
+	}
+	p := (*sent)[0].payload
+	if p["bead"] != "fe-login" || p["rig"] != "frontend" || p["blocker"] != "be-auth" {
+		t.Errorf("payload = %v", p)
+	}
+}
+
+func TestDependencyWatcherSweep(t *testing.T) {
+	blocked := testBlocked()
+	blocked = append(blocked, beads.CrossRigBlocked{
+		Issue:    &beads.Issue{ID: "fe-footer"},
+		Rig:      "frontend",
+		Blockers: []beads.CrossRigBlocker{{ID: "be-api", Rig: "backend", Status: "open"}},
+	})
+	w, resolved, sent := newTestDependencyWatcher(t, blocked)
+
+	// A full sweep catches closes missed while the daemon was down
+	w.sweep("")
+	if len(*resolved) != 3 {
+		t.Errorf("resolved = %v, want every blocked bead checked", *resolved)
+	}
+	if len(*sent) != 2 {
+		t.Errorf("emitted %d events, want 2 (be-api is still open)", len(*sent))
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/errors"
 	"github.com/steveyegge/gastown/internal/filelock"
 	"github.com/steveyegge/gastown/internal/hooks"
@@ -35,6 +38,10 @@ type MailOrchestratorConfig struct {
 
 	// EnablePriorityProcessing enables priority-based message processing.
 	EnablePriorityProcessing bool `json:"enable_priority_processing"`
+
+	// RequestRetries is how many times an unanswered request is resent
+	// before it is escalated.
+	RequestRetries int `json:"request_retries"`
 }
 
 // DefaultMailOrchestratorConfig returns default configuration.
@@ -45,6 +52,7 @@ func DefaultMailOrchestratorConfig() *MailOrchestratorConfig {
 		RetryDelay:               5 * time.Minute,
 		DeadLetterThreshold:      5,
 		EnablePriorityProcessing: true,
+		RequestRetries:           1,
 	}
 }
 
@@ -63,12 +71,20 @@ func DefaultMailOrchestratorConfig() *MailOrchestratorConfig {
 // - inbound.json  - messages pending delivery
 // - outbound.json - messages pending retry
 // - dead-letter.json - permanently failed messages
+// - deferred.json - scheduled messages (deliver_at) waiting to be sent
+// - pending-requests.json - requests (mail.Router.SendRequest) awaiting a reply
+//
+// Unlike the other queues, deferred.json and pending-requests.json are also
+// written by senders, so they are read and rewritten under their locks on
+// every pass rather than held in memory.
 //
 // Lock files are stored in daemon/mail-queues/.gastown/locks/
 type MailOrchestrator struct {
 	townRoot string
 	config   *MailOrchestratorConfig
 	router   *mail.Router
+	send     func(*mail.Message) error        // Sends released messages (router.Send)
+	escalate func(*mail.PendingRequest) error // Escalates unanswered requests
 	tmux     *tmux.Tmux
 	logger   *log.Logger
 	ctx      context.Context
@@ -100,11 +116,14 @@ func NewMailOrchestrator(townRoot string, config *MailOrchestratorConfig, logger
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
 
 	return &MailOrchestrator{
 		townRoot:        townRoot,
 		config:          config,
-		router:          mail.NewRouterWithTownRoot(townRoot, townRoot),
+		router:          router,
+		send:            router.Send,
+		escalate:        escalateUnansweredRequest(townRoot),
 		tmux:            tmux.NewTmux(),
 		logger:          logger,
 		ctx:             ctx,
@@ -125,10 +144,12 @@ func (mo *MailOrchestrator) Start() error {
 	}
 
 	// Start queue processors
-	mo.wg.Add(3)
+	mo.wg.Add(5)
 	go mo.processInboundQueue()
 	go mo.processOutboundQueue()
 	go mo.processRetryQueue()
+	go mo.processDeferredQueue()
+	go mo.processPendingRequests()
 
 	mo.logger.Println("Mail orchestrator started")
 	return nil
@@ -233,9 +254,16 @@ func (mo *MailOrchestrator) queryPriorityMessages(beadsDir string) ([]*mail.Mess
 
 	// Filter for high priority or interrupt delivery
 	var messages []*mail.Message
+	now := time.Now()
 	for _, bm := range beadsMsgs {
 		msg := bm.ToMessage()
 
+		// Drop expired messages from inboxes
+		if msg.IsExpired(now) {
+			mo.expireMessage(msg, beadsDir)
+			continue
+		}
+
 		// Check if needs orchestrated delivery
 		if mo.needsOrchestration(msg) {
 			messages = append(messages, msg)
@@ -352,7 +380,12 @@ func (mo *MailOrchestrator) deliverQueuedMessages() {
 	mo.inboundQueue = nil
 	mo.inboundMu.Unlock()
 
+	now := time.Now()
 	for _, qm := range messages {
+		if qm.Message.IsExpired(now) {
+			mo.logger.Printf("Dropped expired message %s to %s", qm.Message.ID, qm.Message.To)
+			continue
+		}
 		if err := mo.deliverMessage(qm); err != nil {
 			mo.logger.Printf("Failed to deliver message %s: %v", qm.Message.ID, err)
 			mo.handleDeliveryFailure(qm, err)
@@ -501,6 +534,206 @@ func (mo *MailOrchestrator) markDeadLetter(msg *mail.Message) error {
 	return err
 }
 
+// expireMessage closes an expired message so it leaves the recipient's inbox.
+func (mo *MailOrchestrator) expireMessage(msg *mail.Message, beadsDir string) {
+	args := []string{"close", msg.ID, "--reason=expired"}
+	if _, err := mail.RunBdCommand(args, filepath.Dir(beadsDir), beadsDir); err != nil {
+		mo.logger.Printf("Error expiring message %s: %v", msg.ID, err)
+		return
+	}
+	mo.logger.Printf("Expired message %s to %s (expired %s)",
+		msg.ID, msg.To, msg.ExpiresAt.Format(time.RFC3339))
+}
+
+// processDeferredQueue releases scheduled messages when they are due.
+func (mo *MailOrchestrator) processDeferredQueue() {
+	defer mo.wg.Done()
+
+	ticker := time.NewTicker(mo.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mo.ctx.Done():
+			return
+		case <-ticker.C:
+			mo.releaseDeferredMessages()
+		}
+	}
+}
+
+// releaseDeferredMessages sends due messages from the deferred queue.
+// Messages that expire before delivery, or fail MaxRetries times, are
+// dead-lettered. The queue stays locked while sending so that a crash can't
+// lose a release.
+func (mo *MailOrchestrator) releaseDeferredMessages() {
+	now := time.Now()
+	var dead []*QueuedMessage
+
+	err := mail.UpdateDeferred(mo.townRoot, func(queue []*mail.DeferredMessage) []*mail.DeferredMessage {
+		var keep []*mail.DeferredMessage
+		for _, dm := range queue {
+			msg := dm.Message
+			switch {
+			case msg == nil:
+				continue
+			case msg.IsExpired(now):
+				dm.Error = "expired before delivery"
+				dead = append(dead, deferredToQueued(dm))
+				continue
+			case !msg.IsDue(now):
+				keep = append(keep, dm)
+				continue
+			}
+
+			dm.Attempts++
+			dm.LastAttempt = now
+			if err := mo.send(msg); err != nil {
+				dm.Error = err.Error()
+				mo.logger.Printf("Failed to release scheduled message %s to %s: %v", msg.ID, msg.To, err)
+				if dm.Attempts >= mo.config.MaxRetries {
+					dead = append(dead, deferredToQueued(dm))
+				} else {
+					keep = append(keep, dm)
+				}
+				continue
+			}
+			mo.logger.Printf("Released scheduled message %s to %s (due %s)",
+				msg.ID, msg.To, msg.DeliverAt.Format(time.RFC3339))
+		}
+		return keep
+	})
+	if err != nil {
+		mo.logger.Printf("Error processing deferred queue: %v", err)
+		return
+	}
+
+	// Deferred messages were never created in beads, so there is no bead to
+	// label; they only go to the dead letter queue. Save it now, since they
+	// are already gone from deferred.json.
+	if len(dead) > 0 {
+		mo.deadLetterMu.Lock()
+		mo.deadLetterQueue = append(mo.deadLetterQueue, dead...)
+		path := filepath.Join(mo.townRoot, "daemon", "mail-queues", "dead-letter.json")
+		if err := mo.saveQueue(path, mo.deadLetterQueue); err != nil {
+			mo.logger.Printf("Warning: failed to save dead letter queue: %v", err)
+		}
+		mo.deadLetterMu.Unlock()
+		for _, qm := range dead {
+			mo.logger.Printf("Moved scheduled message %s to dead letter queue: %s", qm.Message.ID, qm.Error)
+		}
+	}
+}
+
+// processPendingRequests retries or escalates requests whose replies are overdue.
+func (mo *MailOrchestrator) processPendingRequests() {
+	defer mo.wg.Done()
+
+	ticker := time.NewTicker(mo.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mo.ctx.Done():
+			return
+		case <-ticker.C:
+			mo.checkPendingRequests()
+		}
+	}
+}
+
+// checkPendingRequests resends overdue requests until they have been sent
+// RequestRetries more times, then escalates them and stops tracking.
+// Sending happens outside the pending file's lock, since Router.Send reads it
+// to settle replies; results are applied afterwards by correlation ID, so a
+// reply that lands in between still wins.
+func (mo *MailOrchestrator) checkPendingRequests() {
+	pending, err := mail.LoadPendingRequests(mo.townRoot)
+	if err != nil {
+		mo.logger.Printf("Error loading pending requests: %v", err)
+		return
+	}
+
+	now := time.Now()
+	resent := make(map[string]*mail.PendingRequest)
+	escalated := make(map[string]bool)
+	for _, p := range pending {
+		if p.Message == nil || !p.IsOverdue(now) {
+			continue
+		}
+
+		if p.Attempts > mo.config.RequestRetries {
+			if err := mo.escalate(p); err != nil {
+				mo.logger.Printf("Error escalating unanswered request %s: %v", p.CorrelationID, err)
+				continue
+			}
+			escalated[p.CorrelationID] = true
+			mo.logger.Printf("Escalated unanswered request %s from %s to %s after %d attempt(s)",
+				p.CorrelationID, p.Message.From, p.Message.To, p.Attempts)
+			continue
+		}
+
+		deadline := now.Add(p.Timeout)
+		resend := *p.Message
+		resend.ReplyBy = &deadline
+		resend.DeliverAt = nil
+		if err := mo.send(&resend); err != nil {
+			// Try again next pass
+			mo.logger.Printf("Failed to resend request %s to %s: %v", p.CorrelationID, resend.To, err)
+			continue
+		}
+		resent[p.CorrelationID] = &mail.PendingRequest{Message: &resend, Deadline: deadline, Attempts: p.Attempts + 1}
+		mo.logger.Printf("Resent unanswered request %s to %s (attempt %d)", p.CorrelationID, resend.To, p.Attempts+1)
+	}
+	if len(resent) == 0 && len(escalated) == 0 {
+		return
+	}
+
+	err = mail.UpdatePendingRequests(mo.townRoot, func(queue []*mail.PendingRequest) []*mail.PendingRequest {
+		var keep []*mail.PendingRequest
+		for _, p := range queue {
+			if escalated[p.CorrelationID] {
+				continue
+			}
+			if r, ok := resent[p.CorrelationID]; ok {
+				p.Message, p.Deadline, p.Attempts = r.Message, r.Deadline, r.Attempts
+			}
+			keep = append(keep, p)
+		}
+		return keep
+	})
+	if err != nil {
+		mo.logger.Printf("Error saving pending requests: %v", err)
+	}
+}
+
+// escalateUnansweredRequest returns an escalator that routes unanswered
+// requests through gt escalate.
+func escalateUnansweredRequest(townRoot string) func(*mail.PendingRequest) error {
+	return func(p *mail.PendingRequest) error {
+		title := fmt.Sprintf("No reply from %s: %s", p.Message.To, p.Message.Subject)
+		reason := fmt.Sprintf("%s sent request %s at %s and got no reply after %d attempt(s), %s apart.",
+			p.Message.From, p.CorrelationID, p.SentAt.Format(time.RFC3339), p.Attempts, p.Timeout)
+		cmd := exec.Command("gt", "escalate", title, "--severity", config.SeverityMedium, "--reason", reason, "--source", "daemon:mail") //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+}
+
+// deferredToQueued converts a deferred queue entry for the dead letter queue.
+func deferredToQueued(dm *mail.DeferredMessage) *QueuedMessage {
+	return &QueuedMessage{
+		Message:     dm.Message,
+		Attempts:    dm.Attempts,
+		LastAttempt: dm.LastAttempt,
+		QueuedAt:    dm.QueuedAt,
+		Error:       dm.Error,
+	}
+}
+
 // processRetryQueue processes messages in retry queue.
 func (mo *MailOrchestrator) processRetryQueue() {
 	defer mo.wg.Done()
@@ -678,10 +911,15 @@ func (mo *MailOrchestrator) GetStats() *MailOrchestratorStats {
 	deadLetterCount := len(mo.deadLetterQueue)
 	mo.deadLetterMu.Unlock()
 
+	deferred, _ := mail.LoadDeferred(mo.townRoot)
+	pending, _ := mail.LoadPendingRequests(mo.townRoot)
+
 	return &MailOrchestratorStats{
 		InboundQueueSize:    inboundCount,
 		OutboundQueueSize:   outboundCount,
 		DeadLetterQueueSize: deadLetterCount,
+		DeferredQueueSize:   len(deferred),
+		PendingRequests:     len(pending),
 	}
 }
 
@@ -690,4 +928,6 @@ type MailOrchestratorStats struct {
 	InboundQueueSize    int `json:"inbound_queue_size"`
 	OutboundQueueSize   int `json:"outbound_queue_size"`
 	DeadLetterQueueSize int `json:"dead_letter_queue_size"`
-}
\ No newline at end of file
+	DeferredQueueSize   int `json:"deferred_queue_size"`
+	PendingRequests     int `json:"pending_requests"`
+}
@@ -657,3 +657,150 @@ func TestMailOrchestrator_AtomicQueueWrite(t *testing.T) {
 		}
 	}
 }
+
+func TestMailOrchestrator_ReleaseDeferred(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+
+	config := DefaultMailOrchestratorConfig()
+	config.MaxRetries = 2
+	mo := NewMailOrchestrator(tempDir, config, logger)
+
+	var sent []string
+	mo.send = func(msg *mail.Message) error {
+		if msg.To == "broken/" {
+			return fmt.Errorf("no agent found")
+		}
+		sent = append(sent, msg.ID)
+		return nil
+	}
+
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	queue := []*mail.DeferredMessage{
+		{Message: &mail.Message{ID: "due", To: "gastown/witness", DeliverAt: at(-time.Minute)}},
+		{Message: &mail.Message{ID: "later", To: "gastown/witness", DeliverAt: at(time.Hour)}},
+		{Message: &mail.Message{ID: "stale", To: "gastown/witness", DeliverAt: at(-2 * time.Hour), ExpiresAt: at(-time.Hour)}},
+		{Message: &mail.Message{ID: "broken", To: "broken/", DeliverAt: at(-time.Minute)}},
+	}
+	if err := mail.UpdateDeferred(tempDir, func([]*mail.DeferredMessage) []*mail.DeferredMessage { return queue }); err != nil {
+		t.Fatalf("UpdateDeferred: %v", err)
+	}
+
+	mo.releaseDeferredMessages()
+	if len(sent) != 1 || sent[0] != "due" {
+		t.Errorf("sent = %v, want [due]", sent)
+	}
+	remaining, err := mail.LoadDeferred(tempDir)
+	if err != nil {
+		t.Fatalf("LoadDeferred: %v", err)
+	}
+	if len(remaining) != 2 || remaining[0].Message.ID != "later" || remaining[1].Message.ID != "broken" {
+		t.Fatalf("remaining = %+v, want later and broken", remaining)
+	}
+	if remaining[1].Attempts != 1 || remaining[1].Error == "" {
+		t.Errorf("failed release not recorded: %+v", remaining[1])
+	}
+	if stats := mo.GetStats(); stats.DeadLetterQueueSize != 1 || stats.DeferredQueueSize != 2 {
+		t.Errorf("stats = %+v, want 1 dead letter (stale), 2 deferred", stats)
+	}
+
+	// Second failure reaches MaxRetries and dead-letters
+	mo.releaseDeferredMessages()
+	remaining, _ = mail.LoadDeferred(tempDir)
+	if len(remaining) != 1 || remaining[0].Message.ID != "later" {
+		t.Errorf("remaining = %+v, want only later", remaining)
+	}
+	if stats := mo.GetStats(); stats.DeadLetterQueueSize != 2 {
+		t.Errorf("dead letters = %d, want 2", stats.DeadLetterQueueSize)
+	}
+	if n := len(loadTestQueue(t, filepath.Join(tempDir, "daemon", "mail-queues", "dead-letter.json"))); n != 2 {
+		t.Errorf("persisted dead letters = %d, want 2", n)
+	}
+}
+
+func TestMailOrchestrator_CheckPendingRequests(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+
+	config := DefaultMailOrchestratorConfig()
+	config.RequestRetries = 1
+	mo := NewMailOrchestrator(tempDir, config, logger)
+
+	var resent []string
+	mo.send = func(msg *mail.Message) error {
+		resent = append(resent, msg.CorrelationID)
+		return nil
+	}
+	var escalated []string
+	mo.escalate = func(p *mail.PendingRequest) error {
+		escalated = append(escalated, p.CorrelationID)
+		return nil
+	}
+
+	now := time.Now()
+	request := func(id string, deadline time.Time) *mail.PendingRequest {
+		return &mail.PendingRequest{
+			CorrelationID: id,
+			Message:       &mail.Message{From: "gastown/witness", To: "gastown/refinery", Subject: "MERGE_READY Toast", CorrelationID: id},
+			Timeout:       time.Hour,
+			SentAt:        deadline.Add(-time.Hour),
+			Deadline:      deadline,
+			Attempts:      1,
+		}
+	}
+	queue := []*mail.PendingRequest{
+		request("req-overdue", now.Add(-time.Minute)),
+		request("req-waiting", now.Add(time.Hour)),
+	}
+	if err := mail.UpdatePendingRequests(tempDir, func([]*mail.PendingRequest) []*mail.PendingRequest { return queue }); err != nil {
+		t.Fatalf("UpdatePendingRequests: %v", err)
+	}
+
+	// First timeout resends with a fresh deadline
+	mo.checkPendingRequests()
+	if len(resent) != 1 || resent[0] != "req-overdue" || len(escalated) != 0 {
+		t.Fatalf("resent = %v, escalated = %v; want one resend", resent, escalated)
+	}
+	pending, err := mail.LoadPendingRequests(tempDir)
+	if err != nil {
+		t.Fatalf("LoadPendingRequests: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Attempts != 2 || !pending[0].Deadline.After(now) {
+		t.Fatalf("pending = %+v, want req-overdue on attempt 2 with a future deadline", pending[0])
+	}
+	if pending[0].Message.ReplyBy == nil || !pending[0].Message.ReplyBy.Equal(pending[0].Deadline) {
+		t.Errorf("resent request reply-by = %v, want %v", pending[0].Message.ReplyBy, pending[0].Deadline)
+	}
+
+	// Second timeout escalates and stops tracking
+	if err := mail.UpdatePendingRequests(tempDir, func(q []*mail.PendingRequest) []*mail.PendingRequest {
+		q[0].Deadline = now.Add(-time.Minute)
+		return q
+	}); err != nil {
+		t.Fatalf("UpdatePendingRequests: %v", err)
+	}
+	mo.checkPendingRequests()
+	if len(resent) != 1 || len(escalated) != 1 || escalated[0] != "req-overdue" {
+		t.Errorf("resent = %v, escalated = %v; want req-overdue escalated", resent, escalated)
+	}
+	if stats := mo.GetStats(); stats.PendingRequests != 1 {
+		t.Errorf("pending requests = %d, want 1 (req-waiting)", stats.PendingRequests)
+	}
+}
+
+func loadTestQueue(t *testing.T, path string) []*QueuedMessage {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	var queue []*QueuedMessage
+	if err := json.Unmarshal(data, &queue); err != nil {
+		t.Fatalf("parsing %s: %v", path, err)
+	}
+	return queue
+}
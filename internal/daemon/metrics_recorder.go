@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+// defaultMetricsInterval is how often the town is sampled when
+// patrols.metrics.interval isn't set in mayor/daemon.json.
+const defaultMetricsInterval = time.Minute
+
+// MetricsConfig configures the metrics recorder (patrols.metrics in
+// mayor/daemon.json).
+type MetricsConfig struct {
+	PatrolConfig
+
+	// Retention is how long samples are kept (e.g., "72h"). Default 7 days.
+	Retention string `json:"retention,omitempty"`
+
+	// Listen is an address to serve /metrics on (e.g., "127.0.0.1:9464").
+	// Empty disables the listener; gt dashboard serves /metrics regardless.
+	Listen string `json:"listen,omitempty"`
+}
+
+// MetricsRecorder samples town health on an interval into the rolling
+// metrics store (daemon/metrics.jsonl). Event counters - session deaths and
+// MR cycle times - are fed from the curator. With patrols.metrics.listen
+// set, the latest sample is also served at /metrics for Prometheus.
+type MetricsRecorder struct {
+	townRoot  string
+	interval  time.Duration
+	listen    string
+	store     *metrics.Store
+	collector *metrics.Collector
+	server    *http.Server
+	logger    func(format string, args ...interface{})
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewMetricsRecorder creates a metrics recorder for the town. listen is an
+// optional address to serve /metrics on.
+func NewMetricsRecorder(townRoot string, interval, retention time.Duration, listen string, logger func(format string, args ...interface{})) *MetricsRecorder {
+	if interval <= 0 {
+		interval = defaultMetricsInterval
+	}
+	collector, err := metrics.NewCollector(townRoot, costs.LogPath())
+	if err != nil {
+		logger("Warning: metrics recorder starting without saved counters: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MetricsRecorder{
+		townRoot:  townRoot,
+		interval:  interval,
+		listen:    listen,
+		store:     metrics.NewStore(metrics.StorePath(townRoot), retention),
+		collector: collector,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// HandleEvent counts events for the recorder's counters. It is a curator
+// sink.
+func (r *MetricsRecorder) HandleEvent(e *events.Event) {
+	r.collector.Observe(e)
+}
+
+// Start begins the recorder goroutine and, if configured, the /metrics
+// listener.
+func (r *MetricsRecorder) Start() error {
+	if r.listen != "" {
+		mux := http.NewServeMux()
+		mux.Handle(metrics.Path, metrics.NewHandler(r.store))
+		r.server = &http.Server{
+			Addr:              r.listen,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if err := r.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				r.logger("Metrics recorder: serving %s failed: %v", r.listen, err)
+			}
+		}()
+	}
+
+	r.wg.Add(1)
+	go r.run()
+	return nil
+}
+
+// Stop gracefully stops the recorder.
+func (r *MetricsRecorder) Stop() {
+	r.cancel()
+	if r.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = r.server.Shutdown(ctx)
+		cancel()
+	}
+	r.wg.Wait()
+}
+
+// run is the main recorder loop.
+func (r *MetricsRecorder) run() {
+	defer r.wg.Done()
+
+	r.record()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.record()
+		}
+	}
+}
+
+// record takes one sample and appends it to the store. Sources that fail
+// are logged; the rest of the sample is still recorded.
+func (r *MetricsRecorder) record() {
+	snap, err := r.collector.Collect(time.Now())
+	if err != nil {
+		r.logger("Metrics recorder: partial sample: %v", err)
+	}
+	if err := r.store.Append(snap); err != nil {
+		r.logger("Metrics recorder: saving sample failed: %v", err)
+	}
+}
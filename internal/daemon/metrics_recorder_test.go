@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/metrics"
+)
+
+func TestMetricsRecorder_Record(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewMetricsRecorder(townRoot, 0, 0, "", t.Logf)
+	if r.interval != defaultMetricsInterval {
+		t.Errorf("interval = %v, want default %v", r.interval, defaultMetricsInterval)
+	}
+
+	r.HandleEvent(&events.Event{Type: events.TypeSessionDeath, Timestamp: time.Now().UTC().Format(time.RFC3339)})
+	r.record()
+	r.record()
+
+	snaps, err := metrics.NewStore(metrics.StorePath(townRoot), 0).Read(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("recorded %d snapshots, want 2", len(snaps))
+	}
+	if v, _ := snaps[1].Value(metrics.SessionDeaths + "_total"); v != 1 {
+		t.Errorf("session deaths = %v, want 1", v)
+	}
+}
+
+func TestMetricsConfig(t *testing.T) {
+	var cfg DaemonPatrolConfig
+	data := `{"patrols": {"metrics": {"enabled": false, "interval": "5m", "retention": "72h", "listen": ":9464"}}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if IsPatrolEnabled(&cfg, "metrics") {
+		t.Error("metrics enabled, want disabled")
+	}
+	m := cfg.Patrols.Metrics
+	if m.Interval != "5m" || m.Retention != "72h" || m.Listen != ":9464" {
+		t.Errorf("metrics config = %+v", m)
+	}
+
+	d := &Daemon{patrolConfig: &cfg}
+	if got := d.patrolInterval("metrics", defaultMetricsInterval); got != 5*time.Minute {
+		t.Errorf("patrolInterval = %v, want 5m", got)
+	}
+}
@@ -0,0 +1,292 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+// defaultPluginSchedulerInterval is how often plugin gates are evaluated when
+// patrols.plugin_scheduler.interval isn't set in mayor/daemon.json. Cron
+// schedules have minute resolution.
+const defaultPluginSchedulerInterval = time.Minute
+
+// PluginScheduler evaluates the gates of town and rig plugins and dispatches
+// due plugins to an idle dog, so gated plugins run without waiting for the
+// Deacon to remember them. Event gates fire on event types read from
+// .events.jsonl by the feed curator, plus "startup" once when the scheduler
+// starts. Each dispatch is recorded as a plugin run, which is what cooldown
+// and cron gates measure from. Nothing is dispatched while the Deacon is
+// paused, and a plugin waits while a hard budget cap covers its dog.
+type PluginScheduler struct {
+	townRoot string
+	interval time.Duration
+	since    time.Time
+	logger   func(format string, args ...interface{})
+
+	lastRun  func(name string) (time.Time, error)
+	dogs     func() ([]*dog.Dog, error)
+	dispatch func(p *plugin.Plugin) (string, error)
+	record   func(rec plugin.PluginRunRecord) error
+	runCheck func(check, dir string) error
+
+	mu      sync.Mutex
+	pending map[string]bool // Event types seen since the last check
+
+	waiting map[string]bool // Plugins already logged as waiting for a dog
+	paused  bool            // Whether the Deacon pause was already logged
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPluginScheduler creates a plugin scheduler for the town.
+func NewPluginScheduler(townRoot string, interval time.Duration, logger func(format string, args ...interface{})) *PluginScheduler {
+	if interval <= 0 {
+		interval = defaultPluginSchedulerInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &PluginScheduler{
+		townRoot: townRoot,
+		interval: interval,
+		since:    time.Now(),
+		logger:   logger,
+		pending:  map[string]bool{plugin.EventStartup: true},
+		waiting:  make(map[string]bool),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	recorder := plugin.NewRecorder(townRoot)
+	s.lastRun = func(name string) (time.Time, error) {
+		run, err := recorder.GetLastRun(name)
+		if err != nil || run == nil {
+			return time.Time{}, err
+		}
+		return run.CreatedAt, nil
+	}
+	s.record = func(rec plugin.PluginRunRecord) error {
+		_, err := recorder.RecordRun(rec)
+		return err
+	}
+	s.dogs = s.listDogs
+	s.dispatch = s.dispatchToDog
+	return s
+}
+
+// HandleEvent queues an event for event gates. It is registered as a feed
+// curator sink and must not block.
+func (s *PluginScheduler) HandleEvent(e *events.Event) {
+	s.mu.Lock()
+	s.pending[e.Type] = true
+	s.mu.Unlock()
+}
+
+// Start begins the scheduler goroutine.
+func (s *PluginScheduler) Start() error {
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Stop gracefully stops the scheduler.
+func (s *PluginScheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run is the main scheduler loop.
+func (s *PluginScheduler) run() {
+	defer s.wg.Done()
+
+	s.check()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.check()
+		}
+	}
+}
+
+// check evaluates every gated plugin once and dispatches the due ones.
+func (s *PluginScheduler) check() {
+	if paused, _, err := deacon.IsPaused(s.townRoot); err == nil && paused {
+		if !s.paused {
+			s.logger("Plugin scheduler: Deacon is paused, not dispatching plugins")
+			s.paused = true
+		}
+		return
+	}
+	s.paused = false
+
+	s.mu.Lock()
+	seen := s.pending
+	s.pending = make(map[string]bool)
+	s.mu.Unlock()
+
+	plugins, err := s.discover()
+	if err != nil {
+		s.logger("Plugin scheduler: discovering plugins failed: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, p := range plugins {
+		if p.Gate == nil || p.Gate.Type == plugin.GateManual {
+			continue
+		}
+
+		lastRun, err := s.lastRun(p.Name)
+		if err != nil {
+			s.logger("Plugin scheduler: %s: reading last run failed: %v", p.Name, err)
+			continue
+		}
+		status := p.Gate.Evaluate(p.Path, plugin.GateInput{
+			Now:      now,
+			LastRun:  lastRun,
+			Since:    s.since,
+			Events:   seen,
+			RunCheck: s.runCheck,
+		})
+		if !status.Open {
+			continue
+		}
+
+		if !s.dispatchDue(p, status.Reason) && p.Gate.Type == plugin.GateEvent {
+			// Keep the event so the plugin runs once a dog frees up
+			s.HandleEvent(&events.Event{Type: p.Gate.On})
+		}
+	}
+}
+
+// dispatchDue hands a due plugin to an idle dog and records the run.
+// Returns false if it has to wait for a dog or a budget.
+func (s *PluginScheduler) dispatchDue(p *plugin.Plugin, reason string) bool {
+	if blocking := costs.BlockingNow(s.townRoot, costs.DispatchTarget{Rig: p.RigName, Role: "dog"}); len(blocking) > 0 {
+		if !s.waiting[p.Name] {
+			s.logger("Plugin scheduler: %s is due (%s) but held by budget: %s", p.Name, reason, blocking[0])
+			s.waiting[p.Name] = true
+		}
+		return false
+	}
+
+	dogs, err := s.dogs()
+	if err != nil {
+		s.logger("Plugin scheduler: listing dogs failed: %v", err)
+		return false
+	}
+	idle := false
+	for _, d := range dogs {
+		if d.State == dog.StateWorking && d.Work == "plugin:"+p.Name {
+			return true // Previous run still in progress
+		}
+		if d.State == dog.StateIdle {
+			idle = true
+		}
+	}
+	if !idle {
+		if !s.waiting[p.Name] {
+			s.logger("Plugin scheduler: %s is due (%s) but no dog is idle", p.Name, reason)
+			s.waiting[p.Name] = true
+		}
+		return false
+	}
+	delete(s.waiting, p.Name)
+
+	rec := plugin.PluginRunRecord{PluginName: p.Name, RigName: p.RigName}
+	dogName, err := s.dispatch(p)
+	if err != nil {
+		s.logger("Plugin scheduler: dispatching %s failed: %v", p.Name, err)
+		rec.Result = plugin.ResultFailure
+		rec.Body = fmt.Sprintf("Scheduled run (%s) could not be dispatched: %v", reason, err)
+	} else {
+		s.logger("Plugin scheduler: dispatched %s to dog %s (%s)", p.Name, dogName, reason)
+		rec.Result = plugin.ResultDispatched
+		rec.Body = fmt.Sprintf("Scheduled run (%s) dispatched to dog %s", reason, dogName)
+	}
+	if err := s.record(rec); err != nil {
+		s.logger("Plugin scheduler: recording %s run failed: %v", p.Name, err)
+	}
+	return true
+}
+
+// discover returns the town's plugins sorted by name.
+func (s *PluginScheduler) discover() ([]*plugin.Plugin, error) {
+	plugins, err := plugin.NewScanner(s.townRoot, s.rigNames()).DiscoverAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins, nil
+}
+
+// rigsConfig loads mayor/rigs.json, or an empty config if it's missing.
+func (s *PluginScheduler) rigsConfig() *config.RigsConfig {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(s.townRoot))
+	if err != nil {
+		return &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	return rigsConfig
+}
+
+func (s *PluginScheduler) rigNames() []string {
+	rigsConfig := s.rigsConfig()
+	names := make([]string, 0, len(rigsConfig.Rigs))
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *PluginScheduler) listDogs() ([]*dog.Dog, error) {
+	return dog.NewManager(s.townRoot, s.rigsConfig()).List()
+}
+
+// dispatchToDog runs gt dog dispatch, which assigns the plugin to an idle dog
+// and mails it the instructions. Returns the dog's name.
+func (s *PluginScheduler) dispatchToDog(p *plugin.Plugin) (string, error) {
+	args := []string{"dog", "dispatch", "--plugin", p.Name, "--json"}
+	if p.RigName != "" {
+		args = append(args, "--rig", p.RigName)
+	}
+	cmd := exec.Command("gt", args...) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = s.townRoot
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+
+	var result struct {
+		Dog string `json:"dog"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return "", fmt.Errorf("parsing gt dog dispatch output: %w", err)
+	}
+	return result.Dog, nil
+}
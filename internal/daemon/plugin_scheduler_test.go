@@ -0,0 +1,131 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/dog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/plugin"
+)
+
+type testPluginScheduler struct {
+	*PluginScheduler
+	lastRuns   map[string]time.Time
+	dogs       []*dog.Dog
+	dispatched []string
+	records    []plugin.PluginRunRecord
+}
+
+func newTestPluginScheduler(t *testing.T, plugins map[string]string) *testPluginScheduler {
+	t.Helper()
+	townRoot := t.TempDir()
+	for name, gate := range plugins {
+		dir := filepath.Join(townRoot, "plugins", name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		md := "+++\nname = \"" + name + "\"\n" + gate + "\n+++\nDo the thing.\n"
+		if err := os.WriteFile(filepath.Join(dir, "plugin.md"), []byte(md), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ts := &testPluginScheduler{
+		PluginScheduler: NewPluginScheduler(townRoot, 0, t.Logf),
+		lastRuns:        make(map[string]time.Time),
+		dogs:            []*dog.Dog{{Name: "alpha", State: dog.StateIdle}},
+	}
+	ts.lastRun = func(name string) (time.Time, error) { return ts.lastRuns[name], nil }
+	ts.PluginScheduler.dogs = func() ([]*dog.Dog, error) { return ts.dogs, nil }
+	ts.dispatch = func(p *plugin.Plugin) (string, error) {
+		ts.dispatched = append(ts.dispatched, p.Name)
+		return "alpha", nil
+	}
+	ts.record = func(rec plugin.PluginRunRecord) error {
+		ts.records = append(ts.records, rec)
+		ts.lastRuns[rec.PluginName] = time.Now()
+		return nil
+	}
+	return ts
+}
+
+func TestPluginScheduler_DispatchesDuePlugins(t *testing.T) {
+	s := newTestPluginScheduler(t, map[string]string{
+		"cooldown": "[gate]\ntype = \"cooldown\"\nduration = \"1h\"",
+		"on-start": "[gate]\ntype = \"event\"\non = \"startup\"",
+		"on-merge": "[gate]\ntype = \"event\"\non = \"merged\"",
+		"manual":   "[gate]\ntype = \"manual\"",
+		"no-gate":  "",
+	})
+
+	s.check()
+	if got := s.dispatched; len(got) != 2 || got[0] != "cooldown" || got[1] != "on-start" {
+		t.Fatalf("first check dispatched %v, want [cooldown on-start]", got)
+	}
+	for _, rec := range s.records {
+		if rec.Result != plugin.ResultDispatched {
+			t.Errorf("%s recorded as %s, want dispatched", rec.PluginName, rec.Result)
+		}
+	}
+
+	// Cooldown now active, startup already consumed; a merge arrives
+	s.dispatched = nil
+	s.HandleEvent(&events.Event{Type: events.TypeMerged})
+	s.check()
+	if got := s.dispatched; len(got) != 1 || got[0] != "on-merge" {
+		t.Fatalf("second check dispatched %v, want [on-merge]", got)
+	}
+}
+
+func TestPluginScheduler_WaitsForIdleDog(t *testing.T) {
+	s := newTestPluginScheduler(t, map[string]string{
+		"on-merge": "[gate]\ntype = \"event\"\non = \"merged\"",
+	})
+	s.dogs = []*dog.Dog{{Name: "alpha", State: dog.StateWorking, Work: "gt-abc"}}
+
+	s.HandleEvent(&events.Event{Type: events.TypeMerged})
+	s.check()
+	if len(s.dispatched) != 0 || len(s.records) != 0 {
+		t.Fatalf("dispatched %v with no idle dog", s.dispatched)
+	}
+
+	// The event is kept until a dog is free
+	s.dogs[0].State = dog.StateIdle
+	s.check()
+	if len(s.dispatched) != 1 {
+		t.Fatalf("dispatched %v once a dog was idle, want [on-merge]", s.dispatched)
+	}
+}
+
+func TestPluginScheduler_SkipsRunningPlugin(t *testing.T) {
+	s := newTestPluginScheduler(t, map[string]string{
+		"cooldown": "[gate]\ntype = \"cooldown\"\nduration = \"1m\"",
+	})
+	s.dogs = []*dog.Dog{
+		{Name: "alpha", State: dog.StateWorking, Work: "plugin:cooldown"},
+		{Name: "bravo", State: dog.StateIdle},
+	}
+
+	s.check()
+	if len(s.dispatched) != 0 {
+		t.Errorf("dispatched %v while the previous run is in progress", s.dispatched)
+	}
+}
+
+func TestPluginScheduler_IdleWhileDeaconPaused(t *testing.T) {
+	s := newTestPluginScheduler(t, map[string]string{
+		"cooldown": "[gate]\ntype = \"cooldown\"",
+	})
+	if err := deacon.Pause(s.townRoot, "test", "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.check()
+	if len(s.dispatched) != 0 {
+		t.Errorf("dispatched %v while the Deacon is paused", s.dispatched)
+	}
+}
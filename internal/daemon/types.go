@@ -101,7 +101,9 @@ type PatrolConfig struct {
 	// Enabled controls whether this patrol runs during heartbeat.
 	Enabled bool `json:"enabled"`
 
-	// Interval is how often to run this patrol (not used yet).
+	// Interval is how often to run this patrol (e.g., "30s").
+	// Honored by the agent monitor, budget watcher, plugin scheduler and
+	// metrics recorder.
 	Interval string `json:"interval,omitempty"`
 
 	// Agent is the agent type for this patrol (not used yet).
@@ -110,11 +112,16 @@ type PatrolConfig struct {
 
 // PatrolsConfig holds configuration for all patrols.
 type PatrolsConfig struct {
-	Refinery         *PatrolConfig     `json:"refinery,omitempty"`
-	Witness          *PatrolConfig     `json:"witness,omitempty"`
-	Deacon           *PatrolConfig     `json:"deacon,omitempty"`
-	DoltServer       *DoltServerConfig `json:"dolt_server,omitempty"`
-	MailOrchestrator *PatrolConfig     `json:"mail_orchestrator,omitempty"`
+	Refinery          *PatrolConfig     `json:"refinery,omitempty"`
+	Witness           *PatrolConfig     `json:"witness,omitempty"`
+	Deacon            *PatrolConfig     `json:"deacon,omitempty"`
+	DoltServer        *DoltServerConfig `json:"dolt_server,omitempty"`
+	MailOrchestrator  *PatrolConfig     `json:"mail_orchestrator,omitempty"`
+	AgentMonitor      *PatrolConfig     `json:"agent_monitor,omitempty"`
+	BudgetWatcher     *PatrolConfig     `json:"budget_watcher,omitempty"`
+	PluginScheduler   *PatrolConfig     `json:"plugin_scheduler,omitempty"`
+	Metrics           *MetricsConfig    `json:"metrics,omitempty"`
+	DependencyWatcher *PatrolConfig     `json:"dependency_watcher,omitempty"`
 }
 
 // DaemonPatrolConfig is the structure of mayor/daemon.json.
@@ -170,6 +177,26 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		if config.Patrols.MailOrchestrator != nil {
 			return config.Patrols.MailOrchestrator.Enabled
 		}
+	case "agent-monitor":
+		if config.Patrols.AgentMonitor != nil {
+			return config.Patrols.AgentMonitor.Enabled
+		}
+	case "budget-watcher":
+		if config.Patrols.BudgetWatcher != nil {
+			return config.Patrols.BudgetWatcher.Enabled
+		}
+	case "plugin-scheduler":
+		if config.Patrols.PluginScheduler != nil {
+			return config.Patrols.PluginScheduler.Enabled
+		}
+	case "metrics":
+		if config.Patrols.Metrics != nil {
+			return config.Patrols.Metrics.Enabled
+		}
+	case "dependency-watcher":
+		if config.Patrols.DependencyWatcher != nil {
+			return config.Patrols.DependencyWatcher.Enabled
+		}
 	}
 	return true // Default: enabled
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/monitoring"
 )
 
 // Default parameters for stuck-session detection.
@@ -230,3 +231,28 @@ func (s *AgentHealthState) CooldownRemaining(cooldown time.Duration) time.Durati
 func (s *AgentHealthState) ShouldForceKill(threshold int) bool {
 	return s.ConsecutiveFailures >= threshold
 }
+
+// MonitoredResponse reports whether the daemon's agent monitor saw the
+// agent's pane change within window while in a healthy status. That counts
+// as a health-check response without nudging the agent. Returns the observed
+// status and activity time for display; ok is false when the monitor isn't
+// running, hasn't seen the agent, or saw it blocked, erroring or idle.
+func MonitoredResponse(townRoot, agentID string, window time.Duration) (status monitoring.AgentStatus, lastActivity time.Time, ok bool) {
+	file, err := monitoring.LoadStatusFile(monitoring.StatusFile(townRoot))
+	if err != nil || !file.Fresh(monitoring.StatusFileMaxAge) {
+		return "", time.Time{}, false
+	}
+	activity, found := file.Agents[agentID]
+	if !found {
+		return "", time.Time{}, false
+	}
+
+	switch activity.CurrentStatus {
+	case monitoring.StatusBlocked, monitoring.StatusError, monitoring.StatusIdle:
+		return activity.CurrentStatus, activity.LastActivity, false
+	}
+	if time.Since(activity.LastActivity) > window {
+		return activity.CurrentStatus, activity.LastActivity, false
+	}
+	return activity.CurrentStatus, activity.LastActivity, true
+}
@@ -39,7 +39,7 @@ func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 
 	// All good
-	if report.Outdated == 0 && report.Missing == 0 && report.Modified == 0 && report.New == 0 && report.Untracked == 0 {
+	if report.Outdated == 0 && report.Missing == 0 && report.Modified == 0 && report.New == 0 && report.Untracked == 0 && report.Affected == 0 {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
@@ -68,6 +68,9 @@ func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
 			details = append(details, fmt.Sprintf("  %s: untracked (will update)", f.Name))
 			needsFix = true
 		}
+		if len(f.StaleDeps) > 0 {
+			details = append(details, fmt.Sprintf("  %s: depends on changed %s", f.Name, strings.Join(f.StaleDeps, ", ")))
+		}
 	}
 
 	// Determine status
@@ -93,6 +96,9 @@ func (c *FormulaCheck) Run(ctx *CheckContext) *CheckResult {
 	if report.Modified > 0 {
 		parts = append(parts, fmt.Sprintf("%d modified", report.Modified))
 	}
+	if report.Affected > 0 {
+		parts = append(parts, fmt.Sprintf("%d affected by base changes", report.Affected))
+	}
 
 	message := fmt.Sprintf("Formulas: %s", strings.Join(parts, ", "))
 
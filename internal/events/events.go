@@ -54,6 +54,11 @@ const (
 	TypeSessionDeath = "session_death" // Feed-visible session termination
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
+	// Agent status events (emitted by the daemon's agent monitor)
+	TypeAgentBlocked = "agent_blocked" // Pane output shows the agent is blocked
+	TypeAgentError   = "agent_error"   // Pane output shows an error
+	TypeAgentIdle    = "agent_idle"    // No pane output for the idle timeout
+
 	// Witness patrol events
 	TypePatrolStarted   = "patrol_started"
 	TypePolecatChecked  = "polecat_checked"
@@ -68,8 +73,23 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Convoy events
+	TypeConvoyClosed = "convoy_closed"
+
+	// Dependency events
+	TypeUnblocked = "unblocked" // A bead's cross-rig blocker closed
+
+	// Guard events (audit-only, from gt tap guard)
+	TypeGuardBlocked = "guard_blocked" // A PreToolUse guard blocked a tool call
 )
 
+// TraceKey is the payload key carrying a bead's lifecycle trace ID.
+// gt sling mints the trace; every event about the bead's work afterwards
+// (spawn, done, merge, convoy close) repeats it so gt trace can stitch the
+// lifecycle back together.
+const TraceKey = "trace_id"
+
 // EventsFile is the name of the raw events log.
 const EventsFile = ".events.jsonl"
 
@@ -138,6 +158,19 @@ func write(event Event) error {
 
 // Payload helpers for common event structures.
 
+// WithTrace adds a trace ID to a payload. An empty traceID leaves the
+// payload untouched. Returns the payload for chaining.
+func WithTrace(payload map[string]interface{}, traceID string) map[string]interface{} {
+	if traceID == "" {
+		return payload
+	}
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	payload[TraceKey] = traceID
+	return payload
+}
+
 // SlingPayload creates a payload for sling events.
 func SlingPayload(beadID, target string) map[string]interface{} {
 	return map[string]interface{}{
@@ -213,6 +246,41 @@ func MergePayload(mrID, worker, branch, reason string) map[string]interface{} {
 	return p
 }
 
+// ConvoyClosedPayload creates a payload for convoy closed events.
+// beads: the issues the convoy tracked
+func ConvoyClosedPayload(convoyID, reason string, beads []string) map[string]interface{} {
+	p := map[string]interface{}{
+		"convoy": convoyID,
+		"beads":  beads,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// UnblockedPayload creates a payload for unblocked events.
+// rig: the rig the bead lives in; blocker: the closed bead in another rig
+func UnblockedPayload(beadID, rig, blocker string) map[string]interface{} {
+	return map[string]interface{}{
+		"bead":    beadID,
+		"rig":     rig,
+		"blocker": blocker,
+	}
+}
+
+// GuardBlockedPayload creates a payload for guard blocked events.
+// rule: the policy rule that matched; match: the command or path it matched;
+// policy: the policy file the rule came from
+func GuardBlockedPayload(rule, tool, match, policy string) map[string]interface{} {
+	return map[string]interface{}{
+		"rule":   rule,
+		"tool":   tool,
+		"match":  match,
+		"policy": policy,
+	}
+}
+
 // PatrolPayload creates a payload for patrol start/complete events.
 func PatrolPayload(rig string, polecatCount int, message string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -294,6 +362,24 @@ func SessionDeathPayload(session, agent, reason, caller string) map[string]inter
 	}
 }
 
+// AgentStatusPayload creates a payload for agent status events.
+// agent: Gas Town agent identity (e.g., "gastown/polecats/Toast")
+// session: tmux session name
+// from/to: status before and after (e.g., "working" → "blocked")
+// message: pane line or reason behind the new status
+func AgentStatusPayload(agent, session, from, to, message string) map[string]interface{} {
+	p := map[string]interface{}{
+		"agent":   agent,
+		"session": session,
+		"from":    from,
+		"to":      to,
+	}
+	if message != "" {
+		p["message"] = message
+	}
+	return p
+}
+
 // MassDeathPayload creates a payload for mass death events.
 // count: number of sessions that died
 // window: time window in which deaths occurred (e.g., "5s")
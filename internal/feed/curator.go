@@ -44,6 +44,7 @@ type Curator struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
+	sinks    []func(*events.Event)
 }
 
 // Deduplication/aggregation settings
@@ -71,6 +72,13 @@ func NewCurator(townRoot string) *Curator {
 	}
 }
 
+// AddSink registers fn to receive every raw event the curator reads,
+// including audit-only ones, before feed filtering. Call before Start.
+// fn runs on the curator goroutine and must not block.
+func (c *Curator) AddSink(fn func(*events.Event)) {
+	c.sinks = append(c.sinks, fn)
+}
+
 // Start begins the curator goroutine.
 func (c *Curator) Start() error {
 	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
@@ -138,6 +146,10 @@ func (c *Curator) processLine(line string) {
 		return // Skip malformed lines
 	}
 
+	for _, sink := range c.sinks {
+		sink(&rawEvent)
+	}
+
 	// Filter by visibility - only process feed-visible events
 	if rawEvent.Visibility != events.VisibilityFeed && rawEvent.Visibility != events.VisibilityBoth {
 		return
@@ -365,6 +377,20 @@ func (c *Curator) generateSummary(event *events.Event) string {
 		}
 		return "Merge failed"
 
+	case events.TypeConvoyClosed:
+		if convoy, ok := event.Payload["convoy"].(string); ok {
+			return fmt.Sprintf("Convoy %s closed", convoy)
+		}
+		return "Convoy closed"
+
+	case events.TypeUnblocked:
+		bead, _ := event.Payload["bead"].(string)
+		blocker, _ := event.Payload["blocker"].(string)
+		if bead != "" && blocker != "" {
+			return fmt.Sprintf("%s unblocked: %s closed", bead, blocker)
+		}
+		return "Work unblocked"
+
 	case events.TypeSessionDeath:
 		session, _ := event.Payload["session"].(string)
 		reason, _ := event.Payload["reason"].(string)
@@ -194,3 +194,48 @@ func TestCurator_GeneratesSummary(t *testing.T) {
 		}
 	}
 }
+
+func TestCurator_SinksSeeAllEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	eventsPath := filepath.Join(tmpDir, events.EventsFile)
+	if err := os.WriteFile(eventsPath, []byte{}, 0644); err != nil {
+		t.Fatalf("creating events file: %v", err)
+	}
+
+	seen := make(chan string, 10)
+	curator := NewCurator(tmpDir)
+	curator.AddSink(func(e *events.Event) {
+		seen <- e.Type
+	})
+	if err := curator.Start(); err != nil {
+		t.Fatalf("starting curator: %v", err)
+	}
+	defer curator.Stop()
+
+	time.Sleep(50 * time.Millisecond)
+
+	// Audit-only events never reach the feed but must reach sinks
+	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("opening events file: %v", err)
+	}
+	for _, e := range []events.Event{
+		{Type: events.TypeSling, Actor: "mayor", Visibility: events.VisibilityFeed},
+		{Type: "internal_check", Actor: "daemon", Visibility: events.VisibilityAudit},
+	} {
+		data, _ := json.Marshal(e)
+		f.Write(append(data, '\n'))
+	}
+	f.Close()
+
+	for _, want := range []string{events.TypeSling, "internal_check"} {
+		select {
+		case got := <-seen:
+			if got != want {
+				t.Errorf("sink got %q, want %q", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("sink never received %q", want)
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// formulaExt is the file suffix of formula files.
+const formulaExt = ".formula.toml"
+
+// Resolver loads formulas by name and resolves their composition
+// directives: extends, [[include]], [compose] expand and aspects.
+//
+// Names are looked up as <name>.formula.toml in each directory in order,
+// then in the embedded formulas.
+type Resolver struct {
+	dirs []string
+}
+
+// NewResolver creates a resolver that searches dirs before the embedded
+// formulas.
+func NewResolver(dirs ...string) *Resolver {
+	return &Resolver{dirs: dirs}
+}
+
+// Load reads, resolves and validates the named formula.
+func (r *Resolver) Load(name string) (*Formula, error) {
+	return r.load(name, nil)
+}
+
+// Parse parses formula content, resolving composition against the
+// resolver's formulas.
+func (r *Resolver) Parse(data []byte) (*Formula, error) {
+	return r.parse(data, nil)
+}
+
+// readFormula returns the content of the named formula.
+func (r *Resolver) readFormula(name string) ([]byte, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid formula name %q", name)
+	}
+	for _, dir := range r.dirs {
+		data, err := os.ReadFile(filepath.Join(dir, name+formulaExt)) //nolint:gosec // G304: name has no separators
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("reading formula %s: %w", name, err)
+		}
+	}
+	data, err := formulasFS.ReadFile("formulas/" + name + formulaExt)
+	if err != nil {
+		return nil, fmt.Errorf("formula not found: %s", name)
+	}
+	return data, nil
+}
+
+func (r *Resolver) load(name string, stack []string) (*Formula, error) {
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("formula composition cycle: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+	data, err := r.readFormula(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := r.parse(data, stack)
+	if err != nil {
+		return nil, fmt.Errorf("formula %s: %w", name, err)
+	}
+	return f, nil
+}
+
+func (r *Resolver) parse(data []byte, stack []string) (*Formula, error) {
+	var f Formula
+	if _, err := toml.Decode(string(data), &f); err != nil {
+		return nil, fmt.Errorf("parsing TOML: %w", err)
+	}
+	if f.Name != "" {
+		stack = append(stack, f.Name)
+	}
+
+	if err := r.compose(&f, stack); err != nil {
+		return nil, err
+	}
+
+	// Infer type from content if not explicitly set
+	f.inferType()
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// compose applies f's composition directives to its steps, in order:
+// extends, include, compose.expand, compose.aspects.
+func (r *Resolver) compose(f *Formula, stack []string) error {
+	loadWorkflow := func(name, directive string) (*Formula, error) {
+		g, err := r.load(name, stack)
+		if err != nil {
+			return nil, err
+		}
+		if g.Type != TypeWorkflow {
+			return nil, fmt.Errorf("%s %s: %s is a %s formula, not a workflow", directive, name, name, g.Type)
+		}
+		return g, nil
+	}
+
+	if len(f.Extends) > 0 {
+		var steps []Step
+		vars := make(map[string]Var)
+		for _, name := range f.Extends {
+			base, err := loadWorkflow(name, "extends")
+			if err != nil {
+				return err
+			}
+			steps = mergeSteps(steps, base.Steps)
+			for k, v := range base.Vars {
+				vars[k] = v
+			}
+		}
+		if f.Type != "" && f.Type != TypeWorkflow {
+			return fmt.Errorf("%s formula cannot extend workflow formulas", f.Type)
+		}
+		f.Type = TypeWorkflow
+		f.Steps = mergeSteps(steps, f.Steps)
+		for k, v := range f.Vars {
+			vars[k] = v
+		}
+		f.Vars = vars
+	}
+
+	for _, inc := range f.Include {
+		g, err := loadWorkflow(inc.Formula, "include")
+		if err != nil {
+			return err
+		}
+		f.Steps = append(f.Steps, includedSteps(g.Steps, inc)...)
+		for k, v := range g.Vars {
+			if _, ok := f.Vars[k]; !ok {
+				if f.Vars == nil {
+					f.Vars = make(map[string]Var)
+				}
+				f.Vars[k] = v
+			}
+		}
+	}
+
+	if f.Compose == nil {
+		return nil
+	}
+	for _, ex := range f.Compose.Expand {
+		g, err := r.load(ex.With, stack)
+		if err != nil {
+			return err
+		}
+		if g.Type != TypeExpansion {
+			return fmt.Errorf("compose.expand %s: %s is a %s formula, not an expansion", ex.Target, ex.With, g.Type)
+		}
+		steps, err := expandStep(f.Steps, ex.Target, g.Template)
+		if err != nil {
+			return fmt.Errorf("compose.expand with %s: %w", ex.With, err)
+		}
+		f.Steps = steps
+	}
+	for _, name := range f.Compose.Aspects {
+		g, err := r.load(name, stack)
+		if err != nil {
+			return err
+		}
+		if g.Type != TypeAspect {
+			return fmt.Errorf("compose.aspects: %s is a %s formula, not an aspect", name, g.Type)
+		}
+		f.Steps = applyAdvice(f.Steps, g)
+	}
+	return nil
+}
+
+// mergeSteps overlays steps onto base. A step with a base step's ID
+// overrides the fields it sets; other steps are appended.
+func mergeSteps(base, steps []Step) []Step {
+	merged := append([]Step(nil), base...)
+	index := make(map[string]int)
+	for i, s := range merged {
+		index[s.ID] = i
+	}
+	for _, s := range steps {
+		i, ok := index[s.ID]
+		if !ok {
+			index[s.ID] = len(merged)
+			merged = append(merged, s)
+			continue
+		}
+		m := &merged[i]
+		if s.Title != "" {
+			m.Title = s.Title
+		}
+		if s.Description != "" {
+			m.Description = s.Description
+		}
+		if s.Needs != nil {
+			m.Needs = s.Needs
+		}
+		if s.When != "" {
+			m.When = s.When
+		}
+		if s.Foreach != "" {
+			m.Foreach = s.Foreach
+		}
+	}
+	return merged
+}
+
+// includedSteps returns steps to include, prefixed as inc asks.
+func includedSteps(steps []Step, inc Include) []Step {
+	rename := func(id string) string { return id }
+	if inc.Prefix != "" {
+		rename = func(id string) string { return inc.Prefix + "." + id }
+	}
+	var out []Step
+	for _, s := range steps {
+		s.ID = rename(s.ID)
+		needs := make([]string, 0, len(s.Needs))
+		for _, need := range s.Needs {
+			needs = append(needs, rename(need))
+		}
+		if len(needs) == 0 {
+			needs = append(needs, inc.Needs...)
+		}
+		s.Needs = needs
+		if inc.Prefix != "" && s.When != "" {
+			for _, other := range steps {
+				s.When = renameOutputRef(s.When, other.ID, rename(other.ID))
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// renameOutputRef rewrites "<from>.output" references in a when expression.
+func renameOutputRef(when, from, to string) string {
+	re := regexp.MustCompile(`(^|[^A-Za-z0-9_.-])` + regexp.QuoteMeta(from) + `\.output\b`)
+	return re.ReplaceAllString(when, "${1}"+to+".output")
+}
+
+// expandStep replaces step target with steps generated from templates.
+// Templates without needs take the target's needs, and steps that needed
+// the target need the templates nothing else needs.
+func expandStep(steps []Step, target string, templates []Template) ([]Step, error) {
+	at := -1
+	for i := range steps {
+		if steps[i].ID == target {
+			at = i
+			break
+		}
+	}
+	if at < 0 {
+		return nil, fmt.Errorf("unknown target step: %s", target)
+	}
+	t := steps[at]
+	sub := strings.NewReplacer(
+		"{target.title}", t.Title,
+		"{target.description}", t.Description,
+		"{target}", t.ID,
+	).Replace
+
+	var generated []Step
+	needed := make(map[string]bool)
+	for _, tmpl := range templates {
+		s := Step{
+			ID:          sub(tmpl.ID),
+			Title:       sub(tmpl.Title),
+			Description: sub(tmpl.Description),
+			When:        t.When,
+			Foreach:     t.Foreach,
+		}
+		for _, need := range tmpl.Needs {
+			s.Needs = append(s.Needs, sub(need))
+			needed[sub(need)] = true
+		}
+		if len(s.Needs) == 0 {
+			s.Needs = append([]string(nil), t.Needs...)
+		}
+		generated = append(generated, s)
+	}
+	var leaves []string
+	for _, s := range generated {
+		if !needed[s.ID] {
+			leaves = append(leaves, s.ID)
+		}
+	}
+
+	out := make([]Step, 0, len(steps)+len(generated)-1)
+	out = append(out, steps[:at]...)
+	out = append(out, generated...)
+	out = append(out, steps[at+1:]...)
+	return replaceNeed(out, target, leaves, nil), nil
+}
+
+// applyAdvice injects an aspect's advice steps around matching steps.
+// Before steps run in order ahead of the step, taking over its needs;
+// after steps run in order behind it, and its dependents wait for them.
+func applyAdvice(steps []Step, aspect *Formula) []Step {
+	for _, adv := range aspect.Advice {
+		var globs []string
+		if adv.Target != "" {
+			globs = []string{adv.Target}
+		} else {
+			for _, pc := range aspect.Pointcuts {
+				globs = append(globs, pc.Glob)
+			}
+		}
+		var targets []string
+		for _, s := range steps {
+			for _, g := range globs {
+				if ok, _ := path.Match(g, s.ID); ok {
+					targets = append(targets, s.ID)
+					break
+				}
+			}
+		}
+		for _, id := range targets {
+			steps = adviseStep(steps, id, adv.Around)
+		}
+	}
+	return steps
+}
+
+func adviseStep(steps []Step, id string, around AdviceAround) []Step {
+	var t Step
+	for _, s := range steps {
+		if s.ID == id {
+			t = s
+			break
+		}
+	}
+	sub := strings.NewReplacer("{step.id}", t.ID, "{step.title}", t.Title).Replace
+	instantiate := func(adv []Step, first []string) []Step {
+		var out []Step
+		prev := first
+		for _, a := range adv {
+			s := Step{
+				ID:          sub(a.ID),
+				Title:       sub(a.Title),
+				Description: sub(a.Description),
+				Needs:       prev,
+				When:        a.When,
+				Foreach:     t.Foreach,
+			}
+			if s.When == "" {
+				s.When = t.When
+			}
+			out = append(out, s)
+			prev = []string{s.ID}
+		}
+		return out
+	}
+
+	before := instantiate(around.Before, append([]string(nil), t.Needs...))
+	after := instantiate(around.After, []string{t.ID})
+
+	var out []Step
+	for _, s := range steps {
+		if s.ID != t.ID {
+			out = append(out, s)
+			continue
+		}
+		out = append(out, before...)
+		if len(before) > 0 {
+			s.Needs = []string{before[len(before)-1].ID}
+		}
+		out = append(out, s)
+		out = append(out, after...)
+	}
+	if len(after) == 0 {
+		return out
+	}
+	skip := make(map[string]bool)
+	for _, s := range after {
+		skip[s.ID] = true
+	}
+	return replaceNeed(out, t.ID, []string{after[len(after)-1].ID}, skip)
+}
+
+// replaceNeed replaces id in the needs of every step not in skip.
+func replaceNeed(steps []Step, id string, with []string, skip map[string]bool) []Step {
+	for i := range steps {
+		if skip[steps[i].ID] {
+			continue
+		}
+		var needs []string
+		replaced := false
+		for _, need := range steps[i].Needs {
+			if need == id {
+				needs = append(needs, with...)
+				replaced = true
+			} else {
+				needs = append(needs, need)
+			}
+		}
+		if replaced {
+			steps[i].Needs = needs
+		}
+	}
+	return steps
+}
+
+// composedFormulas returns the names of the formulas f's content composes
+// (extends, include, compose), without resolving them.
+func composedFormulas(data []byte) []string {
+	var f struct {
+		Extends StringList `toml:"extends"`
+		Include []Include  `toml:"include"`
+		Compose *Compose   `toml:"compose"`
+	}
+	if _, err := toml.Decode(string(data), &f); err != nil {
+		return nil
+	}
+	names := append([]string(nil), f.Extends...)
+	for _, inc := range f.Include {
+		names = append(names, inc.Formula)
+	}
+	if f.Compose != nil {
+		for _, ex := range f.Compose.Expand {
+			names = append(names, ex.With)
+		}
+		names = append(names, f.Compose.Aspects...)
+	}
+	return names
+}
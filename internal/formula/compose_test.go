@@ -0,0 +1,297 @@
+package formula
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFormula writes <dir>/<name>.formula.toml.
+func writeFormula(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name+formulaExt), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stepIDs(f *Formula) []string {
+	var ids []string
+	for _, s := range f.Steps {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+const baseFormula = `
+formula = "base"
+type = "workflow"
+
+[[steps]]
+id = "design"
+title = "Design {{feature}}"
+
+[[steps]]
+id = "implement"
+title = "Implement"
+description = "Write it"
+needs = ["design"]
+
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["implement"]
+
+[vars.feature]
+required = true
+
+[vars.reviewer]
+default = "mayor"
+`
+
+func TestResolver_Extends(t *testing.T) {
+	dir := t.TempDir()
+	writeFormula(t, dir, "base", baseFormula)
+
+	f, err := NewResolver(dir).Parse([]byte(`
+formula = "derived"
+extends = "base"
+
+[[steps]]
+id = "implement"
+description = "Write it test-first"
+
+[[steps]]
+id = "review"
+title = "Review"
+needs = ["implement"]
+
+[[steps]]
+id = "submit"
+needs = ["review"]
+
+[vars.reviewer]
+default = "witness"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if f.Type != TypeWorkflow {
+		t.Errorf("Type = %q, want workflow", f.Type)
+	}
+	if got, want := stepIDs(f), []string{"design", "implement", "submit", "review"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+	impl := f.GetStep("implement")
+	if impl.Title != "Implement" || impl.Description != "Write it test-first" || !reflect.DeepEqual(impl.Needs, []string{"design"}) {
+		t.Errorf("implement = %+v, want base title and needs with overridden description", impl)
+	}
+	if got := f.GetStep("submit").Needs; !reflect.DeepEqual(got, []string{"review"}) {
+		t.Errorf("submit needs %v, want [review]", got)
+	}
+	if f.Vars["reviewer"].Default != "witness" || !f.Vars["feature"].Required {
+		t.Errorf("vars = %+v, want base vars with reviewer overridden", f.Vars)
+	}
+}
+
+func TestResolver_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeFormula(t, dir, "sync", `
+formula = "sync"
+
+[[steps]]
+id = "fetch"
+
+[[steps]]
+id = "rebase"
+needs = ["fetch"]
+when = "fetch.output != 'up-to-date'"
+
+[vars.remote]
+default = "origin"
+`)
+
+	f, err := NewResolver(dir).Parse([]byte(`
+formula = "work"
+
+[[steps]]
+id = "implement"
+
+[[include]]
+formula = "sync"
+prefix = "sync"
+needs = ["implement"]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, want := stepIDs(f), []string{"implement", "sync.fetch", "sync.rebase"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("steps = %v, want %v", got, want)
+	}
+	if got := f.GetStep("sync.fetch").Needs; !reflect.DeepEqual(got, []string{"implement"}) {
+		t.Errorf("sync.fetch needs %v, want [implement]", got)
+	}
+	rebase := f.GetStep("sync.rebase")
+	if !reflect.DeepEqual(rebase.Needs, []string{"sync.fetch"}) || rebase.When != "sync.fetch.output != 'up-to-date'" {
+		t.Errorf("sync.rebase = %+v", rebase)
+	}
+	if f.Vars["remote"].Default != "origin" {
+		t.Errorf("included vars not merged: %+v", f.Vars)
+	}
+}
+
+func TestResolver_AspectAdvice(t *testing.T) {
+	dir := t.TempDir()
+	writeFormula(t, dir, "base", baseFormula)
+	writeFormula(t, dir, "audit", `
+formula = "audit"
+type = "aspect"
+
+[[advice]]
+[advice.around]
+
+[[advice.around.before]]
+id = "{step.id}-prescan"
+title = "Prescan {step.title}"
+
+[[advice.around.after]]
+id = "{step.id}-postscan"
+
+[[advice.around.after]]
+id = "{step.id}-report"
+
+[[pointcuts]]
+glob = "impl*"
+`)
+
+	f, err := NewResolver(dir).Parse([]byte(`
+formula = "secure"
+extends = ["base"]
+
+[compose]
+aspects = ["audit"]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []string{"design", "implement-prescan", "implement", "implement-postscan", "implement-report", "submit"}
+	if got := stepIDs(f); !reflect.DeepEqual(got, want) {
+		t.Fatalf("steps = %v, want %v", got, want)
+	}
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if got := f.GetStep("implement-prescan").Title; got != "Prescan Implement" {
+		t.Errorf("prescan title = %q", got)
+	}
+}
+
+func TestResolver_ComposeExpand(t *testing.T) {
+	dir := t.TempDir()
+	writeFormula(t, dir, "base", baseFormula)
+	writeFormula(t, dir, "twice", `
+formula = "twice"
+type = "expansion"
+
+[[template]]
+id = "{target}.draft"
+title = "Draft: {target.title}"
+
+[[template]]
+id = "{target}.polish"
+needs = ["{target}.draft"]
+`)
+
+	f, err := NewResolver(dir).Parse([]byte(`
+formula = "careful"
+extends = "base"
+
+[[compose.expand]]
+target = "implement"
+with = "twice"
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got, want := stepIDs(f), []string{"design", "implement.draft", "implement.polish", "submit"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("steps = %v, want %v", got, want)
+	}
+	draft := f.GetStep("implement.draft")
+	if draft.Title != "Draft: Implement" || !reflect.DeepEqual(draft.Needs, []string{"design"}) {
+		t.Errorf("draft = %+v", draft)
+	}
+	if got := f.GetStep("submit").Needs; !reflect.DeepEqual(got, []string{"implement.polish"}) {
+		t.Errorf("submit needs %v, want [implement.polish]", got)
+	}
+}
+
+func TestResolver_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeFormula(t, dir, "a", "formula = \"a\"\nextends = \"b\"")
+	writeFormula(t, dir, "b", "formula = \"b\"\nextends = \"a\"")
+	writeFormula(t, dir, "base", baseFormula)
+	r := NewResolver(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"cycle", "formula = \"c\"\nextends = \"a\"", "composition cycle"},
+		{"missing", "formula = \"c\"\nextends = \"nope\"", "formula not found: nope"},
+		{"not a workflow", "formula = \"c\"\nextends = \"rule-of-five\"", "not a workflow"},
+		{"bad expand target", "formula = \"c\"\nextends = \"base\"\n[[compose.expand]]\ntarget = \"nope\"\nwith = \"rule-of-five\"", "unknown target step"},
+		{"path in name", "formula = \"c\"\nextends = \"../base\"", "invalid formula name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.Parse([]byte(tt.content)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolver_DirectoryOverridesEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	writeFormula(t, dir, "shiny", `
+formula = "shiny"
+
+[[steps]]
+id = "implement"
+
+[[steps]]
+id = "submit"
+needs = ["implement"]
+`)
+
+	f, err := NewResolver(dir).Load("shiny-secure")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []string{"implement-security-prescan", "implement", "implement-security-postscan", "submit-security-prescan", "submit", "submit-security-postscan"}
+	if got := stepIDs(f); !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %v, want %v", got, want)
+	}
+}
+
+func TestEmbeddedFormulasResolve(t *testing.T) {
+	entries, err := formulasFS.ReadDir("formulas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewResolver()
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), formulaExt)
+		if _, err := r.Load(name); err != nil {
+			t.Errorf("Load(%s): %v", name, err)
+		}
+	}
+}
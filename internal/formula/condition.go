@@ -0,0 +1,282 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a parsed step `when` expression.
+//
+// The grammar is deliberately small:
+//
+//	expr    = or
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | compare
+//	compare = operand [ ( "==" | "!=" ) operand ]
+//	operand = string | identifier | "(" expr ")"
+//
+// Strings are single- or double-quoted. An identifier names a formula
+// variable (or "item" inside a foreach step), or the output of an earlier
+// step as "<step-id>.output". true, false and bare numbers are literals.
+// Every value is a string; a value is truthy unless it is empty, "false"
+// or "0".
+type Condition struct {
+	src  string
+	root condNode
+}
+
+// condNode is a node in a parsed condition.
+type condNode interface {
+	eval(lookup func(name string) string) string
+}
+
+type condLiteral string
+
+type condRef string
+
+type condNot struct{ x condNode }
+
+type condBinary struct {
+	op   string
+	l, r condNode
+}
+
+func (n condLiteral) eval(func(string) string) string { return string(n) }
+
+func (n condRef) eval(lookup func(string) string) string { return lookup(string(n)) }
+
+func (n condNot) eval(lookup func(string) string) string {
+	return boolString(!truthy(n.x.eval(lookup)))
+}
+
+func (n condBinary) eval(lookup func(string) string) string {
+	switch n.op {
+	case "||":
+		return boolString(truthy(n.l.eval(lookup)) || truthy(n.r.eval(lookup)))
+	case "&&":
+		return boolString(truthy(n.l.eval(lookup)) && truthy(n.r.eval(lookup)))
+	case "==":
+		return boolString(n.l.eval(lookup) == n.r.eval(lookup))
+	default: // "!="
+		return boolString(n.l.eval(lookup) != n.r.eval(lookup))
+	}
+}
+
+// ParseCondition parses a `when` expression.
+func ParseCondition(expr string) (*Condition, error) {
+	toks, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid when %q: %w", expr, err)
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("invalid when %q: empty expression", expr)
+	}
+	p := &condParser{toks: toks}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid when %q: %w", expr, err)
+	}
+	return &Condition{src: expr, root: root}, nil
+}
+
+// String returns the source expression.
+func (c *Condition) String() string {
+	return c.src
+}
+
+// Eval evaluates the condition. lookup resolves identifiers; unknown names
+// should resolve to "".
+func (c *Condition) Eval(lookup func(name string) string) bool {
+	return truthy(c.root.eval(lookup))
+}
+
+// Refs returns the identifiers the condition reads, in order of first use.
+func (c *Condition) Refs() []string {
+	var refs []string
+	seen := make(map[string]bool)
+	var walk func(n condNode)
+	walk = func(n condNode) {
+		switch n := n.(type) {
+		case condRef:
+			if !seen[string(n)] {
+				seen[string(n)] = true
+				refs = append(refs, string(n))
+			}
+		case condNot:
+			walk(n.x)
+		case condBinary:
+			walk(n.l)
+			walk(n.r)
+		}
+	}
+	walk(c.root)
+	return refs
+}
+
+// OutputRef returns the step ID if ref names a step output ("<id>.output").
+func OutputRef(ref string) (string, bool) {
+	id, ok := strings.CutSuffix(ref, ".output")
+	return id, ok && id != ""
+}
+
+func truthy(s string) bool {
+	return s != "" && s != "false" && s != "0"
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+type condTokenKind int
+
+const (
+	tokOp condTokenKind = iota
+	tokString
+	tokIdent
+)
+
+type condToken struct {
+	kind condTokenKind
+	text string
+}
+
+func tokenizeCondition(s string) ([]condToken, error) {
+	var toks []condToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, condToken{tokOp, string(c)})
+			i++
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"),
+			strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="):
+			toks = append(toks, condToken{tokOp, s[i : i+2]})
+			i += 2
+		case c == '!':
+			toks = append(toks, condToken{tokOp, "!"})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, condToken{tokString, s[i+1 : i+1+end]})
+			i += end + 2
+		case isIdentByte(c):
+			j := i
+			for j < len(s) && isIdentByte(s[j]) {
+				j++
+			}
+			toks = append(toks, condToken{tokIdent, s[i:j]})
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return toks, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+type condParser struct {
+	toks []condToken
+	pos  int
+}
+
+func (p *condParser) peekOp(op string) bool {
+	return p.pos < len(p.toks) && p.toks[p.pos].kind == tokOp && p.toks[p.pos].text == op
+}
+
+func (p *condParser) parseOr() (condNode, error) {
+	l, err := p.parseAnd()
+	for err == nil && p.peekOp("||") {
+		p.pos++
+		var r condNode
+		if r, err = p.parseAnd(); err == nil {
+			l = condBinary{op: "||", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *condParser) parseAnd() (condNode, error) {
+	l, err := p.parseUnary()
+	for err == nil && p.peekOp("&&") {
+		p.pos++
+		var r condNode
+		if r, err = p.parseUnary(); err == nil {
+			l = condBinary{op: "&&", l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *condParser) parseUnary() (condNode, error) {
+	if p.peekOp("!") {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return condNot{x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *condParser) parseCompare() (condNode, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!="} {
+		if p.peekOp(op) {
+			p.pos++
+			r, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return condBinary{op: op, l: l, r: r}, nil
+		}
+	}
+	return l, nil
+}
+
+func (p *condParser) parseOperand() (condNode, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.toks[p.pos]
+	p.pos++
+	switch {
+	case tok.kind == tokString:
+		return condLiteral(tok.text), nil
+	case tok.kind == tokIdent && (tok.text == "true" || tok.text == "false" || (tok.text[0] >= '0' && tok.text[0] <= '9')):
+		return condLiteral(tok.text), nil
+	case tok.kind == tokIdent:
+		return condRef(tok.text), nil
+	case tok.text == "(":
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekOp(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+}
@@ -0,0 +1,66 @@
+package formula
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCondition_Eval(t *testing.T) {
+	env := map[string]string{
+		"mode":         "fast",
+		"skip_tests":   "false",
+		"count":        "0",
+		"build.output": "ok",
+	}
+	lookup := func(name string) string { return env[name] }
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`mode == "fast"`, true},
+		{`mode != 'fast'`, false},
+		{`mode`, true},
+		{`missing`, false},
+		{`!skip_tests`, true},
+		{`count`, false},
+		{`count == 0`, true},
+		{`build.output == "ok" && mode == "fast"`, true},
+		{`build.output == "fail" || !(mode == "slow")`, true},
+		{`!mode == "fast"`, false},
+		{`true && false`, false},
+	}
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q): %v", tt.expr, err)
+		}
+		if got := cond.Eval(lookup); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	for _, expr := range []string{``, `mode ==`, `(mode`, `"open`, `mode = "x"`, `a b`, `&& a`} {
+		if _, err := ParseCondition(expr); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCondition_Refs(t *testing.T) {
+	cond, err := ParseCondition(`lang == "go" && (test.output != "" || lang == "rust") && true`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cond.Refs(), []string{"lang", "test.output"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Refs() = %v, want %v", got, want)
+	}
+	if id, ok := OutputRef("test.output"); !ok || id != "test" {
+		t.Errorf("OutputRef(test.output) = %q, %v", id, ok)
+	}
+	if _, ok := OutputRef("lang"); ok {
+		t.Error("OutputRef(lang) reported an output")
+	}
+}
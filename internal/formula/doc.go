@@ -77,6 +77,45 @@
 //	title = "Publish"
 //	needs = ["build"]
 //
+// # Conditions, Loops and Variables
+//
+// Workflow steps may set when, a condition over vars and the outputs of
+// steps they need, and foreach, naming a comma-separated list var:
+//
+//	[[steps]]
+//	id = "build"
+//	title = "Build for {{item}}"
+//	foreach = "platforms"
+//
+//	[[steps]]
+//	id = "announce"
+//	when = "publish.output != 'dry-run'"
+//	needs = ["publish"]
+//
+// Expand instantiates a formula with variable values: foreach steps fan out
+// into "<id>.<item>" steps, conditions on vars alone are decided, and
+// {{var}} placeholders in titles and descriptions are filled in. The
+// expanded formula's TopologicalSort and ReadySteps cover the fanned-out
+// steps, and NextSteps evaluates the remaining output conditions:
+//
+//	x, err := f.Expand(map[string]string{"platforms": "linux,darwin"})
+//	ready, skipped := x.NextSteps(completed, outputs)
+//
+// # Composition
+//
+// A formula may build on others. extends merges base workflow steps (a
+// step with a base step's ID overrides the fields it sets), [[include]]
+// appends another workflow's steps, and [compose] applies expansion
+// formulas to target steps and aspect formulas' advice around matching
+// steps. Parse resolves these against the embedded formulas; ParseFile and
+// Resolver also search directories on disk:
+//
+//	formula = "shiny-secure"
+//	extends = "shiny"
+//
+//	[compose]
+//	aspects = ["security-audit"]
+//
 // # Validation
 //
 // The package performs comprehensive validation:
@@ -84,6 +123,8 @@
 //   - Required fields (formula name, valid type)
 //   - Unique IDs within steps/legs/templates/aspects
 //   - Valid dependency references (needs/depends_on)
+//   - Foreach vars are declared; when conditions parse and only read
+//     declared vars and outputs of needed steps
 //   - Cycle detection in dependency graphs
 //
 // # Cycle Detection
@@ -29,6 +29,12 @@ type FormulaStatus struct {
 	EmbeddedHash  string // hash computed from embedded content
 	InstalledHash string // hash we installed (from .installed.json)
 	CurrentHash   string // hash of current file on disk
+
+	// DependsOn lists the formulas this one extends, includes or composes.
+	DependsOn []string
+	// StaleDeps lists dependencies, direct or transitive, whose status
+	// isn't "ok": a change there changes what this formula resolves to.
+	StaleDeps []string
 }
 
 // HealthReport contains the results of checking formula health.
@@ -41,6 +47,7 @@ type HealthReport struct {
 	Missing   int // file was deleted
 	New       int // new formula not yet installed
 	Untracked int // file exists but not in .installed.json (safe to update)
+	Affected  int // depends on a formula that isn't ok
 }
 
 // computeHash computes SHA256 hash of data.
@@ -245,9 +252,55 @@ func CheckFormulaHealth(beadsPath string) (*HealthReport, error) {
 		report.Formulas = append(report.Formulas, status)
 	}
 
+	trackDependencies(report, formulasDir)
+
 	return report, nil
 }
 
+// trackDependencies fills in DependsOn and StaleDeps from each formula's
+// composition directives, read from the installed file or, if there is
+// none, the embedded one.
+func trackDependencies(report *HealthReport, formulasDir string) {
+	byName := make(map[string]*FormulaStatus)
+	for i := range report.Formulas {
+		status := &report.Formulas[i]
+		byName[status.Name] = status
+
+		data, err := os.ReadFile(filepath.Join(formulasDir, status.Name))
+		if err != nil {
+			data, err = formulasFS.ReadFile("formulas/" + status.Name)
+		}
+		if err != nil {
+			continue
+		}
+		for _, dep := range composedFormulas(data) {
+			status.DependsOn = append(status.DependsOn, dep+formulaExt)
+		}
+	}
+
+	for i := range report.Formulas {
+		status := &report.Formulas[i]
+		seen := map[string]bool{status.Name: true}
+		queue := append([]string(nil), status.DependsOn...)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			dep, ok := byName[name]
+			if seen[name] || !ok {
+				continue
+			}
+			seen[name] = true
+			if dep.Status != "ok" {
+				status.StaleDeps = append(status.StaleDeps, name)
+			}
+			queue = append(queue, dep.DependsOn...)
+		}
+		if len(status.StaleDeps) > 0 {
+			report.Affected++
+		}
+	}
+}
+
 // UpdateFormulas updates formulas that are safe to update (outdated, missing, or untracked).
 // Skips user-modified formulas (tracked files that user changed).
 // Returns counts of updated, skipped (modified), and reinstalled (missing).
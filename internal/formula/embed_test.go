@@ -746,3 +746,56 @@ func TestCheckFormulaHealth_MixedScenarios(t *testing.T) {
 		t.Errorf("formula %s status = %q, want %q", modifiedFormula, statusMap[modifiedFormula], "modified")
 	}
 }
+
+// TestCheckFormulaHealth_TracksDependencies checks that a change to a base
+// formula is flagged in the formulas that compose it.
+func TestCheckFormulaHealth_TracksDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	if _, err := ProvisionFormulas(tmpDir); err != nil {
+		t.Fatalf("ProvisionFormulas() error: %v", err)
+	}
+
+	report, err := CheckFormulaHealth(tmpDir)
+	if err != nil {
+		t.Fatalf("CheckFormulaHealth() error: %v", err)
+	}
+	if report.Affected != 0 {
+		t.Errorf("Affected = %d on a fresh install, want 0", report.Affected)
+	}
+	for _, f := range report.Formulas {
+		if f.Name == "shiny-enterprise.formula.toml" {
+			if len(f.DependsOn) != 2 || f.DependsOn[0] != "shiny.formula.toml" || f.DependsOn[1] != "rule-of-five.formula.toml" {
+				t.Errorf("shiny-enterprise DependsOn = %v", f.DependsOn)
+			}
+		}
+	}
+
+	// User customizes the base formula
+	shinyPath := filepath.Join(tmpDir, ".beads", "formulas", "shiny.formula.toml")
+	data, err := os.ReadFile(shinyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(shinyPath, append(data, "\n# local tweak\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err = CheckFormulaHealth(tmpDir)
+	if err != nil {
+		t.Fatalf("CheckFormulaHealth() error: %v", err)
+	}
+	stale := make(map[string][]string)
+	for _, f := range report.Formulas {
+		if len(f.StaleDeps) > 0 {
+			stale[f.Name] = f.StaleDeps
+		}
+	}
+	for _, derived := range []string{"shiny-secure.formula.toml", "shiny-enterprise.formula.toml"} {
+		if deps := stale[derived]; len(deps) != 1 || deps[0] != "shiny.formula.toml" {
+			t.Errorf("%s StaleDeps = %v, want [shiny.formula.toml]", derived, deps)
+		}
+	}
+	if report.Affected != len(stale) || len(stale) != 2 {
+		t.Errorf("Affected = %d, stale = %v; want the two shiny variants", report.Affected, stale)
+	}
+}
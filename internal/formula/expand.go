@@ -0,0 +1,270 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// varPattern matches {{name}} placeholders.
+var varPattern = regexp.MustCompile(`\{\{([A-Za-z0-9_.-]+)\}\}`)
+
+// Expand instantiates a workflow formula with the given variable values,
+// returning the expanded formula. The receiver is not modified.
+//
+// Variables fall back to their defaults, and a missing required variable is
+// an error. Foreach steps fan out into one step per list item; a step that
+// needs a foreach step needs every instance of it, except that two steps
+// looping over the same var are paired item by item. Steps whose when
+// condition reads only vars are decided now: if false, the step is dropped
+// and its dependents inherit its needs. Conditions on step outputs are kept
+// for NextSteps. Finally {{var}} and {{item}} are interpolated into titles
+// and descriptions; unknown placeholders are left as they are.
+//
+// Other formula types are returned unchanged.
+func (f *Formula) Expand(vars map[string]string) (*Formula, error) {
+	out := *f
+	if f.Type != TypeWorkflow {
+		return &out, nil
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	bound := make(map[string]string)
+	for name, v := range f.Vars {
+		bound[name] = v.Default
+	}
+	for name, value := range vars {
+		bound[name] = value
+	}
+	var missing []string
+	for name, v := range f.Vars {
+		if v.Required && bound[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required variable(s): %s", strings.Join(missing, ", "))
+	}
+	out.bound = bound
+
+	// Instance IDs of each foreach step, by item
+	items := make(map[string][]string)
+	for _, step := range f.Steps {
+		if step.Foreach != "" {
+			items[step.ID] = splitList(bound[step.Foreach])
+		}
+	}
+	byID := make(map[string]*Step)
+	for i := range f.Steps {
+		byID[f.Steps[i].ID] = &f.Steps[i]
+	}
+
+	// mapNeeds resolves a step's needs to expanded IDs. A need on a foreach
+	// step with no items stays as the template ID, which is dropped below.
+	mapNeeds := func(step *Step, item string) []string {
+		var needs []string
+		for _, need := range step.Needs {
+			dep := byID[need]
+			switch {
+			case dep.Foreach == "" || len(items[need]) == 0:
+				needs = append(needs, need)
+			case item != "" && dep.Foreach == step.Foreach:
+				needs = append(needs, need+"."+item)
+			default:
+				for _, it := range items[need] {
+					needs = append(needs, need+"."+it)
+				}
+			}
+		}
+		return needs
+	}
+
+	var expanded []Step
+	dropped := make(map[string][]string) // ID -> needs
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if step.Foreach == "" {
+			s := *step
+			s.Needs = mapNeeds(step, "")
+			expanded = append(expanded, s)
+			continue
+		}
+		if len(items[step.ID]) == 0 {
+			dropped[step.ID] = mapNeeds(step, "")
+			continue
+		}
+		for _, item := range items[step.ID] {
+			s := *step
+			s.ID = step.ID + "." + item
+			s.Needs = mapNeeds(step, item)
+			s.Foreach = ""
+			s.item = item
+			expanded = append(expanded, s)
+		}
+	}
+
+	// Decide conditions that don't depend on step outputs
+	var kept []Step
+	for _, step := range expanded {
+		if step.When != "" {
+			cond, err := ParseCondition(step.When)
+			if err != nil {
+				return nil, fmt.Errorf("step %q: %w", step.ID, err)
+			}
+			if !readsOutputs(cond) {
+				if !cond.Eval(out.lookup(&step, nil)) {
+					dropped[step.ID] = step.Needs
+					continue
+				}
+				step.When = ""
+			}
+		}
+		kept = append(kept, step)
+	}
+
+	// Dependents of dropped steps inherit their needs
+	var resolve func(ids []string, seen map[string]bool) []string
+	resolve = func(ids []string, seen map[string]bool) []string {
+		var res []string
+		for _, id := range ids {
+			if needs, ok := dropped[id]; ok {
+				res = append(res, resolve(needs, seen)...)
+			} else if !seen[id] {
+				seen[id] = true
+				res = append(res, id)
+			}
+		}
+		return res
+	}
+	out.skipped = make(map[string]bool)
+	for id := range dropped {
+		out.skipped[id] = true
+	}
+	for i := range kept {
+		step := &kept[i]
+		step.Needs = resolve(step.Needs, make(map[string]bool))
+		lookup := out.lookup(step, nil)
+		interpolate := func(s string) string {
+			return varPattern.ReplaceAllStringFunc(s, func(m string) string {
+				name := m[2 : len(m)-2]
+				if _, ok := bound[name]; ok || (name == "item" && step.item != "") {
+					return lookup(name)
+				}
+				return m
+			})
+		}
+		step.Title = interpolate(step.Title)
+		step.Description = interpolate(step.Description)
+	}
+	out.Steps = kept
+
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("expanded formula: %w", err)
+	}
+	return &out, nil
+}
+
+// NextSteps returns the workflow steps that can run given the completed
+// steps and their outputs, and the steps to skip because their when
+// condition is false. Skipped steps count as completed for their
+// dependents, so a skip can make further steps ready or skipped in the
+// same call; callers should mark skipped steps done.
+func (f *Formula) NextSteps(completed map[string]bool, outputs map[string]string) (ready, skipped []string) {
+	done := make(map[string]bool, len(completed))
+	for id, ok := range completed {
+		done[id] = ok
+	}
+	needsMet := func(step *Step) bool {
+		for _, need := range step.Needs {
+			if !done[need] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		var newly []string
+		for i := range f.Steps {
+			step := &f.Steps[i]
+			if done[step.ID] || !needsMet(step) || step.When == "" {
+				continue
+			}
+			cond, err := ParseCondition(step.When)
+			if err == nil && !cond.Eval(f.lookup(step, outputs)) {
+				newly = append(newly, step.ID)
+			}
+		}
+		if len(newly) == 0 {
+			break
+		}
+		for _, id := range newly {
+			done[id] = true
+		}
+		skipped = append(skipped, newly...)
+	}
+
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if !done[step.ID] && needsMet(step) {
+			ready = append(ready, step.ID)
+		}
+	}
+	return ready, skipped
+}
+
+// ExpansionSteps returns the IDs of workflow steps that use when or
+// foreach. bd cooks formulas without applying either, so callers that hand
+// a formula to bd should refuse these until it does.
+func (f *Formula) ExpansionSteps() []string {
+	var ids []string
+	for _, step := range f.Steps {
+		if step.When != "" || step.Foreach != "" {
+			ids = append(ids, step.ID)
+		}
+	}
+	return ids
+}
+
+// lookup resolves condition identifiers for a step: "<id>.output" from
+// outputs, "item" from the step's foreach value, anything else from the
+// bound vars or var defaults.
+func (f *Formula) lookup(step *Step, outputs map[string]string) func(string) string {
+	return func(name string) string {
+		if id, ok := OutputRef(name); ok {
+			return outputs[id]
+		}
+		if name == "item" && step.item != "" {
+			return step.item
+		}
+		if value, ok := f.bound[name]; ok {
+			return value
+		}
+		return f.Vars[name].Default
+	}
+}
+
+// readsOutputs reports whether a condition refers to any step output.
+func readsOutputs(cond *Condition) bool {
+	for _, ref := range cond.Refs() {
+		if _, ok := OutputRef(ref); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated list var, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
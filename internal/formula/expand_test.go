@@ -0,0 +1,276 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const releaseFormula = `
+formula = "release"
+type = "workflow"
+
+[[steps]]
+id = "prepare"
+title = "Prepare {{version}}"
+
+[[steps]]
+id = "build"
+title = "Build {{version}} for {{item}}"
+description = "GOOS={{item}} make build ({{unknown}})"
+foreach = "platforms"
+needs = ["prepare"]
+
+[[steps]]
+id = "test"
+title = "Test {{item}}"
+foreach = "platforms"
+needs = ["build"]
+
+[[steps]]
+id = "sign"
+title = "Sign binaries"
+when = "sign == 'true'"
+needs = ["build"]
+
+[[steps]]
+id = "publish"
+title = "Publish {{version}}"
+needs = ["test", "sign"]
+
+[[steps]]
+id = "announce"
+title = "Announce"
+when = "publish.output != 'dry-run'"
+needs = ["publish"]
+
+[vars.version]
+required = true
+
+[vars.platforms]
+default = "linux,darwin"
+
+[vars.sign]
+default = "false"
+`
+
+func TestExpand(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	x, err := f.Expand(map[string]string{"version": "v1.2.0"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+
+	var ids []string
+	for _, step := range x.Steps {
+		ids = append(ids, step.ID)
+	}
+	want := []string{"prepare", "build.linux", "build.darwin", "test.linux", "test.darwin", "publish", "announce"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expanded IDs = %v, want %v", ids, want)
+	}
+
+	// Paired with the matching build; sign was dropped so publish inherits its needs
+	if got := x.GetStep("test.darwin").Needs; !reflect.DeepEqual(got, []string{"build.darwin"}) {
+		t.Errorf("test.darwin needs %v, want [build.darwin]", got)
+	}
+	if got, want := x.GetStep("publish").Needs, []string{"test.linux", "test.darwin", "build.linux", "build.darwin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("publish needs %v, want %v", got, want)
+	}
+
+	build := x.GetStep("build.linux")
+	if build.Title != "Build v1.2.0 for linux" || build.Description != "GOOS=linux make build ({{unknown}})" {
+		t.Errorf("build.linux = %q / %q", build.Title, build.Description)
+	}
+	if got := x.GetStep("prepare").Title; got != "Prepare v1.2.0" {
+		t.Errorf("prepare title = %q", got)
+	}
+
+	// The original formula is untouched
+	if len(f.Steps) != 6 || f.Steps[1].Title != "Build {{version}} for {{item}}" {
+		t.Error("Expand modified the receiver")
+	}
+
+	order, err := x.TopologicalSort()
+	if err != nil {
+		t.Fatalf("TopologicalSort: %v", err)
+	}
+	pos := make(map[string]int)
+	for i, id := range order {
+		pos[id] = i
+	}
+	for _, step := range x.Steps {
+		for _, need := range step.Needs {
+			if pos[need] >= pos[step.ID] {
+				t.Errorf("%s sorted before its need %s: %v", step.ID, need, order)
+			}
+		}
+	}
+}
+
+func TestExpand_WhenTrueAndEmptyForeach(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := f.Expand(map[string]string{"version": "v2", "platforms": "", "sign": "true"})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	sign := x.GetStep("sign")
+	if sign == nil || sign.When != "" {
+		t.Fatalf("sign = %+v, want kept with condition resolved", sign)
+	}
+	// No platforms: build and test vanish and sign falls back to prepare
+	if !reflect.DeepEqual(sign.Needs, []string{"prepare"}) {
+		t.Errorf("sign needs %v, want [prepare]", sign.Needs)
+	}
+	if got := x.GetStep("publish").Needs; !reflect.DeepEqual(got, []string{"prepare", "sign"}) {
+		t.Errorf("publish needs %v, want [prepare sign]", got)
+	}
+}
+
+func TestExpand_MissingRequiredVar(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Expand(nil); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("Expand(nil) error = %v, want missing version", err)
+	}
+}
+
+func TestExpand_DuplicateItems(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Expand(map[string]string{"version": "v1", "platforms": "linux, linux"}); err == nil {
+		t.Error("Expand with duplicate items succeeded, want duplicate step id error")
+	}
+}
+
+func TestExpansionSteps(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"build", "test", "sign", "announce"}
+	if got := f.ExpansionSteps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpansionSteps() = %v, want %v", got, want)
+	}
+}
+
+func TestNextSteps_OutputConditions(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := f.Expand(map[string]string{"version": "v1", "platforms": "linux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed := map[string]bool{"prepare": true, "build.linux": true, "test.linux": true}
+	ready, skipped := x.NextSteps(completed, nil)
+	if !reflect.DeepEqual(ready, []string{"publish"}) || len(skipped) != 0 {
+		t.Fatalf("NextSteps = %v, %v; want [publish], []", ready, skipped)
+	}
+
+	completed["publish"] = true
+	ready, skipped = x.NextSteps(completed, map[string]string{"publish": "dry-run"})
+	if len(ready) != 0 || !reflect.DeepEqual(skipped, []string{"announce"}) {
+		t.Errorf("after dry run NextSteps = %v, %v; want [], [announce]", ready, skipped)
+	}
+	ready, _ = x.NextSteps(completed, map[string]string{"publish": "released"})
+	if !reflect.DeepEqual(ready, []string{"announce"}) {
+		t.Errorf("after release ready = %v, want [announce]", ready)
+	}
+}
+
+func TestNextSteps_SkipUnblocksDependents(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "gate"
+
+[[steps]]
+id = "check"
+
+[[steps]]
+id = "fix"
+when = "check.output == 'broken'"
+needs = ["check"]
+
+[[steps]]
+id = "report"
+needs = ["fix"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ready, skipped := f.NextSteps(map[string]bool{"check": true}, map[string]string{"check": "fine"})
+	if !reflect.DeepEqual(ready, []string{"report"}) || !reflect.DeepEqual(skipped, []string{"fix"}) {
+		t.Errorf("NextSteps = %v, %v; want [report], [fix]", ready, skipped)
+	}
+	if got := f.ReadySteps(map[string]bool{"check": true}); !reflect.DeepEqual(got, []string{"report"}) {
+		t.Errorf("ReadySteps = %v, want [report]", got)
+	}
+}
+
+func TestValidate_StepConditions(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps string
+		want  string
+	}{
+		{"unknown foreach var", `
+[[steps]]
+id = "a"
+foreach = "nope"`, "unknown var: nope"},
+		{"bad expression", `
+[[steps]]
+id = "a"
+when = "mode =="`, "invalid when"},
+		{"unknown when var", `
+[[steps]]
+id = "a"
+when = "nope"`, "unknown var: nope"},
+		{"item outside foreach", `
+[[steps]]
+id = "a"
+when = "item == 'x'"`, "unknown var: item"},
+		{"output of unneeded step", `
+[[steps]]
+id = "a"
+
+[[steps]]
+id = "b"
+when = "a.output == 'x'"`, "does not need"},
+		{"output of unknown step", `
+[[steps]]
+id = "a"
+when = "z.output"`, "unknown step: z"},
+		{"output of foreach step", `
+[[steps]]
+id = "a"
+foreach = "mode"
+
+[[steps]]
+id = "b"
+needs = ["a"]
+when = "a.output"`, "foreach step"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte("formula = \"t\"\n[vars.mode]\n" + tt.steps))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -3,34 +3,23 @@ package formula
 import (
 	"fmt"
 	"os"
-
-	"github.com/BurntSushi/toml"
+	"path/filepath"
 )
 
-// ParseFile reads and parses a formula.toml file.
+// ParseFile reads and parses a formula.toml file. Formulas it composes are
+// looked up next to it, then in the embedded formulas.
 func ParseFile(path string) (*Formula, error) {
 	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from trusted formula directory
 	if err != nil {
 		return nil, fmt.Errorf("reading formula file: %w", err)
 	}
-	return Parse(data)
+	return NewResolver(filepath.Dir(path)).Parse(data)
 }
 
-// Parse parses formula.toml content from bytes.
+// Parse parses formula.toml content from bytes. Formulas it composes are
+// looked up in the embedded formulas; use a Resolver to search directories.
 func Parse(data []byte) (*Formula, error) {
-	var f Formula
-	if _, err := toml.Decode(string(data), &f); err != nil {
-		return nil, fmt.Errorf("parsing TOML: %w", err)
-	}
-
-	// Infer type from content if not explicitly set
-	f.inferType()
-
-	if err := f.Validate(); err != nil {
-		return nil, err
-	}
-
-	return &f, nil
+	return NewResolver().Parse(data)
 }
 
 // inferType sets the formula type based on content when not explicitly set.
@@ -46,7 +35,7 @@ func (f *Formula) inferType() {
 		f.Type = TypeConvoy
 	} else if len(f.Template) > 0 {
 		f.Type = TypeExpansion
-	} else if len(f.Aspects) > 0 {
+	} else if len(f.Aspects) > 0 || len(f.Advice) > 0 {
 		f.Type = TypeAspect
 	}
 }
@@ -137,6 +126,69 @@ func (f *Formula) validateWorkflow() error {
 		return err
 	}
 
+	return f.validateStepConditions(seen)
+}
+
+// validateStepConditions checks foreach and when fields. A when may read
+// declared vars ("item" too in foreach steps) and the outputs of steps the
+// step transitively needs.
+func (f *Formula) validateStepConditions(seen map[string]bool) error {
+	deps := make(map[string][]string)
+	foreach := make(map[string]bool)
+	for _, step := range f.Steps {
+		deps[step.ID] = step.Needs
+		foreach[step.ID] = step.Foreach != ""
+	}
+	var needsTransitively func(id, target string, visited map[string]bool) bool
+	needsTransitively = func(id, target string, visited map[string]bool) bool {
+		for _, dep := range deps[id] {
+			if dep == target {
+				return true
+			}
+			if !visited[dep] {
+				visited[dep] = true
+				if needsTransitively(dep, target, visited) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, step := range f.Steps {
+		if step.Foreach != "" {
+			if _, ok := f.Vars[step.Foreach]; !ok {
+				return fmt.Errorf("step %q foreach references unknown var: %s", step.ID, step.Foreach)
+			}
+		}
+		if step.When == "" {
+			continue
+		}
+		cond, err := ParseCondition(step.When)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
+		for _, ref := range cond.Refs() {
+			id, isOutput := OutputRef(ref)
+			switch {
+			case isOutput && f.skipped[id]:
+				// Dropped during expansion; reads as empty
+			case isOutput && !seen[id]:
+				return fmt.Errorf("step %q when references unknown step: %s", step.ID, id)
+			case isOutput && foreach[id]:
+				return fmt.Errorf("step %q when references output of foreach step: %s", step.ID, id)
+			case isOutput && !needsTransitively(step.ID, id, make(map[string]bool)):
+				return fmt.Errorf("step %q when references output of %s, which it does not need", step.ID, id)
+			case isOutput, ref == "item" && (step.Foreach != "" || step.item != ""):
+				// Valid
+			default:
+				if _, ok := f.Vars[ref]; !ok {
+					return fmt.Errorf("step %q when references unknown var: %s", step.ID, ref)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -170,8 +222,24 @@ func (f *Formula) validateExpansion() error {
 }
 
 func (f *Formula) validateAspect() error {
-	if len(f.Aspects) == 0 {
-		return fmt.Errorf("aspect formula requires at least one aspect")
+	if len(f.Aspects) == 0 && len(f.Advice) == 0 {
+		return fmt.Errorf("aspect formula requires at least one aspect or advice")
+	}
+
+	for i, adv := range f.Advice {
+		if adv.Target == "" && len(f.Pointcuts) == 0 {
+			return fmt.Errorf("advice %d has no target and the formula has no pointcuts", i+1)
+		}
+		if len(adv.Around.Before) == 0 && len(adv.Around.After) == 0 {
+			return fmt.Errorf("advice %d has no before or after steps", i+1)
+		}
+		for _, steps := range [][]Step{adv.Around.Before, adv.Around.After} {
+			for _, step := range steps {
+				if step.ID == "" {
+					return fmt.Errorf("advice %d step missing required id field", i+1)
+				}
+			}
+		}
 	}
 
 	// Check aspect IDs are unique
@@ -324,26 +392,15 @@ func (f *Formula) TopologicalSort() ([]string, error) {
 
 // ReadySteps returns steps that have no unmet dependencies.
 // completed is a set of step IDs that have been completed.
+// Workflow steps whose when condition is false are skipped and don't block
+// their dependents; use NextSteps to pass step outputs and learn which
+// steps were skipped.
 func (f *Formula) ReadySteps(completed map[string]bool) []string {
 	var ready []string
 
 	switch f.Type {
 	case TypeWorkflow:
-		for _, step := range f.Steps {
-			if completed[step.ID] {
-				continue
-			}
-			allMet := true
-			for _, need := range step.Needs {
-				if !completed[need] {
-					allMet = false
-					break
-				}
-			}
-			if allMet {
-				ready = append(ready, step.ID)
-			}
-		}
+		ready, _ = f.NextSteps(completed, nil)
 	case TypeExpansion:
 		for _, tmpl := range f.Template {
 			if completed[tmpl.ID] {
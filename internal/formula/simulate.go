@@ -0,0 +1,380 @@
+package formula
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Outcome is the scripted result of a simulated step.
+type Outcome string
+
+const (
+	// OutcomePass completes the step (the default).
+	OutcomePass Outcome = "pass"
+	// OutcomeFail fails the step; its dependents never become ready.
+	OutcomeFail Outcome = "fail"
+	// OutcomeSkip skips the step; its dependents proceed.
+	OutcomeSkip Outcome = "skip"
+)
+
+// StepStatus is the state of a step in a simulated molecule.
+type StepStatus string
+
+const (
+	StepPending StepStatus = "pending"
+	StepDone    StepStatus = "done"
+	StepFailed  StepStatus = "failed"
+	StepSkipped StepStatus = "skipped"
+)
+
+// StepScript scripts one step of a simulation. Output is what the step
+// reports, visible to later when conditions as "<id>.output".
+type StepScript struct {
+	Outcome Outcome `toml:"outcome"`
+	Output  string  `toml:"output"`
+}
+
+// SimStep is a step of a simulated molecule.
+type SimStep struct {
+	ID     string
+	Title  string
+	Needs  []string
+	Status StepStatus
+	Output string
+	Wave   int // 1-based round the step ran or was skipped in; 0 if never reached
+}
+
+// Simulation is the final state of a formula run in memory.
+type Simulation struct {
+	// Formula is the instantiated formula that was run.
+	Formula *Formula
+
+	// Steps holds every step, in formula order.
+	Steps []SimStep
+
+	// Waves lists the steps that ran together, in order. Steps in a wave
+	// had all their needs met at the same time and could run in parallel.
+	Waves [][]string
+
+	// SynthesisInputs are the legs a convoy's synthesis step received.
+	SynthesisInputs []string
+}
+
+// Simulate instantiates f with vars and walks it to completion without
+// running anything. Ready steps are taken a wave at a time and given the
+// outcome scripted for them (pass when unscripted). Workflow when
+// conditions see scripted outputs. A convoy's synthesis runs as a final
+// step once its legs are done.
+func Simulate(f *Formula, vars map[string]string, script map[string]StepScript) (*Simulation, error) {
+	x, err := f.Expand(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := &Simulation{Formula: x}
+	index := make(map[string]int)
+	for _, id := range x.GetAllIDs() {
+		index[id] = len(sim.Steps)
+		step := SimStep{ID: id, Needs: x.GetDependencies(id), Status: StepPending}
+		switch x.Type {
+		case TypeWorkflow:
+			step.Title = x.GetStep(id).Title
+		case TypeExpansion:
+			step.Title = x.GetTemplate(id).Title
+		case TypeConvoy:
+			step.Title = x.GetLeg(id).Title
+		case TypeAspect:
+			step.Title = x.GetAspect(id).Title
+		}
+		sim.Steps = append(sim.Steps, step)
+	}
+	hasSynthesis := x.Type == TypeConvoy && x.Synthesis != nil
+	if hasSynthesis {
+		needs := x.Synthesis.DependsOn
+		if len(needs) == 0 {
+			needs = x.GetAllIDs()
+		}
+		index["synthesis"] = len(sim.Steps)
+		sim.Steps = append(sim.Steps, SimStep{ID: "synthesis", Title: x.Synthesis.Title, Needs: needs, Status: StepPending})
+	}
+
+	for id, s := range script {
+		if _, ok := index[id]; !ok {
+			return nil, fmt.Errorf("script references unknown step: %s", id)
+		}
+		switch s.Outcome {
+		case "", OutcomePass, OutcomeFail, OutcomeSkip:
+		default:
+			return nil, fmt.Errorf("step %s: invalid outcome %q (must be pass, fail or skip)", id, s.Outcome)
+		}
+	}
+
+	done := make(map[string]bool)
+	outputs := make(map[string]string)
+	for wave := 1; ; wave++ {
+		var ready, skipped []string
+		if x.Type == TypeWorkflow {
+			ready, skipped = x.NextSteps(done, outputs)
+		} else {
+			ready = x.ReadySteps(done)
+		}
+		if hasSynthesis && !done["synthesis"] {
+			met := true
+			for _, need := range sim.Steps[index["synthesis"]].Needs {
+				met = met && done[need]
+			}
+			if met {
+				ready = append(ready, "synthesis")
+			}
+		}
+
+		var ran []string
+		for _, id := range skipped {
+			step := &sim.Steps[index[id]]
+			step.Status, step.Wave = StepSkipped, wave
+			done[id] = true
+		}
+		for _, id := range ready {
+			step := &sim.Steps[index[id]]
+			if step.Status != StepPending {
+				continue // Failed in an earlier wave
+			}
+			step.Wave = wave
+			s := script[id]
+			switch s.Outcome {
+			case OutcomeFail:
+				step.Status = StepFailed
+				ran = append(ran, id)
+			case OutcomeSkip:
+				step.Status = StepSkipped
+				done[id] = true
+			default:
+				step.Status = StepDone
+				step.Output = s.Output
+				outputs[id] = s.Output
+				done[id] = true
+				ran = append(ran, id)
+			}
+			if id == "synthesis" {
+				for _, need := range step.Needs {
+					if sim.Steps[index[need]].Status == StepDone {
+						sim.SynthesisInputs = append(sim.SynthesisInputs, need)
+					}
+				}
+			}
+		}
+		if !progressed(sim, wave) {
+			break
+		}
+		if len(ran) > 0 {
+			sim.Waves = append(sim.Waves, ran)
+		}
+	}
+	return sim, nil
+}
+
+// progressed reports whether any step changed state in wave.
+func progressed(sim *Simulation, wave int) bool {
+	for _, s := range sim.Steps {
+		if s.Wave == wave {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is "done" when every step is done or skipped, "failed" when a
+// step failed, and "stuck" otherwise.
+func (s *Simulation) Status() string {
+	status := "done"
+	for _, step := range s.Steps {
+		switch step.Status {
+		case StepFailed:
+			return "failed"
+		case StepPending:
+			status = "stuck"
+		}
+	}
+	return status
+}
+
+// Order returns the steps that ran (done or failed), wave by wave.
+func (s *Simulation) Order() []string {
+	var order []string
+	for _, wave := range s.Waves {
+		order = append(order, wave...)
+	}
+	return order
+}
+
+// StepsWith returns the IDs of steps with the given status, in formula order.
+func (s *Simulation) StepsWith(status StepStatus) []string {
+	var ids []string
+	for _, step := range s.Steps {
+		if step.Status == status {
+			ids = append(ids, step.ID)
+		}
+	}
+	return ids
+}
+
+// Fixture is a formula test file: a formula and scripted cases to run it
+// through.
+//
+//	formula = "mol-refinery-patrol"
+//
+//	[vars]
+//	rig = "gastown"
+//
+//	[[cases]]
+//	name = "tests fail"
+//	[cases.steps.run-tests]
+//	outcome = "fail"
+//	[cases.expect]
+//	status = "failed"
+//	failed = ["run-tests"]
+//	blocked = ["merge-push"]
+type Fixture struct {
+	Formula string            `toml:"formula"`
+	Vars    map[string]string `toml:"vars"`
+	Cases   []FixtureCase     `toml:"cases"`
+}
+
+// FixtureCase is one scripted run of a fixture's formula. Vars add to and
+// override the fixture's vars.
+type FixtureCase struct {
+	Name   string                `toml:"name"`
+	Vars   map[string]string     `toml:"vars"`
+	Steps  map[string]StepScript `toml:"steps"`
+	Expect Expectation           `toml:"expect"`
+}
+
+// Expectation asserts on a simulation's final state. Unset fields are not
+// checked. Step lists compare as sets, except Order, which is the exact
+// sequence of steps that ran. Before holds [earlier, later] pairs that must
+// run in different waves, in that order. Blocked steps are those left
+// pending because a need failed.
+type Expectation struct {
+	Status    string     `toml:"status"`
+	Order     []string   `toml:"order"`
+	Before    [][]string `toml:"before"`
+	Done      []string   `toml:"done"`
+	Failed    []string   `toml:"failed"`
+	Skipped   []string   `toml:"skipped"`
+	Blocked   []string   `toml:"blocked"`
+	Synthesis []string   `toml:"synthesis"`
+}
+
+// ParseFixture parses a formula test fixture.
+func ParseFixture(data []byte) (*Fixture, error) {
+	var fx Fixture
+	if _, err := toml.Decode(string(data), &fx); err != nil {
+		return nil, fmt.Errorf("parsing fixture: %w", err)
+	}
+	if fx.Formula == "" {
+		return nil, fmt.Errorf("fixture formula field is required")
+	}
+	if len(fx.Cases) == 0 {
+		return nil, fmt.Errorf("fixture has no cases")
+	}
+	for i, c := range fx.Cases {
+		if c.Name == "" {
+			fx.Cases[i].Name = fmt.Sprintf("case %d", i+1)
+		}
+	}
+	return &fx, nil
+}
+
+// Run simulates f for the case, returning the simulation and the
+// expectations it failed.
+func (c *FixtureCase) Run(f *Formula, fixtureVars map[string]string) (*Simulation, []string, error) {
+	vars := make(map[string]string)
+	for k, v := range fixtureVars {
+		vars[k] = v
+	}
+	for k, v := range c.Vars {
+		vars[k] = v
+	}
+	sim, err := Simulate(f, vars, c.Steps)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sim, c.Expect.Check(sim), nil
+}
+
+// Check returns a description of each way sim differs from e.
+func (e *Expectation) Check(sim *Simulation) []string {
+	var failures []string
+	if e.Status != "" && e.Status != sim.Status() {
+		failures = append(failures, fmt.Sprintf("status: got %s, want %s", sim.Status(), e.Status))
+	}
+	if e.Order != nil && strings.Join(e.Order, ",") != strings.Join(sim.Order(), ",") {
+		failures = append(failures, fmt.Sprintf("order: got %v, want %v", sim.Order(), e.Order))
+	}
+
+	position := make(map[string]int)
+	for i, id := range sim.Order() {
+		position[id] = i + 1
+	}
+	for _, pair := range e.Before {
+		if len(pair) != 2 {
+			failures = append(failures, fmt.Sprintf("before: %v is not an [earlier, later] pair", pair))
+			continue
+		}
+		a, b := position[pair[0]], position[pair[1]]
+		if a == 0 || b == 0 || a > b || sameWave(sim, pair[0], pair[1]) {
+			failures = append(failures, fmt.Sprintf("before: %s did not run before %s", pair[0], pair[1]))
+		}
+	}
+
+	sets := []struct {
+		name string
+		want []string
+		got  []string
+	}{
+		{"done", e.Done, sim.StepsWith(StepDone)},
+		{"failed", e.Failed, sim.StepsWith(StepFailed)},
+		{"skipped", e.Skipped, sim.StepsWith(StepSkipped)},
+		{"blocked", e.Blocked, sim.StepsWith(StepPending)},
+		{"synthesis", e.Synthesis, sim.SynthesisInputs},
+	}
+	for _, s := range sets {
+		if s.want != nil && !sameSet(s.want, s.got) {
+			failures = append(failures, fmt.Sprintf("%s: got %v, want %v", s.name, s.got, s.want))
+		}
+	}
+	return failures
+}
+
+// sameWave reports whether two steps ran in the same wave.
+func sameWave(sim *Simulation, a, b string) bool {
+	var wa, wb int
+	for _, s := range sim.Steps {
+		switch s.ID {
+		case a:
+			wa = s.Wave
+		case b:
+			wb = s.Wave
+		}
+	}
+	return wa == wb
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSimulate_Workflow(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := Simulate(f, map[string]string{"version": "v1"}, map[string]StepScript{
+		"publish": {Output: "dry-run"},
+	})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if sim.Status() != "done" {
+		t.Errorf("Status = %s, want done", sim.Status())
+	}
+	wantWaves := [][]string{
+		{"prepare"},
+		{"build.linux", "build.darwin"},
+		{"test.linux", "test.darwin"},
+		{"publish"},
+	}
+	if !reflect.DeepEqual(sim.Waves, wantWaves) {
+		t.Errorf("Waves = %v, want %v", sim.Waves, wantWaves)
+	}
+	if got := sim.StepsWith(StepSkipped); !reflect.DeepEqual(got, []string{"announce"}) {
+		t.Errorf("skipped = %v, want [announce] (publish was a dry run)", got)
+	}
+}
+
+func TestSimulate_FailureBlocksDependents(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := Simulate(f, map[string]string{"version": "v1"}, map[string]StepScript{
+		"build.darwin": {Outcome: OutcomeFail},
+		"test.linux":   {Outcome: OutcomeSkip},
+	})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if sim.Status() != "failed" {
+		t.Errorf("Status = %s, want failed", sim.Status())
+	}
+	if got := sim.Order(); !reflect.DeepEqual(got, []string{"prepare", "build.linux", "build.darwin"}) {
+		t.Errorf("Order = %v", got)
+	}
+	if got := sim.StepsWith(StepPending); !reflect.DeepEqual(got, []string{"test.darwin", "publish", "announce"}) {
+		t.Errorf("blocked = %v", got)
+	}
+	if got := sim.StepsWith(StepSkipped); !reflect.DeepEqual(got, []string{"test.linux"}) {
+		t.Errorf("skipped = %v", got)
+	}
+}
+
+func TestSimulate_ConvoySynthesis(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "review"
+type = "convoy"
+
+[[legs]]
+id = "security"
+
+[[legs]]
+id = "perf"
+
+[[legs]]
+id = "style"
+
+[synthesis]
+title = "Combine"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := Simulate(f, nil, map[string]StepScript{"perf": {Outcome: OutcomeSkip}})
+	if err != nil {
+		t.Fatalf("Simulate: %v", err)
+	}
+	if !reflect.DeepEqual(sim.Waves, [][]string{{"security", "style"}, {"synthesis"}}) {
+		t.Errorf("Waves = %v", sim.Waves)
+	}
+	if !reflect.DeepEqual(sim.SynthesisInputs, []string{"security", "style"}) {
+		t.Errorf("SynthesisInputs = %v, want [security style]", sim.SynthesisInputs)
+	}
+}
+
+func TestSimulate_ScriptErrors(t *testing.T) {
+	f, err := Parse([]byte(releaseFormula))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"version": "v1"}
+	if _, err := Simulate(f, vars, map[string]StepScript{"build": {}}); err == nil || !strings.Contains(err.Error(), "unknown step: build") {
+		t.Errorf("unexpanded step ID: err = %v", err)
+	}
+	if _, err := Simulate(f, vars, map[string]StepScript{"prepare": {Outcome: "explode"}}); err == nil {
+		t.Error("invalid outcome accepted")
+	}
+}
+
+func TestFixture_Run(t *testing.T) {
+	fx, err := ParseFixture([]byte(`
+formula = "mol-refinery-patrol"
+
+[[cases]]
+name = "happy path"
+[cases.expect]
+status = "done"
+before = [["queue-scan", "run-tests"], ["run-tests", "merge-push"]]
+failed = []
+
+[[cases]]
+name = "tests fail"
+[cases.steps.run-tests]
+outcome = "fail"
+[cases.expect]
+status = "failed"
+order = ["inbox-check", "queue-scan", "process-branch", "run-tests"]
+failed = ["run-tests"]
+
+[[cases]]
+name = "wrong expectations"
+[cases.expect]
+status = "failed"
+order = ["queue-scan"]
+before = [["merge-push", "run-tests"]]
+skipped = ["inbox-check"]
+`))
+	if err != nil {
+		t.Fatalf("ParseFixture: %v", err)
+	}
+	f, err := NewResolver().Load(fx.Formula)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, wantFailures := range []int{0, 0, 4} {
+		c := &fx.Cases[i]
+		_, failures, err := c.Run(f, fx.Vars)
+		if err != nil {
+			t.Fatalf("%s: %v", c.Name, err)
+		}
+		if len(failures) != wantFailures {
+			t.Errorf("%s: failures = %q, want %d", c.Name, failures, wantFailures)
+		}
+	}
+}
+
+func TestParseFixture_Errors(t *testing.T) {
+	for _, data := range []string{
+		`[[cases]]`,
+		`formula = "x"`,
+		`formula = `,
+	} {
+		if _, err := ParseFixture([]byte(data)); err == nil {
+			t.Errorf("ParseFixture(%q) succeeded", data)
+		}
+	}
+}
@@ -8,6 +8,8 @@
 //   - aspect: Multi-aspect parallel analysis (like convoy but for analysis)
 package formula
 
+import "fmt"
+
 // FormulaType represents the type of formula.
 type FormulaType string
 
@@ -46,6 +48,21 @@ type Formula struct {
 
 	// Aspect-specific (similar to convoy but for analysis)
 	Aspects []Aspect `toml:"aspects"`
+
+	// Advice injects steps around matching steps of formulas that compose
+	// this aspect; Pointcuts select the steps for advice without a target.
+	Advice    []Advice   `toml:"advice"`
+	Pointcuts []Pointcut `toml:"pointcuts"`
+
+	// Composition, resolved at parse time (see Resolver)
+	Extends StringList `toml:"extends"`
+	Include []Include  `toml:"include"`
+	Compose *Compose   `toml:"compose"`
+
+	// Set by Expand: the variable values the formula was expanded with,
+	// and the steps it dropped (false static when, empty foreach).
+	bound   map[string]string
+	skipped map[string]bool
 }
 
 // Aspect represents a parallel analysis aspect in an aspect formula.
@@ -56,6 +73,76 @@ type Aspect struct {
 	Description string `toml:"description"`
 }
 
+// Advice adds steps before and after each step matching Target, a glob over
+// step IDs. In advice steps {step.id} and {step.title} refer to the
+// matched step.
+type Advice struct {
+	Target string       `toml:"target"`
+	Around AdviceAround `toml:"around"`
+}
+
+// AdviceAround holds the steps an Advice injects.
+type AdviceAround struct {
+	Before []Step `toml:"before"`
+	After  []Step `toml:"after"`
+}
+
+// Pointcut selects steps by ID glob for advice without its own target.
+type Pointcut struct {
+	Glob string `toml:"glob"`
+}
+
+// Include pulls the steps of another workflow formula into this one.
+// With Prefix set, included step IDs become "<prefix>.<id>". Included steps
+// without needs get Needs, so they can be ordered after local steps.
+type Include struct {
+	Formula string   `toml:"formula"`
+	Prefix  string   `toml:"prefix"`
+	Needs   []string `toml:"needs"`
+}
+
+// Compose applies other formulas to this one's steps.
+type Compose struct {
+	// Aspects are aspect formulas whose advice is applied.
+	Aspects []string `toml:"aspects"`
+
+	// Expand replaces target steps with an expansion formula's templates.
+	Expand []ComposeExpand `toml:"expand"`
+}
+
+// ComposeExpand replaces step Target with the templates of expansion formula
+// With. In templates {target}, {target.title} and {target.description}
+// refer to the replaced step.
+type ComposeExpand struct {
+	Target string `toml:"target"`
+	With   string `toml:"with"`
+}
+
+// StringList is a list of strings that may be written in TOML as a single
+// string or an array.
+type StringList []string
+
+// UnmarshalTOML implements toml.Unmarshaler.
+func (l *StringList) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		*l = StringList{v}
+	case []any:
+		list := make(StringList, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("expected string, got %T", item)
+			}
+			list = append(list, s)
+		}
+		*l = list
+	default:
+		return fmt.Errorf("expected string or array of strings, got %T", v)
+	}
+	return nil
+}
+
 // Input represents an input parameter for a formula.
 type Input struct {
 	Description    string   `toml:"description"`
@@ -93,6 +180,17 @@ type Step struct {
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
+
+	// When is a condition (see ParseCondition) over vars and the outputs of
+	// earlier steps. A step whose condition is false is skipped.
+	When string `toml:"when"`
+
+	// Foreach names a list var (comma-separated). Expand fans the step out
+	// into one step per item, with IDs "<id>.<item>" and {{item}} bound.
+	Foreach string `toml:"foreach"`
+
+	// item is the foreach value bound to an expanded step.
+	item string
 }
 
 // Template represents a template step in an expansion formula.
@@ -872,6 +872,13 @@ func (g *Git) Merge(branch string) error {
 	return err
 }
 
+// MergeFFOnly fast-forwards the current branch to ref, failing if that would
+// require a merge commit.
+func (g *Git) MergeFFOnly(ref string) error {
+	_, err := g.run("merge", "--ff-only", ref)
+	return err
+}
+
 // MergeNoFF merges the given branch with --no-ff flag and a custom message.
 func (g *Git) MergeNoFF(branch, message string) error {
 	_, err := g.run("merge", "--no-ff", "-m", message, branch)
@@ -911,6 +918,12 @@ func (g *Git) Rebase(onto string) error {
 	return err
 }
 
+// ResetHard resets the current branch, index, and working tree to ref.
+func (g *Git) ResetHard(ref string) error {
+	_, err := g.run("reset", "--hard", ref)
+	return err
+}
+
 // AbortMerge aborts a merge in progress.
 func (g *Git) AbortMerge() error {
 	_, err := g.run("merge", "--abort")
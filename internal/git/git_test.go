@@ -501,3 +501,52 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestMergeFFOnlyAndResetHard(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.Rev("HEAD")
+	if err != nil {
+		t.Fatalf("Rev: %v", err)
+	}
+	branch, _ := g.CurrentBranch()
+
+	// Advance a side branch, then fast-forward the original branch onto it
+	if err := g.CreateBranch("ahead"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("ahead"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add("new.txt"); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := g.Commit("add new"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	ahead, _ := g.Rev("HEAD")
+
+	if err := g.Checkout(branch); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := g.MergeFFOnly("ahead"); err != nil {
+		t.Fatalf("MergeFFOnly: %v", err)
+	}
+	if head, _ := g.Rev("HEAD"); head != ahead {
+		t.Errorf("HEAD = %s after fast-forward, want %s", head, ahead)
+	}
+
+	if err := g.ResetHard(base); err != nil {
+		t.Fatalf("ResetHard: %v", err)
+	}
+	if head, _ := g.Rev("HEAD"); head != base {
+		t.Errorf("HEAD = %s after reset, want %s", head, base)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); !os.IsNotExist(err) {
+		t.Errorf("new.txt should be gone after hard reset")
+	}
+}
@@ -0,0 +1,319 @@
+package guard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// HookInput is the PreToolUse payload Claude Code sends on stdin.
+type HookInput struct {
+	SessionID     string                 `json:"session_id"`
+	Cwd           string                 `json:"cwd"`
+	HookEventName string                 `json:"hook_event_name"`
+	ToolName      string                 `json:"tool_name"`
+	ToolInput     map[string]interface{} `json:"tool_input"`
+}
+
+// ParseHookInput parses a PreToolUse payload.
+func ParseHookInput(data []byte) (*HookInput, error) {
+	var in HookInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("parsing hook input: %w", err)
+	}
+	if in.ToolName == "" {
+		return nil, fmt.Errorf("hook input has no tool_name")
+	}
+	return &in, nil
+}
+
+// Context is who is making the tool call and where.
+type Context struct {
+	Role     string // Simple role (polecat, crew, ...), empty for humans
+	Rig      string
+	TownRoot string
+	RigPath  string
+	Worktree string // Defaults to the git toplevel of Cwd
+	Home     string
+	Cwd      string
+}
+
+// WorktreeFor returns the git worktree containing dir, or dir itself.
+func WorktreeFor(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return dir
+	}
+	if top := strings.TrimSpace(string(out)); top != "" {
+		return top
+	}
+	return dir
+}
+
+// Decision is the outcome of evaluating a tool call.
+type Decision struct {
+	Allowed bool
+	Rule    *Rule  // The rule that decided; nil if none matched
+	Source  string // Policy file of Rule
+	Match   string // The command or path that matched, for messages
+}
+
+// Evaluate checks a tool call against policies in order. The first matching
+// rule decides; without one the call is allowed.
+func Evaluate(policies []*Policy, in *HookInput, ctx Context) Decision {
+	for _, p := range policies {
+		for i := range p.Rules {
+			r := &p.Rules[i]
+			match, ok := r.matches(in, ctx)
+			if !ok {
+				continue
+			}
+			return Decision{
+				Allowed: r.Action == ActionAllow,
+				Rule:    r,
+				Source:  p.Source,
+				Match:   match,
+			}
+		}
+	}
+	return Decision{Allowed: true}
+}
+
+// matches reports whether the rule applies to the tool call, with the
+// command or path it matched.
+func (r *Rule) matches(in *HookInput, ctx Context) (string, bool) {
+	if len(r.Roles) > 0 && !contains(r.Roles, ctx.Role) {
+		return "", false
+	}
+	if len(r.Rigs) > 0 && !contains(r.Rigs, ctx.Rig) {
+		return "", false
+	}
+	if len(r.Tools) > 0 && !matchAny(r.Tools, in.ToolName, false) {
+		return "", false
+	}
+	match := in.ToolName
+
+	// Commands narrow the call to the simple commands that matched; their
+	// arguments are the paths a Bash call touches. Without a command filter,
+	// only path-like arguments of any command count.
+	var commands []string
+	if command, _ := in.ToolInput["command"].(string); command != "" {
+		commands = SplitCommands(command)
+	}
+	if len(r.Commands) > 0 {
+		var matched []string
+		for _, c := range commands {
+			if matchAny(r.Commands, c, false) {
+				matched = append(matched, c)
+			}
+		}
+		if len(matched) == 0 {
+			return "", false
+		}
+		commands = matched
+		match = matched[0]
+	}
+
+	if len(r.Paths) > 0 {
+		paths := touchedPaths(in, commands, len(r.Commands) > 0, ctx)
+		for _, path := range paths {
+			if r.matchesPath(path, ctx) {
+				return path, true
+			}
+		}
+		return "", false
+	}
+	return match, true
+}
+
+// matchesPath reports whether path matches every path pattern of the rule.
+// Negated patterns match paths outside them.
+func (r *Rule) matchesPath(path string, ctx Context) bool {
+	for _, pat := range r.Paths {
+		negate := strings.HasPrefix(pat, "!")
+		pat = expandVars(strings.TrimPrefix(pat, "!"), ctx)
+		re, err := compileGlob(pat, true)
+		if err != nil || re.MatchString(path) == negate {
+			return false
+		}
+	}
+	return true
+}
+
+// touchedPaths returns the absolute paths a tool call reads or writes. When
+// a rule names commands, every non-flag argument of the matched commands is
+// taken as a path; otherwise only arguments that look like paths are, so a
+// commit message mentioning .beads doesn't count as touching it.
+func touchedPaths(in *HookInput, commands []string, anyArg bool, ctx Context) []string {
+	var paths []string
+	for _, key := range []string{"file_path", "notebook_path", "path"} {
+		if p, _ := in.ToolInput[key].(string); p != "" {
+			paths = append(paths, absPath(p, ctx))
+		}
+	}
+	for _, c := range commands {
+		words := splitWords(c)
+		for _, arg := range words[min(1, len(words)):] {
+			arg = redirection.ReplaceAllString(arg, "")
+			if arg == "" || strings.HasPrefix(arg, "-") {
+				continue
+			}
+			if anyArg || isPathLike(arg) {
+				paths = append(paths, absPath(arg, ctx))
+			}
+		}
+	}
+	return paths
+}
+
+// isPathLike reports whether a command argument looks like a file path
+// rather than a word or message.
+func isPathLike(arg string) bool {
+	if strings.ContainsAny(arg, " \t") {
+		return false
+	}
+	return strings.Contains(arg, "/") || strings.HasPrefix(arg, ".") || strings.HasPrefix(arg, "~")
+}
+
+// splitWords splits a simple command into words, removing single and double
+// quotes so a quoted argument stays one word.
+func splitWords(c string) []string {
+	var (
+		words []string
+		word  strings.Builder
+		quote rune
+		in    bool
+	)
+	for _, ch := range c {
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				word.WriteRune(ch)
+			}
+		case ch == '"' || ch == '\'':
+			quote, in = ch, true
+		case ch == ' ' || ch == '\t':
+			if in {
+				words = append(words, word.String())
+				word.Reset()
+				in = false
+			}
+		default:
+			word.WriteRune(ch)
+			in = true
+		}
+	}
+	if in {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func absPath(p string, ctx Context) string {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		p = ctx.Home + p[1:]
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(ctx.Cwd, p)
+	}
+	return filepath.Clean(p)
+}
+
+// expandVars substitutes {town}, {rig}, {worktree} and {home} in a pattern.
+func expandVars(pat string, ctx Context) string {
+	return strings.NewReplacer(
+		"{town}", ctx.TownRoot,
+		"{rig}", ctx.RigPath,
+		"{worktree}", ctx.Worktree,
+		"{home}", ctx.Home,
+	).Replace(pat)
+}
+
+var (
+	commandSeparator = regexp.MustCompile(`&&|\|\||[;|\n]`)
+	envName          = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	redirection      = regexp.MustCompile(`^[0-9]*(>>?|<)&?`)
+)
+
+// SplitCommands splits a Bash command line into simple commands on &&, ||,
+// ;, | and newlines, collapsing whitespace and dropping leading VAR=value
+// assignments. Quoting isn't interpreted.
+func SplitCommands(line string) []string {
+	parts := commandSeparator.Split(line, -1)
+	var commands []string
+	for _, part := range parts {
+		fields := strings.Fields(part)
+		for len(fields) > 0 && isAssignment(fields[0]) {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 {
+			commands = append(commands, strings.Join(fields, " "))
+		}
+	}
+	return commands
+}
+
+func isAssignment(field string) bool {
+	name, _, ok := strings.Cut(field, "=")
+	return ok && envName.MatchString(name)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, s string, path bool) bool {
+	for _, pat := range patterns {
+		if re, err := compileGlob(pat, path); err == nil && re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileGlob compiles a rule pattern. "re:" patterns are regular
+// expressions, matched unanchored. Globs are anchored: * matches any run of
+// characters, except / in path patterns, where ** matches across elements
+// (and a trailing /** matches the directory itself). Path patterns not
+// starting with / match at any depth.
+func compileGlob(pat string, path bool) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pat, "re:"); ok {
+		return regexp.Compile(expr)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if path && !strings.HasPrefix(pat, "/") {
+		b.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pat); i++ {
+		switch c := pat[i]; {
+		case path && strings.HasPrefix(pat[i:], "/**") && i+3 == len(pat):
+			b.WriteString("(/.*)?")
+			i += 2
+		case c == '*' && path && strings.HasPrefix(pat[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*' && path:
+			b.WriteString("[^/]*")
+		case c == '*':
+			b.WriteString(".*")
+		case c == '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
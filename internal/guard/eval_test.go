@@ -0,0 +1,183 @@
+package guard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func bash(command string) *HookInput {
+	return &HookInput{ToolName: "Bash", ToolInput: map[string]interface{}{"command": command}}
+}
+
+func edit(path string) *HookInput {
+	return &HookInput{ToolName: "Edit", ToolInput: map[string]interface{}{"file_path": path}}
+}
+
+func polecatContext() Context {
+	return Context{
+		Role:     "polecat",
+		Rig:      "gastown",
+		TownRoot: "/gt",
+		RigPath:  "/gt/gastown",
+		Worktree: "/gt/gastown/polecats/nux/gastown",
+		Home:     "/home/me",
+		Cwd:      "/gt/gastown/polecats/nux/gastown",
+	}
+}
+
+func TestEvaluateDefaultPolicy(t *testing.T) {
+	policies := []*Policy{DefaultPolicy()}
+	policies[0].Source = "/gt/settings/guard-policy.json"
+
+	tests := []struct {
+		name string
+		in   *HookInput
+		rule string // empty if allowed
+	}{
+		{"plain push", bash("git push origin main"), ""},
+		{"force push", bash("git push --force origin main"), "no-force-push"},
+		{"force push short flag", bash("git push -f"), "no-force-push"},
+		{"force with lease", bash("git push origin main --force-with-lease"), "no-force-push"},
+		{"force push after cd", bash("cd /tmp && git push -f origin main"), "no-force-push"},
+		{"force push with env", bash("GIT_TRACE=1 git push --force"), "no-force-push"},
+		{"branch named like a flag", bash("git push origin fix-f"), ""},
+		{"edit beads file", edit("/gt/gastown/.beads/issues.jsonl"), "no-direct-beads-edits"},
+		{"cat beads file", bash("cat .beads/issues.jsonl"), "no-direct-beads-edits"},
+		{"beads dir itself", bash("ls ../.beads"), "no-direct-beads-edits"},
+		{"redirect into beads", bash("echo x >.beads/notes"), "no-direct-beads-edits"},
+		{"quoted beads path", bash(`cat "./.beads/issues.jsonl"`), "no-direct-beads-edits"},
+		{"commit message mentions beads", bash(`git commit -m ".beads cleanup"`), ""},
+		{"bare word args", bash("grep beads README.md"), ""},
+		{"edit source", edit("/gt/gastown/polecats/nux/gastown/main.go"), ""},
+		{"bd command", bash("bd close gt-abc"), ""},
+		{"rm -rf in worktree", bash("rm -rf build ./dist"), ""},
+		{"rm -rf outside worktree", bash("rm -rf ../../crew"), "no-rm-rf-outside-worktree"},
+		{"rm -rf absolute", bash("rm -rf /"), "no-rm-rf-outside-worktree"},
+		{"rm -r -f home", bash("rm -r -f ~/notes"), "no-rm-rf-outside-worktree"},
+		{"plain rm outside", bash("rm /tmp/scratch.txt"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Evaluate(policies, tt.in, polecatContext())
+			if tt.rule == "" {
+				if !d.Allowed {
+					t.Errorf("blocked by %s (matched %q), want allowed", d.Rule.Name, d.Match)
+				}
+				return
+			}
+			if d.Allowed || d.Rule == nil || d.Rule.Name != tt.rule {
+				t.Fatalf("decision = %+v, want blocked by %s", d, tt.rule)
+			}
+			if d.Source != policies[0].Source || d.Match == "" {
+				t.Errorf("source = %q, match = %q", d.Source, d.Match)
+			}
+		})
+	}
+}
+
+func TestEvaluateRoleAndRigScope(t *testing.T) {
+	policies := []*Policy{DefaultPolicy()}
+	crew := polecatContext()
+	crew.Role = "crew"
+	if d := Evaluate(policies, bash("git push --force"), crew); !d.Allowed {
+		t.Errorf("crew blocked by polecat-only rule %s", d.Rule.Name)
+	}
+
+	rigOnly := &Policy{Rules: []Rule{{Name: "frozen", Tools: []string{"Write"}, Rigs: []string{"beads"}}}}
+	write := &HookInput{ToolName: "Write", ToolInput: map[string]interface{}{"file_path": "x.go"}}
+	if d := Evaluate([]*Policy{rigOnly}, write, polecatContext()); !d.Allowed {
+		t.Error("rule scoped to rig beads applied in gastown")
+	}
+	ctx := polecatContext()
+	ctx.Rig = "beads"
+	if d := Evaluate([]*Policy{rigOnly}, write, ctx); d.Allowed {
+		t.Error("rule scoped to rig beads didn't apply there")
+	}
+}
+
+func TestEvaluateFirstMatchWins(t *testing.T) {
+	rig := &Policy{Source: "rig", Rules: []Rule{{
+		Name:     "refinery-may-force",
+		Action:   ActionAllow,
+		Commands: []string{"git push --force*"},
+	}}}
+	town := &Policy{Source: "town", Rules: []Rule{{
+		Name:     "never-force",
+		Commands: []string{"git push *--force*"},
+	}}}
+
+	d := Evaluate([]*Policy{rig, town}, bash("git push --force origin main"), polecatContext())
+	if !d.Allowed || d.Rule == nil || d.Rule.Name != "refinery-may-force" {
+		t.Errorf("decision = %+v, want rig allow rule to win", d)
+	}
+	d = Evaluate([]*Policy{rig, town}, bash("git push origin main --force"), polecatContext())
+	if d.Allowed || d.Source != "town" {
+		t.Errorf("decision = %+v, want town deny", d)
+	}
+	if d := Evaluate(nil, bash("rm -rf /"), polecatContext()); !d.Allowed || d.Rule != nil {
+		t.Errorf("no policies: decision = %+v, want allowed", d)
+	}
+}
+
+func TestEvaluateToolGlobs(t *testing.T) {
+	p := &Policy{Rules: []Rule{{Name: "no-mcp", Tools: []string{"mcp__*"}}}}
+	if d := Evaluate([]*Policy{p}, &HookInput{ToolName: "mcp__github__create_pr"}, polecatContext()); d.Allowed {
+		t.Error("mcp tool not blocked")
+	}
+	if d := Evaluate([]*Policy{p}, bash("ls"), polecatContext()); !d.Allowed {
+		t.Error("Bash blocked by mcp__* rule")
+	}
+}
+
+func TestSplitCommands(t *testing.T) {
+	got := SplitCommands("cd  /tmp && FOO=1 BAR=2 make test || echo fail; cat x | grep y\nls")
+	want := []string{"cd /tmp", "make test", "echo fail", "cat x", "grep y", "ls"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitCommands = %q, want %q", got, want)
+	}
+}
+
+func TestCompileGlob(t *testing.T) {
+	tests := []struct {
+		pat   string
+		path  bool
+		s     string
+		match bool
+	}{
+		{"git push *--force*", false, "git push origin --force", true},
+		{"git push *--force*", false, "git pull --force", false},
+		{"re:^gh pr", false, "gh pr create", true},
+		{".beads/**", true, "/gt/rig/.beads/issues.jsonl", true},
+		{".beads/**", true, "/gt/rig/.beads", true},
+		{".beads/**", true, "/gt/rig/.beadsx/a", false},
+		{"/gt/*/settings", true, "/gt/rig/settings", true},
+		{"/gt/*/settings", true, "/gt/rig/sub/settings", false},
+		{"/gt/**/settings", true, "/gt/rig/sub/settings", true},
+		{"*.env", true, "/gt/rig/prod.env", true},
+	}
+	for _, tt := range tests {
+		re, err := compileGlob(tt.pat, tt.path)
+		if err != nil {
+			t.Fatalf("compileGlob(%q): %v", tt.pat, err)
+		}
+		if got := re.MatchString(tt.s); got != tt.match {
+			t.Errorf("%q matches %q = %v, want %v", tt.pat, tt.s, got, tt.match)
+		}
+	}
+}
+
+func TestParseHookInput(t *testing.T) {
+	in, err := ParseHookInput([]byte(`{"session_id":"s1","cwd":"/gt/rig","hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"ls"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.ToolName != "Bash" || in.Cwd != "/gt/rig" || in.ToolInput["command"] != "ls" {
+		t.Errorf("parsed = %+v", in)
+	}
+	if _, err := ParseHookInput([]byte(`{"cwd":"/x"}`)); err == nil {
+		t.Error("payload without tool_name parsed")
+	}
+	if _, err := ParseHookInput([]byte(`nope`)); err == nil {
+		t.Error("malformed payload parsed")
+	}
+}
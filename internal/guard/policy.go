@@ -0,0 +1,229 @@
+// Package guard evaluates Claude Code PreToolUse payloads against declarative
+// allow/deny policies.
+//
+// Policies live in settings/guard-policy.json in the town and in each rig.
+// Each rule matches on the tool name, Bash command patterns and the paths a
+// tool touches, optionally scoped to roles and rigs. Rig rules are checked
+// before town rules and the first matching rule decides; with no match the
+// tool call is allowed. gt tap guard policy runs the evaluation as a hook, so
+// adding a guard is a policy edit rather than a new command.
+package guard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PolicyFileName is the policy file in a town's or rig's settings directory.
+const PolicyFileName = "guard-policy.json"
+
+// PolicyType is the type field of policy files.
+const PolicyType = "guard-policy"
+
+// CurrentPolicyVersion is the current policy schema version.
+const CurrentPolicyVersion = 1
+
+// Action is what a matching rule does with the tool call.
+type Action string
+
+const (
+	ActionDeny  Action = "deny"
+	ActionAllow Action = "allow"
+)
+
+// Policy is the structure of settings/guard-policy.json.
+type Policy struct {
+	Type    string `json:"type"`    // "guard-policy"
+	Version int    `json:"version"` // schema version
+	Rules   []Rule `json:"rules"`
+
+	// Source is the file the policy was loaded from.
+	Source string `json:"-"`
+}
+
+// Rule is one allow or deny rule. Every condition that is set must match;
+// unset conditions match anything.
+//
+// Patterns are globs where * matches any run of characters (within one path
+// element for paths, where ** crosses elements), or regular expressions when
+// prefixed "re:". Path patterns may use {town}, {rig}, {worktree} and {home},
+// are matched anywhere in the path unless they start with / or a variable,
+// and are negated with a leading "!" ("!{worktree}/**" matches paths outside
+// the agent's worktree).
+type Rule struct {
+	Name    string `json:"name"`
+	Action  Action `json:"action"`            // "deny" (default) or "allow"
+	Message string `json:"message,omitempty"` // Shown to the agent when denied
+
+	// Tools are tool names (e.g., "Bash", "Edit", "mcp__*").
+	Tools []string `json:"tools,omitempty"`
+
+	// Commands match each simple command of a Bash command line (split on
+	// &&, ||, ;, | and newlines, with leading VAR=value assignments dropped).
+	Commands []string `json:"commands,omitempty"`
+
+	// Paths match the files a tool touches: file_path, notebook_path or
+	// path inputs, and the arguments of matched Bash commands.
+	Paths []string `json:"paths,omitempty"`
+
+	// Roles are agent roles from GT_ROLE (mayor, deacon, witness, refinery,
+	// polecat, crew).
+	Roles []string `json:"roles,omitempty"`
+
+	// Rigs are rig names.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// TownPolicyPath returns the path of a town's policy file.
+func TownPolicyPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", PolicyFileName)
+}
+
+// RigPolicyPath returns the path of a rig's policy file.
+func RigPolicyPath(rigPath string) string {
+	return filepath.Join(rigPath, "settings", PolicyFileName)
+}
+
+// LoadPolicy loads and validates a policy file. Returns nil, nil if the file
+// doesn't exist.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted town/rig roots
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading guard policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing guard policy %s: %w", path, err)
+	}
+	p.Source = path
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid guard policy %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks the policy's type, version, actions and patterns.
+func (p *Policy) Validate() error {
+	if p.Type != "" && p.Type != PolicyType {
+		return fmt.Errorf("type %q, want %q", p.Type, PolicyType)
+	}
+	if p.Version > CurrentPolicyVersion {
+		return fmt.Errorf("version %d is newer than supported (%d)", p.Version, CurrentPolicyVersion)
+	}
+	for i, r := range p.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		switch r.Action {
+		case "", ActionDeny, ActionAllow:
+		default:
+			return fmt.Errorf("rule %s: action %q, want deny or allow", name, r.Action)
+		}
+		if len(r.Tools) == 0 && len(r.Commands) == 0 && len(r.Paths) == 0 {
+			return fmt.Errorf("rule %s: needs tools, commands or paths", name)
+		}
+		for _, pat := range r.Tools {
+			if _, err := compileGlob(pat, false); err != nil {
+				return fmt.Errorf("rule %s: tool pattern %q: %w", name, pat, err)
+			}
+		}
+		for _, pat := range r.Commands {
+			if _, err := compileGlob(pat, false); err != nil {
+				return fmt.Errorf("rule %s: command pattern %q: %w", name, pat, err)
+			}
+		}
+		for _, pat := range r.Paths {
+			if _, err := compileGlob(strings.TrimPrefix(pat, "!"), true); err != nil {
+				return fmt.Errorf("rule %s: path pattern %q: %w", name, pat, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadPolicies loads the rig policy (if rigPath is set) and the town policy,
+// in evaluation order. Missing files are skipped.
+func LoadPolicies(townRoot, rigPath string) ([]*Policy, error) {
+	var paths []string
+	if rigPath != "" {
+		paths = append(paths, RigPolicyPath(rigPath))
+	}
+	if townRoot != "" {
+		paths = append(paths, TownPolicyPath(townRoot))
+	}
+
+	var policies []*Policy
+	for _, path := range paths {
+		p, err := LoadPolicy(path)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+// DefaultPolicy returns the starter town policy written by
+// gt tap guard policy --init.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Type:    PolicyType,
+		Version: CurrentPolicyVersion,
+		Rules: []Rule{
+			{
+				Name:     "no-force-push",
+				Action:   ActionDeny,
+				Message:  "Force pushes rewrite shared history. Rebase onto the remote and push normally.",
+				Tools:    []string{"Bash"},
+				Commands: []string{`re:^git\s+push\b.*\s(--force|--force-with-lease|-f)(=\S*)?(\s|$)`},
+				Roles:    []string{"polecat"},
+			},
+			{
+				Name:    "no-direct-beads-edits",
+				Action:  ActionDeny,
+				Message: "Don't touch .beads/ directly; use bd or gt commands so the database stays consistent.",
+				Tools:   []string{"Bash", "Edit", "MultiEdit", "Write", "NotebookEdit"},
+				Paths:   []string{".beads/**"},
+				Roles:   []string{"polecat"},
+			},
+			{
+				Name:     "no-rm-rf-outside-worktree",
+				Action:   ActionDeny,
+				Message:  "Recursive deletes are limited to your own worktree.",
+				Tools:    []string{"Bash"},
+				Commands: []string{`re:^rm\s+(.*\s)?(-[a-zA-Z]*[rR][a-zA-Z]*|--recursive)(\s|$)`},
+				Paths:    []string{"!{worktree}/**"},
+				Roles:    []string{"polecat"},
+			},
+		},
+	}
+}
+
+// SavePolicy writes a policy file.
+func SavePolicy(path string, p *Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding guard policy: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil { //nolint:gosec // G306: policy isn't secret
+		return fmt.Errorf("writing guard policy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePolicy(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPolicies(t *testing.T) {
+	town := t.TempDir()
+	rig := filepath.Join(town, "gastown")
+
+	// Nothing configured
+	policies, err := LoadPolicies(town, rig)
+	if err != nil || len(policies) != 0 {
+		t.Fatalf("LoadPolicies with no files = %v, %v", policies, err)
+	}
+
+	writePolicy(t, TownPolicyPath(town), `{"type":"guard-policy","version":1,"rules":[{"name":"town-rule","tools":["Write"]}]}`)
+	writePolicy(t, RigPolicyPath(rig), `{"type":"guard-policy","version":1,"rules":[{"name":"rig-rule","action":"allow","tools":["Write"]}]}`)
+
+	policies, err = LoadPolicies(town, rig)
+	if err != nil {
+		t.Fatalf("LoadPolicies: %v", err)
+	}
+	if len(policies) != 2 || policies[0].Rules[0].Name != "rig-rule" || policies[1].Rules[0].Name != "town-rule" {
+		t.Fatalf("policies = %+v, want rig then town", policies)
+	}
+	if policies[0].Source != RigPolicyPath(rig) {
+		t.Errorf("source = %q", policies[0].Source)
+	}
+
+	// Town only, e.g. the mayor
+	policies, _ = LoadPolicies(town, "")
+	if len(policies) != 1 || policies[0].Rules[0].Name != "town-rule" {
+		t.Errorf("town-only policies = %+v", policies)
+	}
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name, content, wantErr string
+	}{
+		{"malformed", `{`, "parsing"},
+		{"wrong type", `{"type":"escalation","rules":[]}`, "type"},
+		{"future version", `{"version":99,"rules":[]}`, "version"},
+		{"bad action", `{"rules":[{"name":"r","action":"maybe","tools":["Bash"]}]}`, "action"},
+		{"no conditions", `{"rules":[{"name":"r","roles":["polecat"]}]}`, "needs tools"},
+		{"bad regexp", `{"rules":[{"name":"r","commands":["re:("]}]}`, "command pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), PolicyFileName)
+			writePolicy(t, path, tt.content)
+			_, err := LoadPolicy(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPolicy err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSaveDefaultPolicy(t *testing.T) {
+	path := TownPolicyPath(t.TempDir())
+	if err := SavePolicy(path, DefaultPolicy()); err != nil {
+		t.Fatalf("SavePolicy: %v", err)
+	}
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	if len(p.Rules) != 3 || p.Type != PolicyType || p.Version != CurrentPolicyVersion {
+		t.Errorf("round-tripped policy = %+v", p)
+	}
+}
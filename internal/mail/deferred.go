@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// DeferredQueuePath returns the path of the mail orchestrator's deferred
+// queue, which holds scheduled messages until their delivery time.
+func DeferredQueuePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "mail-queues", "deferred.json")
+}
+
+// DeferredMessage is a scheduled message waiting in the deferred queue.
+// It has the same shape as the orchestrator's other queue entries.
+type DeferredMessage struct {
+	Message     *Message  `json:"message"`
+	Attempts    int       `json:"attempts"`
+	LastAttempt time.Time `json:"last_attempt"`
+	QueuedAt    time.Time `json:"queued_at"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// LoadDeferred returns the messages in a town's deferred queue.
+func LoadDeferred(townRoot string) ([]*DeferredMessage, error) {
+	return loadQueueFile[*DeferredMessage](DeferredQueuePath(townRoot))
+}
+
+// UpdateDeferred replaces a town's deferred queue with what fn returns,
+// holding the queue's write lock throughout.
+func UpdateDeferred(townRoot string, fn func([]*DeferredMessage) []*DeferredMessage) error {
+	return updateQueueFile(DeferredQueuePath(townRoot), fn)
+}
+
+// scheduleLabels returns the deliver-at and expires-at labels of a message.
+func scheduleLabels(msg *Message) []string {
+	var labels []string
+	if msg.DeliverAt != nil {
+		labels = append(labels, "deliver-at:"+msg.DeliverAt.UTC().Format(time.RFC3339))
+	}
+	if msg.ExpiresAt != nil {
+		labels = append(labels, "expires-at:"+msg.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return labels
+}
+
+// dropExpired filters out expired messages.
+func dropExpired(messages []*Message) []*Message {
+	now := timeNow()
+	live := messages[:0]
+	for _, msg := range messages {
+		if !msg.IsExpired(now) {
+			live = append(live, msg)
+		}
+	}
+	return live
+}
@@ -0,0 +1,106 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRouterSendDefersScheduledMessage(t *testing.T) {
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+
+	deliverAt := time.Now().Add(2 * time.Hour)
+	msg := &Message{
+		From:      "gastown/crew/max",
+		To:        "queue:work",
+		Subject:   "Re-check nux after CI",
+		DeliverAt: &deliverAt,
+	}
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	queue, err := LoadDeferred(town)
+	if err != nil {
+		t.Fatalf("LoadDeferred: %v", err)
+	}
+	if len(queue) != 1 {
+		t.Fatalf("deferred queue has %d messages, want 1", len(queue))
+	}
+	got := queue[0].Message
+	if got.Subject != msg.Subject || got.ID == "" || got.DeliverAt == nil || !got.DeliverAt.Equal(deliverAt) {
+		t.Errorf("deferred message = %+v", got)
+	}
+	if msg.ID != "" {
+		t.Error("Send modified the caller's message")
+	}
+}
+
+func TestRouterSendRejectsExpiryBeforeDelivery(t *testing.T) {
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+
+	deliverAt := time.Now().Add(2 * time.Hour)
+	expiresAt := deliverAt.Add(-time.Minute)
+	msg := &Message{To: "queue:work", Subject: "Too late", DeliverAt: &deliverAt, ExpiresAt: &expiresAt}
+	if err := r.Send(msg); err == nil {
+		t.Fatal("Send accepted a message expiring before delivery")
+	}
+	if queue, _ := LoadDeferred(town); len(queue) != 0 {
+		t.Errorf("deferred queue has %d messages, want 0", len(queue))
+	}
+}
+
+func TestScheduleLabelsRoundTrip(t *testing.T) {
+	deliverAt := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	expiresAt := deliverAt.Add(4 * time.Hour)
+	msg := &Message{DeliverAt: &deliverAt, ExpiresAt: &expiresAt}
+
+	bm := BeadsMessage{ID: "hq-1", Labels: append([]string{"from:mayor/"}, scheduleLabels(msg)...)}
+	got := bm.ToMessage()
+	if got.DeliverAt == nil || !got.DeliverAt.Equal(deliverAt) {
+		t.Errorf("DeliverAt = %v, want %v", got.DeliverAt, deliverAt)
+	}
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, expiresAt)
+	}
+
+	if !got.IsDue(deliverAt) || got.IsDue(deliverAt.Add(-time.Second)) {
+		t.Error("IsDue wrong around DeliverAt")
+	}
+	if got.IsExpired(expiresAt.Add(-time.Second)) || !got.IsExpired(expiresAt) {
+		t.Error("IsExpired wrong around ExpiresAt")
+	}
+	if (&Message{}).IsExpired(time.Now()) || !(&Message{}).IsDue(time.Now()) {
+		t.Error("unscheduled message should be due and never expire")
+	}
+}
+
+func TestMailboxListDropsExpired(t *testing.T) {
+	mb := NewMailbox(t.TempDir())
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	for _, msg := range []*Message{
+		{ID: "live", Subject: "Still relevant", ExpiresAt: &future},
+		{ID: "stale", Subject: "CI finished an hour ago", ExpiresAt: &past},
+		{ID: "plain", Subject: "No expiry"},
+	} {
+		if err := mb.Append(msg); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	msgs, err := mb.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("List returned %d messages, want 2", len(msgs))
+	}
+	for _, msg := range msgs {
+		if msg.ID == "stale" {
+			t.Error("List returned expired message")
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// envelopeFence opens the fenced JSON block that carries a protocol
+// message's typed payload. The block closes with a bare ``` line.
+const envelopeFence = "```gt-envelope"
+
+// Envelope is the structured part of a protocol message body. It follows
+// the human-readable text in a fenced JSON block:
+//
+//	Branch: polecat/Toast/gt-abc
+//	Issue: gt-abc
+//
+//	```gt-envelope
+//	{"type": "MERGED", "version": 1, "payload": {...}}
+//	```
+//
+// Readers prefer the envelope and fall back to the legacy "Key: value" text
+// for messages sent before it existed or written by hand.
+type Envelope struct {
+	// Type is the protocol message type, matching the subject keyword.
+	Type string `json:"type"`
+
+	// Version is the payload schema version.
+	Version int `json:"version"`
+
+	// Payload is the typed message data.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Format renders the envelope as a fenced block.
+func (e *Envelope) Format() (string, error) {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return envelopeFence + "\n" + string(data) + "\n```\n", nil
+}
+
+// SplitEnvelope separates a message body into its text and envelope.
+// The envelope is nil if the body has none. An error means the body has an
+// envelope block that isn't valid JSON or isn't closed.
+func SplitEnvelope(body string) (string, *Envelope, error) {
+	lines := strings.Split(body, "\n")
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == envelopeFence {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return body, nil, nil
+	}
+
+	end := -1
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "```" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return body, nil, fmt.Errorf("unterminated %s block", envelopeFence)
+	}
+
+	var env Envelope
+	if err := json.Unmarshal([]byte(strings.Join(lines[start+1:end], "\n")), &env); err != nil {
+		return body, nil, fmt.Errorf("invalid envelope JSON: %w", err)
+	}
+	text := strings.TrimRight(strings.Join(append(lines[:start:start], lines[end+1:]...), "\n"), "\n")
+	if text != "" {
+		text += "\n"
+	}
+	return text, &env, nil
+}
+
+// JoinEnvelope appends env to text as a fenced block.
+func JoinEnvelope(text string, env *Envelope) (string, error) {
+	block, err := env.Format()
+	if err != nil {
+		return "", err
+	}
+	if text == "" {
+		return block, nil
+	}
+	return strings.TrimRight(text, "\n") + "\n\n" + block, nil
+}
+
+// Schema describes the typed payload of one protocol message type, and
+// encodes, decodes and validates bodies that carry it.
+type Schema[T any] struct {
+	// Type is the subject keyword of the message type (e.g., "MERGED" for
+	// "MERGED Toast", "HELP" for "HELP: tests failing").
+	Type string
+
+	// Version is the current payload version. Envelopes of this version or
+	// older decode; newer ones are rejected. Bump it when fields change
+	// meaning, not when they are added.
+	Version int
+
+	// Legacy parses a body without an envelope. Nil means envelopes are
+	// required.
+	Legacy func(subject, body string) (*T, error)
+
+	// Validate checks a decoded payload. Nil accepts any payload.
+	Validate func(p *T) error
+}
+
+// Encode returns text followed by p in an envelope.
+func (s *Schema[T]) Encode(text string, p *T) (string, error) {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s payload: %w", s.Type, err)
+	}
+	return JoinEnvelope(text, &Envelope{Type: s.Type, Version: s.Version, Payload: payload})
+}
+
+// Decode returns the payload of a message body, from its envelope if it has
+// one and otherwise from the legacy text.
+func (s *Schema[T]) Decode(subject, body string) (*T, error) {
+	_, env, err := SplitEnvelope(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Type, err)
+	}
+	if env == nil {
+		if s.Legacy == nil {
+			return nil, fmt.Errorf("%s: missing %s block", s.Type, envelopeFence)
+		}
+		return s.Legacy(subject, body)
+	}
+
+	if env.Type != s.Type {
+		return nil, fmt.Errorf("%s: envelope is for %s", s.Type, env.Type)
+	}
+	if env.Version < 1 || env.Version > s.Version {
+		return nil, fmt.Errorf("%s: unsupported envelope version %d (supported: 1-%d)", s.Type, env.Version, s.Version)
+	}
+	var p T
+	if err := json.Unmarshal(env.Payload, &p); err != nil {
+		return nil, fmt.Errorf("%s: invalid payload: %w", s.Type, err)
+	}
+	return &p, nil
+}
+
+// Check decodes and validates a message body.
+func (s *Schema[T]) Check(subject, body string) error {
+	p, err := s.Decode(subject, body)
+	if err != nil {
+		return err
+	}
+	if s.Validate != nil {
+		if err := s.Validate(p); err != nil {
+			return fmt.Errorf("%s: %w", s.Type, err)
+		}
+	}
+	return nil
+}
+
+// checker is the untyped view of a Schema used for send-time validation.
+type checker interface {
+	Check(subject, body string) error
+}
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[string]checker{}
+)
+
+// RegisterSchema makes Router.Send validate messages of s.Type against s.
+// Each type has one owner; registering a type twice panics. Returns s so
+// packages can register at declaration.
+func RegisterSchema[T any](s *Schema[T]) *Schema[T] {
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	if _, ok := schemas[s.Type]; ok {
+		panic("mail: schema registered twice for " + s.Type)
+	}
+	schemas[s.Type] = s
+	return s
+}
+
+// ValidateProtocolBody checks the body of a protocol message against the
+// schema registered for its subject keyword. Messages without a registered
+// schema pass.
+func ValidateProtocolBody(subject, body string) error {
+	schemasMu.RLock()
+	s, ok := schemas[subjectKeyword(subject)]
+	schemasMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return s.Check(subject, body)
+}
+
+// subjectKeyword returns the protocol keyword of a subject: its first word
+// without a trailing colon ("HELP: x" -> "HELP").
+func subjectKeyword(subject string) string {
+	fields := strings.Fields(subject)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(fields[0], ":")
+}
@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type pingPayload struct {
+	Target string `json:"target"`
+	Count  int    `json:"count,omitempty"`
+}
+
+var pingSchema = RegisterSchema(&Schema[pingPayload]{
+	Type:    "TEST_PING",
+	Version: 2,
+	Legacy: func(subject, body string) (*pingPayload, error) {
+		for _, line := range strings.Split(body, "\n") {
+			if strings.HasPrefix(line, "Target: ") {
+				return &pingPayload{Target: strings.TrimPrefix(line, "Target: ")}, nil
+			}
+		}
+		return &pingPayload{}, nil
+	},
+	Validate: func(p *pingPayload) error {
+		if p.Target == "" {
+			return fmt.Errorf("missing target")
+		}
+		return nil
+	},
+})
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	body, err := pingSchema.Encode("Target: deacon/\nPlease respond.", &pingPayload{Target: "deacon/", Count: 3})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	text, env, err := SplitEnvelope(body)
+	if err != nil || env == nil {
+		t.Fatalf("SplitEnvelope = %v, %v", env, err)
+	}
+	if text != "Target: deacon/\nPlease respond.\n" {
+		t.Errorf("text = %q", text)
+	}
+	if env.Type != "TEST_PING" || env.Version != 2 {
+		t.Errorf("envelope = %+v", env)
+	}
+
+	p, err := pingSchema.Decode("TEST_PING", body)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if p.Target != "deacon/" || p.Count != 3 {
+		t.Errorf("payload = %+v", p)
+	}
+}
+
+func TestSchemaDecodeFallbackAndErrors(t *testing.T) {
+	// No envelope: legacy text
+	p, err := pingSchema.Decode("TEST_PING", "Target: mayor/")
+	if err != nil || p.Target != "mayor/" {
+		t.Errorf("legacy decode = %+v, %v", p, err)
+	}
+
+	for name, body := range map[string]string{
+		"bad json":     "```gt-envelope\n{not json\n```\n",
+		"unterminated": "```gt-envelope\n{\"type\": \"TEST_PING\", \"version\": 1, \"payload\": {}}\n",
+		"wrong type":   "```gt-envelope\n{\"type\": \"MERGED\", \"version\": 1, \"payload\": {}}\n```\n",
+		"too new":      "```gt-envelope\n{\"type\": \"TEST_PING\", \"version\": 3, \"payload\": {}}\n```\n",
+		"bad payload":  "```gt-envelope\n{\"type\": \"TEST_PING\", \"version\": 1, \"payload\": {\"count\": \"x\"}}\n```\n",
+	} {
+		if _, err := pingSchema.Decode("TEST_PING", body); err == nil {
+			t.Errorf("%s: Decode succeeded", name)
+		}
+	}
+
+	// Older versions still decode
+	old := "```gt-envelope\n{\"type\": \"TEST_PING\", \"version\": 1, \"payload\": {\"target\": \"x\"}}\n```\n"
+	if p, err := pingSchema.Decode("TEST_PING", old); err != nil || p.Target != "x" {
+		t.Errorf("v1 decode = %+v, %v", p, err)
+	}
+}
+
+func TestValidateProtocolBody(t *testing.T) {
+	tests := []struct {
+		subject, body string
+		wantErr       bool
+	}{
+		{"TEST_PING deacon", "Target: deacon/", false},
+		{"TEST_PING deacon", "Who knows", true},
+		{"TEST_PING: deacon", "```gt-envelope\n{\"type\": \"TEST_PING\", \"version\": 2, \"payload\": {}}\n```", true},
+		{"Re: TEST_PING deacon", "anything", false}, // Not a protocol subject
+		{"Status check", "How's it going?", false},
+	}
+	for _, tt := range tests {
+		err := ValidateProtocolBody(tt.subject, tt.body)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateProtocolBody(%q, %q) = %v, wantErr %v", tt.subject, tt.body, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRouterSendRejectsMalformedProtocolMessage(t *testing.T) {
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+
+	err := r.Send(&Message{From: "mayor/", To: "deacon/", Subject: "TEST_PING deacon", Body: "no target here"})
+	if err == nil || !strings.Contains(err.Error(), "missing target") {
+		t.Errorf("Send = %v, want missing target error", err)
+	}
+}
@@ -0,0 +1,805 @@
+package mail
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/filelock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// The search index is an inverted index over all town mail: the mail beads in
+// {townRoot}/.beads (open and archived) and the .jsonl archive files.
+//
+// It lives in {townRoot}/.runtime as two files. mail-index.json is a snapshot
+// of the index; mail-index.jsonl is a log of messages delivered or deleted
+// since, which Router.Send and gt mail delete append to without rewriting the
+// snapshot. Opening the index
+// replays the log, picks up new archive lines, and folds both into the
+// snapshot once the log grows.
+
+const (
+	indexVersion = 1
+
+	// subjectWeight is how many body occurrences a subject occurrence of a
+	// term counts as.
+	subjectWeight = 3
+
+	// subjectTermPrefix keys the postings of subject-only terms.
+	subjectTermPrefix = "subject:"
+
+	// indexCompactAt is the log length at which the snapshot is rewritten.
+	indexCompactAt = 200
+
+	// maxTermLength skips long tokens (hashes, base64) that nobody searches for.
+	maxTermLength = 40
+)
+
+// BM25 ranking parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// listTownMail returns every mail bead in a town. It can be overridden in tests.
+var listTownMail = listTownMailBeads
+
+// IndexPath returns the path of a town's mail search index.
+func IndexPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "mail-index.json")
+}
+
+func indexLogPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "mail-index.jsonl")
+}
+
+// IndexedMessage is what the index keeps of a message. Bodies aren't stored;
+// use gt mail read <id> for the full message.
+type IndexedMessage struct {
+	ID        string      `json:"id"`
+	From      string      `json:"from"`
+	To        string      `json:"to"`
+	CC        []string    `json:"cc,omitempty"`
+	Subject   string      `json:"subject"`
+	ThreadID  string      `json:"thread_id,omitempty"`
+	Priority  Priority    `json:"priority"`
+	Type      MessageType `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Archive   string      `json:"archive,omitempty"` // Archive file; empty for mail beads
+	Length    int         `json:"length"`            // Weighted term count, for ranking
+}
+
+// deliveredTo reports whether the message was addressed or CC'd to identity.
+func (m *IndexedMessage) deliveredTo(identity string) bool {
+	if AddressToIdentity(m.To) == identity {
+		return true
+	}
+	for _, cc := range m.CC {
+		if AddressToIdentity(cc) == identity {
+			return true
+		}
+	}
+	return false
+}
+
+// indexEntry is one line of the index log.
+type indexEntry struct {
+	IndexedMessage
+	Terms   map[string]int `json:"terms"`
+	Removed bool           `json:"removed,omitempty"` // Message was deleted; ID is the only other field
+}
+
+func newIndexEntry(msg *Message, archive string) *indexEntry {
+	e := &indexEntry{
+		IndexedMessage: IndexedMessage{
+			ID:        msg.ID,
+			From:      msg.From,
+			To:        msg.To,
+			CC:        msg.CC,
+			Subject:   msg.Subject,
+			ThreadID:  msg.ThreadID,
+			Priority:  msg.Priority,
+			Type:      msg.Type,
+			Timestamp: msg.Timestamp,
+			Archive:   archive,
+		},
+		Terms: make(map[string]int),
+	}
+	if e.Priority == "" {
+		e.Priority = PriorityNormal
+	}
+	if e.Type == "" {
+		e.Type = TypeNotification
+	}
+	for _, t := range tokenize(msg.Subject) {
+		e.Terms[t] += subjectWeight
+		e.Terms[subjectTermPrefix+t]++
+		e.Length += subjectWeight
+	}
+	for _, t := range tokenize(msg.Body) {
+		e.Terms[t]++
+		e.Length++
+	}
+	return e
+}
+
+// tokenize splits text into lowercase terms of letters and digits.
+func tokenize(s string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if len(f) <= maxTermLength {
+			terms = append(terms, f)
+		}
+	}
+	return terms
+}
+
+// Index is a full-text index over a town's mail.
+type Index struct {
+	Version  int                        `json:"version"`
+	BuiltAt  time.Time                  `json:"built_at"`
+	Docs     map[string]*IndexedMessage `json:"docs"`
+	Postings map[string]map[string]int  `json:"postings"` // term -> message ID -> weighted frequency
+	Archives map[string]int64           `json:"archives"` // archive path -> bytes indexed
+	Removed  map[string]bool            `json:"removed"`  // deleted message IDs, kept out of rebuilds
+
+	townRoot  string
+	logOffset int64 // bytes of the log already replayed
+	pending   int   // log entries not yet in the snapshot
+}
+
+func newIndex(townRoot string) *Index {
+	return &Index{
+		Version:  indexVersion,
+		Docs:     make(map[string]*IndexedMessage),
+		Postings: make(map[string]map[string]int),
+		Archives: make(map[string]int64),
+		Removed:  make(map[string]bool),
+		townRoot: townRoot,
+	}
+}
+
+// Len returns the number of indexed messages.
+func (ix *Index) Len() int {
+	return len(ix.Docs)
+}
+
+// add indexes an entry. Messages already indexed are skipped, so a mail bead
+// and its archived copy count once, and so are deleted messages.
+func (ix *Index) add(e *indexEntry) bool {
+	if e.ID == "" || ix.Docs[e.ID] != nil || ix.Removed[e.ID] {
+		return false
+	}
+	doc := e.IndexedMessage
+	ix.Docs[e.ID] = &doc
+	for term, tf := range e.Terms {
+		p := ix.Postings[term]
+		if p == nil {
+			p = make(map[string]int)
+			ix.Postings[term] = p
+		}
+		p[e.ID] = tf
+	}
+	return true
+}
+
+// remove drops a message from the index. Deleted mail beads are closed, not
+// gone, so the ID is remembered to keep rebuilds from indexing them again.
+func (ix *Index) remove(id string) {
+	ix.Removed[id] = true
+	if ix.Docs[id] == nil {
+		return
+	}
+	delete(ix.Docs, id)
+	for term, p := range ix.Postings {
+		delete(p, id)
+		if len(p) == 0 {
+			delete(ix.Postings, term)
+		}
+	}
+}
+
+// AppendToIndex records a delivered message in a town's index log.
+func AppendToIndex(townRoot string, msg *Message) error {
+	data, err := json.Marshal(newIndexEntry(msg, ""))
+	if err != nil {
+		return err
+	}
+	return appendIndexLog(townRoot, append(data, '\n'))
+}
+
+// RemoveFromIndex records deleted messages in a town's index log so they
+// stop turning up in searches.
+func RemoveFromIndex(townRoot string, ids ...string) error {
+	var data []byte
+	for _, id := range ids {
+		line, err := json.Marshal(&indexEntry{IndexedMessage: IndexedMessage{ID: id}, Removed: true})
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return appendIndexLog(townRoot, data)
+}
+
+// appendIndexLog appends whole lines to a town's index log.
+func appendIndexLog(townRoot string, data []byte) error {
+	return filelock.WithWriteLock(IndexPath(townRoot), func() error {
+		if err := os.MkdirAll(filepath.Dir(IndexPath(townRoot)), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(indexLogPath(townRoot), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: index is non-sensitive operational data
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = f.Write(data)
+		return err
+	})
+}
+
+// LoadIndex loads a town's index snapshot and replays its log. A town
+// without an index gets an empty one with a zero BuiltAt.
+func LoadIndex(townRoot string) (*Index, error) {
+	ix := newIndex(townRoot)
+	err := filelock.WithReadLock(IndexPath(townRoot), ix.load)
+	if err != nil {
+		return nil, errors.Transient("mail.LoadIndex", err).
+			WithContext("index", IndexPath(townRoot)).
+			WithHint("Rebuild the index with: gt mail search --reindex")
+	}
+	return ix, nil
+}
+
+// load reads the snapshot into an empty index and replays the log. Callers
+// hold a lock on the index.
+func (ix *Index) load() error {
+	data, err := os.ReadFile(IndexPath(ix.townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(data, ix); err != nil {
+		return fmt.Errorf("parsing mail index: %w", err)
+	}
+	if ix.Version != indexVersion {
+		// Older format: treat as unbuilt so it gets rebuilt
+		*ix = *newIndex(ix.townRoot)
+		return nil
+	}
+	return ix.replayLog()
+}
+
+// replayLog indexes log entries written since the last replay.
+func (ix *Index) replayLog() error {
+	f, err := os.Open(indexLogPath(ix.townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := f.Seek(ix.logOffset, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			break // EOF, or a partial line still being written
+		}
+		ix.logOffset += int64(len(line))
+		ix.pending++
+		var e indexEntry
+		if json.Unmarshal(line, &e) != nil {
+			continue
+		}
+		if e.Removed {
+			ix.remove(e.ID)
+		} else {
+			ix.add(&e)
+		}
+	}
+	return nil
+}
+
+// indexArchive indexes archive lines past the recorded offset. Returns false
+// if the archive shrank (was purged or rewritten) and needs a rebuild.
+func (ix *Index) indexArchive(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if _, known := ix.Archives[path]; known {
+				return false, nil
+			}
+			return true, nil
+		}
+		return true, err
+	}
+	offset := ix.Archives[path]
+	if info.Size() < offset {
+		return false, nil
+	}
+	if info.Size() == offset {
+		return true, nil
+	}
+
+	f, err := os.Open(path) //nolint:gosec // G304: archive paths are under the town root
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return true, err
+	}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		offset += int64(len(line))
+		var msg Message
+		if json.Unmarshal(line, &msg) == nil {
+			ix.add(newIndexEntry(&msg, path))
+		}
+	}
+	ix.Archives[path] = offset
+	return true, nil
+}
+
+// archivePaths returns the town's mail archives: the shared beads archive and
+// the legacy JSONL archives of crew, witness and refinery mailboxes.
+func archivePaths(townRoot string) []string {
+	paths := []string{filepath.Join(townRoot, ".beads", "archive.jsonl")}
+	for _, pattern := range []string{
+		filepath.Join(townRoot, "*", "*", "mail", "inbox.jsonl.archive"),
+		filepath.Join(townRoot, "*", "crew", "*", "mail", "inbox.jsonl.archive"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		paths = append(paths, matches...)
+	}
+	return paths
+}
+
+// save writes the snapshot and clears the log. Callers hold the write lock.
+func (ix *Index) save() error {
+	if err := os.MkdirAll(filepath.Dir(IndexPath(ix.townRoot)), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	if err := util.AtomicWriteFile(IndexPath(ix.townRoot), data, 0644); err != nil {
+		return err
+	}
+	if err := os.Remove(indexLogPath(ix.townRoot)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	ix.logOffset = 0
+	ix.pending = 0
+	return nil
+}
+
+// RebuildIndex indexes all of a town's mail from scratch, except messages
+// that have been deleted.
+func RebuildIndex(townRoot string) (*Index, error) {
+	ix := newIndex(townRoot)
+	err := filelock.WithWriteLock(IndexPath(townRoot), func() error {
+		// Deletions are only recorded in the index, so carry them over
+		if old := newIndex(townRoot); old.load() == nil {
+			ix.Removed = old.Removed
+		}
+		messages, err := listTownMail(townRoot)
+		if err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			ix.add(newIndexEntry(msg, ""))
+		}
+		for _, path := range archivePaths(townRoot) {
+			if _, err := ix.indexArchive(path); err != nil {
+				return err
+			}
+		}
+		ix.BuiltAt = timeNow()
+		return ix.save()
+	})
+	if err != nil {
+		return nil, errors.Transient("mail.RebuildIndex", err).
+			WithContext("town_root", townRoot).
+			WithHint("Check beads database is accessible: bd list --type=message")
+	}
+	return ix, nil
+}
+
+// OpenIndex returns an up-to-date index for a town, building it on first use
+// and rebuilding it when an archive has been purged.
+func OpenIndex(townRoot string) (*Index, error) {
+	ix, err := LoadIndex(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if ix.BuiltAt.IsZero() {
+		return RebuildIndex(townRoot)
+	}
+
+	changed := false
+	for _, path := range archivePaths(townRoot) {
+		before := ix.Archives[path]
+		ok, err := ix.indexArchive(path)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return RebuildIndex(townRoot)
+		}
+		changed = changed || ix.Archives[path] != before
+	}
+
+	if changed || ix.pending >= indexCompactAt {
+		// Best-effort: a failed compaction leaves the log to replay next time
+		_ = filelock.WithWriteLock(IndexPath(townRoot), func() error {
+			compacted, err := compactIndex(townRoot)
+			if err == nil && compacted != nil {
+				ix = compacted
+			}
+			return err
+		})
+	}
+	return ix, nil
+}
+
+// compactIndex folds the log and new archive lines into the snapshot. It
+// starts over from the files rather than reusing an index loaded earlier:
+// another process may have compacted in between, leaving that index's log
+// offset pointing into a different log. Returns nil if the index needs a
+// rebuild instead. Callers hold the write lock.
+func compactIndex(townRoot string) (*Index, error) {
+	ix := newIndex(townRoot)
+	if err := ix.load(); err != nil {
+		return nil, err
+	}
+	if ix.BuiltAt.IsZero() {
+		return nil, nil
+	}
+	for _, path := range archivePaths(townRoot) {
+		ok, err := ix.indexArchive(path)
+		if err != nil || !ok {
+			return nil, err
+		}
+	}
+	if err := ix.save(); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// listTownMailBeads lists every message bead, open or closed, in town beads.
+func listTownMailBeads(townRoot string) ([]*Message, error) {
+	beadsDir := filepath.Join(townRoot, ".beads")
+	if err := beads.EnsureCustomTypes(beadsDir); err != nil {
+		return nil, err
+	}
+	args := []string{"list", "--type", "message", "--status=all", "--json", "--limit=0"}
+	stdout, err := runBdCommand(args, townRoot, beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	var beadsMsgs []BeadsMessage
+	if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+		if len(stdout) == 0 || string(stdout) == "null" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	messages := make([]*Message, 0, len(beadsMsgs))
+	for i := range beadsMsgs {
+		messages = append(messages, beadsMsgs[i].ToMessage())
+	}
+	return messages, nil
+}
+
+// Query is a parsed search query.
+type Query struct {
+	Terms     []string  // Terms every result contains; a trailing * matches a prefix
+	Subject   []string  // Terms every result has in its subject
+	BodyOnly  bool      // Terms must occur in the body, not just the subject
+	From      string    // Sender contains (case-insensitive)
+	To        string    // Recipient or CC contains (case-insensitive)
+	Thread    string    // Thread ID
+	Priority  Priority  // Exact priority
+	Before    time.Time // Sent before (exclusive)
+	After     time.Time // Sent at or after
+	Recipient string    // Only messages delivered to this address; empty for all mailboxes
+}
+
+// queryFields are the field prefixes ParseQuery understands.
+var queryFields = map[string]bool{
+	"from": true, "to": true, "thread": true, "priority": true,
+	"before": true, "after": true, "subject": true,
+}
+
+// ParseQuery parses a search query: words (all must match, "word*" matches a
+// prefix) and field filters from:, to:, thread:, priority:, before:, after:
+// and subject:. Dates are YYYY-MM-DD, RFC 3339, or an age like 7d or 12h.
+func ParseQuery(s string) (*Query, error) {
+	q := &Query{}
+	for _, tok := range splitQuery(s) {
+		if field, value, ok := strings.Cut(tok, ":"); ok && queryFields[strings.ToLower(field)] && value != "" {
+			if err := q.setField(strings.ToLower(field), value); err != nil {
+				return nil, errors.User("mail.InvalidSearchQuery", err.Error()).
+					WithContext("query", s).
+					WithHint("Fields: from:, to:, thread:, priority:, before:, after:, subject:")
+			}
+			continue
+		}
+		q.Terms = append(q.Terms, queryTerms(tok)...)
+	}
+	return q, nil
+}
+
+func (q *Query) setField(field, value string) error {
+	switch field {
+	case "from":
+		q.From = value
+	case "to":
+		q.To = value
+	case "thread":
+		q.Thread = value
+	case "priority":
+		switch p := Priority(strings.ToLower(value)); p {
+		case PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent:
+			q.Priority = p
+		default:
+			return fmt.Errorf("priority %q, want low, normal, high or urgent", value)
+		}
+	case "before", "after":
+		t, err := parseQueryTime(value)
+		if err != nil {
+			return err
+		}
+		if field == "before" {
+			q.Before = t
+		} else {
+			q.After = t
+		}
+	case "subject":
+		q.Subject = append(q.Subject, queryTerms(value)...)
+	}
+	return nil
+}
+
+// queryTerms tokenizes a query word, keeping a trailing * prefix marker.
+func queryTerms(tok string) []string {
+	terms := tokenize(tok)
+	if len(terms) > 0 && strings.HasSuffix(tok, "*") {
+		terms[len(terms)-1] += "*"
+	}
+	return terms
+}
+
+// splitQuery splits a query on whitespace, keeping double-quoted text together.
+func splitQuery(s string) []string {
+	var toks []string
+	var b strings.Builder
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if b.Len() > 0 {
+				toks = append(toks, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		toks = append(toks, b.String())
+	}
+	return toks
+}
+
+// parseQueryTime parses a date, a timestamp, or an age (7d, 12h, 30m).
+func parseQueryTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if n := len(s); n > 1 {
+		var unit time.Duration
+		switch s[n-1] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		}
+		var count int
+		if _, err := fmt.Sscanf(s[:n-1], "%d", &count); err == nil && unit != 0 && count >= 0 {
+			return timeNow().Add(-time.Duration(count) * unit), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD, RFC 3339 or an age like 7d", s)
+}
+
+// SearchResult is a ranked search hit.
+type SearchResult struct {
+	*IndexedMessage
+	Score float64 `json:"score"`
+}
+
+// Search returns messages matching the query, best first. Queries without
+// terms return matching messages newest first. limit <= 0 means no limit.
+func (ix *Index) Search(q *Query, limit int) []SearchResult {
+	type termHits struct {
+		docs map[string]int
+		df   int
+	}
+	var hits []termHits
+	for _, t := range q.Terms {
+		docs := ix.lookup(t, q.BodyOnly)
+		hits = append(hits, termHits{docs, len(docs)})
+	}
+	for _, t := range q.Subject {
+		docs := ix.lookup(subjectTermPrefix+t, false)
+		hits = append(hits, termHits{docs, len(docs)})
+	}
+
+	n := float64(len(ix.Docs))
+	avgLen := ix.avgLength()
+	var recipient string
+	if q.Recipient != "" {
+		recipient = AddressToIdentity(q.Recipient)
+	}
+
+	var results []SearchResult
+	consider := func(id string, doc *IndexedMessage) {
+		if !q.matchFields(doc, recipient) {
+			return
+		}
+		score := 0.0
+		for _, h := range hits {
+			tf, ok := h.docs[id]
+			if !ok {
+				return
+			}
+			idf := math.Log(1 + (n-float64(h.df)+0.5)/(float64(h.df)+0.5))
+			norm := 1 - bm25B + bm25B*float64(doc.Length)/avgLen
+			score += idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*norm)
+		}
+		results = append(results, SearchResult{IndexedMessage: doc, Score: score})
+	}
+	if len(hits) > 0 {
+		// Candidates are the docs of the rarest term; consider checks the rest
+		sort.Slice(hits, func(i, j int) bool { return hits[i].df < hits[j].df })
+		for id := range hits[0].docs {
+			if doc := ix.Docs[id]; doc != nil {
+				consider(id, doc)
+			}
+		}
+	} else {
+		for id, doc := range ix.Docs {
+			consider(id, doc)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Timestamp.Equal(results[j].Timestamp) {
+			return results[i].Timestamp.After(results[j].Timestamp)
+		}
+		return results[i].ID < results[j].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// lookup returns the documents containing a term with their frequencies. A
+// trailing * matches every term with that prefix. With bodyOnly, subject
+// occurrences don't count.
+func (ix *Index) lookup(term string, bodyOnly bool) map[string]int {
+	terms := []string{term}
+	if prefix, ok := strings.CutSuffix(term, "*"); ok {
+		terms = terms[:0]
+		fielded := strings.Contains(prefix, ":")
+		for t := range ix.Postings {
+			if strings.HasPrefix(t, prefix) && (fielded || !strings.Contains(t, ":")) {
+				terms = append(terms, t)
+			}
+		}
+	}
+
+	docs := make(map[string]int)
+	for _, t := range terms {
+		subject := ix.Postings[subjectTermPrefix+t]
+		for id, tf := range ix.Postings[t] {
+			if bodyOnly {
+				tf -= subjectWeight * subject[id]
+			}
+			if tf > 0 {
+				docs[id] += tf
+			}
+		}
+	}
+	return docs
+}
+
+func (ix *Index) avgLength() float64 {
+	if len(ix.Docs) == 0 {
+		return 1
+	}
+	total := 0
+	for _, doc := range ix.Docs {
+		total += doc.Length
+	}
+	return math.Max(1, float64(total)/float64(len(ix.Docs)))
+}
+
+// matchFields applies the query's field filters to a message.
+func (q *Query) matchFields(doc *IndexedMessage, recipient string) bool {
+	if recipient != "" && !doc.deliveredTo(recipient) {
+		return false
+	}
+	if q.From != "" && !containsFold(doc.From, q.From) {
+		return false
+	}
+	if q.To != "" && !containsFold(doc.To, q.To) {
+		cc := false
+		for _, addr := range doc.CC {
+			cc = cc || containsFold(addr, q.To)
+		}
+		if !cc {
+			return false
+		}
+	}
+	if q.Thread != "" && doc.ThreadID != q.Thread {
+		return false
+	}
+	if q.Priority != "" && doc.Priority != q.Priority {
+		return false
+	}
+	if !q.Before.IsZero() && !doc.Timestamp.Before(q.Before) {
+		return false
+	}
+	if !q.After.IsZero() && doc.Timestamp.Before(q.After) {
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,294 @@
+package mail
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var indexTestTime = time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+func indexTestMessage(id, from, to, subject, body string, age time.Duration) *Message {
+	return &Message{
+		ID:        id,
+		From:      from,
+		To:        to,
+		Subject:   subject,
+		Body:      body,
+		Timestamp: indexTestTime.Add(-age),
+		Priority:  PriorityNormal,
+		Type:      TypeNotification,
+	}
+}
+
+func buildTestIndex(msgs ...*Message) *Index {
+	ix := newIndex("")
+	for _, msg := range msgs {
+		ix.add(newIndexEntry(msg, ""))
+	}
+	return ix
+}
+
+func resultIDs(results []SearchResult) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func mustQuery(t *testing.T, s string) *Query {
+	t.Helper()
+	q, err := ParseQuery(s)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q): %v", s, err)
+	}
+	return q
+}
+
+func TestIndexSearchRanking(t *testing.T) {
+	ix := buildTestIndex(
+		indexTestMessage("hq-1", "gastown/witness", "mayor/", "Patrol report", "All polecats healthy. Merge queue empty.", 3*time.Hour),
+		indexTestMessage("hq-2", "gastown/witness", "mayor/", "Merge conflict on nux", "Refinery hit a merge conflict rebasing.", 2*time.Hour),
+		indexTestMessage("hq-3", "gastown/Toast", "gastown/witness", "POLECAT_DONE Toast", "Work complete, branch pushed for merge.", time.Hour),
+		indexTestMessage("hq-4", "mayor/", "gastown/Toast", "Handoff", "Context refresh.", 0),
+	)
+
+	got := resultIDs(ix.Search(mustQuery(t, "merge"), 0))
+	want := []string{"hq-2", "hq-1", "hq-3"} // Subject match ranks first; ties by recency
+	if len(got) != 3 || got[0] != want[0] {
+		t.Errorf("merge = %v, want %v first", got, want[0])
+	}
+
+	if got := resultIDs(ix.Search(mustQuery(t, "merge conflict"), 0)); len(got) != 1 || got[0] != "hq-2" {
+		t.Errorf("merge conflict = %v, want [hq-2]", got)
+	}
+	if got := resultIDs(ix.Search(mustQuery(t, "polecat*"), 0)); len(got) != 2 {
+		t.Errorf("polecat* = %v, want hq-1 and hq-3", got)
+	}
+	if got := ix.Search(mustQuery(t, "nonexistent"), 0); len(got) != 0 {
+		t.Errorf("nonexistent = %v", resultIDs(got))
+	}
+	if got := ix.Search(mustQuery(t, "merge"), 2); len(got) != 2 {
+		t.Errorf("limit 2 returned %d", len(got))
+	}
+}
+
+func TestIndexSearchFields(t *testing.T) {
+	urgent := indexTestMessage("hq-5", "deacon/", "gastown/witness", "Escalation", "Stuck polecat.", 48*time.Hour)
+	urgent.Priority = PriorityUrgent
+	urgent.ThreadID = "thread-abc"
+	urgent.CC = []string{"mayor/"}
+	ix := buildTestIndex(
+		indexTestMessage("hq-1", "gastown/witness", "mayor/", "Patrol report", "All polecats healthy.", 3*time.Hour),
+		indexTestMessage("hq-3", "gastown/Toast", "gastown/witness", "POLECAT_DONE Toast", "Merge ready.", time.Hour),
+		urgent,
+	)
+
+	timeNow = func() time.Time { return indexTestTime }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"from:witness", []string{"hq-1"}},
+		{"to:witness", []string{"hq-3", "hq-5"}},
+		{"to:mayor", []string{"hq-1", "hq-5"}}, // CC counts
+		{"thread:thread-abc", []string{"hq-5"}},
+		{"priority:urgent", []string{"hq-5"}},
+		{"after:1d", []string{"hq-3", "hq-1"}},
+		{"before:1d", []string{"hq-5"}},
+		{"before:2026-03-14", []string{"hq-5"}},
+		{"subject:patrol", []string{"hq-1"}},
+		{"subject:polecat*", []string{"hq-3"}},
+		{"polecat* from:deacon", []string{"hq-5"}},
+		{`"patrol report"`, []string{"hq-1"}},
+	}
+	for _, tt := range tests {
+		got := resultIDs(ix.Search(mustQuery(t, tt.query), 0))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// Recipient restricts to one mailbox, including CCs
+	q := mustQuery(t, "")
+	q.Recipient = "mayor"
+	if got := resultIDs(ix.Search(q, 0)); len(got) != 2 {
+		t.Errorf("mayor's mail = %v, want hq-1 and hq-5", got)
+	}
+
+	// BodyOnly ignores subject matches
+	q = mustQuery(t, "patrol")
+	q.BodyOnly = true
+	if got := ix.Search(q, 0); len(got) != 0 {
+		t.Errorf("body-only patrol = %v, want none", resultIDs(got))
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, s := range []string{"priority:critical", "before:yesterday", "after:2026-13-01"} {
+		if _, err := ParseQuery(s); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded", s)
+		}
+	}
+	// Unknown fields are plain text
+	q := mustQuery(t, "see http://example.com")
+	if len(q.Terms) != 4 {
+		t.Errorf("terms = %q", q.Terms)
+	}
+}
+
+func TestIndexLogAndArchives(t *testing.T) {
+	town := t.TempDir()
+	listTownMail = func(string) ([]*Message, error) {
+		return []*Message{indexTestMessage("hq-1", "gastown/witness", "mayor/", "Patrol report", "All healthy.", time.Hour)}, nil
+	}
+	defer func() { listTownMail = listTownMailBeads }()
+
+	ix, err := OpenIndex(town)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if ix.Len() != 1 || ix.BuiltAt.IsZero() {
+		t.Fatalf("first open built %d docs", ix.Len())
+	}
+
+	// Delivered mail goes to the log and is searchable on next open
+	if err := AppendToIndex(town, indexTestMessage("hq-2", "mayor/", "gastown/Toast", "Deploy window", "Deploy tonight.", 0)); err != nil {
+		t.Fatalf("AppendToIndex: %v", err)
+	}
+	ix, err = OpenIndex(town)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if got := resultIDs(ix.Search(mustQuery(t, "deploy"), 0)); len(got) != 1 || got[0] != "hq-2" {
+		t.Fatalf("deploy = %v", got)
+	}
+
+	// New archive lines are indexed and fold the log into the snapshot
+	archive := filepath.Join(town, ".beads", "archive.jsonl")
+	if err := os.MkdirAll(filepath.Dir(archive), 0755); err != nil {
+		t.Fatal(err)
+	}
+	line, _ := json.Marshal(indexTestMessage("msg-old", "gastown/witness", "mayor/", "Old outage", "Dolt was down.", 30*24*time.Hour))
+	dup, _ := json.Marshal(indexTestMessage("hq-1", "gastown/witness", "mayor/", "Patrol report", "All healthy.", time.Hour))
+	if err := os.WriteFile(archive, []byte(string(line)+"\n"+string(dup)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ix, err = OpenIndex(town)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if ix.Len() != 3 {
+		t.Errorf("indexed %d messages, want 3 (archive copy of hq-1 skipped)", ix.Len())
+	}
+	if got := ix.Search(mustQuery(t, "outage"), 0); len(got) != 1 || got[0].Archive != archive {
+		t.Errorf("outage = %+v", got)
+	}
+	if _, err := os.Stat(indexLogPath(town)); !os.IsNotExist(err) {
+		t.Errorf("log not compacted: %v", err)
+	}
+
+	reloaded, err := LoadIndex(town)
+	if err != nil || reloaded.Len() != 3 {
+		t.Fatalf("LoadIndex = %d docs, %v", reloaded.Len(), err)
+	}
+
+	// Purging the archive rebuilds from beads
+	if err := os.WriteFile(archive, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	ix, err = OpenIndex(town)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if ix.Len() != 1 {
+		t.Errorf("after purge indexed %d messages, want 1", ix.Len())
+	}
+}
+
+func TestIndexCompactAfterAnotherCompaction(t *testing.T) {
+	town := t.TempDir()
+	listTownMail = func(string) ([]*Message, error) {
+		return []*Message{indexTestMessage("hq-1", "gastown/witness", "mayor/", "Patrol report", "All healthy.", time.Hour)}, nil
+	}
+	defer func() { listTownMail = listTownMailBeads }()
+
+	if _, err := OpenIndex(town); err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if err := AppendToIndex(town, indexTestMessage("hq-2", "mayor/", "gastown/Toast", "Deploy window for the release train", "Deploy tonight after the convoy lands.", 0)); err != nil {
+		t.Fatalf("AppendToIndex: %v", err)
+	}
+	stale, err := LoadIndex(town)
+	if err != nil || stale.logOffset == 0 {
+		t.Fatalf("LoadIndex: offset %d, %v", stale.logOffset, err)
+	}
+
+	// Another process compacts, then more mail arrives in a fresh, shorter log
+	if _, err := compactIndex(town); err != nil {
+		t.Fatalf("compactIndex: %v", err)
+	}
+	if err := AppendToIndex(town, indexTestMessage("hq-3", "mayor/", "gastown/nux", "Hi", "Ok.", 0)); err != nil {
+		t.Fatalf("AppendToIndex: %v", err)
+	}
+
+	ix, err := compactIndex(town)
+	if err != nil {
+		t.Fatalf("compactIndex: %v", err)
+	}
+	if ix.Len() != 3 || ix.Docs["hq-3"] == nil {
+		t.Errorf("compacted %d docs, want hq-1..hq-3", ix.Len())
+	}
+	if reloaded, err := LoadIndex(town); err != nil || reloaded.Len() != 3 {
+		t.Errorf("LoadIndex after compaction = %d docs, %v", reloaded.Len(), err)
+	}
+}
+
+func TestIndexRemove(t *testing.T) {
+	town := t.TempDir()
+	listTownMail = func(string) ([]*Message, error) {
+		return []*Message{
+			indexTestMessage("hq-1", "gastown/witness", "mayor/", "Patrol report", "All healthy.", time.Hour),
+			indexTestMessage("hq-2", "gastown/witness", "mayor/", "Patrol report", "Toast is stuck.", 0),
+		}, nil
+	}
+	defer func() { listTownMail = listTownMailBeads }()
+
+	if _, err := OpenIndex(town); err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if err := RemoveFromIndex(town, "hq-2"); err != nil {
+		t.Fatalf("RemoveFromIndex: %v", err)
+	}
+	ix, err := OpenIndex(town)
+	if err != nil {
+		t.Fatalf("OpenIndex: %v", err)
+	}
+	if got := resultIDs(ix.Search(mustQuery(t, "patrol"), 0)); len(got) != 1 || got[0] != "hq-1" {
+		t.Errorf("patrol after delete = %v, want [hq-1]", got)
+	}
+	if got := ix.Search(mustQuery(t, "stuck"), 0); len(got) != 0 {
+		t.Errorf("stuck after delete = %v", resultIDs(got))
+	}
+
+	// The closed bead is still listed, but a rebuild leaves it out
+	ix, err = RebuildIndex(town)
+	if err != nil {
+		t.Fatalf("RebuildIndex: %v", err)
+	}
+	if ix.Len() != 1 || ix.Docs["hq-2"] != nil {
+		t.Errorf("rebuild indexed %d docs, want hq-1 only", ix.Len())
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"sync"
 	"time"
@@ -85,12 +84,19 @@ func (m *Mailbox) Path() string {
 	return m.path
 }
 
-// List returns all open messages in the mailbox.
+// List returns all open messages in the mailbox. Expired messages are left out.
 func (m *Mailbox) List() ([]*Message, error) {
+	var messages []*Message
+	var err error
 	if m.legacy {
-		return m.listLegacy()
+		messages, err = m.listLegacy()
+	} else {
+		messages, err = m.listBeads()
+	}
+	if err != nil {
+		return nil, err
 	}
-	return m.listBeads()
+	return dropExpired(messages), nil
 }
 
 func (m *Mailbox) listBeads() ([]*Message, error) {
@@ -657,17 +663,17 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 		if err := os.Remove(m.ArchivePath()); err != nil && !os.IsNotExist(err) {
 			return 0, err
 		}
+		m.unindex(messages)
 		return len(messages), nil
 	}
 
 	// Filter by age
 	cutoff := timeNow().AddDate(0, 0, -olderThanDays)
-	var keep []*Message
-	purged := 0
+	var keep, purged []*Message
 
 	for _, msg := range messages {
 		if msg.Timestamp.Before(cutoff) {
-			purged++
+			purged = append(purged, msg)
 		} else {
 			keep = append(keep, msg)
 		}
@@ -683,8 +689,28 @@ func (m *Mailbox) PurgeArchive(olderThanDays int) (int, error) {
 			return 0, err
 		}
 	}
+	m.unindex(purged)
 
-	return purged, nil
+	return len(purged), nil
+}
+
+// unindex removes purged messages from the town's search index
+// (best-effort). Their mail beads are closed but still listed, so without
+// this a rebuild would index them again.
+func (m *Mailbox) unindex(messages []*Message) {
+	dir := m.workDir
+	if m.legacy {
+		dir = filepath.Dir(m.path)
+	}
+	townRoot := detectTownRoot(dir)
+	if townRoot == "" {
+		return
+	}
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+	_ = RemoveFromIndex(townRoot, ids...)
 }
 
 func (m *Mailbox) rewriteArchive(messages []*Message) error {
@@ -714,83 +740,6 @@ func (m *Mailbox) rewriteArchive(messages []*Message) error {
 	return os.Rename(tmpPath, archivePath)
 }
 
-// SearchOptions specifies search parameters.
-type SearchOptions struct {
-	Query       string // Regex pattern to search for
-	FromFilter  string // Optional: only match messages from this sender
-	SubjectOnly bool   // Only search subject
-	BodyOnly    bool   // Only search body
-}
-
-// Search finds messages matching the given criteria.
-// Returns messages from both inbox and archive.
-// Query and FromFilter are treated as literal strings (not regex) to prevent ReDoS.
-func (m *Mailbox) Search(opts SearchOptions) ([]*Message, error) {
-	// Use QuoteMeta to escape special regex chars - prevents ReDoS attacks
-	// and provides intuitive literal string matching for users
-	re, err := regexp.Compile("(?i)" + regexp.QuoteMeta(opts.Query))
-	if err != nil {
-		return nil, errors.User("mail.InvalidSearchPattern", "invalid search pattern").
-			WithContext("pattern", opts.Query).
-			WithHint("Search uses literal string matching (case-insensitive)")
-	}
-
-	var fromRe *regexp.Regexp
-	if opts.FromFilter != "" {
-		fromRe, err = regexp.Compile("(?i)" + regexp.QuoteMeta(opts.FromFilter))
-		if err != nil {
-			return nil, errors.User("mail.InvalidFromFilter", "invalid from filter").
-				WithContext("filter", opts.FromFilter).
-				WithHint("From filter uses literal string matching (case-insensitive)")
-		}
-	}
-
-	// Get inbox messages
-	inbox, err := m.List()
-	if err != nil {
-		return nil, err
-	}
-
-	// Get archived messages
-	archived, err := m.ListArchived()
-	if err != nil && !os.IsNotExist(err) {
-		return nil, err
-	}
-
-	// Combine and search
-	all := append(inbox, archived...)
-	var matches []*Message
-
-	for _, msg := range all {
-		// Apply from filter
-		if fromRe != nil && !fromRe.MatchString(msg.From) {
-			continue
-		}
-
-		// Search in specified fields
-		matched := false
-		if opts.SubjectOnly {
-			matched = re.MatchString(msg.Subject)
-		} else if opts.BodyOnly {
-			matched = re.MatchString(msg.Body)
-		} else {
-			// Search in both subject and body
-			matched = re.MatchString(msg.Subject) || re.MatchString(msg.Body)
-		}
-
-		if matched {
-			matches = append(matches, msg)
-		}
-	}
-
-	// Sort by timestamp (newest first)
-	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Timestamp.After(matches[j].Timestamp)
-	})
-
-	return matches, nil
-}
-
 // Count returns the total and unread message counts.
 func (m *Mailbox) Count() (total, unread int, err error) {
 	messages, err := m.List()
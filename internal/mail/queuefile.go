@@ -0,0 +1,65 @@
+package mail
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/filelock"
+)
+
+// loadQueueFile reads a JSON array queue file under its read lock.
+// A missing file is an empty queue.
+func loadQueueFile[T any](path string) ([]T, error) {
+	var queue []T
+	err := filelock.WithReadLock(path, func() error {
+		var err error
+		queue, err = readQueueFile[T](path)
+		return err
+	})
+	return queue, err
+}
+
+// updateQueueFile replaces a JSON array queue file with what fn returns,
+// holding the file's write lock throughout.
+func updateQueueFile[T any](path string, fn func([]T) []T) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return filelock.WithWriteLock(path, func() error {
+		queue, err := readQueueFile[T](path)
+		if err != nil {
+			return err
+		}
+		queue = fn(queue)
+		if queue == nil {
+			queue = []T{}
+		}
+
+		data, err := json.MarshalIndent(queue, "", "  ")
+		if err != nil {
+			return err
+		}
+		// Atomic write: write to temp file, then rename
+		tmpPath := path + ".tmp"
+		if err := os.WriteFile(tmpPath, data, 0644); err != nil { //nolint:gosec // G306: queue is non-sensitive operational data
+			return err
+		}
+		return os.Rename(tmpPath, path)
+	})
+}
+
+func readQueueFile[T any](path string) ([]T, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town root
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var queue []T
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
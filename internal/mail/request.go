@@ -0,0 +1,224 @@
+package mail
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// PendingRequestsPath returns the path of the file tracking requests that
+// are waiting for a reply.
+func PendingRequestsPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "mail-queues", "pending-requests.json")
+}
+
+// PendingRequest is a request sent with Router.SendRequest that has not
+// been answered yet. The mail orchestrator resends it or escalates when
+// Deadline passes.
+type PendingRequest struct {
+	// CorrelationID identifies the request; replies carry it.
+	CorrelationID string `json:"correlation_id"`
+
+	// ThreadID is the request's thread. Replies in the thread from anyone
+	// but the requester answer the request.
+	ThreadID string `json:"thread_id"`
+
+	// ReplySubjects are subjects that answer the request when sent by its
+	// recipient, for protocols whose replies are not threaded.
+	ReplySubjects []string `json:"reply_subjects,omitempty"`
+
+	// Message is the request as sent, kept for resending.
+	Message *Message `json:"message"`
+
+	// Timeout is how long each attempt waits for a reply.
+	Timeout time.Duration `json:"timeout"`
+
+	// SentAt is when the request was first sent.
+	SentAt time.Time `json:"sent_at"`
+
+	// Deadline is when the current attempt times out.
+	Deadline time.Time `json:"deadline"`
+
+	// Attempts counts how many times the request has been sent.
+	Attempts int `json:"attempts"`
+}
+
+// IsOverdue returns true if the request's reply deadline has passed.
+func (p *PendingRequest) IsOverdue(now time.Time) bool {
+	return !now.Before(p.Deadline)
+}
+
+// IsAnsweredBy returns true if msg is a reply to the request: it carries the
+// correlation ID or belongs to the request's thread, and comes from someone
+// other than the requester; or it comes from the recipient with one of the
+// request's ReplySubjects.
+func (p *PendingRequest) IsAnsweredBy(msg *Message) bool {
+	from := AddressToIdentity(msg.From)
+	if from != AddressToIdentity(p.Message.From) {
+		if msg.CorrelationID != "" && msg.CorrelationID == p.CorrelationID {
+			return true
+		}
+		if msg.ThreadID != "" && msg.ThreadID == p.ThreadID {
+			return true
+		}
+	}
+	if from == AddressToIdentity(p.Message.To) {
+		for _, subject := range p.ReplySubjects {
+			if msg.Subject == subject {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadPendingRequests returns a town's unanswered requests.
+func LoadPendingRequests(townRoot string) ([]*PendingRequest, error) {
+	return loadQueueFile[*PendingRequest](PendingRequestsPath(townRoot))
+}
+
+// UpdatePendingRequests replaces a town's pending requests with what fn
+// returns, holding the file's write lock throughout.
+func UpdatePendingRequests(townRoot string, fn func([]*PendingRequest) []*PendingRequest) error {
+	return updateQueueFile(PendingRequestsPath(townRoot), fn)
+}
+
+// SendRequest sends msg as a request that expects a reply within timeout.
+// The request gets a correlation ID and thread if it has none, and is
+// tracked until a reply is sent through any Router in the town. If the reply
+// doesn't come in time the mail orchestrator resends the request, then
+// escalates.
+//
+// replySubjects lists subjects that also count as replies when sent by the
+// recipient, for protocols like MERGE_READY whose answers are sent on their
+// own rather than as threaded replies.
+func (r *Router) SendRequest(msg *Message, timeout time.Duration, replySubjects ...string) (*PendingRequest, error) {
+	if r.townRoot == "" {
+		return nil, errors.User("mail.RequestWithoutTown", "requests need a town to track replies").
+			WithContext("recipient", msg.To).
+			WithHint("Create the router with NewRouterWithTownRoot")
+	}
+	if timeout <= 0 {
+		return nil, errors.User("mail.InvalidTimeout", fmt.Sprintf("request timeout must be positive, got %s", timeout)).
+			WithContext("recipient", msg.To)
+	}
+
+	req := *msg
+	if req.CorrelationID == "" {
+		req.CorrelationID = generateCorrelationID()
+	}
+	if req.ThreadID == "" {
+		req.ThreadID = generateThreadID()
+	}
+	now := timeNow()
+	start := now
+	if req.DeliverAt != nil && req.DeliverAt.After(now) {
+		start = *req.DeliverAt
+	}
+	deadline := start.Add(timeout)
+	req.ReplyBy = &deadline
+
+	if err := r.Send(&req); err != nil {
+		return nil, err
+	}
+
+	pending := &PendingRequest{
+		CorrelationID: req.CorrelationID,
+		ThreadID:      req.ThreadID,
+		ReplySubjects: replySubjects,
+		Message:       &req,
+		Timeout:       timeout,
+		SentAt:        now,
+		Deadline:      deadline,
+		Attempts:      1,
+	}
+	err := UpdatePendingRequests(r.townRoot, func(queue []*PendingRequest) []*PendingRequest {
+		return append(queue, pending)
+	})
+	if err != nil {
+		return nil, errors.Transient("mail.TrackRequest", err).
+			WithContext("recipient", req.To).
+			WithContext("correlation_id", req.CorrelationID).
+			WithHint("The request was sent but its reply won't be tracked; check permissions on " +
+				filepath.Dir(PendingRequestsPath(r.townRoot)))
+	}
+	return pending, nil
+}
+
+// resolveRequests stops tracking the requests msg answers (best-effort).
+// The pending file is only rewritten when msg answers something.
+func (r *Router) resolveRequests(msg *Message) {
+	if r.townRoot == "" {
+		return
+	}
+	if _, err := os.Stat(PendingRequestsPath(r.townRoot)); err != nil {
+		return
+	}
+	pending, err := LoadPendingRequests(r.townRoot)
+	if err != nil {
+		return
+	}
+	for _, p := range pending {
+		if p.Message != nil && p.IsAnsweredBy(msg) {
+			_, _ = ResolveRequests(r.townRoot, msg)
+			return
+		}
+	}
+}
+
+// ResolveRequests removes and returns the pending requests msg answers.
+func ResolveRequests(townRoot string, msg *Message) ([]*PendingRequest, error) {
+	var answered []*PendingRequest
+	err := UpdatePendingRequests(townRoot, func(queue []*PendingRequest) []*PendingRequest {
+		var keep []*PendingRequest
+		for _, p := range queue {
+			if p.Message != nil && p.IsAnsweredBy(msg) {
+				answered = append(answered, p)
+				continue
+			}
+			keep = append(keep, p)
+		}
+		return keep
+	})
+	return answered, err
+}
+
+// CorrelateReply addresses reply as the answer to request: it joins the
+// request's thread, carries its correlation ID, and goes back to the
+// requester unless already addressed. Returns reply for chaining.
+func CorrelateReply(reply, request *Message) *Message {
+	reply.CorrelationID = request.CorrelationID
+	reply.ThreadID = request.ThreadID
+	reply.ReplyTo = request.ID
+	if reply.To == "" {
+		reply.To = request.From
+	}
+	return reply
+}
+
+// requestLabels returns the correlation and reply-by labels of a message.
+func requestLabels(msg *Message) []string {
+	var labels []string
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
+	if msg.ReplyBy != nil {
+		labels = append(labels, "reply-by:"+msg.ReplyBy.UTC().Format(time.RFC3339))
+	}
+	return labels
+}
+
+// generateCorrelationID creates a random request correlation ID.
+// Falls back to time-based ID if crypto/rand fails (extremely rare).
+func generateCorrelationID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("req-%x", time.Now().UnixNano())
+	}
+	return "req-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,86 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRouterSendRequestTracksReply(t *testing.T) {
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+
+	// Scheduled so Send defers instead of needing bd
+	deliverAt := time.Now().Add(time.Hour)
+	msg := &Message{
+		From:      "gastown/witness",
+		To:        "queue:merges",
+		Subject:   "MERGE_READY Toast",
+		DeliverAt: &deliverAt,
+	}
+	req, err := r.SendRequest(msg, 30*time.Minute, "MERGED Toast")
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if req.CorrelationID == "" || req.ThreadID == "" {
+		t.Fatalf("request missing correlation or thread: %+v", req)
+	}
+	if want := deliverAt.Add(30 * time.Minute); !req.Deadline.Equal(want) {
+		t.Errorf("Deadline = %v, want %v (timeout counts from delivery)", req.Deadline, want)
+	}
+	if deferred, _ := LoadDeferred(town); len(deferred) != 1 || deferred[0].Message.CorrelationID != req.CorrelationID {
+		t.Errorf("deferred request = %+v", deferred)
+	}
+
+	// The requester's own follow-up doesn't answer it
+	r.resolveRequests(&Message{From: "gastown/witness", To: "queue:merges", ThreadID: req.ThreadID})
+	if pending, _ := LoadPendingRequests(town); len(pending) != 1 {
+		t.Fatalf("pending = %d after requester follow-up, want 1", len(pending))
+	}
+
+	reply := CorrelateReply(&Message{From: "gastown/refinery", Subject: "Re: MERGE_READY Toast"}, req.Message)
+	if reply.To != "gastown/witness" || reply.CorrelationID != req.CorrelationID {
+		t.Errorf("CorrelateReply = %+v", reply)
+	}
+	r.resolveRequests(reply)
+	if pending, _ := LoadPendingRequests(town); len(pending) != 0 {
+		t.Errorf("pending = %d after reply, want 0", len(pending))
+	}
+}
+
+func TestPendingRequestIsAnsweredBy(t *testing.T) {
+	p := &PendingRequest{
+		CorrelationID: "req-1",
+		ThreadID:      "thread-1",
+		ReplySubjects: []string{"MERGED Toast", "MERGE_FAILED Toast"},
+		Message:       &Message{From: "gastown/witness", To: "gastown/refinery"},
+	}
+
+	tests := []struct {
+		name string
+		msg  *Message
+		want bool
+	}{
+		{"correlation", &Message{From: "gastown/refinery", CorrelationID: "req-1"}, true},
+		{"thread", &Message{From: "mayor/", ThreadID: "thread-1"}, true},
+		{"reply subject from recipient", &Message{From: "gastown/refinery", Subject: "MERGE_FAILED Toast"}, true},
+		{"reply subject from someone else", &Message{From: "gastown/nux", Subject: "MERGED Toast"}, false},
+		{"requester resend", &Message{From: "gastown/witness", CorrelationID: "req-1", ThreadID: "thread-1"}, false},
+		{"unrelated", &Message{From: "gastown/refinery", Subject: "MERGED Nux", ThreadID: "thread-2"}, false},
+	}
+	for _, tt := range tests {
+		if got := p.IsAnsweredBy(tt.msg); got != tt.want {
+			t.Errorf("%s: IsAnsweredBy = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSendRequestNeedsTimeout(t *testing.T) {
+	town := t.TempDir()
+	r := NewRouterWithTownRoot(town, town)
+	if _, err := r.SendRequest(&Message{To: "queue:merges"}, 0); err == nil {
+		t.Error("SendRequest accepted a zero timeout")
+	}
+	if _, err := NewRouterWithTownRoot(town, "").SendRequest(&Message{To: "queue:merges"}, time.Minute); err == nil {
+		t.Error("SendRequest accepted a router without a town")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
@@ -592,7 +593,36 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+// Messages with a future DeliverAt go to the mail orchestrator's deferred
+// queue and are sent when due. A sent reply stops tracking the requests it
+// answers (see SendRequest). Protocol messages whose body doesn't match their
+// registered schema (see RegisterSchema) are rejected.
 func (r *Router) Send(msg *Message) error {
+	// Reject malformed protocol messages here rather than in the recipient
+	if err := ValidateProtocolBody(msg.Subject, msg.Body); err != nil {
+		return errors.User("mail.MalformedProtocolMessage", err.Error()).
+			WithContext("recipient", msg.To).
+			WithContext("sender", msg.From).
+			WithContext("subject", msg.Subject).
+			WithHint("Protocol messages need the fields their recipient parses; see internal/protocol and internal/witness for the formats")
+	}
+
+	// Scheduled for later - hold in the deferred queue
+	if !msg.IsDue(timeNow()) {
+		return r.deferMessage(msg)
+	}
+
+	if err := r.route(msg); err != nil {
+		return err
+	}
+
+	// A reply settles the requests it answers
+	r.resolveRequests(msg)
+	return nil
+}
+
+// route delivers a message according to its address type.
+func (r *Router) route(msg *Message) error {
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -622,6 +652,49 @@ func (r *Router) Send(msg *Message) error {
 	return r.sendToSingle(msg)
 }
 
+// deferMessage adds a scheduled message to the deferred queue. The mail
+// orchestrator sends it through Send once DeliverAt has passed.
+func (r *Router) deferMessage(msg *Message) error {
+	if r.townRoot == "" {
+		return errors.User("mail.DeferWithoutTown", "scheduled mail needs a town").
+			WithContext("recipient", msg.To).
+			WithHint("Run gt mail send from inside a Gas Town workspace")
+	}
+	if err := msg.validateSchedule(); err != nil {
+		return err
+	}
+
+	// Catch bad direct addresses now rather than at delivery time
+	if !isListAddress(msg.To) && !isQueueAddress(msg.To) && !isAnnounceAddress(msg.To) &&
+		!isChannelAddress(msg.To) && !isGroupAddress(msg.To) {
+		if err := r.validateRecipient(AddressToIdentity(msg.To)); err != nil {
+			return errors.Permanent("mail.InvalidRecipient", err).
+				WithContext("recipient", msg.To).
+				WithContext("sender", msg.From).
+				WithContext("subject", msg.Subject)
+		}
+	}
+
+	deferred := *msg
+	if deferred.ID == "" {
+		deferred.ID = generateID()
+	}
+	now := timeNow()
+	if deferred.Timestamp.IsZero() {
+		deferred.Timestamp = now
+	}
+	err := UpdateDeferred(r.townRoot, func(queue []*DeferredMessage) []*DeferredMessage {
+		return append(queue, &DeferredMessage{Message: &deferred, QueuedAt: now})
+	})
+	if err != nil {
+		return errors.Transient("mail.DeferMessage", err).
+			WithContext("recipient", msg.To).
+			WithContext("deliver_at", msg.DeliverAt.Format(time.RFC3339)).
+			WithHint("Check permissions on " + filepath.Dir(DeferredQueuePath(r.townRoot)))
+	}
+	return nil
+}
+
 // sendToGroup resolves a @group address and sends individual messages to each member.
 func (r *Router) sendToGroup(msg *Message) error {
 	group := parseGroupAddress(msg.To)
@@ -720,9 +793,11 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
+	labels = append(labels, requestLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
-	args := []string{"create", msg.Subject,
+	args := []string{"create", "--json", msg.Subject,
 		"--type", "message",
 		"--assignee", toIdentity,
 		"-d", msg.Body,
@@ -749,7 +824,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if err := r.ensureCustomTypes(beadsDir); err != nil {
 		return err
 	}
-	_, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return errors.Transient("mail.CreateMessage", err).
 			WithContext("recipient", msg.To).
@@ -758,6 +833,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 			WithContext("beads_dir", beadsDir).
 			WithHint("Check beads is installed and database is accessible: bd --version")
 	}
+	r.indexSent(msg, out)
 
 	// Notify recipient if they have an active session (best-effort notification)
 	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
@@ -771,6 +847,25 @@ func (r *Router) sendToSingle(msg *Message) error {
 	return nil
 }
 
+// indexSent adds a delivered message to the town's search index under the
+// bead ID bd create reported (best-effort).
+func (r *Router) indexSent(msg *Message, created []byte) {
+	if r.townRoot == "" {
+		return
+	}
+	indexed := *msg
+	var issue struct {
+		ID string `json:"id"`
+	}
+	if json.Unmarshal(created, &issue) == nil && issue.ID != "" {
+		indexed.ID = issue.ID
+	}
+	if indexed.Timestamp.IsZero() {
+		indexed.Timestamp = timeNow()
+	}
+	_ = AppendToIndex(r.townRoot, &indexed)
+}
+
 // sendToList expands a mailing list and sends individual copies to each recipient.
 // Each recipient gets their own message copy with the same content.
 // Returns a ListDeliveryResult with details about the fan-out.
@@ -846,10 +941,12 @@ func (r *Router) sendToQueue(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
+	labels = append(labels, requestLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
 	// Use queue:<name> as assignee so inbox queries can filter by queue
-	args := []string{"create", msg.Subject,
+	args := []string{"create", "--json", msg.Subject,
 		"--type", "message",
 		"--assignee", msg.To, // queue:name
 		"-d", msg.Body,
@@ -875,7 +972,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 	if err := r.ensureCustomTypes(beadsDir); err != nil {
 		return err
 	}
-	_, err = runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return errors.Transient("mail.CreateQueueMessage", err).
 			WithContext("queue_name", queueName).
@@ -883,6 +980,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 			WithContext("subject", msg.Subject).
 			WithHint("Check queue exists and beads is accessible: bd list --type=message --assignee=queue:" + queueName)
 	}
+	r.indexSent(msg, out)
 
 	// No notification for queue messages - workers poll or check on their own schedule
 
@@ -924,10 +1022,12 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
+	labels = append(labels, requestLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=announce:<name> -d <body>
 	// Use announce:<name> as assignee so queries can filter by channel
-	args := []string{"create", msg.Subject,
+	args := []string{"create", "--json", msg.Subject,
 		"--type", "message",
 		"--assignee", msg.To, // announce:name
 		"-d", msg.Body,
@@ -953,7 +1053,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	if err := r.ensureCustomTypes(beadsDir); err != nil {
 		return err
 	}
-	_, err = runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return errors.Transient("mail.CreateAnnounceMessage", err).
 			WithContext("announce_name", announceName).
@@ -961,6 +1061,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 			WithContext("subject", msg.Subject).
 			WithHint("Check announce channel exists and beads is accessible")
 	}
+	r.indexSent(msg, out)
 
 	// No notification for announce messages - readers poll or check on their own schedule
 
@@ -1010,10 +1111,12 @@ func (r *Router) sendToChannel(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	labels = append(labels, scheduleLabels(msg)...)
+	labels = append(labels, requestLabels(msg)...)
 
 	// Build command: bd create <subject> --type=message --assignee=channel:<name> -d <body>
 	// Use channel:<name> as assignee so queries can filter by channel
-	args := []string{"create", msg.Subject,
+	args := []string{"create", "--json", msg.Subject,
 		"--type", "message",
 		"--assignee", msg.To, // channel:name
 		"-d", msg.Body,
@@ -1039,7 +1142,7 @@ func (r *Router) sendToChannel(msg *Message) error {
 	if err := r.ensureCustomTypes(beadsDir); err != nil {
 		return err
 	}
-	_, err = runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	out, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
 	if err != nil {
 		return errors.Transient("mail.CreateChannelMessage", err).
 			WithContext("channel_name", channelName).
@@ -1047,6 +1150,7 @@ func (r *Router) sendToChannel(msg *Message) error {
 			WithContext("subject", msg.Subject).
 			WithHint("Check channel is open and beads is accessible")
 	}
+	r.indexSent(msg, out)
 
 	// Enforce channel retention policy (on-write cleanup)
 	_ = b.EnforceChannelRetention(channelName)
@@ -125,6 +125,23 @@ type Message struct {
 	// ClaimedAt is when the queue message was claimed.
 	// Only set for queue messages after claiming.
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
+	// DeliverAt schedules the message. Until then it waits in the mail
+	// orchestrator's deferred queue instead of the recipient's inbox.
+	DeliverAt *time.Time `json:"deliver_at,omitempty"`
+
+	// ExpiresAt is when the message stops being useful. Expired messages
+	// drop out of inboxes; scheduled ones that expire before delivery are
+	// dead-lettered.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// CorrelationID ties a request to its reply (see Router.SendRequest).
+	// Replies carry the request's correlation ID.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// ReplyBy is when a reply to this request is due. The mail
+	// orchestrator retries or escalates requests left unanswered.
+	ReplyBy *time.Time `json:"reply_by,omitempty"`
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -157,6 +174,8 @@ func NewReplyMessage(from, to, subject, body string, original *Message) *Message
 		Type:      TypeReply,
 		ThreadID:  original.ThreadID,
 		ReplyTo:   original.ID,
+
+		CorrelationID: original.CorrelationID,
 	}
 }
 
@@ -214,6 +233,16 @@ func (m *Message) IsClaimed() bool {
 	return m.ClaimedBy != ""
 }
 
+// IsDue returns true if the message is unscheduled or its delivery time has come.
+func (m *Message) IsDue(now time.Time) bool {
+	return m.DeliverAt == nil || !now.Before(*m.DeliverAt)
+}
+
+// IsExpired returns true if the message has an expiry that has passed.
+func (m *Message) IsExpired(now time.Time) bool {
+	return m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
+}
+
 // Validate checks that the message has a valid routing configuration.
 // Returns an error if to, queue, and channel are not mutually exclusive.
 func (m *Message) Validate() error {
@@ -254,6 +283,18 @@ func (m *Message) Validate() error {
 			WithHint("ClaimedAt field should only be set for queue messages")
 	}
 
+	return m.validateSchedule()
+}
+
+// validateSchedule checks that a scheduled message doesn't expire before delivery.
+func (m *Message) validateSchedule() error {
+	if m.DeliverAt != nil && m.ExpiresAt != nil && !m.ExpiresAt.After(*m.DeliverAt) {
+		return errors.User("mail.ExpiresBeforeDelivery", "message would expire before it is delivered").
+			WithContext("message_id", m.ID).
+			WithContext("deliver_at", m.DeliverAt.Format(time.RFC3339)).
+			WithContext("expires_at", m.ExpiresAt.Format(time.RFC3339)).
+			WithHint("Set expires_at after deliver_at")
+	}
 	return nil
 }
 
@@ -289,7 +330,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X, deliver-at:X, expires-at:X, correlation:X, reply-by:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
@@ -303,6 +344,10 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+	deliverAt *time.Time // When a scheduled message was due
+	expiresAt *time.Time // When the message expires
+	corrID    string     // Request/reply correlation ID
+	replyBy   *time.Time // When a reply to the request is due
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -329,6 +374,23 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, "deliver-at:") {
+			ts := strings.TrimPrefix(label, "deliver-at:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				bm.deliverAt = &t
+			}
+		} else if strings.HasPrefix(label, "expires-at:") {
+			ts := strings.TrimPrefix(label, "expires-at:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				bm.expiresAt = &t
+			}
+		} else if strings.HasPrefix(label, "correlation:") {
+			bm.corrID = strings.TrimPrefix(label, "correlation:")
+		} else if strings.HasPrefix(label, "reply-by:") {
+			ts := strings.TrimPrefix(label, "reply-by:")
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				bm.replyBy = &t
+			}
 		}
 	}
 }
@@ -397,6 +459,11 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Channel:   bm.channel,
 		ClaimedBy: bm.claimedBy,
 		ClaimedAt: bm.claimedAt,
+		DeliverAt: bm.deliverAt,
+		ExpiresAt: bm.expiresAt,
+
+		CorrelationID: bm.corrID,
+		ReplyBy:       bm.replyBy,
 	}
 }
 
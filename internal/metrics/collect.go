@@ -0,0 +1,298 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/monitoring"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxPendingMRAge bounds how long a gt done waits for its merge before it
+// is no longer timed.
+const maxPendingMRAge = 7 * 24 * time.Hour
+
+// CounterStateFile returns the path to the collector's persisted counters.
+func CounterStateFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "metrics-state.json")
+}
+
+// counterState is what the collector accumulates from events. It is saved
+// after every collection so counters survive daemon restarts.
+type counterState struct {
+	SessionDeaths float64              `json:"session_deaths"`
+	Cycles        map[string]cycleStat `json:"cycles,omitempty"`  // by rig
+	Pending       map[string]pendingMR `json:"pending,omitempty"` // by branch
+}
+
+// cycleStat sums MR cycle times for one rig.
+type cycleStat struct {
+	Sum   float64 `json:"sum"`
+	Count float64 `json:"count"`
+}
+
+// pendingMR is a branch handed to the merge queue by gt done.
+type pendingMR struct {
+	Rig    string    `json:"rig"`
+	DoneAt time.Time `json:"done_at"`
+}
+
+// Collector samples a town. Gauges are read fresh from beads, the agent
+// monitor's status file and the cost ledger on each Collect; counters are
+// accumulated from events passed to Observe.
+type Collector struct {
+	townRoot   string
+	ledgerPath string
+	stateFile  string
+
+	// list queries beads in a work directory; replaced in tests.
+	list func(workDir string, opts beads.ListOptions) ([]*beads.Issue, error)
+
+	mu    sync.Mutex
+	state counterState
+}
+
+// NewCollector creates a collector for the town, restoring counters saved
+// by a previous run.
+func NewCollector(townRoot, ledgerPath string) (*Collector, error) {
+	c := &Collector{
+		townRoot:   townRoot,
+		ledgerPath: ledgerPath,
+		stateFile:  CounterStateFile(townRoot),
+		list: func(workDir string, opts beads.ListOptions) ([]*beads.Issue, error) {
+			return beads.New(workDir).List(opts)
+		},
+	}
+	var err error
+	if data, readErr := os.ReadFile(c.stateFile); readErr == nil {
+		if jsonErr := json.Unmarshal(data, &c.state); jsonErr != nil {
+			err = fmt.Errorf("loading metrics counters: %w", jsonErr)
+		}
+	} else if !os.IsNotExist(readErr) {
+		err = fmt.Errorf("loading metrics counters: %w", readErr)
+	}
+	if c.state.Cycles == nil {
+		c.state.Cycles = make(map[string]cycleStat)
+	}
+	if c.state.Pending == nil {
+		c.state.Pending = make(map[string]pendingMR)
+	}
+	return c, err
+}
+
+// Observe counts an event. It is a feed.Curator sink and doesn't block.
+func (c *Collector) Observe(e *events.Event) {
+	switch e.Type {
+	case events.TypeSessionDeath, events.TypeDone, events.TypeMerged:
+	default:
+		return
+	}
+	at, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return
+	}
+	branch, _ := e.Payload["branch"].(string)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch e.Type {
+	case events.TypeSessionDeath:
+		c.state.SessionDeaths++
+	case events.TypeDone:
+		if branch != "" {
+			rig, _, _ := strings.Cut(e.Actor, "/")
+			c.state.Pending[branch] = pendingMR{Rig: rig, DoneAt: at}
+		}
+	case events.TypeMerged:
+		mr, ok := c.state.Pending[branch]
+		if !ok {
+			return
+		}
+		delete(c.state.Pending, branch)
+		if rig, _ := e.Payload["rig"].(string); rig != "" {
+			mr.Rig = rig
+		}
+		stat := c.state.Cycles[mr.Rig]
+		stat.Sum += at.Sub(mr.DoneAt).Seconds()
+		stat.Count++
+		c.state.Cycles[mr.Rig] = stat
+	}
+}
+
+// Collect samples the town at now. Sources that fail are left out of the
+// snapshot and reported in the returned error; the snapshot is always
+// usable.
+func (c *Collector) Collect(now time.Time) (*Snapshot, error) {
+	snap := &Snapshot{At: now.UTC()}
+	var errs []error
+
+	c.collectPolecats(snap)
+	errs = append(errs, c.collectMergeQueues(snap)...)
+	errs = append(errs, c.collectEscalations(snap))
+	errs = append(errs, c.collectMail(snap))
+	errs = append(errs, c.collectCost(snap, now))
+	errs = append(errs, c.collectCounters(snap, now))
+
+	return snap, errors.Join(errs...)
+}
+
+// collectPolecats counts polecats by rig and status, from the agent
+// monitor. Nothing is recorded while the monitor isn't running.
+func (c *Collector) collectPolecats(snap *Snapshot) {
+	file, err := monitoring.LoadStatusFile(monitoring.StatusFile(c.townRoot))
+	if err != nil || !file.Fresh(monitoring.StatusFileMaxAge) {
+		return
+	}
+
+	idleTimeout := monitoring.DefaultIdleConfig().Timeout
+	counts := make(map[[2]string]int)
+	for id := range file.Agents {
+		parts := strings.Split(id, "/")
+		if len(parts) != 3 || parts[1] != "polecats" {
+			continue
+		}
+		status, _ := file.Status(id, idleTimeout)
+		counts[[2]string{parts[0], string(status)}]++
+	}
+	for k, n := range counts {
+		snap.Add(Polecats, float64(n), "rig", k[0], "state", k[1])
+	}
+}
+
+// collectMergeQueues records open merge requests for every registered rig.
+func (c *Collector) collectMergeQueues(snap *Snapshot) []error {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(c.townRoot))
+	if err != nil {
+		return []error{fmt.Errorf("loading rigs: %w", err)}
+	}
+
+	var errs []error
+	for name := range rigsConfig.Rigs {
+		issues, err := c.list(filepath.Join(c.townRoot, name), beads.ListOptions{
+			Label:    "gt:merge-request",
+			Status:   "open",
+			Priority: -1,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("merge queue %s: %w", name, err))
+			continue
+		}
+		snap.Add(MergeQueueDepth, float64(len(issues)), "rig", name)
+	}
+	return errs
+}
+
+// collectEscalations counts open escalations by severity.
+func (c *Collector) collectEscalations(snap *Snapshot) error {
+	issues, err := c.list(c.townRoot, beads.ListOptions{
+		Label:    "gt:escalation",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("escalations: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		severity := config.SeverityMedium
+		for _, label := range issue.Labels {
+			if s, ok := strings.CutPrefix(label, "severity:"); ok {
+				severity = s
+			}
+		}
+		counts[severity]++
+	}
+	for _, severity := range []string{config.SeverityCritical, config.SeverityHigh, config.SeverityMedium, config.SeverityLow} {
+		snap.Add(EscalationsOpen, float64(counts[severity]), "severity", severity)
+		delete(counts, severity)
+	}
+	for severity, n := range counts {
+		snap.Add(EscalationsOpen, float64(n), "severity", severity)
+	}
+	return nil
+}
+
+// collectMail counts unread (open) messages by recipient.
+func (c *Collector) collectMail(snap *Snapshot) error {
+	issues, err := c.list(c.townRoot, beads.ListOptions{
+		Label:    "gt:message",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Assignee]++
+	}
+	for to, n := range counts {
+		snap.Add(MailBacklog, float64(n), "to", to)
+	}
+	return nil
+}
+
+// collectCost sums the last hour of spend in the cost ledger by rig.
+// Ledger entries are cumulative per session, so spend is measured from each
+// session's previous entry. Town-level agents (mayor, deacon) have no rig
+// label.
+func (c *Collector) collectCost(snap *Snapshot, now time.Time) error {
+	since := now.Add(-time.Hour)
+	entries, err := costs.ReadLog(c.ledgerPath, since.Add(-costs.LedgerLookback))
+	if err != nil {
+		return err
+	}
+
+	byRig := make(map[string]float64)
+	for _, u := range costs.UsageFromLog(entries) {
+		if !u.At.Before(since) {
+			byRig[u.Rig] += u.CostUSD
+		}
+	}
+	if _, ok := byRig[""]; !ok {
+		byRig[""] = 0
+	}
+	for rig, usd := range byRig {
+		snap.Add(CostUSDPerHour, usd, "rig", rig)
+	}
+	return nil
+}
+
+// collectCounters records the event counters and saves them.
+func (c *Collector) collectCounters(snap *Snapshot, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snap.Add(SessionDeaths+"_total", c.state.SessionDeaths)
+	for rig, stat := range c.state.Cycles {
+		snap.Add(MRCycleSeconds+"_sum", stat.Sum, "rig", rig)
+		snap.Add(MRCycleSeconds+"_count", stat.Count, "rig", rig)
+	}
+	for branch, mr := range c.state.Pending {
+		if now.Sub(mr.DoneAt) > maxPendingMRAge {
+			delete(c.state.Pending, branch)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.stateFile), 0755); err != nil {
+		return fmt.Errorf("creating metrics directory: %w", err)
+	}
+	if err := util.AtomicWriteJSON(c.stateFile, &c.state); err != nil {
+		return fmt.Errorf("saving metrics counters: %w", err)
+	}
+	return nil
+}
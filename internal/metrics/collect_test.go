@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/costs"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/monitoring"
+)
+
+// newTestCollector returns a collector for a town with one rig, gastown,
+// whose beads queries are answered from issues (keyed by work dir and
+// label).
+func newTestCollector(t *testing.T, issues map[string][]*beads.Issue) *Collector {
+	t.Helper()
+	townRoot := t.TempDir()
+	rigs := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gastown": {}}}
+	if err := os.MkdirAll(filepath.Dir(constants.MayorRigsPath(townRoot)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(constants.MayorRigsPath(townRoot), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewCollector(townRoot, filepath.Join(townRoot, "costs.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.list = func(workDir string, opts beads.ListOptions) ([]*beads.Issue, error) {
+		rel, _ := filepath.Rel(townRoot, workDir)
+		return issues[rel+" "+opts.Label], nil
+	}
+	return c
+}
+
+func TestCollector_Gauges(t *testing.T) {
+	c := newTestCollector(t, map[string][]*beads.Issue{
+		"gastown gt:merge-request": {{ID: "gt-mr1"}, {ID: "gt-mr2"}},
+		". gt:escalation":          {{ID: "hq-e1", Labels: []string{"gt:escalation", "severity:high"}}, {ID: "hq-e2"}},
+		". gt:message":             {{ID: "hq-m1", Assignee: "mayor/"}, {ID: "hq-m2", Assignee: "mayor/"}, {ID: "hq-m3", Assignee: "gastown/witness"}},
+	})
+
+	tracker := monitoring.NewStatusTracker()
+	tracker.SetStatus("gastown/polecats/toast", monitoring.StatusWorking, monitoring.SourceInferred, "")
+	tracker.SetStatus("gastown/polecats/nux", monitoring.StatusWorking, monitoring.SourceInferred, "")
+	tracker.SetStatus("gastown/polecats/ace", monitoring.StatusBlocked, monitoring.SourceInferred, "")
+	tracker.SetStatus("gastown/witness", monitoring.StatusWorking, monitoring.SourceInferred, "")
+	if err := monitoring.SaveStatusFile(monitoring.StatusFile(c.townRoot), tracker); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	writeLedger(t, c.ledgerPath,
+		// Entries are cumulative per session: toast spent $2.50 this hour
+		costs.LogEntry{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 3.5, EndedAt: now.Add(-10 * time.Minute)},
+		costs.LogEntry{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 2, EndedAt: now.Add(-30 * time.Minute)},
+		costs.LogEntry{SessionID: "gt-gastown-toast", Rig: "gastown", CostUSD: 1, EndedAt: now.Add(-2 * time.Hour)},
+		costs.LogEntry{SessionID: "gt-gastown-nux", Rig: "gastown", CostUSD: 50, EndedAt: now.Add(-3 * time.Hour)},
+		costs.LogEntry{SessionID: "hq-mayor", Role: "mayor", CostUSD: 0.25, EndedAt: now.Add(-5 * time.Minute)},
+	)
+
+	snap, err := c.Collect(now)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		labels []string
+		want   float64
+	}{
+		{Polecats, []string{"rig", "gastown", "state", "working"}, 2},
+		{Polecats, []string{"rig", "gastown", "state", "blocked"}, 1},
+		{MergeQueueDepth, []string{"rig", "gastown"}, 2},
+		{EscalationsOpen, []string{"severity", "high"}, 1},
+		{EscalationsOpen, []string{"severity", "medium"}, 1},
+		{EscalationsOpen, []string{"severity", "critical"}, 0},
+		{MailBacklog, []string{"to", "mayor/"}, 2},
+		{MailBacklog, []string{"to", "gastown/witness"}, 1},
+		{CostUSDPerHour, []string{"rig", "gastown"}, 2.5},
+		{CostUSDPerHour, nil, 0.25},
+		{SessionDeaths + "_total", nil, 0},
+	} {
+		got, ok := snap.Value(tt.name, tt.labels...)
+		if !ok || got != tt.want {
+			t.Errorf("%s%v = %v (found %v), want %v", tt.name, tt.labels, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCollector_EventCounters(t *testing.T) {
+	c := newTestCollector(t, nil)
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := func(typ, actor string, offset time.Duration, payload map[string]interface{}) *events.Event {
+		return &events.Event{Type: typ, Actor: actor, Timestamp: at.Add(offset).Format(time.RFC3339), Payload: payload}
+	}
+
+	c.Observe(event(events.TypeDone, "gastown/polecats/toast", 0, events.DonePayload("gt-1", "polecat/toast")))
+	c.Observe(event(events.TypeDone, "gastown/polecats/nux", 0, events.DonePayload("gt-2", "polecat/nux")))
+	c.Observe(event(events.TypeMerged, "gastown/refinery", 10*time.Minute, map[string]interface{}{"branch": "polecat/toast"}))
+	c.Observe(event(events.TypeMerged, "gastown/refinery", 30*time.Minute, map[string]interface{}{"branch": "polecat/nux"}))
+	c.Observe(event(events.TypeMerged, "gastown/refinery", 30*time.Minute, map[string]interface{}{"branch": "unknown"}))
+	c.Observe(event(events.TypeSessionDeath, "gt-gastown-toast", 0, nil))
+	c.Observe(event(events.TypeSessionDeath, "gt-gastown-nux", 0, nil))
+
+	snap, _ := c.Collect(at.Add(time.Hour))
+	if v, _ := snap.Value(SessionDeaths + "_total"); v != 2 {
+		t.Errorf("session deaths = %v, want 2", v)
+	}
+	if v, _ := snap.Value(MRCycleSeconds+"_count", "rig", "gastown"); v != 2 {
+		t.Errorf("cycle count = %v, want 2", v)
+	}
+	if v, _ := snap.Value(MRCycleSeconds+"_sum", "rig", "gastown"); v != 2400 {
+		t.Errorf("cycle sum = %v, want 2400", v)
+	}
+
+	// Counters survive a restart
+	restarted, err := NewCollector(c.townRoot, c.ledgerPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted.list = c.list
+	restarted.Observe(event(events.TypeSessionDeath, "gt-gastown-ace", 2*time.Hour, nil))
+	snap, _ = restarted.Collect(at.Add(2 * time.Hour))
+	if v, _ := snap.Value(SessionDeaths + "_total"); v != 3 {
+		t.Errorf("session deaths after restart = %v, want 3", v)
+	}
+}
+
+func TestCollector_PartialFailure(t *testing.T) {
+	c := newTestCollector(t, nil)
+	c.list = func(workDir string, opts beads.ListOptions) ([]*beads.Issue, error) {
+		if opts.Label == "gt:escalation" {
+			return nil, fmt.Errorf("bd unavailable")
+		}
+		return nil, nil
+	}
+
+	snap, err := c.Collect(time.Now())
+	if err == nil {
+		t.Error("Collect did not report the failed source")
+	}
+	if _, ok := snap.Value(EscalationsOpen, "severity", "high"); ok {
+		t.Error("failed source was recorded")
+	}
+	if v, ok := snap.Value(MergeQueueDepth, "rig", "gastown"); !ok || v != 0 {
+		t.Errorf("merge queue depth = %v (found %v), want 0", v, ok)
+	}
+}
+
+func writeLedger(t *testing.T, path string, entries ...costs.LogEntry) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package metrics records periodic samples of town health into a rolling
+// on-disk store and exposes the latest sample in OpenMetrics text format.
+//
+// The daemon's metrics recorder collects a Snapshot on an interval and
+// appends it to daemon/metrics.jsonl. gt dashboard and the daemon serve the
+// newest snapshot at /metrics for Prometheus to scrape.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type is an OpenMetrics metric family type.
+type Type string
+
+const (
+	TypeGauge   Type = "gauge"
+	TypeCounter Type = "counter"
+	TypeSummary Type = "summary"
+)
+
+// Family describes a metric family. Counter samples are named
+// <name>_total; summary samples <name>_sum and <name>_count.
+type Family struct {
+	Name string
+	Type Type
+	Unit string
+	Help string
+}
+
+// Metric family names.
+const (
+	Polecats          = "gastown_polecats"
+	MergeQueueDepth   = "gastown_merge_queue_depth"
+	MRCycleSeconds    = "gastown_mr_cycle_seconds"
+	EscalationsOpen   = "gastown_escalations_open"
+	SessionDeaths     = "gastown_session_deaths"
+	CostUSDPerHour    = "gastown_cost_usd_per_hour"
+	MailBacklog       = "gastown_mail_backlog"
+	LastSampleSeconds = "gastown_metrics_last_sample_timestamp_seconds"
+)
+
+// Families lists the recorded metric families, in exposition order.
+var Families = []Family{
+	{Name: Polecats, Type: TypeGauge, Help: "Polecats by rig and agent monitor state."},
+	{Name: MergeQueueDepth, Type: TypeGauge, Help: "Open merge requests by rig."},
+	{Name: MRCycleSeconds, Type: TypeSummary, Unit: "seconds", Help: "Time from gt done to merge, by rig."},
+	{Name: EscalationsOpen, Type: TypeGauge, Help: "Open escalations by severity."},
+	{Name: SessionDeaths, Type: TypeCounter, Help: "Agent sessions that died."},
+	{Name: CostUSDPerHour, Type: TypeGauge, Help: "Agent spend recorded in the last hour, in USD, by rig."},
+	{Name: MailBacklog, Type: TypeGauge, Help: "Unread messages by recipient."},
+	{Name: LastSampleSeconds, Type: TypeGauge, Unit: "seconds", Help: "When the town was last sampled."},
+}
+
+// Sample is one value of a metric. Name is the full sample name, including
+// any _total, _sum or _count suffix.
+type Sample struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// Snapshot is every sample taken at one point in time.
+type Snapshot struct {
+	At      time.Time `json:"at"`
+	Samples []Sample  `json:"samples"`
+}
+
+// Add appends a sample. labels are key/value pairs; empty values are
+// dropped.
+func (s *Snapshot) Add(name string, value float64, labels ...string) {
+	sample := Sample{Name: name, Value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		if labels[i+1] == "" {
+			continue
+		}
+		if sample.Labels == nil {
+			sample.Labels = make(map[string]string)
+		}
+		sample.Labels[labels[i]] = labels[i+1]
+	}
+	s.Samples = append(s.Samples, sample)
+}
+
+// Value returns the value of the sample with exactly the given name and
+// labels.
+func (s *Snapshot) Value(name string, labels ...string) (float64, bool) {
+	want := Snapshot{}
+	want.Add(name, 0, labels...)
+	key := want.Samples[0].key()
+	for _, sample := range s.Samples {
+		if sample.key() == key {
+			return sample.Value, true
+		}
+	}
+	return 0, false
+}
+
+// key identifies a sample by name and sorted labels.
+func (s Sample) key() string {
+	names := make([]string, 0, len(s.Labels))
+	for k := range s.Labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(s.Name)
+	for _, k := range names {
+		b.WriteString("\x00" + k + "=" + s.Labels[k])
+	}
+	return b.String()
+}
+
+// family returns the family a sample belongs to.
+func family(sample string) (Family, bool) {
+	for _, f := range Families {
+		switch sample {
+		case f.Name:
+			return f, f.Type == TypeGauge
+		case f.Name + "_total":
+			return f, f.Type == TypeCounter
+		case f.Name + "_sum", f.Name + "_count":
+			return f, f.Type == TypeSummary
+		}
+	}
+	return Family{}, false
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the OpenMetrics text format.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Path is where /metrics is served.
+const Path = "/metrics"
+
+// labelEscaper escapes label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Write encodes snap in OpenMetrics text format. Every family is described,
+// even without samples; samples of unknown families are skipped. A nil
+// snapshot writes only the descriptions.
+func Write(w io.Writer, snap *Snapshot) error {
+	byFamily := make(map[string][]Sample)
+	if snap != nil {
+		for _, s := range snap.Samples {
+			if f, ok := family(s.Name); ok {
+				byFamily[f.Name] = append(byFamily[f.Name], s)
+			}
+		}
+		byFamily[LastSampleSeconds] = []Sample{{
+			Name:  LastSampleSeconds,
+			Value: float64(snap.At.UnixMilli()) / 1000,
+		}}
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, f := range Families {
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.Name, f.Type)
+		if f.Unit != "" {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", f.Name, f.Unit)
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.Name, f.Help)
+
+		samples := byFamily[f.Name]
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].key() < samples[j].key()
+		})
+		for _, s := range samples {
+			bw.WriteString(s.Name)
+			writeLabels(bw, s.Labels)
+			bw.WriteString(" " + strconv.FormatFloat(s.Value, 'g', -1, 64) + "\n")
+		}
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+func writeLabels(w *bufio.Writer, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	w.WriteByte('{')
+	for i, k := range names {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(k + `="` + labelEscaper.Replace(labels[k]) + `"`)
+	}
+	w.WriteByte('}')
+}
+
+// Handler serves a store's latest snapshot in OpenMetrics format.
+type Handler struct {
+	store *Store
+}
+
+// NewHandler creates a /metrics handler for store.
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	snap, err := h.store.Latest()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_ = Write(w, snap)
+}
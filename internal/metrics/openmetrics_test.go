@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	snap := &Snapshot{At: time.Unix(1700000000, 500_000_000)}
+	snap.Add(Polecats, 2, "rig", "gastown", "state", "working")
+	snap.Add(Polecats, 1, "rig", "beads", "state", "blocked")
+	snap.Add(SessionDeaths+"_total", 3)
+	snap.Add(MRCycleSeconds+"_sum", 900, "rig", "gastown")
+	snap.Add(MRCycleSeconds+"_count", 2, "rig", "gastown")
+	snap.Add(MailBacklog, 4, "to", `odd "name"`)
+	snap.Add("gastown_unknown", 1)
+	snap.Add(SessionDeaths, 1) // Counter samples need _total
+
+	var b strings.Builder
+	if err := Write(&b, snap); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE gastown_polecats gauge\n# HELP gastown_polecats ",
+		`gastown_polecats{rig="beads",state="blocked"} 1` + "\n" + `gastown_polecats{rig="gastown",state="working"} 2`,
+		"# TYPE gastown_mr_cycle_seconds summary\n# UNIT gastown_mr_cycle_seconds seconds\n",
+		`gastown_mr_cycle_seconds_count{rig="gastown"} 2` + "\n" + `gastown_mr_cycle_seconds_sum{rig="gastown"} 900`,
+		"gastown_session_deaths_total 3\n",
+		`gastown_mail_backlog{to="odd \"name\""} 4`,
+		"gastown_metrics_last_sample_timestamp_seconds 1.7000000005e+09\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "gastown_unknown") || strings.Contains(out, "gastown_session_deaths 1") {
+		t.Errorf("output has samples outside known families:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("output does not end with # EOF")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "metrics.jsonl"), 0)
+	h := NewHandler(store)
+
+	// No samples yet: families are still described
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != ContentType {
+		t.Fatalf("empty store: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if strings.Contains(rec.Body.String(), "\n"+LastSampleSeconds+" ") {
+		t.Errorf("empty store reported a sample time:\n%s", rec.Body.String())
+	}
+
+	snap := &Snapshot{At: time.Now()}
+	snap.Add(MergeQueueDepth, 5, "rig", "gastown")
+	if err := store.Append(snap); err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	if !strings.Contains(rec.Body.String(), `gastown_merge_queue_depth{rig="gastown"} 5`) {
+		t.Errorf("latest sample not served:\n%s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", rec.Code)
+	}
+}
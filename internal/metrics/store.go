@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// DefaultRetention is how long snapshots are kept when
+// patrols.metrics.retention isn't set in mayor/daemon.json.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// compactInterval is how often the store drops expired snapshots.
+const compactInterval = time.Hour
+
+// tailChunk is how much of the store Latest reads at first.
+const tailChunk = 64 * 1024
+
+// StorePath returns the path to the town's metrics store.
+func StorePath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "metrics.jsonl")
+}
+
+// Store is a rolling time series of snapshots, one JSON line each, oldest
+// first. Appends come from a single writer (the daemon); snapshots older
+// than the retention are dropped as the store grows.
+type Store struct {
+	path      string
+	retention time.Duration
+
+	mu        sync.Mutex
+	compacted time.Time
+}
+
+// NewStore opens the store at path, keeping snapshots for retention.
+func NewStore(path string, retention time.Duration) *Store {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Store{path: path, retention: retention}
+}
+
+// Append adds a snapshot, compacting the store at most once an hour.
+func (s *Store) Append(snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("marshaling snapshot: %w", err)
+	}
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating metrics directory: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: metrics are non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening metrics store: %w", err)
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	if snap.At.Sub(s.compacted) >= compactInterval {
+		if err := s.compact(snap.At); err != nil {
+			return err
+		}
+		s.compacted = snap.At
+	}
+	return nil
+}
+
+// compact rewrites the store without snapshots older than the retention.
+func (s *Store) compact(now time.Time) error {
+	snaps, err := s.Read(now.Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range snaps {
+		if err := enc.Encode(&snaps[i]); err != nil {
+			return fmt.Errorf("marshaling snapshot: %w", err)
+		}
+	}
+	if err := util.AtomicWriteFile(s.path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("compacting metrics store: %w", err)
+	}
+	return nil
+}
+
+// Read returns the snapshots taken at or after since, oldest first.
+// Malformed lines are skipped. A missing store has no snapshots.
+func (s *Store) Read(since time.Time) ([]Snapshot, error) {
+	f, err := os.Open(s.path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening metrics store: %w", err)
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, tailChunk), 16*1024*1024)
+	for scanner.Scan() {
+		var snap Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			continue
+		}
+		if snap.At.Before(since) {
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics store: %w", err)
+	}
+	return snaps, nil
+}
+
+// Latest returns the newest snapshot, or nil if the store is empty. Only
+// the end of the store is read.
+func (s *Store) Latest() (*Snapshot, error) {
+	f, err := os.Open(s.path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening metrics store: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading metrics store: %w", err)
+	}
+	size := info.Size()
+	for chunk := int64(tailChunk); ; chunk *= 2 {
+		off := size - chunk
+		if off < 0 {
+			off = 0
+		}
+		buf := make([]byte, size-off)
+		if _, err := f.ReadAt(buf, off); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("reading metrics store: %w", err)
+		}
+		buf = bytes.TrimRight(buf, "\n")
+		start := bytes.LastIndexByte(buf, '\n')
+		if start < 0 && off > 0 {
+			continue // Last line is longer than the chunk
+		}
+		if len(buf) == 0 {
+			return nil, nil
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(buf[start+1:], &snap); err != nil {
+			return s.latestFromRead()
+		}
+		return &snap, nil
+	}
+}
+
+// latestFromRead finds the newest well-formed snapshot by reading the whole
+// store, for when the last line is torn.
+func (s *Store) latestFromRead() (*Snapshot, error) {
+	snaps, err := s.Read(time.Time{})
+	if err != nil || len(snaps) == 0 {
+		return nil, err
+	}
+	return &snaps[len(snaps)-1], nil
+}
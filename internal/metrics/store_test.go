@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_AppendAndLatest(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "daemon", "metrics.jsonl"), 0)
+
+	if snap, err := store.Latest(); err != nil || snap != nil {
+		t.Fatalf("empty store: Latest = %v, %v", snap, err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		snap := &Snapshot{At: start.Add(time.Duration(i) * time.Minute)}
+		snap.Add(SessionDeaths+"_total", float64(i))
+		if err := store.Append(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	latest, err := store.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := latest.Value(SessionDeaths + "_total"); v != 2 || !latest.At.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("Latest = %+v, want the third snapshot", latest)
+	}
+
+	snaps, err := store.Read(start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 {
+		t.Errorf("Read(since) = %d snapshots, want 2", len(snaps))
+	}
+}
+
+func TestStore_LatestSkipsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	store := NewStore(path, 0)
+	snap := &Snapshot{At: time.Now().UTC()}
+	snap.Add(MergeQueueDepth, 7, "rig", "gastown")
+	if err := store.Append(snap); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"at":"2026-01-01T00:00:00Z","samp`)
+	f.Close()
+
+	latest, err := store.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := latest.Value(MergeQueueDepth, "rig", "gastown"); !ok || v != 7 {
+		t.Errorf("Latest = %+v, want the last complete snapshot", latest)
+	}
+}
+
+func TestStore_LatestLongLine(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "metrics.jsonl"), 0)
+	snap := &Snapshot{At: time.Now().UTC()}
+	for i := 0; i < 3000; i++ {
+		snap.Add(MailBacklog, 1, "to", strings.Repeat("x", 20)+string(rune('a'+i%26))+time.Duration(i).String())
+	}
+	if err := store.Append(snap); err != nil {
+		t.Fatal(err)
+	}
+	latest, err := store.Latest()
+	if err != nil || latest == nil || len(latest.Samples) != 3000 {
+		t.Fatalf("Latest of a %d-sample snapshot failed: %v", len(snap.Samples), err)
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "metrics.jsonl"), 24*time.Hour)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// One snapshot every 6 hours for two days; compaction runs hourly
+	for i := 0; i <= 8; i++ {
+		if err := store.Append(&Snapshot{At: start.Add(time.Duration(i) * 6 * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snaps, err := store.Read(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 5 || !snaps[0].At.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("after compaction: %d snapshots from %v, want 5 from %v", len(snaps), snaps[0].At, start.Add(24*time.Hour))
+	}
+}
@@ -0,0 +1,81 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// StatusFileMaxAge is how old the status file can be before readers assume
+// the monitor has stopped and ignore it.
+const StatusFileMaxAge = 5 * time.Minute
+
+// StatusFileData is the persisted form of a StatusTracker, written by the
+// daemon's agent monitor and read by commands that need agent state.
+type StatusFileData struct {
+	// UpdatedAt is when the monitor last sampled
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Agents maps agent ID to its activity and status history
+	Agents map[string]*AgentActivity `json:"agents"`
+}
+
+// StatusFile returns the path to the persisted agent status file.
+func StatusFile(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "agent-status.json")
+}
+
+// LoadStatusFile reads persisted agent status.
+// Returns empty data if the file doesn't exist (monitor not running yet).
+func LoadStatusFile(path string) (*StatusFileData, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &StatusFileData{Agents: make(map[string]*AgentActivity)}, nil
+		}
+		return nil, fmt.Errorf("reading agent status: %w", err)
+	}
+
+	var file StatusFileData
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing agent status: %w", err)
+	}
+	if file.Agents == nil {
+		file.Agents = make(map[string]*AgentActivity)
+	}
+	return &file, nil
+}
+
+// SaveStatusFile atomically writes the tracker's agents and history to path.
+func SaveStatusFile(path string, tracker *StatusTracker) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating status directory: %w", err)
+	}
+	return util.AtomicWriteJSON(path, &StatusFileData{
+		UpdatedAt: time.Now().UTC(),
+		Agents:    tracker.Snapshot(),
+	})
+}
+
+// Fresh reports whether the file was written within maxAge, i.e. whether
+// a monitor is running and its statuses can be trusted.
+func (f *StatusFileData) Fresh(maxAge time.Duration) bool {
+	return !f.UpdatedAt.IsZero() && time.Since(f.UpdatedAt) <= maxAge
+}
+
+// Status returns an agent's current status as the tracker would report it,
+// applying idleTimeout to the recorded last activity.
+func (f *StatusFileData) Status(agentID string, idleTimeout time.Duration) (AgentStatus, bool) {
+	activity, ok := f.Agents[agentID]
+	if !ok {
+		return StatusOffline, false
+	}
+	if activity.CurrentStatus != StatusIdle && activity.IsIdle(idleTimeout) {
+		return StatusIdle, true
+	}
+	return activity.CurrentStatus, true
+}
@@ -0,0 +1,184 @@
+package monitoring
+
+import (
+	"strings"
+	"time"
+)
+
+// PaneSource lists agent sessions and captures their recent output.
+// *tmux.Tmux satisfies it.
+type PaneSource interface {
+	ListSessions() ([]string, error)
+	CapturePaneLines(session string, lines int) ([]string, error)
+}
+
+// Transition records an agent status change observed by a Sampler.
+type Transition struct {
+	AgentID string
+	Session string
+	From    AgentStatus // Empty if the agent was not tracked before
+	To      AgentStatus
+	Message string // Pane line or reason that explains the new status
+	At      time.Time
+}
+
+// DefaultSampleLines is how many trailing pane lines are inspected per sample.
+const DefaultSampleLines = 10
+
+// Sampler feeds a StatusTracker from pane output.
+//
+// The tracker treats every UpdateFromOutput as activity, so the sampler only
+// reports output when it changed since the last sample. An agent whose pane
+// stays the same for the idle timeout is marked idle by CheckIdle.
+type Sampler struct {
+	tracker *StatusTracker
+	source  PaneSource
+	lines   int
+
+	// agentFor maps a session name to an agent ID; false skips the session.
+	agentFor func(session string) (string, bool)
+
+	lastOutput map[string]string // agentID -> last output fed to the tracker
+	sessions   map[string]string // agentID -> session name
+}
+
+// NewSampler creates a sampler that reads panes from source.
+// agentFor maps session names to agent IDs and filters out unrelated sessions.
+func NewSampler(tracker *StatusTracker, source PaneSource, agentFor func(session string) (string, bool)) *Sampler {
+	s := &Sampler{
+		tracker:    tracker,
+		source:     source,
+		lines:      DefaultSampleLines,
+		agentFor:   agentFor,
+		lastOutput: make(map[string]string),
+		sessions:   make(map[string]string),
+	}
+	// Agents restored from history keep their activity time until output changes.
+	for agentID, activity := range tracker.Snapshot() {
+		s.lastOutput[agentID] = activity.LastOutput
+	}
+	return s
+}
+
+// SetLines sets how many trailing pane lines are inspected per sample.
+func (s *Sampler) SetLines(lines int) {
+	if lines > 0 {
+		s.lines = lines
+	}
+}
+
+// Sample captures every agent pane once, updates the tracker, and returns the
+// status transitions it observed. Agents whose sessions are gone are removed
+// from the tracker and reported as offline.
+func (s *Sampler) Sample() ([]Transition, error) {
+	sessions, err := s.source.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var transitions []Transition
+	seen := make(map[string]bool)
+
+	for _, sess := range sessions {
+		agentID, ok := s.agentFor(sess)
+		if !ok {
+			continue
+		}
+		seen[agentID] = true
+		s.sessions[agentID] = sess
+
+		lines, err := s.source.CapturePaneLines(sess, s.lines)
+		if err != nil {
+			continue // Session may have just exited; next sample will tell
+		}
+		output := paneOutput(lines)
+
+		prev, _, tracked := s.tracker.GetStatus(agentID)
+		if tracked && output == s.lastOutput[agentID] {
+			continue // No new output: not activity
+		}
+		s.lastOutput[agentID] = output
+
+		report := s.tracker.UpdateFromOutput(agentID, output)
+		current, _, _ := s.tracker.GetStatus(agentID)
+		if !tracked {
+			prev = ""
+		}
+		if current != prev {
+			transitions = append(transitions, Transition{
+				AgentID: agentID,
+				Session: sess,
+				From:    prev,
+				To:      current,
+				Message: matchedLine(lines, report.Status, s.tracker.Detector()),
+				At:      report.Timestamp,
+			})
+		}
+	}
+
+	// Agents whose sessions disappeared
+	for _, agentID := range s.tracker.ListAgents() {
+		if seen[agentID] {
+			continue
+		}
+		prev, _, _ := s.tracker.GetStatus(agentID)
+		s.tracker.RemoveAgent(agentID)
+		delete(s.lastOutput, agentID)
+		transitions = append(transitions, Transition{
+			AgentID: agentID,
+			Session: s.sessions[agentID],
+			From:    prev,
+			To:      StatusOffline,
+			Message: "session not running",
+			At:      time.Now(),
+		})
+		delete(s.sessions, agentID)
+	}
+
+	// Agents whose output hasn't changed for the idle timeout
+	for _, agentID := range s.tracker.CheckIdle() {
+		activity, _ := s.tracker.GetActivity(agentID)
+		from := StatusAvailable
+		if n := len(activity.StatusHistory); n > 1 {
+			from = activity.StatusHistory[n-2].Status
+		}
+		transitions = append(transitions, Transition{
+			AgentID: agentID,
+			Session: s.sessions[agentID],
+			From:    from,
+			To:      StatusIdle,
+			Message: "no output for " + s.tracker.GetIdleConfig().Timeout.String(),
+			At:      time.Now(),
+		})
+	}
+
+	return transitions, nil
+}
+
+// paneOutput joins pane lines, dropping the blank tail tmux pads panes with.
+func paneOutput(lines []string) string {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	return strings.Join(lines[:end], "\n")
+}
+
+// matchedLine returns the last line that detects as status, or the last
+// non-blank line if none does.
+func matchedLine(lines []string, status AgentStatus, detector *Detector) string {
+	last := ""
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if last == "" {
+			last = line
+		}
+		if got, ok := detector.Registry().DetectStatus(line); ok && got == status {
+			return line
+		}
+	}
+	return last
+}
@@ -0,0 +1,132 @@
+package monitoring
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakePanes is a PaneSource backed by a map of session -> pane lines.
+type fakePanes map[string][]string
+
+func (f fakePanes) ListSessions() ([]string, error) {
+	sessions := make([]string, 0, len(f))
+	for s := range f {
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+func (f fakePanes) CapturePaneLines(session string, _ int) ([]string, error) {
+	return f[session], nil
+}
+
+// agentByPrefix tracks sessions named "gt-<agent>" as <agent>.
+func agentByPrefix(session string) (string, bool) {
+	if !strings.HasPrefix(session, "gt-") {
+		return "", false
+	}
+	return strings.TrimPrefix(session, "gt-"), true
+}
+
+func TestSampler_Transitions(t *testing.T) {
+	panes := fakePanes{
+		"gt-nux":   {"Reading main.go", ""},
+		"personal": {"BLOCKED: not ours"},
+	}
+	tracker := NewStatusTracker()
+	s := NewSampler(tracker, panes, agentByPrefix)
+
+	got, err := s.Sample()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].AgentID != "nux" || got[0].From != "" || got[0].To != StatusWorking {
+		t.Fatalf("first sample = %+v, want nux → working", got)
+	}
+	if tracker.AgentCount() != 1 {
+		t.Errorf("tracked %d agents, want only gt- sessions", tracker.AgentCount())
+	}
+
+	// Unchanged output is not a transition
+	if got, _ := s.Sample(); len(got) != 0 {
+		t.Errorf("unchanged pane produced %+v", got)
+	}
+
+	panes["gt-nux"] = []string{"Reading main.go", "BLOCKED: waiting on gt-abc to merge", ""}
+	got, _ = s.Sample()
+	if len(got) != 1 || got[0].From != StatusWorking || got[0].To != StatusBlocked {
+		t.Fatalf("sample = %+v, want working → blocked", got)
+	}
+	if got[0].Message != "BLOCKED: waiting on gt-abc to merge" || got[0].Session != "gt-nux" {
+		t.Errorf("transition = %+v", got[0])
+	}
+
+	delete(panes, "gt-nux")
+	got, _ = s.Sample()
+	if len(got) != 1 || got[0].To != StatusOffline || tracker.AgentCount() != 0 {
+		t.Errorf("sample = %+v, want nux → offline and untracked", got)
+	}
+}
+
+func TestSampler_IdleWhenOutputStops(t *testing.T) {
+	panes := fakePanes{"gt-nux": {"Thinking..."}}
+	tracker := NewStatusTrackerWithConfig(IdleConfig{Timeout: 20 * time.Millisecond, Enabled: true})
+	s := NewSampler(tracker, panes, agentByPrefix)
+
+	if _, err := s.Sample(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	got, _ := s.Sample()
+	if len(got) != 1 || got[0].From != StatusThinking || got[0].To != StatusIdle {
+		t.Fatalf("sample = %+v, want thinking → idle", got)
+	}
+	if got, _ := s.Sample(); len(got) != 0 {
+		t.Errorf("idle agent re-announced: %+v", got)
+	}
+
+	history := tracker.GetStatusHistory("nux", 0)
+	if len(history) != 2 || history[1].Status != StatusIdle {
+		t.Errorf("history = %+v, want thinking then idle", history)
+	}
+}
+
+func TestStatusFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon", "agent-status.json")
+
+	empty, err := LoadStatusFile(path)
+	if err != nil || len(empty.Agents) != 0 || empty.Fresh(time.Minute) {
+		t.Fatalf("LoadStatusFile(missing) = %+v, %v", empty, err)
+	}
+
+	tracker := NewStatusTracker()
+	tracker.UpdateFromOutput("gastown/polecats/nux", "ERROR: build failed")
+	if err := SaveStatusFile(path, tracker); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := LoadStatusFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !file.Fresh(time.Minute) {
+		t.Error("just-written file is not fresh")
+	}
+	if status, ok := file.Status("gastown/polecats/nux", time.Hour); !ok || status != StatusError {
+		t.Errorf("Status = %s, %v; want error", status, ok)
+	}
+	if status, _ := file.Status("gastown/polecats/nux", 0); status != StatusIdle {
+		t.Errorf("Status with zero idle timeout = %s, want idle", status)
+	}
+
+	// A restored tracker resumes without treating old output as new activity
+	restored := NewStatusTracker()
+	restored.Restore(file.Agents)
+	s := NewSampler(restored, fakePanes{"gt-gastown/polecats/nux": {"ERROR: build failed"}}, agentByPrefix)
+	if got, _ := s.Sample(); len(got) != 0 {
+		t.Errorf("restored agent re-announced: %+v", got)
+	}
+}
@@ -231,6 +231,34 @@ func (t *StatusTracker) Detector() *Detector {
 	return t.detector
 }
 
+// Snapshot returns a copy of every tracked agent's activity, for persistence.
+func (t *StatusTracker) Snapshot() map[string]*AgentActivity {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snapshot := make(map[string]*AgentActivity, len(t.agents))
+	for agentID, activity := range t.agents {
+		activityCopy := *activity
+		activityCopy.StatusHistory = make([]StatusReport, len(activity.StatusHistory))
+		copy(activityCopy.StatusHistory, activity.StatusHistory)
+		snapshot[agentID] = &activityCopy
+	}
+	return snapshot
+}
+
+// Restore replaces tracked agents with a previously saved snapshot.
+func (t *StatusTracker) Restore(snapshot map[string]*AgentActivity) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.agents = make(map[string]*AgentActivity, len(snapshot))
+	for agentID, activity := range snapshot {
+		activityCopy := *activity
+		activityCopy.AgentID = agentID
+		t.agents[agentID] = &activityCopy
+	}
+}
+
 // Clear removes all tracked agents.
 func (t *StatusTracker) Clear() {
 	t.mu.Lock()
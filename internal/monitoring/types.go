@@ -130,25 +130,25 @@ func DefaultIdleConfig() IdleConfig {
 // AgentActivity tracks activity information for an agent.
 type AgentActivity struct {
 	// AgentID is the agent identifier
-	AgentID string
+	AgentID string `json:"agent_id"`
 
 	// LastActivity is when the agent last had activity
-	LastActivity time.Time
+	LastActivity time.Time `json:"last_activity"`
 
 	// LastOutput is the most recent output line
-	LastOutput string
+	LastOutput string `json:"last_output,omitempty"`
 
 	// CurrentStatus is the current detected status
-	CurrentStatus AgentStatus
+	CurrentStatus AgentStatus `json:"current_status"`
 
 	// CurrentSource is how the current status was determined
-	CurrentSource StatusSource
+	CurrentSource StatusSource `json:"current_source"`
 
 	// StatusHistory maintains recent status changes
-	StatusHistory []StatusReport
+	StatusHistory []StatusReport `json:"status_history,omitempty"`
 
 	// IdleSince indicates when the agent became idle (nil if not idle)
-	IdleSince *time.Time
+	IdleSince *time.Time `json:"idle_since,omitempty"`
 }
 
 // IsIdle returns true if the agent is currently considered idle.
@@ -195,5 +195,15 @@ func (a *AgentActivity) MarkIdle() {
 		a.IdleSince = &now
 		a.CurrentStatus = StatusIdle
 		a.CurrentSource = SourceInferred
+
+		a.StatusHistory = append(a.StatusHistory, StatusReport{
+			AgentID:   a.AgentID,
+			Status:    StatusIdle,
+			Source:    SourceInferred,
+			Timestamp: now,
+		})
+		if len(a.StatusHistory) > 50 {
+			a.StatusHistory = a.StatusHistory[len(a.StatusHistory)-50:]
+		}
 	}
 }
@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// DefaultSMTPPort is the submission port used when none is configured.
+const DefaultSMTPPort = 587
+
+// smtpTimeout bounds a single SMTP conversation.
+const smtpTimeout = 30 * time.Second
+
+// EmailNotifier sends plain-text email over SMTP.
+type EmailNotifier struct {
+	cfg config.SMTPTransport
+}
+
+// NewEmailNotifier creates an email notifier for the given SMTP transport.
+func NewEmailNotifier(cfg *config.SMTPTransport) *EmailNotifier {
+	return &EmailNotifier{cfg: *cfg}
+}
+
+// Channel implements Notifier.
+func (e *EmailNotifier) Channel() string {
+	return "email"
+}
+
+// Send implements Notifier.
+func (e *EmailNotifier) Send(ctx context.Context, to string, msg Message) error {
+	port := e.cfg.Port
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(port))
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return errors.Transient("notify.email", err).WithContext("server", addr)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// Port 465 is SMTPS: TLS from the first byte
+	if port == 465 {
+		conn = tls.Client(conn, &tls.Config{ServerName: e.cfg.Host})
+	}
+
+	c, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return smtpError(err, addr)
+	}
+	defer c.Close()
+
+	if port != 465 {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+				return smtpError(err, addr)
+			}
+		}
+	}
+
+	if e.cfg.Username != "" {
+		password := ""
+		if e.cfg.PasswordEnv != "" {
+			password = os.Getenv(e.cfg.PasswordEnv)
+		}
+		auth := smtp.PlainAuth("", e.cfg.Username, password, e.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return smtpError(err, addr)
+		}
+	}
+
+	if err := c.Mail(e.cfg.From); err != nil {
+		return smtpError(err, addr)
+	}
+	if err := c.Rcpt(to); err != nil {
+		return smtpError(err, addr)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(err, addr)
+	}
+	if _, err := w.Write(e.format(to, msg)); err != nil {
+		return smtpError(err, addr)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError(err, addr)
+	}
+
+	// The server accepted the message; a failed QUIT doesn't undo delivery,
+	// and reporting it would make the caller resend.
+	if err := c.Quit(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: email to %s delivered, but QUIT to %s failed: %v\n", to, addr, err)
+	}
+	return nil
+}
+
+// format renders msg as an RFC 5322 message.
+func (e *EmailNotifier) format(to string, msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", oneLine(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// smtpError classifies an SMTP failure. 4xx replies and connection errors
+// are transient; 5xx replies (bad recipient, auth rejected) are permanent.
+func smtpError(err error, addr string) error {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return errors.Permanent("notify.email", err).
+			WithContext("server", addr).
+			WithHint("The SMTP server rejected the message. Check transports.smtp and contacts.human_email in settings/escalation.json.")
+	}
+	return errors.Transient("notify.email", err).WithContext("server", addr)
+}
@@ -0,0 +1,190 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// smtpSink is a minimal SMTP server that records delivered messages.
+// rcptReplies, if set, supplies the RCPT reply for successive connections.
+// dropQuit hangs up on QUIT without replying.
+type smtpSink struct {
+	ln          net.Listener
+	mu          sync.Mutex
+	messages    []string
+	rcptReplies []string
+	dropQuit    bool
+}
+
+func newSMTPSink(t *testing.T, rcptReplies ...string) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpSink{ln: ln, rcptReplies: rcptReplies}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpSink) transport() *config.SMTPTransport {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return &config.SMTPTransport{Host: host, Port: p, From: "gastown@example.com"}
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	rcptReply := "250 OK"
+	s.mu.Lock()
+	if len(s.rcptReplies) > 0 {
+		rcptReply = s.rcptReplies[0]
+		s.rcptReplies = s.rcptReplies[1:]
+	}
+	dropQuit := s.dropQuit
+	s.mu.Unlock()
+
+	reply("220 sink ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(cmd, "MAIL"):
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT"):
+			reply(rcptReply)
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			if dropQuit {
+				return
+			}
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpSink) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func fastRetry() errors.RetryConfig {
+	return errors.RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+}
+
+func TestEmailNotifier_Send(t *testing.T) {
+	sink := newSMTPSink(t)
+	n := NewEmailNotifier(sink.transport())
+
+	res := Send(context.Background(), n, "oncall@example.com", Message{
+		Subject: "[CRITICAL] Refinery\nstuck",
+		Body:    "Escalation ID: hq-esc1\nSeverity: critical",
+	}, fastRetry())
+	if !res.OK() || res.Attempts != 1 || res.Channel != "email" {
+		t.Fatalf("Send = %+v", res)
+	}
+
+	msgs := sink.received()
+	if len(msgs) != 1 {
+		t.Fatalf("sink got %d messages, want 1", len(msgs))
+	}
+	for _, want := range []string{
+		"From: gastown@example.com\r\n",
+		"To: oncall@example.com\r\n",
+		"Subject: [CRITICAL] Refinery stuck\r\n",
+		"Escalation ID: hq-esc1\r\nSeverity: critical",
+	} {
+		if !strings.Contains(msgs[0], want) {
+			t.Errorf("message missing %q:\n%s", want, msgs[0])
+		}
+	}
+}
+
+func TestEmailNotifier_RetriesTransientReply(t *testing.T) {
+	sink := newSMTPSink(t, "451 try again later", "250 OK")
+	n := NewEmailNotifier(sink.transport())
+
+	res := Send(context.Background(), n, "oncall@example.com", Message{Subject: "s", Body: "b"}, fastRetry())
+	if !res.OK() || res.Attempts != 2 {
+		t.Fatalf("Send = %+v, want success on attempt 2", res)
+	}
+	if len(sink.received()) != 1 {
+		t.Errorf("sink got %d messages, want 1", len(sink.received()))
+	}
+}
+
+func TestEmailNotifier_QuitFailureAfterDataIsDelivered(t *testing.T) {
+	sink := newSMTPSink(t)
+	sink.mu.Lock()
+	sink.dropQuit = true
+	sink.mu.Unlock()
+	n := NewEmailNotifier(sink.transport())
+
+	res := Send(context.Background(), n, "oncall@example.com", Message{Subject: "s", Body: "b"}, fastRetry())
+	if !res.OK() || res.Attempts != 1 {
+		t.Fatalf("Send = %+v, want success without resend", res)
+	}
+	if len(sink.received()) != 1 {
+		t.Errorf("sink got %d messages, want 1", len(sink.received()))
+	}
+}
+
+func TestEmailNotifier_PermanentReplyFailsFast(t *testing.T) {
+	sink := newSMTPSink(t, "550 no such user", "250 OK")
+	n := NewEmailNotifier(sink.transport())
+
+	res := Send(context.Background(), n, "nobody@example.com", Message{Subject: "s", Body: "b"}, fastRetry())
+	if res.OK() || res.Attempts != 1 {
+		t.Fatalf("Send = %+v, want permanent failure after 1 attempt", res)
+	}
+	if !strings.Contains(res.Err.Error(), "550") {
+		t.Errorf("error = %v, want SMTP 550", res.Err)
+	}
+}
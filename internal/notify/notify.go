@@ -0,0 +1,73 @@
+// Package notify delivers escalation notifications to humans outside Gas Town.
+//
+// Each Notifier sends a Message over one channel (SMTP email, an HTTP SMS
+// gateway, a named webhook). Send wraps a notifier with retry so transient failures (timeouts,
+// 4xx SMTP replies, 5xx/429 gateway responses) are retried with backoff and
+// permanent ones fail fast.
+package notify
+
+import (
+	"context"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// Message is a notification to deliver. Email and SMS use only the text
+// fields; webhooks expose every field to their payload template.
+type Message struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+
+	Kind     string                 `json:"kind"`               // "escalation" or "event"
+	Type     string                 `json:"type"`               // Event type, or "escalation"
+	ID       string                 `json:"id,omitempty"`       // Escalation bead ID
+	Severity string                 `json:"severity,omitempty"` // Escalation severity
+	Actor    string                 `json:"actor,omitempty"`    // Who escalated or emitted the event
+	Time     string                 `json:"time"`               // RFC 3339 timestamp
+	Payload  map[string]interface{} `json:"payload,omitempty"`  // Event payload
+}
+
+// Notifier delivers messages over one channel.
+type Notifier interface {
+	// Channel names the channel for delivery records (e.g., "email", "sms", "webhook:pagerduty").
+	Channel() string
+
+	// Send delivers msg to the recipient in one attempt.
+	// Errors that may succeed on retry are marked transient.
+	Send(ctx context.Context, to string, msg Message) error
+}
+
+// Result records the outcome of delivering one message.
+type Result struct {
+	Channel  string
+	To       string
+	Attempts int
+	Err      error
+}
+
+// OK reports whether the message was delivered.
+func (r Result) OK() bool {
+	return r.Err == nil
+}
+
+// DefaultRetry is the retry policy for escalation deliveries: network-style
+// backoff over roughly ten seconds before giving up.
+func DefaultRetry() errors.RetryConfig {
+	return errors.NetworkRetryConfig()
+}
+
+// Send delivers msg through n, retrying transient failures per retry.
+func Send(ctx context.Context, n Notifier, to string, msg Message, retry errors.RetryConfig) Result {
+	result := Result{Channel: n.Channel(), To: to}
+	result.Err = errors.RetryWithContext(ctx, func() error {
+		result.Attempts++
+		return n.Send(ctx, to, msg)
+	}, retry)
+	return result
+}
+
+// oneLine collapses s to a single line for use in headers and SMS text.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// sinkQueueSize bounds events waiting for delivery. Events beyond it are
+// dropped rather than stalling the caller (the feed curator).
+const sinkQueueSize = 100
+
+// EventSink forwards activity events to the webhooks subscribed to them.
+// Deliveries run on a single background worker with the usual retry policy.
+type EventSink struct {
+	hooks []*Webhook
+	retry errors.RetryConfig
+	logf  func(format string, args ...interface{})
+
+	queue  chan Message
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEventSink builds a sink from the webhooks in cfg that list Events.
+// Returns nil if no endpoint subscribes to any event.
+func NewEventSink(cfg *config.EscalationConfig, logf func(format string, args ...interface{})) (*EventSink, error) {
+	names := make([]string, 0, len(cfg.Webhooks))
+	for name, wh := range cfg.Webhooks {
+		if len(wh.Events) > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	s := &EventSink{
+		retry: DefaultRetry(),
+		logf:  logf,
+		queue: make(chan Message, sinkQueueSize),
+	}
+	for _, name := range names {
+		wh, err := NewWebhook(name, cfg.Webhooks[name])
+		if err != nil {
+			return nil, err
+		}
+		s.hooks = append(s.hooks, wh)
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s, nil
+}
+
+// Start begins the delivery worker.
+func (s *EventSink) Start() {
+	s.wg.Add(1)
+	go s.run()
+}
+
+// Stop stops the worker. Queued events that haven't been sent are dropped.
+func (s *EventSink) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Handle queues an event for every webhook subscribed to its type.
+// It never blocks.
+func (s *EventSink) Handle(e *events.Event) {
+	if !s.wanted(e.Type) {
+		return
+	}
+	select {
+	case s.queue <- EventMessage(e):
+	default:
+		s.logf("Warning: webhook queue full, dropping %s event from %s", e.Type, e.Actor)
+	}
+}
+
+func (s *EventSink) wanted(eventType string) bool {
+	for _, wh := range s.hooks {
+		if wh.Wants(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *EventSink) run() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case msg := <-s.queue:
+			for _, wh := range s.hooks {
+				if !wh.Wants(msg.Type) {
+					continue
+				}
+				if res := Send(s.ctx, wh, "", msg, s.retry); !res.OK() {
+					s.logf("Warning: %s delivery of %s event failed after %d attempts: %v",
+						res.Channel, msg.Type, res.Attempts, res.Err)
+				}
+			}
+		}
+	}
+}
+
+// EventMessage converts an activity event to a webhook message.
+func EventMessage(e *events.Event) Message {
+	subject := fmt.Sprintf("Gas Town: %s", e.Type)
+	if e.Actor != "" {
+		subject += " from " + e.Actor
+	}
+	body := ""
+	if len(e.Payload) > 0 {
+		if data, err := json.MarshalIndent(e.Payload, "", "  "); err == nil {
+			body = string(data)
+		}
+	}
+	return Message{
+		Subject: subject,
+		Body:    body,
+		Kind:    "event",
+		Type:    e.Type,
+		Actor:   e.Actor,
+		Time:    e.Timestamp,
+		Payload: e.Payload,
+	}
+}
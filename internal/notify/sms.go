@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// smsMaxLen caps SMS text at two concatenated segments, in characters.
+const smsMaxLen = 320
+
+// SMSNotifier sends SMS through an HTTP gateway.
+type SMSNotifier struct {
+	cfg    config.SMSGatewayTransport
+	client *http.Client
+}
+
+// NewSMSNotifier creates an SMS notifier for the given gateway.
+func NewSMSNotifier(cfg *config.SMSGatewayTransport) *SMSNotifier {
+	return &SMSNotifier{
+		cfg:    *cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Channel implements Notifier.
+func (s *SMSNotifier) Channel() string {
+	return "sms"
+}
+
+// Send implements Notifier. Only the subject is sent; SMS is a page, not a report.
+func (s *SMSNotifier) Send(ctx context.Context, to string, msg Message) error {
+	text := oneLine(msg.Subject)
+	if runes := []rune(text); len(runes) > smsMaxLen {
+		text = string(runes[:smsMaxLen-3]) + "..."
+	}
+
+	var body []byte
+	var contentType string
+	if s.cfg.Format == "form" {
+		form := url.Values{"To": {to}, "Body": {text}}
+		if s.cfg.From != "" {
+			form.Set("From", s.cfg.From)
+		}
+		body = []byte(form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	} else {
+		var err error
+		body, err = json.Marshal(map[string]string{
+			"to":      to,
+			"from":    s.cfg.From,
+			"message": text,
+		})
+		if err != nil {
+			return errors.Permanent("notify.sms", err)
+		}
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Permanent("notify.sms", err).
+			WithHint("Check transports.sms.url in settings/escalation.json.")
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	if s.cfg.Username != "" {
+		password := ""
+		if s.cfg.PasswordEnv != "" {
+			password = os.Getenv(s.cfg.PasswordEnv)
+		}
+		req.SetBasicAuth(s.cfg.Username, password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Transient("notify.sms", err).WithContext("gateway", s.cfg.URL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	statusErr := fmt.Errorf("gateway returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return errors.Transient("notify.sms", statusErr).WithContext("gateway", s.cfg.URL)
+	}
+	return errors.Permanent("notify.sms", statusErr).
+		WithContext("gateway", s.cfg.URL).
+		WithHint("The SMS gateway rejected the request. Check transports.sms and contacts.human_sms in settings/escalation.json.")
+}
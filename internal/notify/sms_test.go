@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestSMSNotifier_JSONGateway(t *testing.T) {
+	t.Setenv("GT_TEST_SMS_TOKEN", "s3cret")
+
+	var got map[string]string
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	n := NewSMSNotifier(&config.SMSGatewayTransport{
+		URL:     srv.URL,
+		From:    "+15550000000",
+		Headers: map[string]string{"Authorization": "Bearer ${GT_TEST_SMS_TOKEN}"},
+	})
+	res := Send(context.Background(), n, "+15551234567", Message{
+		Subject: "[CRITICAL] Deacon down",
+		Body:    "long details that should not be texted",
+	}, fastRetry())
+	if !res.OK() || res.Attempts != 1 || res.Channel != "sms" {
+		t.Fatalf("Send = %+v", res)
+	}
+
+	if got["to"] != "+15551234567" || got["from"] != "+15550000000" || got["message"] != "[CRITICAL] Deacon down" {
+		t.Errorf("gateway body = %v", got)
+	}
+	if auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q, want expanded env token", auth)
+	}
+}
+
+func TestSMSNotifier_FormGatewayWithBasicAuth(t *testing.T) {
+	t.Setenv("GT_TEST_SMS_PASSWORD", "tok")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "AC123" || pass != "tok" {
+			t.Errorf("basic auth = %q/%q/%v", user, pass, ok)
+		}
+		if r.FormValue("To") != "+15551234567" || r.FormValue("Body") != "page" {
+			t.Errorf("form = %v", r.Form)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	n := NewSMSNotifier(&config.SMSGatewayTransport{
+		URL:         srv.URL,
+		Format:      "form",
+		Username:    "AC123",
+		PasswordEnv: "GT_TEST_SMS_PASSWORD",
+	})
+	if res := Send(context.Background(), n, "+15551234567", Message{Subject: "page"}, fastRetry()); !res.OK() {
+		t.Fatalf("Send = %+v", res)
+	}
+}
+
+func TestSMSNotifier_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := NewSMSNotifier(&config.SMSGatewayTransport{URL: srv.URL})
+	res := Send(context.Background(), n, "+15551234567", Message{Subject: "page"}, fastRetry())
+	if !res.OK() || res.Attempts != 3 {
+		t.Fatalf("Send = %+v, want success on attempt 3", res)
+	}
+}
+
+func TestSMSNotifier_ClientErrorIsPermanent(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "invalid number", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n := NewSMSNotifier(&config.SMSGatewayTransport{URL: srv.URL})
+	res := Send(context.Background(), n, "bogus", Message{Subject: "page"}, fastRetry())
+	if res.OK() || calls.Load() != 1 {
+		t.Fatalf("Send = %+v after %d calls, want one permanent failure", res, calls.Load())
+	}
+	if !strings.Contains(res.Err.Error(), "invalid number") {
+		t.Errorf("error = %v, want gateway detail", res.Err)
+	}
+}
+
+func TestSMSNotifier_TruncatesOnRuneBoundary(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	n := NewSMSNotifier(&config.SMSGatewayTransport{URL: srv.URL})
+	res := Send(context.Background(), n, "+15551234567", Message{Subject: strings.Repeat("é", smsMaxLen+10)}, fastRetry())
+	if !res.OK() {
+		t.Fatalf("Send = %+v", res)
+	}
+
+	want := strings.Repeat("é", smsMaxLen-3) + "..."
+	if got["message"] != want {
+		t.Errorf("message = %q (%d bytes), want %d runes ending in ...", got["message"], len(got["message"]), smsMaxLen)
+	}
+}
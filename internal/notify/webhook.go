@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body when the
+// endpoint has a secret: "sha256=<hex>".
+const SignatureHeader = "X-Gastown-Signature"
+
+// EventHeader carries Message.Type on every webhook request.
+const EventHeader = "X-Gastown-Event"
+
+// SlackPayload is the payload template used for the "slack" action.
+const SlackPayload = `{"text": {{json (printf "%s\n%s" .Subject .Body)}}}`
+
+// templateFuncs are available in webhook payload templates.
+var templateFuncs = template.FuncMap{
+	// json renders a value as a JSON literal, so strings are safely quoted
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Webhook posts messages to a named HTTP endpoint.
+type Webhook struct {
+	name    string
+	cfg     config.WebhookEndpoint
+	payload *template.Template // nil sends the Message as JSON
+	client  *http.Client
+}
+
+// NewWebhook creates a notifier for the named endpoint.
+// Returns an error if the payload template doesn't parse.
+func NewWebhook(name string, cfg *config.WebhookEndpoint) (*Webhook, error) {
+	w := &Webhook{
+		name:   name,
+		cfg:    *cfg,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+	if cfg.Payload != "" {
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(cfg.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("webhook %s: parsing payload template: %w", name, err)
+		}
+		w.payload = tmpl
+	}
+	return w, nil
+}
+
+// Channel implements Notifier.
+func (w *Webhook) Channel() string {
+	return "webhook:" + w.name
+}
+
+// Wants reports whether the endpoint subscribes to the given event type.
+func (w *Webhook) Wants(eventType string) bool {
+	for _, t := range w.cfg.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Send implements Notifier. The recipient is ignored; the endpoint URL is fixed.
+func (w *Webhook) Send(ctx context.Context, _ string, msg Message) error {
+	body, err := w.render(msg)
+	if err != nil {
+		return errors.Permanent("notify.webhook", err).
+			WithContext("webhook", w.name).
+			WithHint("Fix webhooks." + w.name + ".payload in settings/escalation.json; it must render valid JSON.")
+	}
+
+	method := w.cfg.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Permanent("notify.webhook", err).WithContext("webhook", w.name)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, msg.Type)
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	if secret := os.ExpandEnv(w.cfg.Secret); secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return errors.Transient("notify.webhook", err).WithContext("webhook", w.name)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	statusErr := fmt.Errorf("endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return errors.Transient("notify.webhook", statusErr).WithContext("webhook", w.name)
+	}
+	return errors.Permanent("notify.webhook", statusErr).
+		WithContext("webhook", w.name).
+		WithHint("The endpoint rejected the request. Check webhooks." + w.name + " in settings/escalation.json.")
+}
+
+// render produces the request body for msg.
+func (w *Webhook) render(msg Message) ([]byte, error) {
+	if w.payload == nil {
+		return json.Marshal(msg)
+	}
+	var buf bytes.Buffer
+	if err := w.payload.Execute(&buf, msg); err != nil {
+		return nil, fmt.Errorf("rendering payload: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("payload template produced invalid JSON: %s", buf.String())
+	}
+	return buf.Bytes(), nil
+}
+
+// Sign returns the SignatureHeader value for body under secret.
+// Receivers should recompute it and compare with hmac.Equal.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,188 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestWebhook_DefaultPayloadIsSignedMessage(t *testing.T) {
+	t.Setenv("GT_TEST_WEBHOOK_SECRET", "hush")
+
+	var body []byte
+	var signature, event, method string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		event = r.Header.Get(EventHeader)
+		method = r.Method
+	}))
+	defer srv.Close()
+
+	wh, err := NewWebhook("ops", &config.WebhookEndpoint{URL: srv.URL, Secret: "$GT_TEST_WEBHOOK_SECRET"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := Send(context.Background(), wh, "", Message{
+		Subject:  "[HIGH] Deacon down",
+		Kind:     "escalation",
+		Type:     "escalation",
+		ID:       "hq-esc1",
+		Severity: "high",
+	}, fastRetry())
+	if !res.OK() || res.Channel != "webhook:ops" {
+		t.Fatalf("Send = %+v", res)
+	}
+
+	if method != http.MethodPost || event != "escalation" {
+		t.Errorf("method = %q, event = %q", method, event)
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign("hush", body))) {
+		t.Errorf("signature %q does not match body", signature)
+	}
+	var got Message
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("body is not a Message: %v", err)
+	}
+	if got.ID != "hq-esc1" || got.Severity != "high" || got.Subject != "[HIGH] Deacon down" {
+		t.Errorf("body = %+v", got)
+	}
+}
+
+func TestWebhook_PayloadTemplate(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	wh, err := NewWebhook("pagerduty", &config.WebhookEndpoint{
+		URL:     srv.URL,
+		Payload: `{"summary": {{json .Subject}}, "severity": {{json (upper .Severity)}}, "bead": {{json .Payload.bead}}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := Send(context.Background(), wh, "", Message{
+		Subject:  `Refinery "stuck"`,
+		Severity: "critical",
+		Payload:  map[string]interface{}{"bead": "gt-abc"},
+	}, fastRetry())
+	if !res.OK() {
+		t.Fatalf("Send = %+v", res)
+	}
+	if got["summary"] != `Refinery "stuck"` || got["severity"] != "CRITICAL" || got["bead"] != "gt-abc" {
+		t.Errorf("payload = %v", got)
+	}
+}
+
+func TestWebhook_InvalidPayloadIsPermanent(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer srv.Close()
+
+	wh, err := NewWebhook("broken", &config.WebhookEndpoint{URL: srv.URL, Payload: `{"text": {{.Subject}}}`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := Send(context.Background(), wh, "", Message{Subject: "not quoted"}, fastRetry())
+	if res.OK() || res.Attempts != 1 || errors.IsTransient(res.Err) {
+		t.Fatalf("Send = %+v, want one permanent failure", res)
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Errorf("endpoint called %d times with invalid payload", calls)
+	}
+
+	if _, err := NewWebhook("bad", &config.WebhookEndpoint{URL: srv.URL, Payload: `{{.Subject`}); err == nil {
+		t.Error("NewWebhook accepted an unparseable template")
+	}
+}
+
+func TestWebhook_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	wh, _ := NewWebhook("flaky", &config.WebhookEndpoint{URL: srv.URL})
+	res := Send(context.Background(), wh, "", Message{Subject: "x"}, fastRetry())
+	if !res.OK() || res.Attempts != 3 {
+		t.Fatalf("Send = %+v, want success on third attempt", res)
+	}
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer rejecting.Close()
+
+	wh, _ = NewWebhook("rejecting", &config.WebhookEndpoint{URL: rejecting.URL})
+	res = Send(context.Background(), wh, "", Message{Subject: "x"}, fastRetry())
+	if res.OK() || res.Attempts != 1 || errors.IsTransient(res.Err) {
+		t.Fatalf("Send = %+v, want one permanent failure", res)
+	}
+}
+
+func TestEventSink_ForwardsSubscribedEvents(t *testing.T) {
+	received := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg Message
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		received <- msg.Type + " " + msg.Actor
+	}))
+	defer srv.Close()
+
+	cfg := &config.EscalationConfig{Webhooks: map[string]*config.WebhookEndpoint{
+		"merges":      {URL: srv.URL, Events: []string{events.TypeMerged}},
+		"escalations": {URL: srv.URL}, // action-only, no events
+	}}
+	sink, err := NewEventSink(cfg, t.Logf)
+	if err != nil || sink == nil {
+		t.Fatalf("NewEventSink = %v, %v", sink, err)
+	}
+	sink.Start()
+	defer sink.Stop()
+
+	sink.Handle(&events.Event{Type: events.TypeSling, Actor: "mayor"})
+	sink.Handle(&events.Event{Type: events.TypeMerged, Actor: "gastown/refinery"})
+
+	select {
+	case got := <-received:
+		if got != events.TypeMerged+" gastown/refinery" {
+			t.Errorf("received %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("merged event was not delivered")
+	}
+	select {
+	case got := <-received:
+		t.Errorf("unsubscribed event delivered: %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNewEventSink_NilWithoutSubscriptions(t *testing.T) {
+	cfg := &config.EscalationConfig{Webhooks: map[string]*config.WebhookEndpoint{
+		"ops": {URL: "https://example.com/hook"},
+	}}
+	sink, err := NewEventSink(cfg, t.Logf)
+	if err != nil || sink != nil {
+		t.Errorf("NewEventSink = %v, %v; want nil, nil", sink, err)
+	}
+}
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"x * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2026, 3, 18, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9 * * *", time.Date(2026, 3, 19, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 18, 9, 45, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2026, 3, 19, 9, 30, 0, 0, time.UTC)}, // Strictly after
+		{"0 9-17/4 * * *", time.Date(2026, 3, 18, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * mon-fri", time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * *", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 18, 10, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 22, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 1st, or a Friday)
+		{"0 0 1 * fri", time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		sched, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := sched.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+		if !tt.want.IsZero() && !sched.Matches(tt.want) {
+			t.Errorf("%q doesn't match its own next time %v", tt.expr, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// DefaultCooldown is the cooldown gate duration when none is set.
+const DefaultCooldown = "1h"
+
+// EventStartup is the event gate fired once when the scheduler starts.
+const EventStartup = "startup"
+
+// conditionTimeout bounds a condition gate's check command.
+const conditionTimeout = 30 * time.Second
+
+// GateInput is what a gate is evaluated against.
+type GateInput struct {
+	// Now is the evaluation time.
+	Now time.Time
+
+	// LastRun is when the plugin last ran (zero if never).
+	LastRun time.Time
+
+	// Since is when scheduling began. A cron plugin that never ran is only
+	// due for slots after Since, so new plugins don't fire immediately.
+	Since time.Time
+
+	// Events are the event types seen since the last evaluation.
+	Events map[string]bool
+
+	// RunCheck runs a condition gate's check command in dir. Returns nil
+	// if the command exits 0. Defaults to running it with sh -c.
+	RunCheck func(check, dir string) error
+}
+
+// GateStatus is the result of evaluating a gate.
+type GateStatus struct {
+	Open   bool
+	Reason string
+
+	// Next is when a cooldown or cron gate next opens (zero otherwise).
+	Next time.Time
+}
+
+// Validate checks that the gate's fields parse.
+func (g *Gate) Validate() error {
+	if g.Duration != "" {
+		if _, err := time.ParseDuration(g.Duration); err != nil {
+			return fmt.Errorf("%s gate: invalid duration %q", g.Type, g.Duration)
+		}
+	}
+
+	switch g.Type {
+	case GateCooldown, GateManual:
+	case GateCron:
+		if g.Schedule == "" {
+			return fmt.Errorf("cron gate: missing schedule")
+		}
+		if _, err := ParseCron(g.Schedule); err != nil {
+			return err
+		}
+	case GateCondition:
+		if g.Check == "" {
+			return fmt.Errorf("condition gate: missing check")
+		}
+	case GateEvent:
+		if g.On == "" {
+			return fmt.Errorf("event gate: missing on")
+		}
+	default:
+		return fmt.Errorf("unknown gate type %q", g.Type)
+	}
+	return nil
+}
+
+// Evaluate reports whether the plugin in dir should run now.
+//
+// Cooldown gates open once Duration (default 1h) has passed since the last
+// run. Cron gates open once a scheduled time has passed since the last run.
+// Condition gates open when Check exits 0, and event gates when an On event
+// was seen; both treat an optional Duration as a minimum interval between
+// runs. Manual gates never open.
+func (g *Gate) Evaluate(dir string, in GateInput) GateStatus {
+	if err := g.Validate(); err != nil {
+		return GateStatus{Reason: err.Error()}
+	}
+
+	switch g.Type {
+	case GateCooldown:
+		duration := g.Duration
+		if duration == "" {
+			duration = DefaultCooldown
+		}
+		d, _ := time.ParseDuration(duration)
+		if in.LastRun.IsZero() {
+			return GateStatus{Open: true, Reason: "never run"}
+		}
+		next := in.LastRun.Add(d)
+		if in.Now.Before(next) {
+			return GateStatus{Reason: fmt.Sprintf("within %s cooldown", duration), Next: next}
+		}
+		return GateStatus{Open: true, Reason: fmt.Sprintf("%s cooldown elapsed", duration)}
+
+	case GateCron:
+		sched, _ := ParseCron(g.Schedule)
+		base := in.LastRun
+		if base.IsZero() {
+			base = in.Since
+		}
+		next := sched.Next(base)
+		if next.IsZero() {
+			return GateStatus{Reason: fmt.Sprintf("schedule %q never fires", g.Schedule)}
+		}
+		if in.Now.Before(next) {
+			return GateStatus{Reason: "not scheduled yet", Next: next}
+		}
+		return GateStatus{Open: true, Reason: fmt.Sprintf("scheduled at %s", next.Format("2006-01-02 15:04"))}
+
+	case GateCondition:
+		if status, ok := g.minInterval(in); !ok {
+			return status
+		}
+		run := in.RunCheck
+		if run == nil {
+			run = runCheck
+		}
+		if err := run(g.Check, dir); err != nil {
+			return GateStatus{Reason: fmt.Sprintf("check failed: %v", err)}
+		}
+		return GateStatus{Open: true, Reason: "check passed"}
+
+	case GateEvent:
+		if !in.Events[g.On] {
+			return GateStatus{Reason: fmt.Sprintf("waiting for %s event", g.On)}
+		}
+		if status, ok := g.minInterval(in); !ok {
+			return status
+		}
+		return GateStatus{Open: true, Reason: fmt.Sprintf("%s event", g.On)}
+	}
+
+	return GateStatus{Reason: "manual gate"}
+}
+
+// minInterval applies a condition or event gate's optional Duration.
+func (g *Gate) minInterval(in GateInput) (GateStatus, bool) {
+	if g.Duration == "" || in.LastRun.IsZero() {
+		return GateStatus{}, true
+	}
+	d, _ := time.ParseDuration(g.Duration)
+	if next := in.LastRun.Add(d); in.Now.Before(next) {
+		return GateStatus{Reason: fmt.Sprintf("ran within %s", g.Duration), Next: next}, false
+	}
+	return GateStatus{}, true
+}
+
+// runCheck runs a condition gate's check command with sh -c.
+func runCheck(check, dir string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), conditionTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", check) //nolint:gosec // G204: check comes from the plugin definition
+	cmd.Dir = dir
+	return cmd.Run()
+}
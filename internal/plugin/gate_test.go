@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGate_Evaluate(t *testing.T) {
+	now := time.Date(2026, 3, 18, 9, 30, 0, 0, time.Local)
+	since := now.Add(-10 * time.Minute)
+	pass := func(string, string) error { return nil }
+	fail := func(string, string) error { return errors.New("exit status 1") }
+
+	tests := []struct {
+		name     string
+		gate     Gate
+		lastRun  time.Time
+		events   map[string]bool
+		runCheck func(string, string) error
+		wantOpen bool
+		wantNext time.Time
+	}{
+		{"cooldown never run", Gate{Type: GateCooldown, Duration: "2h"}, time.Time{}, nil, nil, true, time.Time{}},
+		{"cooldown elapsed", Gate{Type: GateCooldown, Duration: "2h"}, now.Add(-3 * time.Hour), nil, nil, true, time.Time{}},
+		{"cooldown active", Gate{Type: GateCooldown, Duration: "2h"}, now.Add(-time.Hour), nil, nil, false, now.Add(time.Hour)},
+		{"cooldown default 1h", Gate{Type: GateCooldown}, now.Add(-30 * time.Minute), nil, nil, false, now.Add(30 * time.Minute)},
+
+		{"cron slot passed", Gate{Type: GateCron, Schedule: "0 9 * * *"}, now.Add(-24 * time.Hour), nil, nil, true, time.Time{}},
+		{"cron already ran", Gate{Type: GateCron, Schedule: "0 9 * * *"}, now.Add(-20 * time.Minute), nil, nil, false,
+			time.Date(2026, 3, 19, 9, 0, 0, 0, time.Local)},
+		{"cron never run waits for slot after since", Gate{Type: GateCron, Schedule: "0 9 * * *"}, time.Time{}, nil, nil, false,
+			time.Date(2026, 3, 19, 9, 0, 0, 0, time.Local)},
+		{"cron never run fires after since", Gate{Type: GateCron, Schedule: "*/5 * * * *"}, time.Time{}, nil, nil, true, time.Time{}},
+
+		{"condition passes", Gate{Type: GateCondition, Check: "true"}, time.Time{}, nil, pass, true, time.Time{}},
+		{"condition fails", Gate{Type: GateCondition, Check: "false"}, time.Time{}, nil, fail, false, time.Time{}},
+		{"condition min interval", Gate{Type: GateCondition, Check: "true", Duration: "1h"}, now.Add(-time.Minute), nil, pass, false,
+			now.Add(59 * time.Minute)},
+
+		{"event seen", Gate{Type: GateEvent, On: "merged"}, time.Time{}, map[string]bool{"merged": true}, nil, true, time.Time{}},
+		{"event not seen", Gate{Type: GateEvent, On: "merged"}, time.Time{}, map[string]bool{"sling": true}, nil, false, time.Time{}},
+
+		{"manual", Gate{Type: GateManual}, time.Time{}, nil, nil, false, time.Time{}},
+		{"invalid cron", Gate{Type: GateCron, Schedule: "bogus"}, time.Time{}, nil, nil, false, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.gate.Evaluate(t.TempDir(), GateInput{
+				Now:      now,
+				LastRun:  tt.lastRun,
+				Since:    since,
+				Events:   tt.events,
+				RunCheck: tt.runCheck,
+			})
+			if got.Open != tt.wantOpen {
+				t.Errorf("Open = %v (%s), want %v", got.Open, got.Reason, tt.wantOpen)
+			}
+			if !got.Next.Equal(tt.wantNext) {
+				t.Errorf("Next = %v, want %v", got.Next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestGate_EvaluateRunsCheckInPluginDir(t *testing.T) {
+	dir := t.TempDir()
+	gate := Gate{Type: GateCondition, Check: "test -f marker"}
+
+	if got := gate.Evaluate(dir, GateInput{Now: time.Now()}); got.Open {
+		t.Fatal("gate open without marker file")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "marker"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := gate.Evaluate(dir, GateInput{Now: time.Now()}); !got.Open {
+		t.Fatalf("gate closed with marker file: %s", got.Reason)
+	}
+}
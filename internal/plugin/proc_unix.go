@@ -0,0 +1,32 @@
+//go:build unix
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group, so a timeout
+// can kill everything the plugin spawned.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the command's process group.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// withLimits wraps argv in a shell that sets the memory and CPU rlimits
+// before exec'ing it; rlimits are inherited by everything the plugin spawns.
+// A limit the shell can't set (a lower hard limit is already in place) is
+// left as is.
+func withLimits(argv []string, lim limits) []string {
+	script := fmt.Sprintf(`ulimit -d %d 2>/dev/null; ulimit -t %d 2>/dev/null; exec "$@"`, lim.memoryKB, lim.cpuSeconds)
+	return append([]string{"sh", "-c", script, "gt-plugin"}, argv...)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package plugin
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the command's process. Windows has no process
+// groups to signal, so children it spawned may outlive it.
+func killProcessGroup(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
+
+// withLimits returns argv unchanged: Windows has no rlimits, so only the
+// timeout bounds a plugin there.
+func withLimits(argv []string, lim limits) []string {
+	return argv
+}
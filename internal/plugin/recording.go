@@ -19,6 +19,10 @@ const (
 	ResultSuccess RunResult = "success"
 	ResultFailure RunResult = "failure"
 	ResultSkipped RunResult = "skipped"
+
+	// ResultDispatched records a scheduled run handed to a dog. The dog
+	// records its own success or failure when it finishes.
+	ResultDispatched RunResult = "dispatched"
 )
 
 // PluginRunRecord represents data for creating a plugin run bead.
@@ -0,0 +1,343 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// DefaultTimeout bounds a plugin run when its execution section sets none.
+const DefaultTimeout = 10 * time.Minute
+
+// DefaultMaxMemory caps each plugin process's memory when its execution
+// section sets no max_memory.
+const DefaultMaxMemory = "4GiB"
+
+const (
+	// maxLogBytes caps the output kept in a run log.
+	maxLogBytes = 10 << 20
+
+	// tailBytes is how much trailing output goes into the run record.
+	tailBytes = 2048
+
+	// killGrace is how long to wait for output pipes to close after the
+	// process group is killed.
+	killGrace = 5 * time.Second
+)
+
+// Runner executes plugins in their directory and records the result.
+//
+// A plugin with execution.run has that command run with sh -c; otherwise its
+// instructions are handed to AgentCommand. The run is bounded by
+// execution.timeout (default 10m), after which its whole process group is
+// killed, and each of its processes by execution.max_memory (default 4GiB)
+// and execution.max_cpu (default the timeout) where the platform supports
+// resource limits. Combined stdout and stderr go to a log under LogDir. Each
+// run is recorded as a plugin run bead, and failures are escalated at
+// execution.severity when notify_on_failure is set.
+type Runner struct {
+	townRoot string
+
+	// AgentCommand runs instruction-only plugins (default plugins.agent_command
+	// from town settings). Nil means they can't be run.
+	AgentCommand []string
+
+	// LogDir holds run logs, one directory per plugin
+	// (default <town>/logs/plugins).
+	LogDir string
+
+	record   func(rec PluginRunRecord) (string, error)
+	escalate func(p *Plugin, run *PluginRun, severity string) error
+}
+
+// NewRunner creates a plugin runner for the town.
+func NewRunner(townRoot string) *Runner {
+	r := &Runner{
+		townRoot: townRoot,
+		LogDir:   filepath.Join(townRoot, "logs", "plugins"),
+		record:   NewRecorder(townRoot).RecordRun,
+	}
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && settings.Plugins != nil {
+		r.AgentCommand = settings.Plugins.AgentCommand
+	}
+	r.escalate = r.escalateFailure
+	return r
+}
+
+// Run executes a plugin and waits for it to finish.
+//
+// An error with a nil run means the plugin couldn't be started. An error
+// with a non-nil run means it ran but recording or escalating the result
+// failed; the run still describes the outcome.
+func (r *Runner) Run(ctx context.Context, p *Plugin) (*PluginRun, error) {
+	timeout := DefaultTimeout
+	if p.Execution != nil && p.Execution.Timeout != "" {
+		d, err := time.ParseDuration(p.Execution.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("plugin %s: invalid timeout %q", p.Name, p.Execution.Timeout)
+		}
+		timeout = d
+	}
+
+	lim, err := resourceLimits(p, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	argv, err := r.command(p)
+	if err != nil {
+		return nil, err
+	}
+	argv = withLimits(argv, lim)
+
+	run := &PluginRun{
+		PluginName: p.Name,
+		RigName:    p.RigName,
+		StartTime:  time.Now(),
+	}
+	logFile, err := createRunLog(filepath.Join(r.LogDir, p.Name), run.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+	run.LogPath = logFile.Name()
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(runCtx, argv[0], argv[1:]...) //nolint:gosec // G204: command comes from the plugin definition
+	cmd.Dir = p.Path
+	cmd.Env = append(os.Environ(),
+		"GT_TOWN_ROOT="+r.townRoot,
+		"GT_PLUGIN="+p.Name,
+		"GT_PLUGIN_DIR="+p.Path,
+	)
+	if p.RigName != "" {
+		cmd.Env = append(cmd.Env, "GT_RIG="+p.RigName)
+	}
+	tail := &tailBuffer{max: tailBytes}
+	out := io.MultiWriter(&limitedWriter{w: logFile, remaining: maxLogBytes}, tail)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = killGrace
+
+	runErr := cmd.Run()
+	run.EndTime = time.Now()
+	elapsed := run.EndTime.Sub(run.StartTime).Round(100 * time.Millisecond)
+	if cmd.ProcessState != nil {
+		run.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	switch {
+	case runErr == nil:
+		run.Result = string(ResultSuccess)
+		run.Message = fmt.Sprintf("completed in %s", elapsed)
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		run.Result = string(ResultFailure)
+		run.TimedOut = true
+		run.Message = fmt.Sprintf("timed out after %s", timeout)
+	case cmd.ProcessState == nil:
+		run.Result = string(ResultFailure)
+		run.ExitCode = -1
+		run.Message = fmt.Sprintf("failed to start: %v", runErr)
+	default:
+		run.Result = string(ResultFailure)
+		run.Message = fmt.Sprintf("failed after %s: %v", elapsed, runErr)
+	}
+
+	var errs []error
+	id, err := r.record(PluginRunRecord{
+		PluginName: p.Name,
+		RigName:    p.RigName,
+		Result:     RunResult(run.Result),
+		Body:       runBody(run, tail.String()),
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("recording run: %w", err))
+	}
+	run.RunID = id
+
+	if run.Result == string(ResultFailure) && p.Execution != nil && p.Execution.NotifyOnFailure {
+		severity := p.Execution.Severity
+		if !config.IsValidSeverity(severity) {
+			severity = config.SeverityMedium
+		}
+		if err := r.escalate(p, run, severity); err != nil {
+			errs = append(errs, fmt.Errorf("escalating failure: %w", err))
+		}
+	}
+
+	return run, errors.Join(errs...)
+}
+
+// command returns the argv that executes p.
+func (r *Runner) command(p *Plugin) ([]string, error) {
+	if p.Execution != nil && p.Execution.Run != "" {
+		return []string{"sh", "-c", p.Execution.Run}, nil
+	}
+	if strings.TrimSpace(p.Instructions) == "" {
+		return nil, fmt.Errorf("plugin %s has no run command or instructions", p.Name)
+	}
+	if len(r.AgentCommand) == 0 {
+		return nil, fmt.Errorf("plugin %s has no run command and no agent command is configured; set plugins.agent_command in settings/config.json to run it with an agent", p.Name)
+	}
+	argv := append([]string{}, r.AgentCommand...)
+	return append(argv, p.Instructions), nil
+}
+
+// limits are the resource limits applied to each plugin process.
+type limits struct {
+	memoryKB   int64
+	cpuSeconds int64
+}
+
+// resourceLimits reads a plugin's resource limits, applying the defaults.
+func resourceLimits(p *Plugin, timeout time.Duration) (limits, error) {
+	memory, cpu := DefaultMaxMemory, timeout
+	if p.Execution != nil && p.Execution.MaxMemory != "" {
+		memory = p.Execution.MaxMemory
+	}
+	if p.Execution != nil && p.Execution.MaxCPU != "" {
+		d, err := time.ParseDuration(p.Execution.MaxCPU)
+		if err != nil || d < time.Second {
+			return limits{}, fmt.Errorf("plugin %s: invalid max_cpu %q", p.Name, p.Execution.MaxCPU)
+		}
+		cpu = d
+	}
+
+	bytes, err := parseMemory(memory)
+	if err != nil {
+		return limits{}, fmt.Errorf("plugin %s: invalid max_memory %q: %w", p.Name, memory, err)
+	}
+	return limits{
+		memoryKB:   bytes / 1024,
+		cpuSeconds: int64((cpu + time.Second - 1) / time.Second),
+	}, nil
+}
+
+// memoryUnits maps size suffixes to bytes; binary and decimal spellings of
+// the same unit are both taken as binary.
+var memoryUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+}
+
+// parseMemory parses a memory size like "512MiB" or "2G". A bare number is
+// bytes. Sizes under 1MiB are rejected as mistakes.
+func parseMemory(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	mult := int64(1)
+	for _, u := range memoryUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errors.New("want a size like 512MiB or 2GiB")
+	}
+	if n*mult < 1<<20 {
+		return 0, errors.New("must be at least 1MiB")
+	}
+	return n * mult, nil
+}
+
+// runBody describes a run for its plugin run bead.
+func runBody(run *PluginRun, output string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Plugin %s: %s (exit %d)\n", run.Result, run.Message, run.ExitCode)
+	fmt.Fprintf(&sb, "Log: %s\n", run.LogPath)
+	if output = strings.TrimSpace(output); output != "" {
+		sb.WriteString("\nOutput (tail):\n")
+		sb.WriteString(output)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// escalateFailure routes a failed run through gt escalate.
+func (r *Runner) escalateFailure(p *Plugin, run *PluginRun, severity string) error {
+	title := fmt.Sprintf("Plugin FAILED: %s", p.Name)
+	reason := fmt.Sprintf("Plugin %s %s. Log: %s", p.Name, run.Message, run.LogPath)
+	cmd := exec.Command("gt", "escalate", title, "--severity", severity, "--reason", reason, "--source", "plugin:"+p.Name) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = r.townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// limitedWriter writes up to remaining bytes and silently drops the rest,
+// so a noisy plugin can't fill the disk.
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.remaining > 0 {
+		n := int64(len(p))
+		if n > l.remaining {
+			n = l.remaining
+		}
+		if _, err := l.w.Write(p[:n]); err != nil {
+			return 0, err
+		}
+		l.remaining -= n
+	}
+	return len(p), nil
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	return strings.ToValidUTF8(string(t.buf), "")
+}
+
+// createRunLog creates a new run log in dir named for start. Runs started in
+// the same second get a numeric suffix rather than sharing a log.
+func createRunLog(dir string, start time.Time) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating plugin log directory: %w", err)
+	}
+	base := filepath.Join(dir, start.Format("20060102-150405"))
+	path := base + ".log"
+	for n := 1; ; n++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			return f, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("creating plugin log: %w", err)
+		}
+		path = fmt.Sprintf("%s-%d.log", base, n)
+	}
+}
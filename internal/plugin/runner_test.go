@@ -0,0 +1,224 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRunner struct {
+	*Runner
+	records   []PluginRunRecord
+	escalated []string // Severities
+}
+
+// newTestRunner returns a runner for a temp town whose records and
+// escalations are captured instead of shelling out to bd and gt.
+func newTestRunner(t *testing.T) (*testRunner, string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	tr := &testRunner{Runner: NewRunner(townRoot)}
+	tr.record = func(rec PluginRunRecord) (string, error) {
+		tr.records = append(tr.records, rec)
+		return "hq-wisp-1", nil
+	}
+	tr.escalate = func(p *Plugin, run *PluginRun, severity string) error {
+		tr.escalated = append(tr.escalated, severity)
+		return nil
+	}
+	return tr, townRoot
+}
+
+// writeTestPlugin writes plugins/<name>/plugin.md and loads it back.
+func writeTestPlugin(t *testing.T, townRoot, name, frontmatter, body string) *Plugin {
+	t.Helper()
+	dir := filepath.Join(townRoot, "plugins", name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	md := "+++\nname = \"" + name + "\"\n" + frontmatter + "\n+++\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "plugin.md"), []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewScanner(townRoot, nil).GetPlugin(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRunner_RunScript(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	p := writeTestPlugin(t, townRoot, "hello", `[execution]
+run = "echo hello from $GT_PLUGIN in $(basename $PWD); echo oops >&2"
+notify_on_failure = true`, "")
+
+	run, err := r.Run(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if run.Result != string(ResultSuccess) || run.ExitCode != 0 || run.RunID != "hq-wisp-1" {
+		t.Errorf("run = %+v, want success with exit 0 and run ID", run)
+	}
+
+	log, err := os.ReadFile(run.LogPath)
+	if err != nil {
+		t.Fatalf("reading log: %v", err)
+	}
+	if !strings.Contains(string(log), "hello from hello in hello") || !strings.Contains(string(log), "oops") {
+		t.Errorf("log = %q, want stdout and stderr", log)
+	}
+	if !strings.HasPrefix(run.LogPath, filepath.Join(townRoot, "logs", "plugins", "hello")) {
+		t.Errorf("log path = %s", run.LogPath)
+	}
+
+	if len(r.records) != 1 || r.records[0].Result != ResultSuccess || !strings.Contains(r.records[0].Body, "hello from") {
+		t.Errorf("records = %+v", r.records)
+	}
+	if len(r.escalated) != 0 {
+		t.Errorf("escalated a successful run: %v", r.escalated)
+	}
+}
+
+func TestRunner_FailureEscalates(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	p := writeTestPlugin(t, townRoot, "broken", `[execution]
+run = "exit 3"
+notify_on_failure = true
+severity = "high"`, "")
+
+	run, err := r.Run(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if run.Result != string(ResultFailure) || run.ExitCode != 3 || run.TimedOut {
+		t.Errorf("run = %+v, want failure with exit 3", run)
+	}
+	if len(r.records) != 1 || r.records[0].Result != ResultFailure {
+		t.Errorf("records = %+v, want one failure", r.records)
+	}
+	if len(r.escalated) != 1 || r.escalated[0] != "high" {
+		t.Errorf("escalated = %v, want [high]", r.escalated)
+	}
+}
+
+func TestRunner_FailureWithoutNotify(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	p := writeTestPlugin(t, townRoot, "quiet", `[execution]
+run = "false"
+severity = "bogus"`, "")
+
+	if _, err := r.Run(context.Background(), p); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(r.escalated) != 0 {
+		t.Errorf("escalated without notify_on_failure: %v", r.escalated)
+	}
+}
+
+func TestRunner_Instructions(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	// The prompt is appended as the last argument ($1 here)
+	r.AgentCommand = []string{"sh", "-c", `printf '%s' "$1" > prompt.txt`, "agent"}
+	p := writeTestPlugin(t, townRoot, "agentic", "", "# Do it\n\nCheck the thing.")
+
+	run, err := r.Run(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if run.Result != string(ResultSuccess) {
+		t.Fatalf("run = %+v", run)
+	}
+	prompt, err := os.ReadFile(filepath.Join(p.Path, "prompt.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(prompt) != "# Do it\n\nCheck the thing." {
+		t.Errorf("prompt = %q", prompt)
+	}
+}
+
+func TestRunner_RejectsUnrunnablePlugins(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	empty := writeTestPlugin(t, townRoot, "empty", "", "")
+	badTimeout := writeTestPlugin(t, townRoot, "bad-timeout", "[execution]\nrun = \"true\"\ntimeout = \"soon\"", "")
+
+	for _, p := range []*Plugin{empty, badTimeout} {
+		if run, err := r.Run(context.Background(), p); err == nil || run != nil {
+			t.Errorf("Run(%s) = %+v, %v; want error", p.Name, run, err)
+		}
+	}
+	if len(r.records) != 0 {
+		t.Errorf("recorded runs that never started: %+v", r.records)
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	tail := &tailBuffer{max: 5}
+	_, _ = tail.Write([]byte("abc"))
+	_, _ = tail.Write([]byte("defg"))
+	if got := tail.String(); got != "cdefg" {
+		t.Errorf("tail = %q, want %q", got, "cdefg")
+	}
+}
+
+func TestCreateRunLog_SameSecond(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hello")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		f, err := createRunLog(dir, start)
+		if err != nil {
+			t.Fatalf("createRunLog: %v", err)
+		}
+		f.Close()
+		if seen[f.Name()] {
+			t.Fatalf("run %d reused log %s", i, f.Name())
+		}
+		seen[f.Name()] = true
+	}
+	if !seen[filepath.Join(dir, "20260102-030405.log")] || !seen[filepath.Join(dir, "20260102-030405-2.log")] {
+		t.Errorf("logs = %v", seen)
+	}
+}
+
+func TestRunner_InstructionsNeedAgentCommand(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	p := writeTestPlugin(t, townRoot, "agentic", "", "Check the thing.")
+
+	if len(r.AgentCommand) != 0 {
+		t.Fatalf("AgentCommand = %v without plugins.agent_command, want none", r.AgentCommand)
+	}
+	if _, err := r.Run(context.Background(), p); err == nil || !strings.Contains(err.Error(), "plugins.agent_command") {
+		t.Errorf("Run error = %v, want a hint to configure plugins.agent_command", err)
+	}
+}
+
+func TestResourceLimits(t *testing.T) {
+	tests := []struct {
+		execution *Execution
+		want      limits
+		wantErr   bool
+	}{
+		{nil, limits{memoryKB: 4 << 20, cpuSeconds: 600}, false},
+		{&Execution{MaxMemory: "512MiB", MaxCPU: "90s"}, limits{memoryKB: 512 << 10, cpuSeconds: 90}, false},
+		{&Execution{MaxMemory: "2G"}, limits{memoryKB: 2 << 20, cpuSeconds: 600}, false},
+		{&Execution{MaxMemory: "lots"}, limits{}, true},
+		{&Execution{MaxMemory: "512"}, limits{}, true},
+		{&Execution{MaxCPU: "500ms"}, limits{}, true},
+	}
+	for _, tt := range tests {
+		got, err := resourceLimits(&Plugin{Name: "p", Execution: tt.execution}, DefaultTimeout)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resourceLimits(%+v) error = %v, wantErr %v", tt.execution, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resourceLimits(%+v) = %+v, want %+v", tt.execution, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+//go:build unix
+
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunner_TimeoutKillsProcessGroup(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	p := writeTestPlugin(t, townRoot, "slow", `[execution]
+run = "sleep 30 & echo $! > child.pid; wait"
+timeout = "300ms"
+notify_on_failure = true`, "")
+
+	start := time.Now()
+	run, err := r.Run(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Run took %v after a 300ms timeout", elapsed)
+	}
+	if !run.TimedOut || run.Result != string(ResultFailure) {
+		t.Errorf("run = %+v, want timed out failure", run)
+	}
+	if len(r.escalated) != 1 {
+		t.Errorf("escalated = %v, want one escalation", r.escalated)
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.Path, "child.pid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for processRunning(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("child %d still running after timeout", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestRunner_AppliesResourceLimits(t *testing.T) {
+	r, townRoot := newTestRunner(t)
+	p := writeTestPlugin(t, townRoot, "limited", `[execution]
+run = "echo $(ulimit -d) $(ulimit -t) > limits.txt"
+max_memory = "256MiB"
+max_cpu = "30s"`, "")
+
+	run, err := r.Run(context.Background(), p)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if run.Result != string(ResultSuccess) {
+		t.Fatalf("run = %+v", run)
+	}
+	data, err := os.ReadFile(filepath.Join(p.Path, "limits.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "262144 30" {
+		t.Errorf("plugin saw limits %q, want \"262144 30\" (data KB, CPU seconds)", got)
+	}
+}
+
+// processRunning reports whether pid is alive and not a zombie awaiting reaping.
+func processRunning(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return true // No procfs (macOS): trust kill
+	}
+	// Format: pid (comm) state ...
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
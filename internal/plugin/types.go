@@ -105,9 +105,21 @@ type Tracking struct {
 
 // Execution defines plugin execution settings.
 type Execution struct {
+	// Run is an optional shell command run in the plugin directory instead
+	// of handing the instructions to an agent (e.g., "./run.sh").
+	Run string `json:"run,omitempty" toml:"run,omitempty"`
+
 	// Timeout is the maximum execution time (e.g., "5m").
 	Timeout string `json:"timeout,omitempty" toml:"timeout,omitempty"`
 
+	// MaxMemory caps the data segment of each plugin process (e.g., "512MiB",
+	// "2GiB"). Default: DefaultMaxMemory.
+	MaxMemory string `json:"max_memory,omitempty" toml:"max_memory,omitempty"`
+
+	// MaxCPU caps the CPU time of each plugin process (e.g., "2m").
+	// Default: the timeout.
+	MaxCPU string `json:"max_cpu,omitempty" toml:"max_cpu,omitempty"`
+
 	// NotifyOnFailure escalates on failure.
 	NotifyOnFailure bool `json:"notify_on_failure" toml:"notify_on_failure"`
 
@@ -162,4 +174,8 @@ type PluginRun struct {
 	EndTime    time.Time `json:"end_time,omitempty"`
 	Result     string    `json:"result"` // "success" or "failure"
 	Message    string    `json:"message,omitempty"`
+	ExitCode   int       `json:"exit_code"`
+	TimedOut   bool      `json:"timed_out,omitempty"`
+	LogPath    string    `json:"log_path,omitempty"`
+	RunID      string    `json:"run_id,omitempty"` // Plugin run bead, if recorded
 }
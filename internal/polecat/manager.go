@@ -193,14 +193,19 @@ func (m *Manager) repoBase() (*git.Git, error) {
 
 // polecatDir returns the parent directory for a polecat.
 // This is polecats/<name>/ - the polecat's home directory.
+// For remote rigs the path is on the rig's machine.
 func (m *Manager) polecatDir(name string) string {
-	return filepath.Join(m.rig.Path, "polecats", name)
+	return filepath.Join(m.rig.WorkPath(), "polecats", name)
 }
 
 // clonePath returns the path where the git worktree lives.
 // New structure: polecats/<name>/<rigname>/ - gives LLMs recognizable repo context.
 // Falls back to old structure: polecats/<name>/ for backward compatibility.
 func (m *Manager) clonePath(name string) string {
+	if m.rig.IsRemote() {
+		return m.remoteClonePath(name)
+	}
+
 	// New structure: polecats/<name>/<rigname>/
 	newPath := filepath.Join(m.rig.Path, "polecats", name, m.rig.Name)
 	if info, err := os.Stat(newPath); err == nil && info.IsDir() {
@@ -223,6 +228,10 @@ func (m *Manager) clonePath(name string) string {
 
 // exists checks if a polecat exists.
 func (m *Manager) exists(name string) bool {
+	if m.rig.IsRemote() {
+		ok, err := m.rig.Connection().Exists(m.polecatDir(name))
+		return err == nil && ok
+	}
 	_, err := os.Stat(m.polecatDir(name))
 	return err == nil
 }
@@ -356,6 +365,9 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (*Polecat, error)
 	if m.exists(name) {
 		return nil, ErrPolecatExists.WithContext("name", name).WithContext("rig", m.rig.Name)
 	}
+	if m.rig.IsRemote() {
+		return m.addRemote(name, opts)
+	}
 
 	// New structure: polecats/<name>/<rigname>/ for LLM ergonomics
 	// The polecat's home dir is polecats/<name>/, worktree is polecats/<name>/<rigname>/
@@ -509,6 +521,9 @@ func (m *Manager) RemoveWithOptions(name string, force, nuclear bool) error {
 	if !m.exists(name) {
 		return ErrPolecatNotFound.WithContext("name", name).WithContext("rig", m.rig.Name)
 	}
+	if m.rig.IsRemote() {
+		return m.removeRemote(name, force, nuclear)
+	}
 
 	// Clone path is where the git worktree lives (new or old structure)
 	clonePath := m.clonePath(name)
@@ -678,6 +693,13 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 	if !m.exists(name) {
 		return nil, ErrPolecatNotFound.WithContext("name", name).WithContext("rig", m.rig.Name)
 	}
+	if m.rig.IsRemote() {
+		// Remote rigs: recreate from scratch over the connection
+		if err := m.removeRemote(name, force, false); err != nil {
+			return nil, err
+		}
+		return m.addRemote(name, opts)
+	}
 
 	// Get the old clone path (may be old or new structure)
 	oldClonePath := m.clonePath(name)
@@ -832,6 +854,12 @@ func (m *Manager) ReconcilePool() {
 		namesWithDirs = append(namesWithDirs, p.Name)
 	}
 
+	// Remote rigs: sessions and worktrees live on the rig's machine
+	if m.rig.IsRemote() {
+		m.reconcileRemotePool(namesWithDirs)
+		return
+	}
+
 	// Get names with tmux sessions
 	var namesWithSessions []string
 	if m.tmux != nil {
@@ -889,6 +917,10 @@ func (m *Manager) PoolStatus() (active int, names []string) {
 
 // List returns all polecats in the rig.
 func (m *Manager) List() ([]*Polecat, error) {
+	if m.rig.IsRemote() {
+		return m.listRemote()
+	}
+
 	polecatsDir := filepath.Join(m.rig.Path, "polecats")
 
 	entries, err := os.ReadDir(polecatsDir)
@@ -1051,8 +1083,7 @@ func (m *Manager) loadFromBeads(name string) (*Polecat, error) {
 	clonePath := m.clonePath(name)
 
 	// Get actual branch from worktree (branches are now timestamped)
-	polecatGit := git.NewGit(clonePath)
-	branchName, err := polecatGit.CurrentBranch()
+	branchName, err := m.currentBranch(clonePath)
 	if err != nil {
 		// Fall back to old format if we can't read the branch
 		branchName = fmt.Sprintf("polecat/%s", name)
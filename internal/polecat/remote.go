@@ -0,0 +1,232 @@
+package polecat
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// Remote rig support.
+//
+// When a rig is bound to a remote machine (rigs.json "machine"), its
+// worktrees and tmux sessions live there and are reached through the rig's
+// connection. Beads, the name pool and rig settings stay on the local
+// town, so only the git and filesystem steps below go over the wire.
+
+// currentBranch returns the checked-out branch of a worktree on the rig's machine.
+func (m *Manager) currentBranch(clonePath string) (string, error) {
+	if !m.rig.IsRemote() {
+		return git.NewGit(clonePath).CurrentBranch()
+	}
+	out, err := m.rig.Connection().ExecDir(clonePath, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %s", strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// remoteClonePath mirrors clonePath for a remote rig.
+func (m *Manager) remoteClonePath(name string) string {
+	conn := m.rig.Connection()
+	newPath := filepath.Join(m.polecatDir(name), m.rig.Name)
+	if fi, err := conn.Stat(newPath); err == nil && fi.IsDir() {
+		return newPath
+	}
+	oldPath := m.polecatDir(name)
+	if ok, _ := conn.Exists(filepath.Join(oldPath, ".git")); ok {
+		return oldPath
+	}
+	return newPath
+}
+
+// remoteRepoBase returns the directory to run worktree commands in on a
+// remote rig: the shared bare repo if present, otherwise mayor/rig.
+func (m *Manager) remoteRepoBase() (string, error) {
+	conn := m.rig.Connection()
+	bare := filepath.Join(m.rig.RemotePath, ".repo.git")
+	if fi, err := conn.Stat(bare); err == nil && fi.IsDir() {
+		return bare, nil
+	}
+	mayor := filepath.Join(m.rig.RemotePath, "mayor", "rig")
+	if ok, _ := conn.Exists(mayor); ok {
+		return mayor, nil
+	}
+	return "", errors.System("polecat.NoRepoBase", nil).
+		WithContext("rig", m.rig.Name).
+		WithContext("machine", conn.Name()).
+		WithHint("Initialize the rig on the remote machine with .repo.git or mayor/rig")
+}
+
+// listRemote lists polecats on a remote rig.
+func (m *Manager) listRemote() ([]*Polecat, error) {
+	matches, err := m.rig.Connection().Glob(filepath.Join(m.rig.RemotePath, "polecats", "*") + "/")
+	if err != nil {
+		return nil, errors.Transient("polecat.ListRemote", err).
+			WithContext("rig", m.rig.Name).
+			WithHint("Check ssh access to the rig's machine")
+	}
+
+	var polecats []*Polecat
+	for _, match := range matches {
+		name := filepath.Base(strings.TrimSuffix(match, "/"))
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		polecat, err := m.loadFromBeads(name)
+		if err != nil {
+			continue
+		}
+		polecats = append(polecats, polecat)
+	}
+	return polecats, nil
+}
+
+// reconcileRemotePool reconciles the name pool against a remote rig,
+// killing orphaned remote sessions (session without worktree).
+func (m *Manager) reconcileRemotePool(namesWithDirs []string) {
+	conn := m.rig.Connection()
+	dirSet := make(map[string]bool)
+	for _, name := range namesWithDirs {
+		dirSet[name] = true
+	}
+
+	prefix := fmt.Sprintf("gt-%s-", m.rig.Name)
+	if sessions, err := conn.TmuxListSessions(); err == nil {
+		pool := make(map[string]bool)
+		for _, name := range m.namePool.getNames() {
+			pool[name] = true
+		}
+		for _, sessionName := range sessions {
+			name := strings.TrimPrefix(sessionName, prefix)
+			if name == sessionName || !pool[name] || dirSet[name] {
+				continue
+			}
+			_ = conn.TmuxKillSession(sessionName)
+		}
+	}
+
+	m.namePool.Reconcile(namesWithDirs)
+
+	if base, err := m.remoteRepoBase(); err == nil {
+		_, _ = conn.ExecDir(base, "git", "worktree", "prune")
+	}
+}
+
+// addRemote creates a polecat worktree on a remote rig's machine.
+// Local-only provisioning (overlay, setup hooks, PRIME.md) is skipped;
+// the remote rig is expected to carry its own.
+func (m *Manager) addRemote(name string, opts AddOptions) (*Polecat, error) {
+	conn := m.rig.Connection()
+	polecatDir := m.polecatDir(name)
+	clonePath := filepath.Join(polecatDir, m.rig.Name)
+	branchName := m.buildBranchName(name, opts.HookBead)
+
+	if err := conn.MkdirAll(polecatDir, 0755); err != nil {
+		return nil, errors.System("polecat.CreateDir", err).
+			WithContext("dir", polecatDir).
+			WithContext("machine", conn.Name()).
+			WithHint("Check file system permissions on the remote machine")
+	}
+
+	base, err := m.remoteRepoBase()
+	if err != nil {
+		return nil, err
+	}
+
+	if out, err := conn.ExecDir(base, "git", "fetch", "origin"); err != nil {
+		fmt.Printf("Warning: could not fetch origin on %s: %s\n", conn.Name(), strings.TrimSpace(string(out)))
+	}
+
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(m.rig.Path); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	startPoint := fmt.Sprintf("origin/%s", defaultBranch)
+
+	if out, err := conn.ExecDir(base, "git", "worktree", "add", "-b", branchName, clonePath, startPoint); err != nil {
+		return nil, errors.Transient("polecat.CreateWorktree", err).
+			WithContext("branch", branchName).
+			WithContext("start_point", startPoint).
+			WithContext("path", clonePath).
+			WithContext("machine", conn.Name()).
+			WithContext("output", strings.TrimSpace(string(out))).
+			WithHint("Check that the start point exists on the remote with: git branch -r")
+	}
+
+	agentID := m.agentBeadID(name)
+	if _, err := m.beads.CreateOrReopenAgentBead(agentID, agentID, &beads.AgentFields{
+		RoleType:   "polecat",
+		Rig:        m.rig.Name,
+		AgentState: "spawning",
+		HookBead:   opts.HookBead,
+	}); err != nil {
+		fmt.Printf("Warning: could not create agent bead: %v\n", err)
+	}
+
+	now := time.Now()
+	return &Polecat{
+		Name:      name,
+		Rig:       m.rig.Name,
+		State:     StateWorking,
+		ClonePath: clonePath,
+		Branch:    branchName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// removeRemote deletes a polecat worktree on a remote rig's machine.
+func (m *Manager) removeRemote(name string, force, nuclear bool) error {
+	conn := m.rig.Connection()
+	clonePath := m.clonePath(name)
+	polecatDir := m.polecatDir(name)
+
+	if !nuclear {
+		if status := m.getCleanupStatusFromBead(name); status != CleanupUnknown {
+			if err := m.checkCleanupStatus(name, status, force); err != nil {
+				return err
+			}
+		} else if !force {
+			out, err := conn.ExecDir(clonePath, "git", "status", "--porcelain")
+			if err == nil && strings.TrimSpace(string(out)) != "" {
+				return ErrHasUncommittedWork.
+					WithContext("polecat", name).
+					WithContext("machine", conn.Name())
+			}
+		}
+	}
+
+	if base, err := m.remoteRepoBase(); err == nil {
+		args := []string{"worktree", "remove"}
+		if force || nuclear {
+			args = append(args, "--force")
+		}
+		args = append(args, clonePath)
+		_, _ = conn.ExecDir(base, "git", args...)
+		defer func() { _, _ = conn.ExecDir(base, "git", "worktree", "prune") }()
+	}
+
+	if err := conn.RemoveAll(polecatDir); err != nil {
+		return errors.System("polecat.RemoveClonePath", err).
+			WithContext("path", polecatDir).
+			WithContext("machine", conn.Name()).
+			WithHint("Check file system permissions on the remote machine")
+	}
+
+	m.namePool.Release(name)
+	_ = m.namePool.Save()
+
+	agentID := m.agentBeadID(name)
+	if err := m.beads.CloseAndClearAgentBead(agentID, "polecat removed"); err != nil {
+		if !errors.Is(err, beads.ErrNotFound) {
+			fmt.Printf("Warning: could not close agent bead %s: %v\n", agentID, err)
+		}
+	}
+	return nil
+}
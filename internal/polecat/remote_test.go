@@ -0,0 +1,220 @@
+package polecat
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// fakeRemote is an in-memory remote machine: a set of directories and
+// tmux sessions, recording keys sent to each session.
+type fakeRemote struct {
+	dirs     map[string]bool
+	sessions map[string]string // session -> workdir
+	sent     map[string][]string
+	killed   []string
+}
+
+func newFakeRemote() *fakeRemote {
+	return &fakeRemote{
+		dirs:     make(map[string]bool),
+		sessions: make(map[string]string),
+		sent:     make(map[string][]string),
+	}
+}
+
+func (f *fakeRemote) Name() string  { return "box" }
+func (f *fakeRemote) IsLocal() bool { return false }
+
+func (f *fakeRemote) ReadFile(path string) ([]byte, error) {
+	return nil, &connection.NotFoundError{Path: path}
+}
+func (f *fakeRemote) WriteFile(string, []byte, fs.FileMode) error { return nil }
+func (f *fakeRemote) MkdirAll(path string, _ fs.FileMode) error {
+	f.dirs[path] = true
+	return nil
+}
+func (f *fakeRemote) Remove(path string) error    { delete(f.dirs, path); return nil }
+func (f *fakeRemote) RemoveAll(path string) error { delete(f.dirs, path); return nil }
+func (f *fakeRemote) Stat(path string) (connection.FileInfo, error) {
+	if f.dirs[path] {
+		return connection.BasicFileInfo{FileName: path, FileIsDir: true, FileMode: fs.ModeDir | 0755}, nil
+	}
+	return nil, &connection.NotFoundError{Path: path}
+}
+func (f *fakeRemote) Glob(pattern string) ([]string, error) {
+	prefix := strings.TrimSuffix(strings.TrimSuffix(pattern, "/"), "*")
+	var out []string
+	for d := range f.dirs {
+		rest := strings.TrimPrefix(d, prefix)
+		if rest != d && rest != "" && !strings.Contains(rest, "/") {
+			out = append(out, d+"/")
+		}
+	}
+	return out, nil
+}
+func (f *fakeRemote) Exists(path string) (bool, error) { return f.dirs[path], nil }
+func (f *fakeRemote) Exec(string, ...string) ([]byte, error) {
+	return nil, nil
+}
+func (f *fakeRemote) ExecDir(string, string, ...string) ([]byte, error) {
+	return nil, nil
+}
+func (f *fakeRemote) ExecEnv(map[string]string, string, ...string) ([]byte, error) {
+	return nil, nil
+}
+func (f *fakeRemote) TmuxNewSession(name, dir string) error {
+	if _, ok := f.sessions[name]; ok {
+		return tmux.ErrSessionExists
+	}
+	f.sessions[name] = dir
+	return nil
+}
+func (f *fakeRemote) TmuxKillSession(name string) error {
+	delete(f.sessions, name)
+	f.killed = append(f.killed, name)
+	return nil
+}
+func (f *fakeRemote) TmuxSendKeys(session, keys string) error {
+	f.sent[session] = append(f.sent[session], keys)
+	return nil
+}
+func (f *fakeRemote) TmuxCapturePane(session string, _ int) (string, error) {
+	return "output of " + session, nil
+}
+func (f *fakeRemote) TmuxHasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+func (f *fakeRemote) TmuxListSessions() ([]string, error) {
+	var out []string
+	for name := range f.sessions {
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+func newRemoteRig(t *testing.T, conn connection.Connection) *rig.Rig {
+	t.Helper()
+	r := &rig.Rig{Name: "gastown", Path: t.TempDir()}
+	r.SetConnection(conn, "/srv/gt/gastown")
+	return r
+}
+
+func TestSessionManagerRemoteRouting(t *testing.T) {
+	remote := newFakeRemote()
+	remote.dirs["/srv/gt/gastown/polecats/Toast"] = true
+	remote.dirs["/srv/gt/gastown/polecats/Toast/gastown"] = true
+	remote.sessions["gt-gastown-Toast"] = "/srv/gt/gastown/polecats/Toast/gastown"
+	remote.sessions["gt-other-Nux"] = "/elsewhere"
+
+	r := newRemoteRig(t, remote)
+	if !r.IsRemote() || r.Machine != "box" {
+		t.Fatalf("rig not bound to remote: remote=%v machine=%q", r.IsRemote(), r.Machine)
+	}
+	m := NewSessionManager(tmux.NewTmux(), r)
+
+	if !m.hasPolecat("Toast") {
+		t.Errorf("hasPolecat(Toast) = false on remote rig")
+	}
+	if got := m.clonePath("Toast"); got != "/srv/gt/gastown/polecats/Toast/gastown" {
+		t.Errorf("clonePath = %q", got)
+	}
+
+	running, err := m.IsRunning("Toast")
+	if err != nil || !running {
+		t.Errorf("IsRunning = %v, %v; want true", running, err)
+	}
+
+	out, err := m.Capture("Toast", 10)
+	if err != nil || out != "output of gt-gastown-Toast" {
+		t.Errorf("Capture = %q, %v", out, err)
+	}
+
+	if err := m.Inject("Toast", "hello"); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if got := remote.sent["gt-gastown-Toast"]; len(got) != 1 || got[0] != "hello" {
+		t.Errorf("sent = %v", got)
+	}
+
+	infos, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(infos) != 1 || infos[0].Polecat != "Toast" {
+		t.Errorf("List = %+v, want only Toast", infos)
+	}
+
+	if err := m.Attach("Toast"); err == nil {
+		t.Errorf("Attach on remote rig should fail with a hint")
+	}
+
+	if err := m.Stop("Toast", true); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if len(remote.killed) != 1 || remote.killed[0] != "gt-gastown-Toast" {
+		t.Errorf("killed = %v", remote.killed)
+	}
+}
+
+func TestSessionManagerRemoteStart(t *testing.T) {
+	remote := newFakeRemote()
+	remote.dirs["/srv/gt/gastown/polecats/Toast"] = true
+	remote.dirs["/srv/gt/gastown/polecats/Toast/gastown"] = true
+
+	r := newRemoteRig(t, remote)
+	m := NewSessionManager(tmux.NewTmux(), r)
+
+	if err := m.Start("Toast", SessionStartOptions{}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	dir, ok := remote.sessions["gt-gastown-Toast"]
+	if !ok {
+		t.Fatalf("remote session not created")
+	}
+	if dir != "/srv/gt/gastown/polecats/Toast/gastown" {
+		t.Errorf("session workdir = %q", dir)
+	}
+	sent := remote.sent["gt-gastown-Toast"]
+	if len(sent) != 1 {
+		t.Fatalf("expected startup command, got %v", sent)
+	}
+	if !strings.Contains(sent[0], "GT_ROOT=/srv/gt") || !strings.Contains(sent[0], "GT_POLECAT=Toast") {
+		t.Errorf("startup command missing remote env: %s", sent[0])
+	}
+
+	if err := m.Start("Toast", SessionStartOptions{}); err == nil {
+		t.Errorf("second Start should fail with session running")
+	}
+}
+
+func TestManagerRemoteList(t *testing.T) {
+	remote := newFakeRemote()
+	remote.dirs["/srv/gt/gastown/polecats/Toast"] = true
+	remote.dirs["/srv/gt/gastown/polecats/Toast/gastown"] = true
+	remote.dirs["/srv/gt/gastown/polecats/.hidden"] = true
+
+	r := newRemoteRig(t, remote)
+	m := NewManager(r, nil, nil)
+
+	if !m.exists("Toast") || m.exists("Nux") {
+		t.Errorf("exists: Toast=%v Nux=%v", m.exists("Toast"), m.exists("Nux"))
+	}
+
+	polecats, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(polecats) != 1 || polecats[0].Name != "Toast" {
+		t.Fatalf("List = %+v, want [Toast]", polecats)
+	}
+	if polecats[0].ClonePath != "/srv/gt/gastown/polecats/Toast/gastown" {
+		t.Errorf("ClonePath = %q", polecats[0].ClonePath)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/errors"
 	"github.com/steveyegge/gastown/internal/hooks"
@@ -38,6 +39,8 @@ var (
 )
 
 // SessionManager handles polecat session lifecycle.
+// For rigs bound to a remote machine, session and worktree operations go
+// through the rig's connection instead of the local tmux server.
 type SessionManager struct {
 	tmux *tmux.Tmux
 	rig  *rig.Rig
@@ -102,10 +105,55 @@ func (m *SessionManager) SessionName(polecat string) string {
 	return fmt.Sprintf("gt-%s-%s", m.rig.Name, polecat)
 }
 
+// remote returns the rig's connection if the rig lives on another machine,
+// or nil for local rigs.
+func (m *SessionManager) remote() connection.Connection {
+	if m.rig.IsRemote() {
+		return m.rig.Connection()
+	}
+	return nil
+}
+
+// hasSession checks for a session on the rig's machine.
+func (m *SessionManager) hasSession(sessionID string) (bool, error) {
+	if conn := m.remote(); conn != nil {
+		return conn.TmuxHasSession(sessionID)
+	}
+	return m.tmux.HasSession(sessionID)
+}
+
+// capturePane captures pane output on the rig's machine.
+func (m *SessionManager) capturePane(sessionID string, lines int) (string, error) {
+	if conn := m.remote(); conn != nil {
+		return conn.TmuxCapturePane(sessionID, lines)
+	}
+	return m.tmux.CapturePane(sessionID, lines)
+}
+
+// isDir reports whether path is a directory on the rig's machine.
+func (m *SessionManager) isDir(path string) bool {
+	if conn := m.remote(); conn != nil {
+		info, err := conn.Stat(path)
+		return err == nil && info.IsDir()
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// exists reports whether path exists on the rig's machine.
+func (m *SessionManager) exists(path string) bool {
+	if conn := m.remote(); conn != nil {
+		ok, err := conn.Exists(path)
+		return err == nil && ok
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 // polecatDir returns the parent directory for a polecat.
 // This is polecats/<name>/ - the polecat's home directory.
 func (m *SessionManager) polecatDir(polecat string) string {
-	return filepath.Join(m.rig.Path, "polecats", polecat)
+	return filepath.Join(m.rig.WorkPath(), "polecats", polecat)
 }
 
 // clonePath returns the path where the git worktree lives.
@@ -113,17 +161,16 @@ func (m *SessionManager) polecatDir(polecat string) string {
 // Falls back to old structure: polecats/<name>/ for backward compatibility.
 func (m *SessionManager) clonePath(polecat string) string {
 	// New structure: polecats/<name>/<rigname>/
-	newPath := filepath.Join(m.rig.Path, "polecats", polecat, m.rig.Name)
-	if info, err := os.Stat(newPath); err == nil && info.IsDir() {
+	newPath := filepath.Join(m.rig.WorkPath(), "polecats", polecat, m.rig.Name)
+	if m.isDir(newPath) {
 		return newPath
 	}
 
 	// Old structure: polecats/<name>/ (backward compat)
-	oldPath := filepath.Join(m.rig.Path, "polecats", polecat)
-	if info, err := os.Stat(oldPath); err == nil && info.IsDir() {
+	oldPath := filepath.Join(m.rig.WorkPath(), "polecats", polecat)
+	if m.isDir(oldPath) {
 		// Check if this is actually a git worktree (has .git file or dir)
-		gitPath := filepath.Join(oldPath, ".git")
-		if _, err := os.Stat(gitPath); err == nil {
+		if m.exists(filepath.Join(oldPath, ".git")) {
 			return oldPath
 		}
 	}
@@ -134,12 +181,7 @@ func (m *SessionManager) clonePath(polecat string) string {
 
 // hasPolecat checks if the polecat exists in this rig.
 func (m *SessionManager) hasPolecat(polecat string) bool {
-	polecatPath := m.polecatDir(polecat)
-	info, err := os.Stat(polecatPath)
-	if err != nil {
-		return false
-	}
-	return info.IsDir()
+	return m.isDir(m.polecatDir(polecat))
 }
 
 // Start creates and starts a new session for a polecat.
@@ -153,7 +195,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Check if session already exists
 	// Note: Orphan sessions are cleaned up by ReconcilePool during AllocateName,
 	// so by this point, any existing session should be legitimately in use.
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return errors.Transient("session.CheckSession", err).
 			WithContext("session_id", sessionID).
@@ -169,6 +211,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		workDir = m.clonePath(polecat)
 	}
 
+	// Remote rigs take a reduced startup path over the rig's connection
+	if m.rig.IsRemote() {
+		return m.startRemote(polecat, sessionID, workDir, opts)
+	}
+
 	// Fire pre-session-start hooks (can block session startup)
 	townRoot := filepath.Dir(m.rig.Path)
 	if err := m.firePreSessionStartHooks(townRoot, polecat, workDir, opts.Issue); err != nil {
@@ -285,11 +332,94 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	return nil
 }
 
+// startRemote starts a polecat session on a remote rig's machine.
+// The connection only offers basic tmux operations, so the agent
+// environment is baked into the startup command and local-only niceties
+// (theme, pane-died hook, runtime readiness probes) are skipped.
+// Hooks and bd still run locally, against the rig's local directory.
+func (m *SessionManager) startRemote(polecat, sessionID, workDir string, opts SessionStartOptions) error {
+	conn := m.remote()
+	townRoot := filepath.Dir(m.rig.Path)
+
+	if err := m.firePreSessionStartHooks(townRoot, polecat, m.rig.Path, opts.Issue); err != nil {
+		return errors.User("session.HookBlocked", "pre-session-start hook blocked startup").
+			WithContext("polecat", polecat).
+			WithContext("hook_error", err.Error()).
+			WithHint("Check hook scripts in .runtime/hooks/ and resolve the issue")
+	}
+
+	if opts.Issue != "" {
+		if err := m.validateIssue(opts.Issue, m.rig.Path); err != nil {
+			return err
+		}
+	}
+
+	command := opts.Command
+	if command == "" {
+		address := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+		beacon := session.FormatStartupBeacon(session.BeaconConfig{
+			Recipient: address,
+			Sender:    "witness",
+			Topic:     "assigned",
+			MolID:     opts.Issue,
+		})
+		envVars := config.AgentEnv(config.AgentEnvConfig{
+			Role:             "polecat",
+			Rig:              m.rig.Name,
+			AgentName:        polecat,
+			TownRoot:         filepath.Dir(m.rig.RemotePath),
+			RuntimeConfigDir: opts.RuntimeConfigDir,
+			BeadsNoDaemon:    true,
+		})
+		// Runtime config is resolved from the local rig; GT_ROOT points remote.
+		command = config.BuildStartupCommand(envVars, m.rig.Path, beacon)
+	}
+
+	if err := conn.TmuxNewSession(sessionID, workDir); err != nil {
+		return errors.Transient("session.Create", err).
+			WithContext("session_id", sessionID).
+			WithContext("machine", conn.Name()).
+			WithContext("work_dir", workDir).
+			WithHint("Check ssh access and tmux on the remote machine")
+	}
+	if err := conn.TmuxSendKeys(sessionID, command); err != nil {
+		_ = conn.TmuxKillSession(sessionID)
+		return errors.Transient("session.StartCommand", err).
+			WithContext("session_id", sessionID).
+			WithContext("machine", conn.Name()).
+			WithHint("Check ssh access and tmux on the remote machine")
+	}
+
+	if opts.Issue != "" {
+		agentID := fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat)
+		if err := m.hookIssue(opts.Issue, agentID, m.rig.Path); err != nil {
+			fmt.Printf("Warning: could not hook issue %s: %v\n", opts.Issue, err)
+		}
+	}
+
+	running, err := conn.TmuxHasSession(sessionID)
+	if err != nil {
+		return errors.Transient("session.VerifySession", err).
+			WithContext("session_id", sessionID).
+			WithContext("machine", conn.Name()).
+			WithHint("Check ssh access and tmux on the remote machine")
+	}
+	if !running {
+		return errors.Permanent("session.StartupFailed", nil).
+			WithContext("session_id", sessionID).
+			WithContext("machine", conn.Name()).
+			WithHint("Check the agent command configuration or runtime settings")
+	}
+
+	_ = m.firePostSessionStartHooks(townRoot, polecat, m.rig.Path, opts.Issue)
+	return nil
+}
+
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return errors.Transient("session.CheckSession", err).
 			WithContext("session_id", sessionID).
@@ -311,6 +441,18 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 		}
 	}
 
+	// Remote sessions: the connection's kill already reaps pane descendants
+	if conn := m.remote(); conn != nil {
+		if err := conn.TmuxKillSession(sessionID); err != nil {
+			return errors.Transient("session.Kill", err).
+				WithContext("session_id", sessionID).
+				WithContext("machine", conn.Name()).
+				WithHint("Check ssh access and tmux on the remote machine")
+		}
+		_ = m.firePostShutdownHooks(townRoot, polecat, workDir)
+		return nil
+	}
+
 	// Try graceful shutdown first
 	if !force {
 		_ = m.tmux.SendKeysRaw(sessionID, "C-c")
@@ -334,14 +476,19 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 // IsRunning checks if a polecat session is active.
 func (m *SessionManager) IsRunning(polecat string) (bool, error) {
 	sessionID := m.SessionName(polecat)
-	return m.tmux.HasSession(sessionID)
+	return m.hasSession(sessionID)
+}
+
+// HasSession checks if a session exists on the rig's machine by raw session ID.
+func (m *SessionManager) HasSession(sessionID string) (bool, error) {
+	return m.hasSession(sessionID)
 }
 
 // Status returns detailed status for a polecat session.
 func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return nil, errors.Transient("session.CheckSession", err).
 			WithContext("session_id", sessionID).
@@ -355,7 +502,7 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 		RigName:   m.rig.Name,
 	}
 
-	if !running {
+	if !running || m.rig.IsRemote() {
 		return info, nil
 	}
 
@@ -394,7 +541,13 @@ func (m *SessionManager) Status(polecat string) (*SessionInfo, error) {
 
 // List returns information about all polecat sessions for this rig.
 func (m *SessionManager) List() ([]SessionInfo, error) {
-	sessions, err := m.tmux.ListSessions()
+	var sessions []string
+	var err error
+	if conn := m.remote(); conn != nil {
+		sessions, err = conn.TmuxListSessions()
+	} else {
+		sessions, err = m.tmux.ListSessions()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +576,13 @@ func (m *SessionManager) List() ([]SessionInfo, error) {
 func (m *SessionManager) Attach(polecat string) error {
 	sessionID := m.SessionName(polecat)
 
+	if conn := m.remote(); conn != nil {
+		return errors.User("session.RemoteAttach", "cannot attach to a session on a remote machine").
+			WithContext("session_id", sessionID).
+			WithContext("machine", conn.Name()).
+			WithHint(fmt.Sprintf("Attach over ssh: ssh -t <host> tmux attach -t %s", sessionID))
+	}
+
 	running, err := m.tmux.HasSession(sessionID)
 	if err != nil {
 		return errors.Transient("session.CheckSession", err).
@@ -440,7 +600,7 @@ func (m *SessionManager) Attach(polecat string) error {
 func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return "", errors.Transient("session.CheckSession", err).
 			WithContext("session_id", sessionID).
@@ -450,12 +610,12 @@ func (m *SessionManager) Capture(polecat string, lines int) (string, error) {
 		return "", ErrSessionNotFound.WithContext("session_id", sessionID)
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.capturePane(sessionID, lines)
 }
 
 // CaptureSession returns the recent output from a session by raw session ID.
 func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, error) {
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return "", errors.Transient("session.CheckSession", err).
 			WithContext("session_id", sessionID).
@@ -465,14 +625,14 @@ func (m *SessionManager) CaptureSession(sessionID string, lines int) (string, er
 		return "", ErrSessionNotFound.WithContext("session_id", sessionID)
 	}
 
-	return m.tmux.CapturePane(sessionID, lines)
+	return m.capturePane(sessionID, lines)
 }
 
 // Inject sends a message to a polecat session.
 func (m *SessionManager) Inject(polecat, message string) error {
 	sessionID := m.SessionName(polecat)
 
-	running, err := m.tmux.HasSession(sessionID)
+	running, err := m.hasSession(sessionID)
 	if err != nil {
 		return errors.Transient("session.CheckSession", err).
 			WithContext("session_id", sessionID).
@@ -482,6 +642,10 @@ func (m *SessionManager) Inject(polecat, message string) error {
 		return ErrSessionNotFound.WithContext("session_id", sessionID)
 	}
 
+	if conn := m.remote(); conn != nil {
+		return conn.TmuxSendKeys(sessionID, message)
+	}
+
 	debounceMs := 200 + (len(message)/1024)*100
 	if debounceMs > 1500 {
 		debounceMs = 1500
@@ -490,6 +654,16 @@ func (m *SessionManager) Inject(polecat, message string) error {
 	return m.tmux.SendKeysDebounced(sessionID, message, debounceMs)
 }
 
+// NudgeSession delivers a message to a session on the rig's machine by raw
+// session ID. Local sessions use tmux's reliable nudge; remote sessions
+// get a literal send followed by Enter.
+func (m *SessionManager) NudgeSession(sessionID, message string) error {
+	if conn := m.remote(); conn != nil {
+		return conn.TmuxSendKeys(sessionID, message)
+	}
+	return m.tmux.NudgeSession(sessionID, message)
+}
+
 // StopAll terminates all polecat sessions for this rig.
 func (m *SessionManager) StopAll(force bool) error {
 	infos, err := m.List()
@@ -2,6 +2,8 @@ package protocol
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/mail"
 )
@@ -9,9 +11,20 @@ import (
 // Handler processes a protocol message and returns an error if processing failed.
 type Handler func(msg *mail.Message) error
 
+// AwaitableHandler processes a protocol request and returns the reply to
+// send back to the requester, or nil to send none.
+type AwaitableHandler func(msg *mail.Message) (*mail.Message, error)
+
 // HandlerRegistry maps message types to their handlers.
 type HandlerRegistry struct {
 	handlers map[MessageType]Handler
+
+	// sendReply sends replies from awaitable handlers.
+	sendReply func(*mail.Message) error
+
+	// waiters receive replies to requests sent by this side.
+	mu      sync.Mutex
+	waiters map[*mail.PendingRequest]chan *mail.Message
 }
 
 // NewHandlerRegistry creates a new handler registry.
@@ -26,6 +39,97 @@ func (r *HandlerRegistry) Register(msgType MessageType, handler Handler) {
 	r.handlers[msgType] = handler
 }
 
+// SetReplySender sets how replies from awaitable handlers are sent,
+// usually a mail.Router's Send.
+func (r *HandlerRegistry) SetReplySender(send func(*mail.Message) error) {
+	r.sendReply = send
+}
+
+// RegisterAwaitable adds a handler for a request type whose reply is
+// correlated with the request (see mail.CorrelateReply) and sent back, so
+// the requester's pending request is settled.
+func (r *HandlerRegistry) RegisterAwaitable(msgType MessageType, handler AwaitableHandler) {
+	r.Register(msgType, func(msg *mail.Message) error {
+		reply, err := handler(msg)
+		if err != nil || reply == nil {
+			return err
+		}
+		if r.sendReply == nil {
+			return fmt.Errorf("no reply sender set for %s replies", msgType)
+		}
+		return r.sendReply(mail.CorrelateReply(reply, msg))
+	})
+}
+
+// Await returns a channel that receives the reply to req when this registry
+// processes it (see ProcessProtocolMessage). The channel is buffered and
+// receives at most one message. Timeouts are the mail orchestrator's job;
+// callers that stop waiting should call Forget.
+func (r *HandlerRegistry) Await(req *mail.PendingRequest) <-chan *mail.Message {
+	ch := make(chan *mail.Message, 1)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.waiters == nil {
+		r.waiters = make(map[*mail.PendingRequest]chan *mail.Message)
+	}
+	r.waiters[req] = ch
+	return ch
+}
+
+// Forget stops waiting for the reply to req.
+func (r *HandlerRegistry) Forget(req *mail.PendingRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.waiters, req)
+}
+
+// WaitReply waits up to timeout for the reply to req, reading unread mail
+// with list every interval and delivering replies to this registry's
+// waiters. Messages are left unread for their recipient. Returns nil if no
+// reply came in time; resending and escalating stay with the mail
+// orchestrator.
+func (r *HandlerRegistry) WaitReply(req *mail.PendingRequest, list func() ([]*mail.Message, error), timeout, interval time.Duration) (*mail.Message, error) {
+	ch := r.Await(req)
+	defer r.Forget(req)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		msgs, err := list()
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			r.deliverReply(msg)
+		}
+		select {
+		case reply := <-ch:
+			return reply, nil
+		default:
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, nil
+		}
+		time.Sleep(min(interval, remaining))
+	}
+}
+
+// deliverReply hands msg to the waiters whose requests it answers.
+// Returns true if any did.
+func (r *HandlerRegistry) deliverReply(msg *mail.Message) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivered := false
+	for req, ch := range r.waiters {
+		if req.IsAnsweredBy(msg) {
+			ch <- msg
+			delete(r.waiters, req)
+			delivered = true
+		}
+	}
+	return delivered
+}
+
 // Handle dispatches a message to the appropriate handler.
 // Returns an error if no handler is registered for the message type.
 func (r *HandlerRegistry) Handle(msg *mail.Message) error {
@@ -108,15 +212,19 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 }
 
 // ProcessProtocolMessage processes a protocol message using the registry.
+// Replies to awaited requests are first delivered to their waiters.
 // It returns (true, nil) if the message was handled successfully,
-// (true, error) if handling failed, or (false, nil) if not a protocol message.
+// (true, error) if handling failed, or (false, nil) if not a protocol message
+// or awaited reply.
 func (r *HandlerRegistry) ProcessProtocolMessage(msg *mail.Message) (bool, error) {
+	awaited := r.deliverReply(msg)
+
 	if !IsProtocolMessage(msg.Subject) {
-		return false, nil
+		return awaited, nil
 	}
 
 	if !r.CanHandle(msg) {
-		return false, nil
+		return awaited, nil
 	}
 
 	err := r.Handle(msg)
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -20,7 +21,7 @@ func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
 		Timestamp: time.Now(),
 	}
 
-	body := formatMergeReadyBody(payload)
+	body := encodeBody(mergeReadySchema, formatMergeReadyBody(payload), &payload)
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/witness", rig),
@@ -60,7 +61,7 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 		TargetBranch: targetBranch,
 	}
 
-	body := formatMergedBody(payload)
+	body := encodeBody(mergedSchema, formatMergedBody(payload), &payload)
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
@@ -103,7 +104,7 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		TargetBranch: targetBranch,
 	}
 
-	body := formatMergeFailedBody(payload)
+	body := encodeBody(mergeFailedSchema, formatMergeFailedBody(payload), &payload)
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
@@ -145,7 +146,7 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 		Instructions:  formatRebaseInstructions(targetBranch),
 	}
 
-	body := formatReworkRequestBody(payload)
+	body := encodeBody(reworkRequestSchema, formatReworkRequestBody(payload), &payload)
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
@@ -191,8 +192,57 @@ func formatRebaseInstructions(targetBranch string) string {
 The Refinery will retry the merge after rebase is complete.`, targetBranch, targetBranch)
 }
 
-// ParseMergeReadyPayload parses a MERGE_READY message body into a payload.
+// WithTrace adds the work's lifecycle trace ID (see gt trace) to a protocol
+// message as a "Trace:" line, and to its envelope's trace_id if it has one.
+// An empty traceID leaves the message as is. Returns msg for chaining.
+func WithTrace(msg *mail.Message, traceID string) *mail.Message {
+	if traceID == "" {
+		return msg
+	}
+
+	text, env, err := mail.SplitEnvelope(msg.Body)
+	if err != nil {
+		env = nil
+		text = msg.Body
+	}
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += fmt.Sprintf("Trace: %s\n", traceID)
+
+	if env != nil {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(env.Payload, &fields) == nil && fields != nil {
+			fields["trace_id"], _ = json.Marshal(traceID)
+			if payload, err := json.Marshal(fields); err == nil {
+				env.Payload = payload
+			}
+		}
+		if body, err := mail.JoinEnvelope(text, env); err == nil {
+			msg.Body = body
+			return msg
+		}
+	}
+	msg.Body = text
+	return msg
+}
+
+// ParseTraceID returns the trace ID of a protocol message body, if any.
+func ParseTraceID(body string) string {
+	return parseField(body, "Trace")
+}
+
+// ParseMergeReadyPayload parses a MERGE_READY message body into a payload, from its
+// envelope if it has a valid one and otherwise from the "Key: value" text.
 func ParseMergeReadyPayload(body string) *MergeReadyPayload {
+	if p, err := mergeReadySchema.Decode("", body); err == nil {
+		return p
+	}
+	return parseMergeReadyText(body)
+}
+
+// parseMergeReadyText parses the "Key: value" text of a MERGE_READY message body.
+func parseMergeReadyText(body string) *MergeReadyPayload {
 	return &MergeReadyPayload{
 		Branch:    parseField(body, "Branch"),
 		Issue:     parseField(body, "Issue"),
@@ -200,11 +250,21 @@ func ParseMergeReadyPayload(body string) *MergeReadyPayload {
 		Rig:       parseField(body, "Rig"),
 		Verified:  parseField(body, "Verified"),
 		Timestamp: time.Now(), // Use current time if not parseable
+		TraceID:   ParseTraceID(body),
 	}
 }
 
-// ParseMergedPayload parses a MERGED message body into a payload.
+// ParseMergedPayload parses a MERGED message body into a payload, from its
+// envelope if it has a valid one and otherwise from the "Key: value" text.
 func ParseMergedPayload(body string) *MergedPayload {
+	if p, err := mergedSchema.Decode("", body); err == nil {
+		return p
+	}
+	return parseMergedText(body)
+}
+
+// parseMergedText parses the "Key: value" text of a MERGED message body.
+func parseMergedText(body string) *MergedPayload {
 	payload := &MergedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -212,6 +272,7 @@ func ParseMergedPayload(body string) *MergedPayload {
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
 		MergeCommit:  parseField(body, "Merge-Commit"),
+		TraceID:      ParseTraceID(body),
 	}
 
 	// Parse timestamp
@@ -224,8 +285,17 @@ func ParseMergedPayload(body string) *MergedPayload {
 	return payload
 }
 
-// ParseMergeFailedPayload parses a MERGE_FAILED message body into a payload.
+// ParseMergeFailedPayload parses a MERGE_FAILED message body into a payload, from its
+// envelope if it has a valid one and otherwise from the "Key: value" text.
 func ParseMergeFailedPayload(body string) *MergeFailedPayload {
+	if p, err := mergeFailedSchema.Decode("", body); err == nil {
+		return p
+	}
+	return parseMergeFailedText(body)
+}
+
+// parseMergeFailedText parses the "Key: value" text of a MERGE_FAILED message body.
+func parseMergeFailedText(body string) *MergeFailedPayload {
 	payload := &MergeFailedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -234,6 +304,7 @@ func ParseMergeFailedPayload(body string) *MergeFailedPayload {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		TraceID:      ParseTraceID(body),
 	}
 
 	// Parse timestamp
@@ -246,14 +317,24 @@ func ParseMergeFailedPayload(body string) *MergeFailedPayload {
 	return payload
 }
 
-// ParseReworkRequestPayload parses a REWORK_REQUEST message body into a payload.
+// ParseReworkRequestPayload parses a REWORK_REQUEST message body into a payload, from its
+// envelope if it has a valid one and otherwise from the "Key: value" text.
 func ParseReworkRequestPayload(body string) *ReworkRequestPayload {
+	if p, err := reworkRequestSchema.Decode("", body); err == nil {
+		return p
+	}
+	return parseReworkRequestText(body)
+}
+
+// parseReworkRequestText parses the "Key: value" text of a REWORK_REQUEST message body.
+func parseReworkRequestText(body string) *ReworkRequestPayload {
 	payload := &ReworkRequestPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
 		Polecat:      parseField(body, "Polecat"),
 		Rig:          parseField(body, "Rig"),
 		TargetBranch: parseField(body, "Target"),
+		TraceID:      ParseTraceID(body),
 	}
 
 	// Parse timestamp
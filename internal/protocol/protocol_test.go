@@ -151,6 +151,78 @@ func TestNewReworkRequestMessage(t *testing.T) {
 	}
 }
 
+func TestWithTrace(t *testing.T) {
+	msg := WithTrace(NewMergeFailedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "tests", "Test failed"),
+		"4bf92f3577b34da6a3ce929d0e0e4736")
+
+	if !strings.Contains(msg.Body, "\nTrace: 4bf92f3577b34da6a3ce929d0e0e4736\n") {
+		t.Errorf("Body missing trace: %s", msg.Body)
+	}
+	payload := ParseMergeFailedPayload(msg.Body)
+	if payload.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q, want the trace", payload.TraceID)
+	}
+	if payload.Error != "Test failed" {
+		t.Errorf("Error = %q, want %q", payload.Error, "Test failed")
+	}
+
+	untraced := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+	body := untraced.Body
+	if WithTrace(untraced, "").Body != body {
+		t.Error("WithTrace with empty trace changed the body")
+	}
+	if got := ParseTraceID(body); got != "" {
+		t.Errorf("ParseTraceID(untraced) = %q, want empty", got)
+	}
+}
+
+func TestProtocolEnvelope(t *testing.T) {
+	msg := WithTrace(NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123"),
+		"4bf92f3577b34da6a3ce929d0e0e4736")
+
+	_, env, err := mail.SplitEnvelope(msg.Body)
+	if err != nil || env == nil {
+		t.Fatalf("MERGED body has no envelope (%v): %s", err, msg.Body)
+	}
+	if env.Type != "MERGED" || env.Version != 1 {
+		t.Errorf("envelope = %s v%d", env.Type, env.Version)
+	}
+
+	// Drifted text doesn't matter when the envelope is there
+	drifted := strings.Replace(msg.Body, "Merge-Commit: abc123", "merge commit was abc123", 1)
+	payload := ParseMergedPayload(drifted)
+	if payload.MergeCommit != "abc123" || payload.TargetBranch != "main" || payload.MergedAt.IsZero() {
+		t.Errorf("payload = %+v", payload)
+	}
+	if payload.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q, want the trace from the envelope", payload.TraceID)
+	}
+
+	if err := mail.ValidateProtocolBody(msg.Subject, msg.Body); err != nil {
+		t.Errorf("ValidateProtocolBody: %v", err)
+	}
+}
+
+func TestProtocolSendValidation(t *testing.T) {
+	tests := []struct {
+		subject, body string
+		wantErr       bool
+	}{
+		{"MERGE_READY nux", NewMergeReadyMessage("gastown", "nux", "polecat/nux", "gt-abc").Body, false},
+		{"MERGE_READY nux", "Branch: polecat/nux\nRig: gastown", false}, // Polecat from subject
+		{"MERGE_READY nux", "Ready to merge!", true},
+		{"MERGE_FAILED nux", "Branch: polecat/nux\nError: tests", false},
+		{"REWORK_REQUEST nux", "Please rebase", true},
+		{"MERGED nux", "```gt-envelope\n{\"type\": \"MERGED\", \"version\": 9, \"payload\": {}}\n```", true},
+	}
+	for _, tt := range tests {
+		err := mail.ValidateProtocolBody(tt.subject, tt.body)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s %q: err = %v, wantErr %v", tt.subject, tt.body, err, tt.wantErr)
+		}
+	}
+}
+
 func TestParseMergeReadyPayload(t *testing.T) {
 	body := `Branch: polecat/nux/gt-abc
 Issue: gt-abc
@@ -227,6 +299,129 @@ func TestHandlerRegistry(t *testing.T) {
 	}
 }
 
+func TestAwaitableHandlers(t *testing.T) {
+	// Refinery side: the reply is correlated and sent back
+	refinery := NewHandlerRegistry()
+	var sent []*mail.Message
+	refinery.SetReplySender(func(msg *mail.Message) error {
+		sent = append(sent, msg)
+		return nil
+	})
+	refinery.RegisterAwaitable(TypeMergeReady, func(msg *mail.Message) (*mail.Message, error) {
+		return NewMergedMessage("gastown", ExtractPolecat(msg.Subject), "polecat/nux", "gt-abc", "main", "abc123"), nil
+	})
+
+	request := NewMergeReadyMessage("gastown", "nux", "polecat/nux", "gt-abc")
+	request.CorrelationID = "req-1"
+	if handled, err := refinery.ProcessProtocolMessage(request); !handled || err != nil {
+		t.Fatalf("ProcessProtocolMessage = %v, %v", handled, err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d replies, want 1", len(sent))
+	}
+	reply := sent[0]
+	if reply.CorrelationID != "req-1" || reply.ThreadID != request.ThreadID || reply.To != "gastown/witness" {
+		t.Errorf("reply not correlated: %+v", reply)
+	}
+
+	// Witness side: the awaited reply is delivered
+	witness := NewHandlerRegistry()
+	pending := &mail.PendingRequest{
+		CorrelationID: "req-1",
+		ThreadID:      request.ThreadID,
+		ReplySubjects: ReplySubjects(TypeMergeReady, "nux"),
+		Message:       request,
+	}
+	ch := witness.Await(pending)
+	if handled, err := witness.ProcessProtocolMessage(reply); !handled || err != nil {
+		t.Fatalf("awaited reply: ProcessProtocolMessage = %v, %v", handled, err)
+	}
+	select {
+	case got := <-ch:
+		if got != reply {
+			t.Errorf("awaited %+v, want the reply", got)
+		}
+	default:
+		t.Fatal("awaited reply not delivered")
+	}
+
+	// An uncorrelated MERGE_FAILED from the Refinery also answers by subject
+	ch = witness.Await(pending)
+	failed := NewMergeFailedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "tests", "boom")
+	if handled, _ := witness.ProcessProtocolMessage(failed); !handled {
+		t.Error("MERGE_FAILED not delivered to waiter")
+	}
+	if len(ch) != 1 {
+		t.Error("waiter did not receive MERGE_FAILED")
+	}
+
+	// Without a reply sender, awaitable handlers fail rather than drop replies
+	orphan := NewHandlerRegistry()
+	orphan.RegisterAwaitable(TypeMergeReady, func(*mail.Message) (*mail.Message, error) {
+		return &mail.Message{Subject: "ack"}, nil
+	})
+	if err := orphan.Handle(request); err == nil {
+		t.Error("expected error without a reply sender")
+	}
+}
+
+func TestWaitReply(t *testing.T) {
+	request := NewMergeReadyMessage("gastown", "nux", "polecat/nux", "gt-abc")
+	pending := &mail.PendingRequest{
+		CorrelationID: "req-1",
+		ThreadID:      request.ThreadID,
+		ReplySubjects: ReplySubjects(TypeMergeReady, "nux"),
+		Message:       request,
+	}
+	other := NewMergedMessage("gastown", "toast", "polecat/toast", "gt-def", "main", "def456")
+	merged := NewMergedMessage("gastown", "nux", "polecat/nux", "gt-abc", "main", "abc123")
+
+	// The reply turns up on the second read of the inbox
+	polls := 0
+	list := func() ([]*mail.Message, error) {
+		polls++
+		if polls < 2 {
+			return []*mail.Message{other}, nil
+		}
+		return []*mail.Message{other, merged}, nil
+	}
+	registry := NewHandlerRegistry()
+	reply, err := registry.WaitReply(pending, list, time.Second, time.Millisecond)
+	if err != nil || reply != merged {
+		t.Fatalf("WaitReply = %+v, %v; want the MERGED reply", reply, err)
+	}
+	if len(registry.waiters) != 0 {
+		t.Error("waiter not forgotten after the reply")
+	}
+
+	// No reply in time
+	reply, err = registry.WaitReply(pending, func() ([]*mail.Message, error) {
+		return []*mail.Message{other}, nil
+	}, 5*time.Millisecond, time.Millisecond)
+	if err != nil || reply != nil {
+		t.Errorf("WaitReply without reply = %+v, %v; want nil, nil", reply, err)
+	}
+	if len(registry.waiters) != 0 {
+		t.Error("waiter not forgotten after timing out")
+	}
+}
+
+func TestReplySubjects(t *testing.T) {
+	got := ReplySubjects(TypeMergeReady, "nux")
+	want := []string{"MERGED nux", "MERGE_FAILED nux", "REWORK_REQUEST nux"}
+	if len(got) != len(want) {
+		t.Fatalf("ReplySubjects = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ReplySubjects[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if ReplySubjects(TypeMerged, "nux") != nil {
+		t.Error("MERGED is not a request")
+	}
+}
+
 func TestWrapWitnessHandlers(t *testing.T) {
 	handler := &mockWitnessHandler{}
 	registry := WrapWitnessHandlers(handler)
@@ -69,23 +69,24 @@ func (h *DefaultRefineryHandler) HandleMergeReady(payload *MergeReadyPayload) er
 
 // SendMerged sends a MERGED message to the Witness.
 // Called by the Refinery after successfully merging a branch.
-func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch, mergeCommit string) error {
+// traceID is the work's lifecycle trace, if known.
+func (h *DefaultRefineryHandler) SendMerged(polecat, branch, issue, targetBranch, mergeCommit, traceID string) error {
 	msg := NewMergedMessage(h.Rig, polecat, branch, issue, targetBranch, mergeCommit)
-	return h.Router.Send(msg)
+	return h.Router.Send(WithTrace(msg, traceID))
 }
 
 // SendMergeFailed sends a MERGE_FAILED message to the Witness.
 // Called by the Refinery when a merge fails.
-func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg string) error {
+func (h *DefaultRefineryHandler) SendMergeFailed(polecat, branch, issue, targetBranch, failureType, errorMsg, traceID string) error {
 	msg := NewMergeFailedMessage(h.Rig, polecat, branch, issue, targetBranch, failureType, errorMsg)
-	return h.Router.Send(msg)
+	return h.Router.Send(WithTrace(msg, traceID))
 }
 
 // SendReworkRequest sends a REWORK_REQUEST message to the Witness.
 // Called by the Refinery when a branch has conflicts.
-func (h *DefaultRefineryHandler) SendReworkRequest(polecat, branch, issue, targetBranch string, conflictFiles []string) error {
+func (h *DefaultRefineryHandler) SendReworkRequest(polecat, branch, issue, targetBranch string, conflictFiles []string, traceID string) error {
 	msg := NewReworkRequestMessage(h.Rig, polecat, branch, issue, targetBranch, conflictFiles)
-	return h.Router.Send(msg)
+	return h.Router.Send(WithTrace(msg, traceID))
 }
 
 // NotifyMergeOutcome is a convenience method that sends the appropriate message
@@ -108,19 +109,22 @@ type MergeOutcome struct {
 
 	// ConflictFiles lists files with conflicts (if Conflict is true).
 	ConflictFiles []string
+
+	// TraceID is the work's lifecycle trace (see gt trace), if known.
+	TraceID string
 }
 
 // NotifyMergeOutcome sends the appropriate protocol message based on the outcome.
 func (h *DefaultRefineryHandler) NotifyMergeOutcome(polecat, branch, issue, targetBranch string, outcome MergeOutcome) error {
 	if outcome.Success {
-		return h.SendMerged(polecat, branch, issue, targetBranch, outcome.MergeCommit)
+		return h.SendMerged(polecat, branch, issue, targetBranch, outcome.MergeCommit, outcome.TraceID)
 	}
 
 	if outcome.Conflict {
-		return h.SendReworkRequest(polecat, branch, issue, targetBranch, outcome.ConflictFiles)
+		return h.SendReworkRequest(polecat, branch, issue, targetBranch, outcome.ConflictFiles, outcome.TraceID)
 	}
 
-	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error)
+	return h.SendMergeFailed(polecat, branch, issue, targetBranch, outcome.FailureType, outcome.Error, outcome.TraceID)
 }
 
 // Ensure DefaultRefineryHandler implements RefineryHandler.
@@ -0,0 +1,95 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Payload schemas for the Witness-Refinery protocol. Messages carry their
+// payload in a mail envelope after the "Key: value" text, which is kept for
+// readers and as the fallback for messages without one. Registering the
+// schemas makes mail.Router.Send reject malformed protocol messages.
+var (
+	mergeReadySchema = mail.RegisterSchema(&mail.Schema[MergeReadyPayload]{
+		Type:    string(TypeMergeReady),
+		Version: 1,
+		Legacy: func(subject, body string) (*MergeReadyPayload, error) {
+			p := parseMergeReadyText(body)
+			p.Polecat = polecatOr(p.Polecat, subject)
+			return p, nil
+		},
+		Validate: func(p *MergeReadyPayload) error {
+			return requireFields("branch", p.Branch, "polecat", p.Polecat, "rig", p.Rig)
+		},
+	})
+
+	mergedSchema = mail.RegisterSchema(&mail.Schema[MergedPayload]{
+		Type:    string(TypeMerged),
+		Version: 1,
+		Legacy: func(subject, body string) (*MergedPayload, error) {
+			p := parseMergedText(body)
+			p.Polecat = polecatOr(p.Polecat, subject)
+			return p, nil
+		},
+		Validate: func(p *MergedPayload) error {
+			return requireFields("branch", p.Branch, "polecat", p.Polecat)
+		},
+	})
+
+	mergeFailedSchema = mail.RegisterSchema(&mail.Schema[MergeFailedPayload]{
+		Type:    string(TypeMergeFailed),
+		Version: 1,
+		Legacy: func(subject, body string) (*MergeFailedPayload, error) {
+			p := parseMergeFailedText(body)
+			p.Polecat = polecatOr(p.Polecat, subject)
+			return p, nil
+		},
+		Validate: func(p *MergeFailedPayload) error {
+			return requireFields("branch", p.Branch, "polecat", p.Polecat)
+		},
+	})
+
+	reworkRequestSchema = mail.RegisterSchema(&mail.Schema[ReworkRequestPayload]{
+		Type:    string(TypeReworkRequest),
+		Version: 1,
+		Legacy: func(subject, body string) (*ReworkRequestPayload, error) {
+			p := parseReworkRequestText(body)
+			p.Polecat = polecatOr(p.Polecat, subject)
+			return p, nil
+		},
+		Validate: func(p *ReworkRequestPayload) error {
+			return requireFields("branch", p.Branch, "polecat", p.Polecat)
+		},
+	})
+)
+
+// encodeBody returns text followed by p's envelope. Payloads are plain
+// structs, so encoding can't fail in practice; if it does the text alone is
+// still a valid legacy body.
+func encodeBody[T any](s *mail.Schema[T], text string, p *T) string {
+	body, err := s.Encode(text, p)
+	if err != nil {
+		return text
+	}
+	return body
+}
+
+// polecatOr returns polecat, or the polecat named in subject if it's empty.
+func polecatOr(polecat, subject string) string {
+	if polecat != "" {
+		return polecat
+	}
+	return ExtractPolecat(subject)
+}
+
+// requireFields returns an error naming the first empty field.
+// Arguments alternate between field names and values.
+func requireFields(nameValues ...string) error {
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		if nameValues[i+1] == "" {
+			return fmt.Errorf("missing %s", nameValues[i])
+		}
+	}
+	return nil
+}
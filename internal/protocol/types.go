@@ -8,9 +8,22 @@
 //   - MERGED: Refinery → Witness (merge succeeded, cleanup ok)
 //   - MERGE_FAILED: Refinery → Witness (merge failed, needs rework)
 //   - REWORK_REQUEST: Refinery → Witness (rebase needed)
+//
+// MERGE_READY is a request: the Witness sends it with gt witness merge-ready
+// (see DefaultWitnessHandler.SendMergeReady) and any of MERGED, MERGE_FAILED
+// or REWORK_REQUEST from the Refinery answers it. The mail
+// orchestrator resends or escalates it if no answer comes in time.
+// HandlerRegistry.RegisterAwaitable sends a handler's reply correlated with
+// the request, and Await or WaitReply let the requester block on the answer
+// (see gt witness merge-ready --wait).
+//
+// Message bodies carry "Key: value" text followed by a versioned JSON
+// envelope (see mail.Envelope). Parsers prefer the envelope and fall back to
+// the text; mail.Router.Send rejects bodies that fail either.
 package protocol
 
 import (
+	"fmt"
 	"strings"
 	"time"
 )
@@ -40,6 +53,30 @@ const (
 	TypeReworkRequest MessageType = "REWORK_REQUEST"
 )
 
+// MergeReadyTimeout is how long the Witness waits for the Refinery to answer
+// a MERGE_READY before it is resent, and then escalated.
+const MergeReadyTimeout = 2 * time.Hour
+
+// MergeOutcomePollInterval is how often AwaitMergeOutcome checks the
+// witness inbox.
+const MergeOutcomePollInterval = 5 * time.Second
+
+// replyTypes lists the message types that answer each request type.
+var replyTypes = map[MessageType][]MessageType{
+	TypeMergeReady: {TypeMerged, TypeMergeFailed, TypeReworkRequest},
+}
+
+// ReplySubjects returns the subjects that answer a request of msgType about
+// polecat, for matching replies that aren't threaded (see
+// mail.Router.SendRequest). Returns nil if msgType isn't a request.
+func ReplySubjects(msgType MessageType, polecat string) []string {
+	var subjects []string
+	for _, t := range replyTypes[msgType] {
+		subjects = append(subjects, fmt.Sprintf("%s %s", t, polecat))
+	}
+	return subjects
+}
+
 // ParseMessageType extracts the protocol message type from a mail subject.
 // Returns empty string if subject doesn't match a known protocol type.
 func ParseMessageType(subject string) MessageType {
@@ -82,6 +119,9 @@ type MergeReadyPayload struct {
 
 	// Timestamp is when the message was created.
 	Timestamp time.Time `json:"timestamp"`
+
+	// TraceID is the work's lifecycle trace (see gt trace), if known.
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // MergedPayload contains the data for a MERGED message.
@@ -107,6 +147,9 @@ type MergedPayload struct {
 
 	// TargetBranch is the branch merged into (e.g., "main").
 	TargetBranch string `json:"target_branch"`
+
+	// TraceID is the work's lifecycle trace (see gt trace), if known.
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // MergeFailedPayload contains the data for a MERGE_FAILED message.
@@ -135,6 +178,9 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// TraceID is the work's lifecycle trace (see gt trace), if known.
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -163,6 +209,9 @@ type ReworkRequestPayload struct {
 
 	// Instructions provides specific rebase instructions.
 	Instructions string `json:"instructions,omitempty"`
+
+	// TraceID is the work's lifecycle trace (see gt trace), if known.
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 // IsProtocolMessage returns true if the subject matches a known protocol type.
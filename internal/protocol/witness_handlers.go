@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -214,5 +215,26 @@ Then run 'gt done' to resubmit for merge.`,
 	return h.Router.Send(msg)
 }
 
+// SendMergeReady sends a MERGE_READY request to the Refinery.
+// The returned request is settled when the Refinery sends MERGED,
+// MERGE_FAILED or REWORK_REQUEST for the polecat; until then the mail
+// orchestrator resends it every MergeReadyTimeout and finally escalates.
+func (h *DefaultWitnessHandler) SendMergeReady(polecat, branch, issue string) (*mail.PendingRequest, error) {
+	msg := NewMergeReadyMessage(h.Rig, polecat, branch, issue)
+	return h.Router.SendRequest(msg, MergeReadyTimeout, ReplySubjects(TypeMergeReady, polecat)...)
+}
+
+// AwaitMergeOutcome waits up to timeout for the Refinery's answer to a
+// MERGE_READY request sent with SendMergeReady, checking the witness inbox
+// every MergeOutcomePollInterval. The answer stays in the inbox for the
+// witness to handle. Returns nil if none arrived in time.
+func (h *DefaultWitnessHandler) AwaitMergeOutcome(req *mail.PendingRequest, timeout time.Duration) (*mail.Message, error) {
+	mailbox, err := h.Router.GetMailbox(h.Rig + "/witness")
+	if err != nil {
+		return nil, fmt.Errorf("getting witness mailbox: %w", err)
+	}
+	return NewHandlerRegistry().WaitReply(req, mailbox.ListUnread, timeout, MergeOutcomePollInterval)
+}
+
 // Ensure DefaultWitnessHandler implements WitnessHandler.
 var _ WitnessHandler = (*DefaultWitnessHandler)(nil)
@@ -11,11 +11,13 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/protocol"
@@ -53,6 +55,10 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// BatchSize is how many MRs ride in one speculative merge train.
+	// Values <= 1 disable batching.
+	BatchSize int `json:"batch_size"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -68,6 +74,7 @@ func DefaultMergeQueueConfig() *MergeQueueConfig {
 		RetryFlakyTests:      1,
 		PollInterval:         30 * time.Second,
 		MaxConcurrent:        1,
+		BatchSize:            1,
 	}
 }
 
@@ -88,6 +95,7 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	TraceID         string     // Lifecycle trace of the source issue (see gt trace)
 }
 
 // Engineer is the merge queue processor that polls for ready merge-requests
@@ -98,9 +106,14 @@ type Engineer struct {
 	git     *git.Git
 	config  *MergeQueueConfig
 	workDir string
-	output  io.Writer    // Output destination for user-facing messages
+	output  io.Writer    // Output destination for user-facing messages; safe for concurrent use
 	router  *mail.Router // Mail router for sending protocol messages
 
+	// mergeMu serializes landing on the target branch; worktreeMu serializes
+	// scratch worktree creation and removal (both write shared git metadata).
+	mergeMu    sync.Mutex
+	worktreeMu sync.Mutex
+
 	// stopCh is used for graceful shutdown
 	stopCh chan struct{}
 }
@@ -125,16 +138,17 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		git:     git.NewGit(gitDir),
 		config:  cfg,
 		workDir: gitDir,
-		output:  os.Stdout,
+		output:  &syncWriter{w: os.Stdout},
 		router:  mail.NewRouter(r.Path),
 		stopCh:  make(chan struct{}),
 	}
 }
 
 // SetOutput sets the output writer for user-facing messages.
-// This is useful for testing or redirecting output.
+// This is useful for testing or redirecting output. Call it before
+// processing starts; writes are serialized so parallel MRs can share it.
 func (e *Engineer) SetOutput(w io.Writer) {
-	e.output = w
+	e.output = &syncWriter{w: w}
 }
 
 // LoadConfig loads merge queue configuration from the rig's config.json.
@@ -175,6 +189,7 @@ func (e *Engineer) LoadConfig() error {
 		RetryFlakyTests      *int    `json:"retry_flaky_tests"`
 		PollInterval         *string `json:"poll_interval"`
 		MaxConcurrent        *int    `json:"max_concurrent"`
+		BatchSize            *int    `json:"batch_size"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -209,6 +224,9 @@ func (e *Engineer) LoadConfig() error {
 	if mqRaw.MaxConcurrent != nil {
 		e.config.MaxConcurrent = *mqRaw.MaxConcurrent
 	}
+	if mqRaw.BatchSize != nil {
+		e.config.BatchSize = *mqRaw.BatchSize
+	}
 	if mqRaw.PollInterval != nil {
 		dur, err := time.ParseDuration(*mqRaw.PollInterval)
 		if err != nil {
@@ -251,6 +269,7 @@ func (e *Engineer) ProcessMR(ctx context.Context, mr *beads.Issue) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Target: %s\n", mrFields.Target)
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mrFields.Worker)
 
+	e.logMergeEvent(events.TypeMergeStarted, mr.ID, mrFields.Worker, mrFields.Branch, mrFields.SourceIssue, mrFields.TraceID, "")
 	return e.doMerge(ctx, mrFields.Branch, mrFields.Target, mrFields.SourceIssue)
 }
 
@@ -331,18 +350,15 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
 	}
 
+	// Steps 5-7: squash merge, record the commit, push
+	return e.landSquash(branch, target, sourceIssue)
+}
+
+// landSquash squash merges branch into the checked-out target in the
+// refinery worktree and pushes the result to origin.
+func (e *Engineer) landSquash(branch, target, sourceIssue string) ProcessResult {
 	// Step 5: Perform the actual merge using squash merge
-	// Get the original commit message from the polecat branch to preserve the
-	// conventional commit format (feat:/fix:) instead of creating redundant merge commits
-	originalMsg, err := e.git.GetBranchCommitMessage(branch)
-	if err != nil {
-		// Fallback to a descriptive message if we can't get the original
-		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
-		if sourceIssue != "" {
-			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
-		}
-		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
-	}
+	originalMsg := e.squashMessage(branch, target, sourceIssue)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Squash merging with message: %s\n", strings.TrimSpace(originalMsg))
 	if err := e.git.MergeSquash(branch, originalMsg); err != nil {
 		// ZFC: Use git's porcelain output to detect conflicts instead of parsing stderr.
@@ -392,8 +408,29 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 	}
 }
 
+// squashMessage returns the commit message for squash merging branch.
+// The original commit message from the polecat branch is preserved to keep the
+// conventional commit format (feat:/fix:) instead of creating redundant merge commits.
+func (e *Engineer) squashMessage(branch, target, sourceIssue string) string {
+	originalMsg, err := e.git.GetBranchCommitMessage(branch)
+	if err != nil {
+		// Fallback to a descriptive message if we can't get the original
+		originalMsg = fmt.Sprintf("Squash merge %s into %s", branch, target)
+		if sourceIssue != "" {
+			originalMsg = fmt.Sprintf("Squash merge %s into %s (%s)", branch, target, sourceIssue)
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not get original commit message: %v\n", err)
+	}
+	return originalMsg
+}
+
 // runTests runs the configured test command and returns the result.
 func (e *Engineer) runTests(ctx context.Context) ProcessResult {
+	return e.runTestsIn(ctx, e.workDir)
+}
+
+// runTestsIn runs the configured test command in dir.
+func (e *Engineer) runTestsIn(ctx context.Context, dir string) ProcessResult {
 	if e.config.TestCommand == "" {
 		return ProcessResult{Success: true}
 	}
@@ -413,7 +450,7 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		// Note: TestCommand comes from rig's config.json (trusted infrastructure config),
 		// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
 		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
-		cmd.Dir = e.workDir
+		cmd.Dir = dir
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
@@ -507,7 +544,9 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 		}
 	}
 
-	// 5. Log success
+	// 5. Notify Witness and log success
+	e.notifyMerged(mrFields.Worker, mrFields.Branch, mrFields.SourceIssue, mrFields.Target, result.MergeCommit, mrFields.TraceID)
+	e.logMergeEvent(events.TypeMerged, mr.ID, mrFields.Worker, mrFields.Branch, mrFields.SourceIssue, mrFields.TraceID, "")
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
@@ -521,6 +560,11 @@ func (e *Engineer) handleFailure(mr *beads.Issue, result ProcessResult) {
 	}
 
 	// Log the failure
+	mrFields := beads.ParseMRFields(mr)
+	if mrFields == nil {
+		mrFields = &beads.MRFields{}
+	}
+	e.logMergeEvent(events.TypeMergeFailed, mr.ID, mrFields.Worker, mrFields.Branch, mrFields.SourceIssue, mrFields.TraceID, result.Error)
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ Failed: %s - %s\n", mr.ID, result.Error)
 }
 
@@ -533,6 +577,8 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	_, _ = fmt.Fprintf(e.output, "  Worker: %s\n", mr.Worker)
 	_, _ = fmt.Fprintf(e.output, "  Source: %s\n", mr.SourceIssue)
 
+	e.logMergeEvent(events.TypeMergeStarted, mr.ID, mr.Worker, mr.Branch, mr.SourceIssue, mr.TraceID, "")
+
 	// Use the shared merge logic
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue)
 }
@@ -613,7 +659,9 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
-	// 3. Log success
+	// 3. Notify Witness and log success
+	e.notifyMerged(mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, result.MergeCommit, mr.TraceID)
+	e.logMergeEvent(events.TypeMerged, mr.ID, mr.Worker, mr.Branch, mr.SourceIssue, mr.TraceID, "")
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
@@ -629,7 +677,8 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
+	e.logMergeEvent(events.TypeMergeFailed, mr.ID, mr.Worker, mr.Branch, mr.SourceIssue, mr.TraceID, failureType+": "+result.Error)
+	msg := protocol.WithTrace(protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error), mr.TraceID)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
 	} else {
@@ -662,6 +711,32 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// notifyMerged sends MERGED to the Witness so it can clean up the polecat.
+// The message carries the work's trace so gt trace can follow it.
+func (e *Engineer) notifyMerged(worker, branch, sourceIssue, target, mergeCommit, traceID string) {
+	if worker == "" {
+		return
+	}
+	msg := protocol.WithTrace(protocol.NewMergedMessage(e.rig.Name, worker, branch, sourceIssue, target, mergeCommit), traceID)
+	if err := e.router.Send(msg); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGED to witness: %v\n", err)
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Notified witness of merge for %s\n", worker)
+	}
+}
+
+// logMergeEvent records a merge queue event for the activity feed. The
+// source issue and trace let gt trace place the merge in the issue's
+// lifecycle.
+func (e *Engineer) logMergeEvent(eventType, mrID, worker, branch, sourceIssue, traceID, reason string) {
+	payload := events.MergePayload(mrID, worker, branch, reason)
+	payload["rig"] = e.rig.Name
+	if sourceIssue != "" {
+		payload["bead"] = sourceIssue
+	}
+	_ = events.LogFeed(eventType, e.rig.Name+"/refinery", events.WithTrace(payload, traceID))
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -854,6 +929,7 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			ConvoyID:        fields.ConvoyID,
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
+			TraceID:         fields.TraceID,
 		}
 		mrs = append(mrs, mr)
 	}
@@ -946,6 +1022,7 @@ func (e *Engineer) ListBlockedMRs() ([]*MRInfo, error) {
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
 			BlockedBy:       blockedBy,
+			TraceID:         fields.TraceID,
 		}
 		mrs = append(mrs, mr)
 	}
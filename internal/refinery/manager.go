@@ -1,9 +1,11 @@
 package refinery
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/errors"
 	"github.com/steveyegge/gastown/internal/events"
@@ -62,15 +65,38 @@ func (m *Manager) SessionName() string {
 // IsRunning checks if the refinery session is active.
 // ZFC: tmux session existence is the source of truth.
 func (m *Manager) IsRunning() (bool, error) {
+	if conn := m.remote(); conn != nil {
+		return conn.TmuxHasSession(m.SessionName())
+	}
 	t := tmux.NewTmux()
 	return t.HasSession(m.SessionName())
 }
 
+// remote returns the rig's connection if the rig lives on another machine,
+// or nil for local rigs.
+func (m *Manager) remote() connection.Connection {
+	if m.rig.IsRemote() {
+		return m.rig.Connection()
+	}
+	return nil
+}
+
 // Status returns information about the refinery session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	if conn := m.remote(); conn != nil {
+		running, err := conn.TmuxHasSession(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("checking session on %s: %w", conn.Name(), err)
+		}
+		if !running {
+			return nil, ErrNotRunning
+		}
+		return &tmux.SessionInfo{Name: sessionID}, nil
+	}
+
+	t := tmux.NewTmux()
 
 	running, err := t.HasSession(sessionID)
 	if err != nil {
@@ -96,6 +122,9 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 		// Foreground mode is deprecated - the Refinery agent handles merge processing
 		return fmt.Errorf("foreground mode is deprecated; use background mode (remove --foreground flag)")
 	}
+	if m.rig.IsRemote() {
+		return m.startRemote(agentOverride)
+	}
 
 	// Check if session already exists
 	running, _ := t.HasSession(sessionID)
@@ -200,11 +229,59 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	return nil
 }
 
+// startRemote starts the refinery on a remote rig's machine. Environment
+// is baked into the startup command and local-only steps (settings,
+// theme, readiness probes) are skipped, as for remote polecats.
+func (m *Manager) startRemote(agentOverride string) error {
+	conn := m.rig.Connection()
+	sessionID := m.SessionName()
+
+	if running, err := conn.TmuxHasSession(sessionID); err != nil {
+		return fmt.Errorf("checking session on %s: %w", conn.Name(), err)
+	} else if running {
+		return ErrAlreadyRunning
+	}
+
+	initialPrompt := session.BuildStartupPrompt(session.BeaconConfig{
+		Recipient: fmt.Sprintf("%s/refinery", m.rig.Name),
+		Sender:    "deacon",
+		Topic:     "patrol",
+	}, "Check your hook and begin patrol.")
+
+	envVars := config.AgentEnv(config.AgentEnvConfig{
+		Role:          "refinery",
+		Rig:           m.rig.Name,
+		TownRoot:      path.Dir(m.rig.RemotePath),
+		BeadsNoDaemon: true,
+	})
+	envVars["GT_REFINERY"] = "1"
+
+	// Runtime config is resolved from the local rig; GT_ROOT points remote.
+	command := config.BuildStartupCommand(envVars, m.rig.Path, initialPrompt)
+	if agentOverride != "" {
+		var err error
+		command, err = config.BuildStartupCommandWithAgentOverride(envVars, m.rig.Path, initialPrompt, agentOverride)
+		if err != nil {
+			return fmt.Errorf("building startup command with agent override: %w", err)
+		}
+	}
+
+	workDir := m.rig.RemoteDir(path.Join("refinery", "rig"), path.Join("mayor", "rig"))
+	return m.rig.StartRemoteSession(sessionID, workDir, command)
+}
+
 // Stop stops the refinery.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	if conn := m.remote(); conn != nil {
+		if running, _ := conn.TmuxHasSession(sessionID); !running {
+			return ErrNotRunning
+		}
+		return conn.TmuxKillSession(sessionID)
+	}
+
+	t := tmux.NewTmux()
 
 	// Check if tmux session exists
 	running, _ := t.HasSession(sessionID)
@@ -370,6 +447,8 @@ type MergeResult struct {
 // This function is kept for backwards compatibility but always returns an error
 // indicating that the agent should handle merge processing.
 //
+// For batched merging driven from Go, see ProcessBatch.
+//
 // Deprecated: Use the Refinery agent (Claude) for merge processing.
 func (m *Manager) ProcessMR(mr *MergeRequest) MergeResult {
 	return MergeResult{
@@ -377,6 +456,85 @@ func (m *Manager) ProcessMR(mr *MergeRequest) MergeResult {
 	}
 }
 
+// ProcessBatch merges the next ready MRs as one speculative train.
+//
+// Up to batchSize ready MRs (unclaimed and unblocked) are taken in Queue score
+// order, claimed for workerID so a concurrent refinery skips them, and handed
+// to Engineer.ProcessTrain, which tests the stacked batch once and bisects on
+// failure. Claims on MRs that don't land are released. A batchSize <= 0 uses
+// merge_queue.batch_size from the rig config. Returns one result per MR in the
+// batch, in queue order.
+func (m *Manager) ProcessBatch(ctx context.Context, batchSize int, workerID string) ([]*MergeRequest, []MergeResult, error) {
+	eng := NewEngineer(m.rig)
+	eng.SetOutput(m.output)
+	if err := eng.LoadConfig(); err != nil {
+		return nil, nil, fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if batchSize <= 0 {
+		batchSize = eng.Config().BatchSize
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	ready, err := eng.ListReadyMRs()
+	if err != nil {
+		return nil, nil, err
+	}
+	readyIDs := make(map[string]bool, len(ready))
+	for _, mr := range ready {
+		readyIDs[mr.ID] = true
+	}
+
+	queue, err := m.Queue()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var batch []*MergeRequest
+	var issues []*beads.Issue
+	for _, item := range queue {
+		if len(batch) >= batchSize {
+			break
+		}
+		if !readyIDs[item.MR.ID] {
+			continue
+		}
+		if err := eng.ClaimMR(item.MR.ID, workerID); err != nil {
+			_, _ = fmt.Fprintf(m.output, "Warning: could not claim %s: %v\n", item.MR.ID, err)
+			continue
+		}
+		issue, err := eng.beads.Show(item.MR.ID)
+		if err != nil {
+			_, _ = fmt.Fprintf(m.output, "Warning: skipping %s: %v\n", item.MR.ID, err)
+			m.releaseClaim(eng, item.MR.ID)
+			continue
+		}
+		batch = append(batch, item.MR)
+		issues = append(issues, issue)
+	}
+	if len(batch) == 0 {
+		return nil, nil, ErrNoQueue
+	}
+
+	processed := eng.ProcessTrain(ctx, issues)
+	results := make([]MergeResult, len(processed))
+	for i, r := range processed {
+		results[i] = MergeResult(r)
+		if !r.Success {
+			m.releaseClaim(eng, batch[i].ID)
+		}
+	}
+	return batch, results, nil
+}
+
+// releaseClaim returns a claimed MR to the queue (best-effort).
+func (m *Manager) releaseClaim(eng *Engineer, mrID string) {
+	if err := eng.ReleaseMR(mrID); err != nil {
+		_, _ = fmt.Fprintf(m.output, "Warning: could not release %s: %v\n", mrID, err)
+	}
+}
+
 // completeMR marks an MR as complete.
 // For success, pass closeReason (e.g., CloseReasonMerged).
 // For failures that should return to open, pass empty closeReason.
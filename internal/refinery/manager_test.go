@@ -3,8 +3,10 @@ package refinery
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -119,3 +121,74 @@ func TestManager_Retry_Deprecated(t *testing.T) {
 		t.Errorf("Retry() unexpected error: %v", err)
 	}
 }
+
+// fakeMachine is a remote connection that records tmux sessions. Methods
+// the managers don't use panic through the nil embedded interface.
+type fakeMachine struct {
+	connection.Connection
+	dirs     map[string]bool
+	sessions map[string]string // session -> workdir
+	sent     map[string]string
+}
+
+func newFakeMachine(dirs ...string) *fakeMachine {
+	f := &fakeMachine{dirs: make(map[string]bool), sessions: make(map[string]string), sent: make(map[string]string)}
+	for _, d := range dirs {
+		f.dirs[d] = true
+	}
+	return f
+}
+
+func (f *fakeMachine) Name() string                     { return "box" }
+func (f *fakeMachine) IsLocal() bool                    { return false }
+func (f *fakeMachine) Exists(path string) (bool, error) { return f.dirs[path], nil }
+func (f *fakeMachine) TmuxHasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+func (f *fakeMachine) TmuxNewSession(name, dir string) error {
+	f.sessions[name] = dir
+	return nil
+}
+func (f *fakeMachine) TmuxSendKeys(session, keys string) error {
+	f.sent[session] = keys
+	return nil
+}
+func (f *fakeMachine) TmuxKillSession(name string) error {
+	delete(f.sessions, name)
+	return nil
+}
+
+func TestManager_RemoteRigUsesConnection(t *testing.T) {
+	mgr, _ := setupTestManager(t)
+	remote := newFakeMachine("/srv/gt/testrig/refinery/rig")
+	mgr.rig.SetConnection(remote, "/srv/gt/testrig")
+
+	if err := mgr.Start(false, ""); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	sessionID := mgr.SessionName()
+	if dir := remote.sessions[sessionID]; dir != "/srv/gt/testrig/refinery/rig" {
+		t.Errorf("remote session dir = %q, want the refinery worktree on box", dir)
+	}
+	if cmd := remote.sent[sessionID]; !strings.Contains(cmd, "GT_ROOT=/srv/gt") || !strings.Contains(cmd, "GT_REFINERY=1") {
+		t.Errorf("startup command = %q, want remote GT_ROOT and GT_REFINERY", cmd)
+	}
+
+	if running, err := mgr.IsRunning(); err != nil || !running {
+		t.Errorf("IsRunning = %v, %v; want true from the remote session", running, err)
+	}
+	if err := mgr.Start(false, ""); err != ErrAlreadyRunning {
+		t.Errorf("second Start = %v, want ErrAlreadyRunning", err)
+	}
+	if info, err := mgr.Status(); err != nil || info.Name != sessionID {
+		t.Errorf("Status = %+v, %v", info, err)
+	}
+
+	if err := mgr.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if _, ok := remote.sessions[sessionID]; ok {
+		t.Error("remote session still running after Stop")
+	}
+}
@@ -0,0 +1,315 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// maxReverify bounds how many times an MR is re-verified while waiting to land,
+// beyond one per concurrent worker. MRs in a batch are all verified against the
+// same base, so every MR that lands moves the target under the others; the
+// extra attempts cover pushes from outside the refinery.
+const maxReverify = 3
+
+// ProcessMRInfos processes a batch of merge requests and returns one result
+// per MR, in input order. The caller handles success and failure for each MR,
+// as with ProcessMRInfo.
+//
+// With MaxConcurrent <= 1 the MRs are merged one after another exactly as
+// ProcessMRInfo does. Otherwise up to MaxConcurrent MRs are verified at once,
+// each squash merged and tested in its own scratch worktree. Landing on the
+// target branch stays serialized: if an earlier merge moved the target since
+// an MR was verified, the MR is re-verified against the new base before it
+// lands. MRs land in the order their verification finishes. The refinery
+// patrol reaches this through 'gt refinery process --if-enabled'.
+func (e *Engineer) ProcessMRInfos(ctx context.Context, mrs []*MRInfo) []ProcessResult {
+	results := make([]ProcessResult, len(mrs))
+	limit := e.maxConcurrent()
+
+	if limit <= 1 || len(mrs) <= 1 {
+		for i, mr := range mrs {
+			if ctx.Err() != nil {
+				results[i] = ProcessResult{Error: "processing canceled"}
+				continue
+			}
+			results[i] = e.ProcessMRInfo(ctx, mr)
+		}
+		return results
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Processing %d MRs (max concurrent: %d)\n", len(mrs), limit)
+
+	// One fetch up front gives every verification a fresh origin/<target>.
+	if err := errors.WithNetworkRetry(func() error {
+		return e.git.Fetch("origin")
+	}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: fetch origin: %v (continuing)\n", err)
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, mr := range mrs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] = ProcessResult{Error: "processing canceled"}
+				return
+			}
+			defer func() { <-sem }()
+			results[i] = e.processInScratch(ctx, mr)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// maxConcurrent returns the configured concurrency, never less than one.
+func (e *Engineer) maxConcurrent() int {
+	if e.config.MaxConcurrent < 1 {
+		return 1
+	}
+	return e.config.MaxConcurrent
+}
+
+// processInScratch verifies an MR in a scratch worktree, then lands it.
+func (e *Engineer) processInScratch(ctx context.Context, mr *MRInfo) ProcessResult {
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Processing MR %s: %s → %s (worker: %s)\n", mr.ID, mr.Branch, mr.Target, mr.Worker)
+
+	exists, err := e.git.BranchExists(mr.Branch)
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   errors.New("refinery.merge", errors.NewGitError("check-branch", e.workDir, mr.Branch, err)).Error(),
+		}
+	}
+	if !exists {
+		return ProcessResult{
+			Success: false,
+			Error: errors.User("refinery.merge", fmt.Sprintf("branch %s not found locally", mr.Branch)).
+				WithHint("The polecat may need to push the branch: 'git push origin " + mr.Branch + "'").
+				Error(),
+		}
+	}
+
+	base, err := e.targetBase(mr.Target)
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   errors.New("refinery.merge", errors.NewGitError("rev-parse", e.workDir, mr.Target, err)).Error(),
+		}
+	}
+
+	scratch, err := e.addScratch(mr.ID, base)
+	if err != nil {
+		return ProcessResult{
+			Success: false,
+			Error: errors.System("refinery.merge", err).
+				WithContext("mr", mr.ID).
+				WithHint("Failed to create scratch worktree. Try 'git worktree prune' in the refinery clone.").
+				Error(),
+		}
+	}
+	defer e.removeScratch(scratch)
+
+	sg := git.NewGit(scratch)
+	if result := e.verifyInScratch(ctx, sg, mr, base); !result.Success {
+		return result
+	}
+	return e.landVerified(ctx, sg, mr, base)
+}
+
+// verifyInScratch squash merges the MR onto base in the scratch worktree and
+// runs the configured tests there.
+func (e *Engineer) verifyInScratch(ctx context.Context, sg *git.Git, mr *MRInfo, base string) ProcessResult {
+	if err := sg.Checkout(base); err != nil {
+		return ProcessResult{
+			Success: false,
+			Error:   errors.New("refinery.merge", errors.NewGitError("checkout", sg.WorkDir(), base, err)).Error(),
+		}
+	}
+
+	if err := sg.MergeSquash(mr.Branch, e.squashMessage(mr.Branch, mr.Target, mr.SourceIssue)); err != nil {
+		conflicts, conflictErr := sg.GetConflictingFiles()
+		if conflictErr == nil && len(conflicts) > 0 {
+			return ProcessResult{
+				Success:  false,
+				Conflict: true,
+				Error: errors.User("refinery.merge", fmt.Sprintf("merge conflicts in: %v", conflicts)).
+					WithHint("Rebase the branch to resolve conflicts: 'git rebase origin/" + mr.Target + "'").
+					Error(),
+			}
+		}
+		return ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("merge failed: %v", err),
+		}
+	}
+
+	if e.config.RunTests && e.config.TestCommand != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s: running tests on %s: %s\n", mr.ID, shortSHA(base), e.config.TestCommand)
+		result := e.runTestsIn(ctx, sg.WorkDir())
+		if !result.Success {
+			return ProcessResult{
+				Success:     false,
+				TestsFailed: true,
+				Error:       result.Error,
+			}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s: tests passed\n", mr.ID)
+	}
+
+	return ProcessResult{Success: true}
+}
+
+// landVerified merges a verified MR into the target branch. Only one MR lands
+// at a time; if the target moved since verification, the merge lock is
+// released while the MR is re-verified against the new tip, so other MRs can
+// land or re-verify meanwhile, and the tip is checked again before landing.
+func (e *Engineer) landVerified(ctx context.Context, sg *git.Git, mr *MRInfo, base string) ProcessResult {
+	limit := e.maxConcurrent() + maxReverify
+	for attempt := 0; ; attempt++ {
+		head, landed, result := e.landIfCurrent(mr, base)
+		if landed {
+			return result
+		}
+		if !result.Success {
+			return result
+		}
+		if attempt >= limit {
+			return ProcessResult{
+				Success: false,
+				Error: errors.Transient("refinery.merge", fmt.Errorf("%s moved %d times during verification", mr.Target, attempt)).
+					WithContext("mr", mr.ID).
+					WithHint("The target branch keeps moving faster than the MR can be re-verified; the MR will be retried.").
+					Error(),
+			}
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] %s: %s moved %s → %s since verification, re-verifying\n",
+			mr.ID, mr.Target, shortSHA(base), shortSHA(head))
+		base = head
+		if result := e.verifyInScratch(ctx, sg, mr, base); !result.Success {
+			return result
+		}
+	}
+}
+
+// landIfCurrent lands a verified MR if the target's tip is still base,
+// holding the merge lock throughout. landed reports whether a landing was
+// attempted; if not, head is the tip the MR must be re-verified against, and
+// a failed result means the tip couldn't be read.
+func (e *Engineer) landIfCurrent(mr *MRInfo, base string) (head string, landed bool, result ProcessResult) {
+	e.mergeMu.Lock()
+	defer e.mergeMu.Unlock()
+
+	if err := e.git.Checkout(mr.Target); err != nil {
+		return "", false, ProcessResult{
+			Success: false,
+			Error: errors.New("refinery.merge", errors.NewGitError("checkout", e.workDir, mr.Target, err)).
+				WithHint("Failed to checkout target branch. Ensure git repository is clean.").
+				Error(),
+		}
+	}
+	if err := errors.WithNetworkRetry(func() error {
+		return e.git.Pull("origin", mr.Target)
+	}); err != nil {
+		// Pull might fail if nothing to pull, that's ok
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", mr.Target, err)
+	}
+
+	head, err := e.git.Rev("HEAD")
+	if err != nil {
+		return "", false, ProcessResult{
+			Success: false,
+			Error:   fmt.Sprintf("failed to get %s SHA: %v", mr.Target, err),
+		}
+	}
+	if head != base {
+		return head, false, ProcessResult{Success: true}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] %s: landing on %s\n", mr.ID, mr.Target)
+	return head, true, e.landSquash(mr.Branch, mr.Target, mr.SourceIssue)
+}
+
+// targetBase returns the SHA an MR should be verified against: origin's view
+// of the target when available, the local branch otherwise.
+func (e *Engineer) targetBase(target string) (string, error) {
+	if sha, err := e.git.Rev("origin/" + target); err == nil {
+		return sha, nil
+	}
+	return e.git.Rev(target)
+}
+
+// scratchDir returns the directory holding per-MR scratch worktrees.
+func (e *Engineer) scratchDir() string {
+	return filepath.Join(e.rig.Path, "refinery", "scratch")
+}
+
+// addScratch creates a detached scratch worktree for mrID at base.
+func (e *Engineer) addScratch(mrID, base string) (string, error) {
+	e.worktreeMu.Lock()
+	defer e.worktreeMu.Unlock()
+
+	path := filepath.Join(e.scratchDir(), mrID)
+
+	// Clear leftovers from an interrupted run before reusing the path
+	if _, err := os.Stat(path); err == nil {
+		_ = e.git.WorktreeRemove(path, true)
+		_ = os.RemoveAll(path)
+	}
+	_ = e.git.WorktreePrune()
+
+	if err := os.MkdirAll(e.scratchDir(), 0755); err != nil {
+		return "", fmt.Errorf("creating scratch dir: %w", err)
+	}
+	if err := e.git.WorktreeAddDetached(path, base); err != nil {
+		return "", fmt.Errorf("adding scratch worktree: %w", err)
+	}
+	return path, nil
+}
+
+// removeScratch deletes a scratch worktree (best-effort).
+func (e *Engineer) removeScratch(path string) {
+	e.worktreeMu.Lock()
+	defer e.worktreeMu.Unlock()
+
+	if err := e.git.WorktreeRemove(path, true); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to remove scratch worktree %s: %v\n", path, err)
+		_ = os.RemoveAll(path)
+		_ = e.git.WorktreePrune()
+	}
+}
+
+// shortSHA abbreviates a commit SHA for log output.
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// syncWriter serializes writes so parallel MR logs don't interleave mid-line.
+// The Engineer always wraps its output in one, so workers never need to swap
+// the writer.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
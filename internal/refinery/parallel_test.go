@@ -0,0 +1,172 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// runGit runs a git command in dir and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setupMergeQueueRig creates a rig whose refinery clone tracks a bare origin
+// with a single commit on main.
+func setupMergeQueueRig(t *testing.T) (*rig.Rig, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	runGit(t, root, "init", "--bare", "-b", "main", origin)
+
+	rigPath := filepath.Join(root, "testrig")
+	clone := filepath.Join(rigPath, "refinery", "rig")
+	if err := os.MkdirAll(filepath.Dir(clone), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, root, "clone", origin, clone)
+	runGit(t, clone, "config", "user.email", "test@example.com")
+	runGit(t, clone, "config", "user.name", "Test")
+	runGit(t, clone, "checkout", "-b", "main")
+	writeAndCommit(t, clone, "README.md", "hello\n", "initial")
+	runGit(t, clone, "push", "origin", "main")
+
+	return &rig.Rig{Name: "testrig", Path: rigPath}, clone
+}
+
+// addBranch creates branch from main with a single commit writing file.
+func addBranch(t *testing.T, clone, branch, file, content string) {
+	t.Helper()
+	runGit(t, clone, "checkout", "-b", branch, "main")
+	writeAndCommit(t, clone, file, content, "feat: "+branch)
+	runGit(t, clone, "checkout", "main")
+}
+
+func writeAndCommit(t *testing.T, dir, file, content, msg string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", file)
+	runGit(t, dir, "commit", "-m", msg)
+}
+
+func newParallelEngineer(t *testing.T, r *rig.Rig, testCmd string) (*Engineer, *bytes.Buffer) {
+	t.Helper()
+	e := NewEngineer(r)
+	e.config.MaxConcurrent = 2
+	e.config.RunTests = testCmd != ""
+	e.config.TestCommand = testCmd
+	var out bytes.Buffer
+	e.SetOutput(&out)
+	return e, &out
+}
+
+func TestProcessMRInfos_ParallelReverifiesAfterBaseMoves(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "a.txt", "a\n")
+	addBranch(t, clone, "polecat/b", "b.txt", "b\n")
+
+	runs := filepath.Join(t.TempDir(), "runs")
+	e, out := newParallelEngineer(t, r, "echo run >> "+runs)
+
+	results := e.ProcessMRInfos(context.Background(), []*MRInfo{
+		{ID: "gt-mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "gt-mr-b", Branch: "polecat/b", Target: "main"},
+	})
+
+	for i, res := range results {
+		if !res.Success {
+			t.Fatalf("result %d failed: %s\n%s", i, res.Error, out.String())
+		}
+	}
+
+	// Both changes are on origin/main
+	runGit(t, clone, "fetch", "origin")
+	files := runGit(t, clone, "ls-tree", "--name-only", "origin/main")
+	for _, f := range []string{"a.txt", "b.txt"} {
+		if !strings.Contains(files, f) {
+			t.Errorf("origin/main missing %s; tree: %s", f, files)
+		}
+	}
+
+	// Each MR was tested once in parallel; whichever landed second was
+	// re-verified against the first merge.
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "run"); n != 3 {
+		t.Errorf("test command ran %d times, want 3\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), "re-verifying") {
+		t.Errorf("expected re-verification in output:\n%s", out.String())
+	}
+
+	// Scratch worktrees are cleaned up
+	entries, _ := os.ReadDir(e.scratchDir())
+	if len(entries) != 0 {
+		t.Errorf("scratch dir not cleaned up: %v", entries)
+	}
+}
+
+func TestProcessMRInfos_ReverifyCatchesConflict(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "README.md", "from a\n")
+	addBranch(t, clone, "polecat/b", "README.md", "from b\n")
+
+	e, out := newParallelEngineer(t, r, "")
+
+	results := e.ProcessMRInfos(context.Background(), []*MRInfo{
+		{ID: "gt-mr-a", Branch: "polecat/a", Target: "main"},
+		{ID: "gt-mr-b", Branch: "polecat/b", Target: "main"},
+	})
+
+	// Both verify cleanly against the original main, but only one can land.
+	var merged, conflicted int
+	for _, res := range results {
+		switch {
+		case res.Success:
+			merged++
+		case res.Conflict:
+			conflicted++
+		default:
+			t.Errorf("unexpected failure: %s", res.Error)
+		}
+	}
+	if merged != 1 || conflicted != 1 {
+		t.Errorf("merged=%d conflicted=%d, want 1 and 1\n%s", merged, conflicted, out.String())
+	}
+}
+
+func TestProcessMRInfos_MissingBranch(t *testing.T) {
+	r, _ := setupMergeQueueRig(t)
+	e, _ := newParallelEngineer(t, r, "")
+
+	results := e.ProcessMRInfos(context.Background(), []*MRInfo{
+		{ID: "gt-mr-x", Branch: "polecat/missing", Target: "main"},
+		{ID: "gt-mr-y", Branch: "polecat/also-missing", Target: "main"},
+	})
+	for _, res := range results {
+		if res.Success || !strings.Contains(res.Error, "not found locally") {
+			t.Errorf("expected missing-branch failure, got %+v", res)
+		}
+	}
+}
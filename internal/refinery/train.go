@@ -0,0 +1,320 @@
+package refinery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/errors"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// trainCar is one MR riding in a speculative merge train.
+type trainCar struct {
+	issue  *beads.Issue
+	fields *beads.MRFields
+	result *ProcessResult
+	commit string // Squash commit on the integration ref while stacked
+}
+
+// ProcessTrain merges a batch of MRs as a speculative train (bors style).
+//
+// The MRs are squash merged one after another onto the tip of their target
+// branch in a scratch worktree, and the test command runs once against the
+// stacked result. If it passes, the target is fast-forwarded over the whole
+// batch. If it fails, the batch is bisected: each half is re-stacked on the
+// current tip and tested until the failing MR is isolated. handleFailure is
+// called only for that MR, once the target without it is confirmed green,
+// and for MRs that conflict with the target itself; every MR that lands gets
+// handleSuccess. Infrastructure failures (a push, a checkout, a red target)
+// blame no one: the affected MRs get a failed result and stay in the queue
+// for the next train.
+//
+// MRs should be passed in merge order, typically Manager.Queue's score order.
+// Results are returned in input order.
+func (e *Engineer) ProcessTrain(ctx context.Context, mrs []*beads.Issue) []ProcessResult {
+	results := make([]ProcessResult, len(mrs))
+
+	// Group cars by target, keeping queue order within each group
+	var targets []string
+	byTarget := make(map[string][]*trainCar)
+	for i, mr := range mrs {
+		fields := beads.ParseMRFields(mr)
+		if fields == nil {
+			results[i] = ProcessResult{Error: "no MR fields found in description"}
+			continue
+		}
+		if fields.Target == "" {
+			fields.Target = e.config.TargetBranch
+		}
+		if _, ok := byTarget[fields.Target]; !ok {
+			targets = append(targets, fields.Target)
+		}
+		byTarget[fields.Target] = append(byTarget[fields.Target], &trainCar{
+			issue:  mr,
+			fields: fields,
+			result: &results[i],
+		})
+	}
+
+	e.mergeMu.Lock()
+	defer e.mergeMu.Unlock()
+
+	for _, target := range targets {
+		cars := e.boardTrain(byTarget[target])
+		if len(cars) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Merge train: %d MRs → %s\n", len(cars), target)
+		e.runTrainOnTarget(ctx, target, cars)
+	}
+
+	return results
+}
+
+// boardTrain drops cars whose source branch is missing, failing them, and
+// logs merge_started for the rest.
+func (e *Engineer) boardTrain(cars []*trainCar) []*trainCar {
+	var boarded []*trainCar
+	for _, car := range cars {
+		exists, err := e.git.BranchExists(car.fields.Branch)
+		if err != nil {
+			*car.result = ProcessResult{
+				Error: errors.New("refinery.merge", errors.NewGitError("check-branch", e.workDir, car.fields.Branch, err)).Error(),
+			}
+			continue
+		}
+		if !exists {
+			// Possibly not fetched yet; leave the MR queued rather than bounce it
+			*car.result = ProcessResult{
+				Error: errors.User("refinery.merge", fmt.Sprintf("branch %s not found locally", car.fields.Branch)).
+					WithHint("The polecat may need to push the branch: 'git push origin " + car.fields.Branch + "'").
+					Error(),
+			}
+			continue
+		}
+		e.logMergeEvent(events.TypeMergeStarted, car.issue.ID, car.fields.Worker, car.fields.Branch, car.fields.SourceIssue, car.fields.TraceID, "")
+		boarded = append(boarded, car)
+	}
+	return boarded
+}
+
+// runTrainOnTarget sets up the integration worktree for target and runs the train.
+func (e *Engineer) runTrainOnTarget(ctx context.Context, target string, cars []*trainCar) {
+	base, err := e.syncTarget(target)
+	if err != nil {
+		failCars(cars, ProcessResult{Error: err.Error()})
+		return
+	}
+
+	scratch, err := e.addScratch("train", base)
+	if err != nil {
+		failCars(cars, ProcessResult{
+			Error: errors.System("refinery.train", err).
+				WithHint("Failed to create integration worktree. Try 'git worktree prune' in the refinery clone.").
+				Error(),
+		})
+		return
+	}
+	defer e.removeScratch(scratch)
+
+	e.runTrain(ctx, git.NewGit(scratch), target, cars)
+}
+
+// runTrain stacks cars on the current target tip, tests once, and lands the
+// batch or bisects it.
+func (e *Engineer) runTrain(ctx context.Context, sg *git.Git, target string, cars []*trainCar) {
+	if len(cars) == 0 {
+		return
+	}
+	if ctx.Err() != nil {
+		failCars(cars, ProcessResult{Error: "processing canceled"})
+		return
+	}
+
+	base, err := e.syncTarget(target)
+	if err != nil {
+		failCars(cars, ProcessResult{Error: err.Error()})
+		return
+	}
+
+	stacked, deferred := e.stackTrain(sg, base, target, cars)
+	if len(stacked) > 0 {
+		e.testAndLand(ctx, sg, target, base, stacked)
+	}
+
+	// Cars that only conflicted with an earlier car get another ride on the
+	// new tip. The first car is never deferred, so this always shrinks.
+	e.runTrain(ctx, sg, target, deferred)
+}
+
+// stackTrain squash merges each car onto base in the integration worktree.
+// A car that conflicts with the target itself fails outright; one that only
+// conflicts with cars stacked ahead of it is deferred to a later train.
+func (e *Engineer) stackTrain(sg *git.Git, base, target string, cars []*trainCar) (stacked, deferred []*trainCar) {
+	if err := sg.Checkout(base); err != nil {
+		failCars(cars, ProcessResult{
+			Error: errors.New("refinery.train", errors.NewGitError("checkout", sg.WorkDir(), base, err)).Error(),
+		})
+		return nil, nil
+	}
+
+	for _, car := range cars {
+		msg := e.squashMessage(car.fields.Branch, target, car.fields.SourceIssue)
+		if err := sg.MergeSquash(car.fields.Branch, msg); err != nil {
+			conflicts, conflictErr := sg.GetConflictingFiles()
+			_ = sg.ResetHard("HEAD")
+
+			if conflictErr == nil && len(conflicts) > 0 && len(stacked) > 0 {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] %s conflicts with the train ahead of it, deferring\n", car.issue.ID)
+				deferred = append(deferred, car)
+				continue
+			}
+
+			if conflictErr != nil || len(conflicts) == 0 {
+				// Not the MR's fault as far as we can tell; leave it queued
+				*car.result = ProcessResult{Error: fmt.Sprintf("merge failed: %v", err)}
+				continue
+			}
+			*car.result = ProcessResult{
+				Conflict: true,
+				Error: errors.User("refinery.merge", fmt.Sprintf("merge conflicts in: %v", conflicts)).
+					WithHint("Rebase the branch to resolve conflicts: 'git rebase origin/" + target + "'").
+					Error(),
+			}
+			e.handleFailure(car.issue, *car.result)
+			continue
+		}
+
+		commit, err := sg.Rev("HEAD")
+		if err != nil {
+			*car.result = ProcessResult{Error: fmt.Sprintf("failed to get merge commit SHA: %v", err)}
+			_ = sg.ResetHard("HEAD~1")
+			continue
+		}
+		car.commit = commit
+		stacked = append(stacked, car)
+	}
+
+	return stacked, deferred
+}
+
+// testAndLand tests the stacked cars once. Green batches are fast-forwarded
+// onto the target; red batches are split and retried until the culprit is
+// isolated.
+func (e *Engineer) testAndLand(ctx context.Context, sg *git.Git, target, base string, stacked []*trainCar) {
+	if e.config.RunTests && e.config.TestCommand != "" {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Testing train of %d on %s: %s\n", len(stacked), shortSHA(base), e.config.TestCommand)
+		result := e.runTestsIn(ctx, sg.WorkDir())
+		if !result.Success {
+			if !result.TestsFailed {
+				// The test run itself broke (canceled, say): nothing to bisect
+				failCars(stacked, result)
+				return
+			}
+			if len(stacked) == 1 {
+				e.blameCulprit(ctx, sg, base, stacked[0], result)
+				return
+			}
+
+			mid := len(stacked) / 2
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Train failed, bisecting %d + %d\n", mid, len(stacked)-mid)
+			e.runTrain(ctx, sg, target, stacked[:mid])
+			e.runTrain(ctx, sg, target, stacked[mid:])
+			return
+		}
+	}
+
+	tip := stacked[len(stacked)-1].commit
+	if result := e.fastForward(target, base, tip); !result.Success {
+		failCars(stacked, result)
+		return
+	}
+
+	for _, car := range stacked {
+		*car.result = ProcessResult{
+			Success:     true,
+			MergeCommit: car.commit,
+		}
+		e.handleSuccess(car.issue, *car.result)
+	}
+}
+
+// blameCulprit sends a car that failed tests on its own back for rework, but
+// only after confirming the target passes without it. A red target, or one
+// whose tests can't run, means the failure isn't the MR's.
+func (e *Engineer) blameCulprit(ctx context.Context, sg *git.Git, base string, car *trainCar, failed ProcessResult) {
+	if err := sg.Checkout(base); err != nil {
+		*car.result = ProcessResult{
+			Error: errors.New("refinery.train", errors.NewGitError("checkout", sg.WorkDir(), base, err)).Error(),
+		}
+		return
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] %s failed alone; testing %s without it\n", car.issue.ID, shortSHA(base))
+	if control := e.runTestsIn(ctx, sg.WorkDir()); !control.Success {
+		*car.result = ProcessResult{
+			Error: errors.Transient("refinery.train", fmt.Errorf("%s fails tests without any MR: %s", shortSHA(base), control.Error)).
+				WithHint("Fix the target branch; the MRs stay in the queue.").
+				Error(),
+		}
+		return
+	}
+
+	*car.result = ProcessResult{
+		TestsFailed: true,
+		Error:       failed.Error,
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Train culprit: %s\n", car.issue.ID)
+	e.handleFailure(car.issue, *car.result)
+}
+
+// syncTarget checks out target in the refinery worktree, pulls it, and
+// returns its tip.
+func (e *Engineer) syncTarget(target string) (string, error) {
+	if err := e.git.Checkout(target); err != nil {
+		return "", errors.New("refinery.train", errors.NewGitError("checkout", e.workDir, target, err)).
+			WithHint("Failed to checkout target branch. Ensure git repository is clean.")
+	}
+	if err := errors.WithNetworkRetry(func() error {
+		return e.git.Pull("origin", target)
+	}); err != nil {
+		// Pull might fail if nothing to pull, that's ok
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+	return e.git.Rev("HEAD")
+}
+
+// fastForward moves target from base to tip and pushes it. On push failure the
+// local target is reset to base so the next train starts from origin's view.
+func (e *Engineer) fastForward(target, base, tip string) ProcessResult {
+	if err := e.git.MergeFFOnly(tip); err != nil {
+		return ProcessResult{
+			Error: errors.New("refinery.train", errors.NewGitError("merge --ff-only", e.workDir, tip, err)).Error(),
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing train to origin/%s...\n", target)
+	err := errors.WithNetworkRetry(func() error {
+		return e.git.Push("origin", target, false)
+	})
+	if err != nil {
+		_ = e.git.ResetHard(base)
+		return ProcessResult{
+			Error: errors.Transient("refinery.train", errors.NewGitError("push", e.workDir, target, err)).
+				WithHint("Push failed. Check network connection and remote permissions.").
+				Error(),
+		}
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Fast-forwarded %s: %s → %s\n", target, shortSHA(base), shortSHA(tip))
+	return ProcessResult{Success: true, MergeCommit: tip}
+}
+
+// failCars records the same infrastructure failure for every car without
+// blaming any of them; the MRs stay in the queue for the next run.
+func failCars(cars []*trainCar, result ProcessResult) {
+	for _, car := range cars {
+		*car.result = result
+	}
+}
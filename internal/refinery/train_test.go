@@ -0,0 +1,204 @@
+package refinery
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func mrIssue(id, branch string) *beads.Issue {
+	return &beads.Issue{
+		ID:          id,
+		Description: beads.FormatMRFields(&beads.MRFields{Branch: branch, Target: "main"}),
+	}
+}
+
+func newTrainEngineer(t *testing.T, r *rig.Rig, testCmd string) (*Engineer, *bytes.Buffer) {
+	t.Helper()
+	e := NewEngineer(r)
+	e.config.RunTests = testCmd != ""
+	e.config.TestCommand = testCmd
+	e.config.DeleteMergedBranches = false
+	var out bytes.Buffer
+	e.SetOutput(&out)
+	return e, &out
+}
+
+func countRuns(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "run")
+}
+
+func TestProcessTrain_GreenBatchTestsOnce(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "a.txt", "a\n")
+	addBranch(t, clone, "polecat/b", "b.txt", "b\n")
+	addBranch(t, clone, "polecat/c", "c.txt", "c\n")
+
+	runs := filepath.Join(t.TempDir(), "runs")
+	e, out := newTrainEngineer(t, r, "echo run >> "+runs)
+
+	results := e.ProcessTrain(context.Background(), []*beads.Issue{
+		mrIssue("gt-mr-a", "polecat/a"),
+		mrIssue("gt-mr-b", "polecat/b"),
+		mrIssue("gt-mr-c", "polecat/c"),
+	})
+
+	seen := make(map[string]bool)
+	for i, res := range results {
+		if !res.Success {
+			t.Fatalf("result %d failed: %s\n%s", i, res.Error, out.String())
+		}
+		if res.MergeCommit == "" || seen[res.MergeCommit] {
+			t.Errorf("result %d has missing or duplicate merge commit %q", i, res.MergeCommit)
+		}
+		seen[res.MergeCommit] = true
+	}
+	if n := countRuns(t, runs); n != 1 {
+		t.Errorf("test command ran %d times, want 1", n)
+	}
+
+	// The last car's commit is the new tip of origin/main
+	runGit(t, clone, "fetch", "origin")
+	if tip := runGit(t, clone, "rev-parse", "origin/main"); tip != results[2].MergeCommit {
+		t.Errorf("origin/main = %s, want %s", tip, results[2].MergeCommit)
+	}
+}
+
+func TestProcessTrain_BisectsToCulprit(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "a.txt", "a\n")
+	addBranch(t, clone, "polecat/b", "b.txt", "b\n")
+	addBranch(t, clone, "polecat/bad", "bad.txt", "bad\n")
+	addBranch(t, clone, "polecat/d", "d.txt", "d\n")
+
+	runs := filepath.Join(t.TempDir(), "runs")
+	e, out := newTrainEngineer(t, r, "echo run >> "+runs+" && test ! -f bad.txt")
+
+	results := e.ProcessTrain(context.Background(), []*beads.Issue{
+		mrIssue("gt-mr-a", "polecat/a"),
+		mrIssue("gt-mr-b", "polecat/b"),
+		mrIssue("gt-mr-bad", "polecat/bad"),
+		mrIssue("gt-mr-d", "polecat/d"),
+	})
+
+	for i, res := range results {
+		if i == 2 {
+			if res.Success || !res.TestsFailed {
+				t.Errorf("culprit result = %+v, want TestsFailed", res)
+			}
+			continue
+		}
+		if !res.Success {
+			t.Errorf("result %d failed: %s\n%s", i, res.Error, out.String())
+		}
+	}
+
+	// [a b bad d] red → [a b] green → [bad d] red → [bad] red →
+	// main without bad green → [d] green
+	if n := countRuns(t, runs); n != 6 {
+		t.Errorf("test command ran %d times, want 6\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), "Failed: gt-mr-bad") {
+		t.Errorf("culprit not sent back for rework:\n%s", out.String())
+	}
+
+	runGit(t, clone, "fetch", "origin")
+	files := runGit(t, clone, "ls-tree", "--name-only", "origin/main")
+	if strings.Contains(files, "bad.txt") {
+		t.Errorf("culprit landed on origin/main: %s", files)
+	}
+	for _, f := range []string{"a.txt", "b.txt", "d.txt"} {
+		if !strings.Contains(files, f) {
+			t.Errorf("origin/main missing %s: %s", f, files)
+		}
+	}
+}
+
+func TestProcessTrain_DefersCarConflictingWithTrain(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "README.md", "from a\n")
+	addBranch(t, clone, "polecat/a2", "README.md", "from a2\n")
+	addBranch(t, clone, "polecat/b", "b.txt", "b\n")
+
+	e, out := newTrainEngineer(t, r, "")
+
+	results := e.ProcessTrain(context.Background(), []*beads.Issue{
+		mrIssue("gt-mr-a", "polecat/a"),
+		mrIssue("gt-mr-a2", "polecat/a2"),
+		mrIssue("gt-mr-b", "polecat/b"),
+	})
+
+	if !results[0].Success || !results[2].Success {
+		t.Errorf("expected a and b to land: %+v\n%s", results, out.String())
+	}
+	// a2 is deferred behind a, then conflicts with the new tip on its own ride
+	if results[1].Success || !results[1].Conflict {
+		t.Errorf("a2 result = %+v, want Conflict", results[1])
+	}
+	if !strings.Contains(out.String(), "deferring") {
+		t.Errorf("expected a2 to be deferred:\n%s", out.String())
+	}
+}
+
+func TestProcessTrain_RedTargetBlamesNoOne(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "a.txt", "a\n")
+
+	e, out := newTrainEngineer(t, r, "false")
+
+	results := e.ProcessTrain(context.Background(), []*beads.Issue{mrIssue("gt-mr-a", "polecat/a")})
+	if results[0].Success || results[0].TestsFailed {
+		t.Errorf("result = %+v, want an unblamed failure", results[0])
+	}
+	if strings.Contains(out.String(), "Failed: gt-mr-a") {
+		t.Errorf("MR sent back although main is red without it:\n%s", out.String())
+	}
+}
+
+func TestProcessTrain_PushFailureRequeues(t *testing.T) {
+	r, clone := setupMergeQueueRig(t)
+	addBranch(t, clone, "polecat/a", "a.txt", "a\n")
+	addBranch(t, clone, "polecat/b", "b.txt", "b\n")
+
+	// Origin rejects pushes until the hook is removed
+	origin := runGit(t, clone, "remote", "get-url", "origin")
+	hook := filepath.Join(origin, "hooks", "pre-receive")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho 'remote unavailable' >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	e, out := newTrainEngineer(t, r, "true")
+	mrs := []*beads.Issue{mrIssue("gt-mr-a", "polecat/a"), mrIssue("gt-mr-b", "polecat/b")}
+
+	results := e.ProcessTrain(context.Background(), mrs)
+	for i, res := range results {
+		if res.Success || res.TestsFailed || res.Conflict || !strings.Contains(res.Error, "push") {
+			t.Errorf("result %d = %+v, want an unblamed push failure", i, res)
+		}
+	}
+	if strings.Contains(out.String(), "✗ Failed") {
+		t.Errorf("push failure sent MRs back for rework:\n%s", out.String())
+	}
+
+	// The next train lands both once origin accepts pushes again
+	if err := os.Remove(hook); err != nil {
+		t.Fatal(err)
+	}
+	results = e.ProcessTrain(context.Background(), mrs)
+	for i, res := range results {
+		if !res.Success {
+			t.Errorf("retry result %d failed: %s\n%s", i, res.Error, out.String())
+		}
+	}
+}
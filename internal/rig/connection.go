@@ -0,0 +1,138 @@
+package rig
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/errors"
+)
+
+// IsRemote returns true if the rig's worktrees and sessions live on
+// another machine.
+func (r *Rig) IsRemote() bool {
+	return r.conn != nil && !r.conn.IsLocal()
+}
+
+// Connection returns the connection used to reach the rig's machine.
+// Local rigs get a LocalConnection.
+func (r *Rig) Connection() connection.Connection {
+	if r.conn == nil {
+		return connection.NewLocalConnection()
+	}
+	return r.conn
+}
+
+// SetConnection binds the rig to a machine connection. remotePath is the
+// rig root on that machine and is ignored for local connections.
+func (r *Rig) SetConnection(conn connection.Connection, remotePath string) {
+	r.conn = conn
+	if conn != nil && !conn.IsLocal() {
+		r.Machine = conn.Name()
+		r.RemotePath = remotePath
+	}
+}
+
+// WorkPath returns the rig root on the machine that hosts its worktrees:
+// RemotePath for remote rigs, Path otherwise.
+func (r *Rig) WorkPath() string {
+	if r.IsRemote() {
+		return r.RemotePath
+	}
+	return r.Path
+}
+
+// RemoteDir returns the first of dirs (relative to the rig root) that exists
+// on a remote rig's machine, or RemotePath if none do.
+func (r *Rig) RemoteDir(dirs ...string) string {
+	for _, dir := range dirs {
+		p := path.Join(r.RemotePath, dir)
+		if ok, err := r.conn.Exists(p); err == nil && ok {
+			return p
+		}
+	}
+	return r.RemotePath
+}
+
+// StartRemoteSession starts command in a new tmux session on a remote rig's
+// machine and checks the session survived startup. The connection only
+// offers basic tmux operations, so the agent environment must be part of
+// command.
+func (r *Rig) StartRemoteSession(sessionID, workDir, command string) error {
+	if err := r.conn.TmuxNewSession(sessionID, workDir); err != nil {
+		return fmt.Errorf("creating tmux session on %s: %w", r.conn.Name(), err)
+	}
+	if err := r.conn.TmuxSendKeys(sessionID, command); err != nil {
+		_ = r.conn.TmuxKillSession(sessionID)
+		return fmt.Errorf("starting agent on %s: %w", r.conn.Name(), err)
+	}
+	running, err := r.conn.TmuxHasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("checking session on %s: %w", r.conn.Name(), err)
+	}
+	if !running {
+		return fmt.Errorf("session %s died during startup on %s", sessionID, r.conn.Name())
+	}
+	return nil
+}
+
+// attachMachine resolves a rig's machine through the town machine registry
+// and binds the resulting connection. Local machines are a no-op.
+func (m *Manager) attachMachine(r *Rig, machine string) error {
+	if machine == "" || machine == "local" {
+		return nil
+	}
+
+	registry, err := connection.NewMachineRegistry(constants.MayorMachinesPath(m.townRoot))
+	if err != nil {
+		return errors.System("rig.LoadMachines", err).
+			WithContext("rig_name", r.Name).
+			WithHint("Check mayor/machines.json is valid JSON")
+	}
+	mach, err := registry.Get(machine)
+	if err != nil {
+		return errors.User("rig.MachineNotFound", fmt.Sprintf("machine %q not found", machine)).
+			WithContext("rig_name", r.Name).
+			WithHint("Register the machine in mayor/machines.json or clear the rig's machine field in mayor/rigs.json")
+	}
+	conn, err := registry.Connection(machine)
+	if err != nil {
+		return errors.System("rig.Connect", err).
+			WithContext("rig_name", r.Name).
+			WithContext("machine", machine)
+	}
+
+	remotePath := ""
+	if !conn.IsLocal() {
+		if mach.TownPath == "" {
+			return errors.User("rig.MachineTownPath", "remote machine has no town_path").
+				WithContext("machine", machine).
+				WithHint("Set town_path for the machine in mayor/machines.json")
+		}
+		// Remote paths are POSIX regardless of the local OS.
+		remotePath = path.Join(mach.TownPath, r.Name)
+	}
+	r.SetConnection(conn, remotePath)
+	return nil
+}
+
+// scanRemotePolecats lists polecat directories on a remote rig.
+// The trailing slash makes the remote shell match directories only,
+// so this costs a single round trip.
+func scanRemotePolecats(r *Rig) []string {
+	matches, err := r.conn.Glob(path.Join(r.RemotePath, "polecats", "*") + "/")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, match := range matches {
+		name := path.Base(strings.TrimSuffix(match, "/"))
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,51 @@
+package rig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+func TestAttachMachine(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	registry, err := connection.NewMachineRegistry(constants.MayorMachinesPath(root))
+	if err != nil {
+		t.Fatalf("NewMachineRegistry: %v", err)
+	}
+	if err := registry.Add(&connection.Machine{Name: "box", Type: "ssh", Host: "gt@box", TownPath: "/srv/gt"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := registry.Add(&connection.Machine{Name: "nopath", Type: "ssh", Host: "gt@nopath"}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	m := NewManager(root, rigsConfig, nil)
+
+	local := &Rig{Name: "gastown", Path: filepath.Join(root, "gastown")}
+	if err := m.attachMachine(local, ""); err != nil {
+		t.Fatalf("attachMachine local: %v", err)
+	}
+	if local.IsRemote() || local.WorkPath() != local.Path || !local.Connection().IsLocal() {
+		t.Errorf("local rig should stay local")
+	}
+
+	remote := &Rig{Name: "gastown", Path: filepath.Join(root, "gastown")}
+	if err := m.attachMachine(remote, "box"); err != nil {
+		t.Fatalf("attachMachine box: %v", err)
+	}
+	if !remote.IsRemote() || remote.Machine != "box" {
+		t.Errorf("rig not bound to box: remote=%v machine=%q", remote.IsRemote(), remote.Machine)
+	}
+	if remote.WorkPath() != "/srv/gt/gastown" {
+		t.Errorf("WorkPath = %q, want /srv/gt/gastown", remote.WorkPath())
+	}
+
+	if err := m.attachMachine(&Rig{Name: "x"}, "missing"); err == nil {
+		t.Errorf("expected error for unknown machine")
+	}
+	if err := m.attachMachine(&Rig{Name: "x"}, "nopath"); err == nil {
+		t.Errorf("expected error for machine without town_path")
+	}
+}
@@ -191,9 +191,16 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 		Config:    entry.BeadsConfig,
 	}
 
-	// Scan for polecats
+	// Bind remote machine if the rig lives elsewhere
+	if err := m.attachMachine(rig, entry.Machine); err != nil {
+		return nil, err
+	}
+
+	// Scan for polecats (on the rig's machine)
 	polecatsDir := filepath.Join(rigPath, "polecats")
-	if entries, err := os.ReadDir(polecatsDir); err == nil {
+	if rig.IsRemote() {
+		rig.Polecats = scanRemotePolecats(rig)
+	} else if entries, err := os.ReadDir(polecatsDir); err == nil {
 		for _, e := range entries {
 			if !e.IsDir() {
 				continue
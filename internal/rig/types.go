@@ -3,6 +3,7 @@ package rig
 
 import (
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 )
 
 // Rig represents a managed repository in the workspace.
@@ -37,6 +38,16 @@ type Rig struct {
 
 	// HasMayor indicates if the rig has a mayor clone.
 	HasMayor bool `json:"has_mayor"`
+
+	// Machine is the machine hosting the rig's worktrees and sessions.
+	// Empty means the local machine.
+	Machine string `json:"machine,omitempty"`
+
+	// RemotePath is the rig root on Machine. Only set for remote rigs.
+	RemotePath string `json:"remote_path,omitempty"`
+
+	// conn reaches the rig's machine. Nil means local.
+	conn connection.Connection
 }
 
 // AgentDirs are the standard agent directories in a rig.
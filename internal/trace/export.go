@@ -0,0 +1,227 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// waterfallWidth is the width of the waterfall's bar column.
+const waterfallWidth = 40
+
+// WriteWaterfall renders the trace as a text waterfall: one row per span
+// with a bar placed on a shared timeline, its duration, actor and outcome.
+// Spans still in progress are drawn up to now.
+func WriteWaterfall(w io.Writer, t *Trace, now time.Time) error {
+	root := t.Root()
+	if root == nil {
+		return nil
+	}
+	end := root.End
+	if root.InProgress() {
+		end = now
+	}
+	total := end.Sub(root.Start)
+
+	nameWidth := 0
+	for _, s := range t.Spans {
+		if n := len(s.Name); n > nameWidth {
+			nameWidth = n
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, s := range t.Spans {
+		name := s.Name
+		if i > 0 {
+			name = "  " + name
+		}
+		fmt.Fprintf(bw, "%-*s  %s  %8s", nameWidth+2, name, bar(&s, root.Start, total, now), FormatDuration(s.Duration(now)))
+		if s.Actor != "" {
+			fmt.Fprintf(bw, "  %s", s.Actor)
+		}
+		switch {
+		case s.InProgress():
+			bw.WriteString("  (in progress)")
+		case s.Status == StatusError:
+			bw.WriteString("  failed")
+			if reason := s.Attributes["reason"]; reason != "" {
+				bw.WriteString(": " + reason)
+			}
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// bar draws a span's position on the timeline. Every span gets at least
+// one cell so instant spans stay visible.
+func bar(s *Span, start time.Time, total time.Duration, now time.Time) string {
+	from, width := 0, waterfallWidth
+	if total > 0 {
+		from = int(float64(s.Start.Sub(start)) / float64(total) * waterfallWidth)
+		width = int(float64(s.Duration(now))/float64(total)*waterfallWidth + 0.5)
+	}
+	if from >= waterfallWidth {
+		from = waterfallWidth - 1
+	}
+	if width < 1 {
+		width = 1
+	}
+	if from+width > waterfallWidth {
+		width = waterfallWidth - from
+	}
+	fill := "█"
+	if s.InProgress() {
+		fill = "▒"
+	}
+	return strings.Repeat(" ", from) + strings.Repeat(fill, width) + strings.Repeat(" ", waterfallWidth-from-width)
+}
+
+// FormatDuration formats a span duration compactly (e.g., "4s", "12m30s",
+// "2h13m", "3d4h").
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// ServiceName is the OTLP resource service.name of exported traces.
+const ServiceName = "gastown"
+
+// scopeName is the OTLP instrumentation scope of exported spans.
+const scopeName = "github.com/steveyegge/gastown/internal/trace"
+
+// OTLP/JSON types: the subset of ExportTraceServiceRequest that gt writes.
+// 64-bit integers are strings, as the OTLP JSON mapping requires.
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLP span kind and status codes.
+const (
+	otlpKindInternal = 1
+	otlpStatusUnset  = 0
+	otlpStatusOK     = 1
+	otlpStatusError  = 2
+)
+
+// WriteOTLP writes the trace as one OTLP/JSON ExportTraceServiceRequest
+// line, the format of the OpenTelemetry Collector's file exporter, so
+// traces can be loaded into any OTLP backend later. Spans in progress end
+// at now. Span attributes are prefixed "gt.".
+func WriteOTLP(w io.Writer, t *Trace, now time.Time) error {
+	spans := make([]otlpSpan, 0, len(t.Spans))
+	for _, s := range t.Spans {
+		end := s.End
+		if s.InProgress() {
+			end = now
+		}
+		span := otlpSpan{
+			TraceID:           t.ID,
+			SpanID:            s.ID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              otlpKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusUnset},
+		}
+		switch s.Status {
+		case StatusOK:
+			span.Status.Code = otlpStatusOK
+		case StatusError:
+			span.Status.Code = otlpStatusError
+			span.Status.Message = s.Attributes["reason"]
+		}
+		if s.Actor != "" {
+			span.Attributes = append(span.Attributes, stringAttribute("gt.actor", s.Actor))
+		}
+		keys := make([]string, 0, len(s.Attributes))
+		for k := range s.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			span.Attributes = append(span.Attributes, stringAttribute("gt."+k, s.Attributes[k]))
+		}
+		spans = append(spans, span)
+	}
+
+	req := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			stringAttribute("service.name", ServiceName),
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: scopeName},
+			Spans: spans,
+		}},
+	}}}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshaling trace: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
@@ -0,0 +1,144 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteOTLP(t *testing.T) {
+	tr := Build(testTrace, "gt-abc", lifecycle()[:5]) // failed merge, back in queue
+	now := t0.Add(time.Hour)
+
+	var buf bytes.Buffer
+	if err := WriteOTLP(&buf, tr, now); err != nil {
+		t.Fatalf("WriteOTLP: %v", err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 1 {
+		t.Errorf("wrote %d lines, want one request per line", n)
+	}
+
+	var req otlpRequest
+	if err := json.Unmarshal(buf.Bytes(), &req); err != nil {
+		t.Fatalf("output isn't JSON: %v", err)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("want one resource and scope, got %+v", req)
+	}
+	if attr := req.ResourceSpans[0].Resource.Attributes; len(attr) != 1 || attr[0].Value.StringValue != ServiceName {
+		t.Errorf("resource attributes = %+v", attr)
+	}
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != len(tr.Spans) {
+		t.Fatalf("exported %d spans, want %d", len(spans), len(tr.Spans))
+	}
+	ids := make(map[string]bool)
+	for i, s := range spans {
+		if s.TraceID != testTrace {
+			t.Errorf("span %d traceId = %q", i, s.TraceID)
+		}
+		if len(s.SpanID) != 16 || ids[s.SpanID] {
+			t.Errorf("span %d spanId = %q, want unique 8-byte hex", i, s.SpanID)
+		}
+		ids[s.SpanID] = true
+		if i > 0 && s.ParentSpanID != spans[0].SpanID {
+			t.Errorf("span %d parent = %q, want root", i, s.ParentSpanID)
+		}
+	}
+
+	root := spans[0]
+	if root.ParentSpanID != "" {
+		t.Errorf("root has parent %q", root.ParentSpanID)
+	}
+	if want := "1768035600000000000"; root.StartTimeUnixNano != want {
+		t.Errorf("root start = %s, want %s", root.StartTimeUnixNano, want)
+	}
+	if want := "1768039200000000000"; root.EndTimeUnixNano != want {
+		t.Errorf("in-progress root end = %s, want now (%s)", root.EndTimeUnixNano, want)
+	}
+
+	failed := spans[4]
+	if failed.Name != "merge" || failed.Status.Code != otlpStatusError || failed.Status.Message != "tests: boom" {
+		t.Errorf("failed merge = %s %+v", failed.Name, failed.Status)
+	}
+	var sawActor, sawMR bool
+	for _, a := range failed.Attributes {
+		sawActor = sawActor || (a.Key == "gt.actor" && a.Value.StringValue == "gastown/refinery")
+		sawMR = sawMR || (a.Key == "gt.mr" && a.Value.StringValue == "gt-mr1")
+	}
+	if !sawActor || !sawMR {
+		t.Errorf("failed merge attributes = %+v", failed.Attributes)
+	}
+	if spans[1].Status.Code != otlpStatusOK {
+		t.Errorf("dispatch status = %+v, want ok", spans[1].Status)
+	}
+}
+
+func TestWriteWaterfall(t *testing.T) {
+	tr := Build(testTrace, "gt-abc", lifecycle())
+
+	var buf bytes.Buffer
+	if err := WriteWaterfall(&buf, tr, t0.Add(2*time.Hour)); err != nil {
+		t.Fatalf("WriteWaterfall: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(tr.Spans) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(lines), len(tr.Spans), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "bead gt-abc") || !strings.Contains(lines[0], strings.Repeat("█", waterfallWidth)) {
+		t.Errorf("root row should span the whole timeline: %q", lines[0])
+	}
+	if !strings.Contains(lines[0], "53m00s") {
+		t.Errorf("root row missing duration: %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "  work") || !strings.Contains(lines[2], "39m55s") {
+		t.Errorf("work row = %q", lines[2])
+	}
+	if !strings.Contains(lines[4], "failed: tests: boom") {
+		t.Errorf("failed merge row = %q", lines[4])
+	}
+
+	// Every bar starts where its span starts on the shared timeline
+	workBar := strings.Index(lines[2], "█")
+	convoyBar := strings.Index(lines[7], "█")
+	if workBar >= convoyBar {
+		t.Errorf("work bar at %d should be left of convoy bar at %d", workBar, convoyBar)
+	}
+}
+
+func TestWriteWaterfallInProgress(t *testing.T) {
+	tr := Build(testTrace, "gt-abc", lifecycle()[:3])
+
+	var buf bytes.Buffer
+	if err := WriteWaterfall(&buf, tr, t0.Add(time.Hour)); err != nil {
+		t.Fatalf("WriteWaterfall: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if !strings.Contains(lines[0], "1h00m") || !strings.HasSuffix(lines[0], "(in progress)") {
+		t.Errorf("root row = %q", lines[0])
+	}
+	if !strings.Contains(lines[3], "▒") || !strings.HasSuffix(lines[3], "(in progress)") {
+		t.Errorf("queue row = %q", lines[3])
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{4 * time.Second, "4s"},
+		{12*time.Minute + 30*time.Second, "12m30s"},
+		{2*time.Hour + 13*time.Minute, "2h13m"},
+		{76 * time.Hour, "3d4h"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
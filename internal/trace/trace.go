@@ -0,0 +1,382 @@
+// Package trace reconstructs a bead's lifecycle across agents as a trace of
+// timed spans.
+//
+// gt sling mints a trace ID and stores it on the bead (trace_id). Every
+// agent that touches the work afterwards repeats it: in the event payloads
+// written to .events.jsonl, in the MR bead's fields and in the protocol
+// mail about the work. Load reads the events back and Build turns them into
+// phase spans - dispatch, work, queue, merge, convoy - under a root span for
+// the bead. Traces can be rendered as a waterfall or exported as OTLP/JSON.
+package trace
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Status is the outcome of a span, following OpenTelemetry status codes.
+type Status string
+
+const (
+	StatusUnset Status = "unset" // In progress or outcome unknown
+	StatusOK    Status = "ok"
+	StatusError Status = "error"
+)
+
+// Span is one timed phase of a bead's lifecycle.
+type Span struct {
+	ID         string            `json:"span_id"`
+	ParentID   string            `json:"parent_span_id,omitempty"`
+	Name       string            `json:"name"`
+	Actor      string            `json:"actor,omitempty"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end,omitempty"` // Zero while in progress
+	Status     Status            `json:"status"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// InProgress reports whether the span hasn't ended.
+func (s *Span) InProgress() bool {
+	return s.End.IsZero()
+}
+
+// Duration returns how long the span took, or has taken so far at now.
+func (s *Span) Duration(now time.Time) time.Duration {
+	if s.InProgress() {
+		return now.Sub(s.Start)
+	}
+	return s.End.Sub(s.Start)
+}
+
+// Trace is a bead's lifecycle.
+type Trace struct {
+	ID   string `json:"trace_id"`
+	Bead string `json:"bead"`
+
+	// Derived is set when no event recorded a trace ID - work slung before
+	// tracing existed. ID is then derived from the bead and events are
+	// matched by bead ID and branch alone.
+	Derived bool `json:"derived,omitempty"`
+
+	// Spans holds the root span first, then phases in start order.
+	Spans []Span `json:"spans"`
+
+	// Events are the events the spans were built from, oldest first.
+	Events []events.Event `json:"events"`
+}
+
+// Root returns the root span, or nil for a trace with no events.
+func (t *Trace) Root() *Span {
+	if len(t.Spans) == 0 {
+		return nil
+	}
+	return &t.Spans[0]
+}
+
+// NewID returns a random trace ID: 16 bytes as 32 lowercase hex digits,
+// the W3C Trace Context and OpenTelemetry format.
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand doesn't fail on supported platforms; fall back to
+		// something unique enough rather than an all-zero (invalid) ID.
+		return derivedID(fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid()), 16)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ValidID reports whether id is a well-formed, non-zero trace ID.
+func ValidID(id string) bool {
+	if len(id) != 32 || strings.Trim(id, "0") == "" {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil && strings.ToLower(id) == id
+}
+
+// derivedID hashes seed into n bytes of hex.
+func derivedID(seed string, n int) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:n])
+}
+
+// Load reads the events log and builds the trace for bead. The trace ID is
+// taken from the newest event about the bead that carries one; events with
+// that ID, and untraced events about the bead, make up the trace. Returns
+// a trace with no spans if nothing in the log mentions the bead.
+func Load(eventsPath, bead string) (*Trace, error) {
+	all, err := readEvents(eventsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	traceID := ""
+	branches := make(map[string]bool)
+	for _, e := range all {
+		if !mentions(&e, bead) {
+			continue
+		}
+		if id := payloadString(&e, events.TraceKey); id != "" {
+			traceID = id
+		}
+		if e.Type == events.TypeDone {
+			if branch := payloadString(&e, "branch"); branch != "" {
+				branches[branch] = true
+			}
+		}
+	}
+
+	var matched []events.Event
+	for _, e := range all {
+		id := payloadString(&e, events.TraceKey)
+		switch {
+		case traceID != "" && id == traceID:
+		case id != "" && id != traceID:
+			continue
+		case mentions(&e, bead):
+		case isMergeEvent(e.Type) && branches[payloadString(&e, "branch")]:
+		default:
+			continue
+		}
+		matched = append(matched, e)
+	}
+
+	t := Build(traceID, bead, matched)
+	return t, nil
+}
+
+// readEvents reads every well-formed event in the log, oldest first.
+func readEvents(path string) ([]events.Event, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed from trusted townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	var all []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		all = append(all, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events log: %w", err)
+	}
+	return all, nil
+}
+
+// mentions reports whether an event is about bead, directly or as one of a
+// convoy's tracked issues.
+func mentions(e *events.Event, bead string) bool {
+	for _, key := range []string{"bead", "issue"} {
+		if payloadString(e, key) == bead {
+			return true
+		}
+	}
+	if list, ok := e.Payload["beads"].([]interface{}); ok {
+		for _, v := range list {
+			// Tracked issues may be external refs (external:<rig>:<id>)
+			if s, ok := v.(string); ok && (s == bead || strings.HasSuffix(s, ":"+bead)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isMergeEvent(eventType string) bool {
+	switch eventType {
+	case events.TypeMergeStarted, events.TypeMerged, events.TypeMergeFailed:
+		return true
+	}
+	return false
+}
+
+func payloadString(e *events.Event, key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+// phase is a span bounded by event types. An event ends the open span of
+// its phase before it starts any others; an event that both starts and
+// ends a phase with none open records an instant span.
+type phase struct {
+	name   string
+	starts []string
+	ends   []string
+	failed []string // End types that mark the span failed
+
+	// optional phases may never end (not every bead is in a convoy); if
+	// still open when the events run out they're dropped.
+	optional bool
+}
+
+var phases = []phase{
+	{name: "dispatch", starts: []string{events.TypeSpawn, events.TypeSling}, ends: []string{events.TypeSling}},
+	{name: "work", starts: []string{events.TypeSling, events.TypeHook}, ends: []string{events.TypeDone, events.TypeUnhook}},
+	{name: "queue", starts: []string{events.TypeDone, events.TypeMergeFailed}, ends: []string{events.TypeMergeStarted, events.TypeMerged}},
+	{name: "merge", starts: []string{events.TypeMergeStarted}, ends: []string{events.TypeMerged, events.TypeMergeFailed}, failed: []string{events.TypeMergeFailed}},
+	{name: "convoy", starts: []string{events.TypeMerged}, ends: []string{events.TypeConvoyClosed}, optional: true},
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Build turns a bead's events into a trace. traceID may be empty, in which
+// case one is derived from the bead. Events without a parseable timestamp
+// are ignored.
+func Build(traceID, bead string, evts []events.Event) *Trace {
+	t := &Trace{ID: traceID, Bead: bead}
+	if traceID == "" {
+		t.ID = derivedID("bead:"+bead, 16)
+		t.Derived = true
+	}
+
+	type timed struct {
+		at time.Time
+		e  events.Event
+	}
+	var ordered []timed
+	for _, e := range evts {
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		ordered = append(ordered, timed{at, e})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].at.Before(ordered[j].at) })
+	if len(ordered) == 0 {
+		return t
+	}
+
+	var spans []Span
+	open := make(map[string]*Span)
+	for _, te := range ordered {
+		t.Events = append(t.Events, te.e)
+		closed := make(map[string]bool)
+		for _, p := range phases {
+			if !contains(p.ends, te.e.Type) {
+				continue
+			}
+			span := open[p.name]
+			if span == nil {
+				if !contains(p.starts, te.e.Type) {
+					continue
+				}
+				span = newSpan(p.name, te.at, &te.e)
+			}
+			delete(open, p.name)
+			span.End = te.at
+			span.Actor = te.e.Actor
+			span.Status = StatusOK
+			if contains(p.failed, te.e.Type) {
+				span.Status = StatusError
+			}
+			addAttributes(span, &te.e)
+			span.Attributes["ended_by"] = te.e.Type
+			spans = append(spans, *span)
+			closed[p.name] = true
+		}
+		for _, p := range phases {
+			if closed[p.name] || open[p.name] != nil || !contains(p.starts, te.e.Type) {
+				continue
+			}
+			open[p.name] = newSpan(p.name, te.at, &te.e)
+		}
+	}
+	for _, p := range phases {
+		if span := open[p.name]; span != nil && !p.optional {
+			spans = append(spans, *span)
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+
+	root := Span{
+		Name:   "bead " + bead,
+		Start:  ordered[0].at,
+		Status: StatusUnset,
+	}
+	if len(spans) > 0 && spans[0].Start.Before(root.Start) {
+		root.Start = spans[0].Start
+	}
+	root.Actor = ordered[0].e.Actor
+	for _, te := range ordered {
+		if te.e.Type == events.TypeSling {
+			root.Actor = te.e.Actor // The dispatcher
+			break
+		}
+	}
+	root.Attributes = map[string]string{"bead": bead}
+	inProgress := false
+	for _, s := range spans {
+		if s.InProgress() {
+			inProgress = true
+		} else if s.End.After(root.End) {
+			root.End = s.End
+		}
+	}
+	if inProgress || len(spans) == 0 {
+		root.End = time.Time{}
+	}
+	for _, te := range ordered {
+		if te.e.Type == events.TypeMerged {
+			root.Status = StatusOK
+		}
+	}
+	root.ID = derivedID(t.ID+"/root", 8)
+
+	t.Spans = append([]Span{root}, spans...)
+	for i := 1; i < len(t.Spans); i++ {
+		s := &t.Spans[i]
+		s.ParentID = root.ID
+		s.ID = derivedID(fmt.Sprintf("%s/%s/%d/%d", t.ID, s.Name, s.Start.UnixNano(), i), 8)
+	}
+	return t
+}
+
+// newSpan opens a phase span at an event. Until the span ends its actor is
+// whoever the event hands the work to; the event that ends it names who
+// actually did the phase.
+func newSpan(name string, at time.Time, e *events.Event) *Span {
+	actor := e.Actor
+	if target := payloadString(e, "target"); e.Type == events.TypeSling && target != "" {
+		actor = target
+	}
+	span := &Span{Name: name, Actor: actor, Start: at, Status: StatusUnset, Attributes: make(map[string]string)}
+	addAttributes(span, e)
+	return span
+}
+
+// addAttributes copies an event's string payload fields onto a span.
+func addAttributes(span *Span, e *events.Event) {
+	for k, v := range e.Payload {
+		s, ok := v.(string)
+		if !ok || s == "" || k == events.TraceKey {
+			continue
+		}
+		span.Attributes[k] = s
+	}
+}
@@ -0,0 +1,269 @@
+package trace
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+const testTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+var t0 = time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+func ev(offset time.Duration, eventType, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{
+		Timestamp:  t0.Add(offset).Format(time.RFC3339),
+		Source:     "gt",
+		Type:       eventType,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: events.VisibilityFeed,
+	}
+}
+
+func traced(p map[string]interface{}) map[string]interface{} {
+	return events.WithTrace(p, testTrace)
+}
+
+// lifecycle is a bead slung to a rig, failing its first merge, then merged
+// and its convoy closed.
+func lifecycle() []events.Event {
+	return []events.Event{
+		ev(0, events.TypeSpawn, "gt", traced(events.SpawnPayload("gastown", "nux"))),
+		ev(5*time.Second, events.TypeSling, "mayor", traced(events.SlingPayload("gt-abc", "gastown/polecats/nux"))),
+		ev(40*time.Minute, events.TypeDone, "gastown/polecats/nux", traced(events.DonePayload("gt-abc", "polecat/nux/gt-abc"))),
+		ev(45*time.Minute, events.TypeMergeStarted, "gastown/refinery", traced(events.MergePayload("gt-mr1", "nux", "polecat/nux/gt-abc", ""))),
+		ev(47*time.Minute, events.TypeMergeFailed, "gastown/refinery", traced(events.MergePayload("gt-mr1", "nux", "polecat/nux/gt-abc", "tests: boom"))),
+		ev(50*time.Minute, events.TypeMergeStarted, "gastown/refinery", traced(events.MergePayload("gt-mr1", "nux", "polecat/nux/gt-abc", ""))),
+		ev(52*time.Minute, events.TypeMerged, "gastown/refinery", traced(events.MergePayload("gt-mr1", "nux", "polecat/nux/gt-abc", ""))),
+		ev(53*time.Minute, events.TypeConvoyClosed, "deacon", events.ConvoyClosedPayload("hq-cv-1", "All tracked issues completed", []string{"external:gastown:gt-abc"})),
+	}
+}
+
+type spanWant struct {
+	name   string
+	start  time.Duration
+	end    time.Duration // -1 for in progress
+	status Status
+}
+
+func checkSpans(t *testing.T, tr *Trace, want []spanWant) {
+	t.Helper()
+	var got []string
+	for _, s := range tr.Spans[1:] {
+		got = append(got, s.Name)
+	}
+	if len(tr.Spans)-1 != len(want) {
+		t.Fatalf("spans = %v, want %d", got, len(want))
+	}
+	for i, w := range want {
+		s := tr.Spans[i+1]
+		if s.Name != w.name || !s.Start.Equal(t0.Add(w.start)) || s.Status != w.status {
+			t.Errorf("span %d = %s@%v %s, want %s@%v %s", i, s.Name, s.Start.Sub(t0), s.Status, w.name, w.start, w.status)
+		}
+		if w.end < 0 {
+			if !s.InProgress() {
+				t.Errorf("span %d (%s) ended at %v, want in progress", i, s.Name, s.End.Sub(t0))
+			}
+		} else if !s.End.Equal(t0.Add(w.end)) {
+			t.Errorf("span %d (%s) ends %v, want %v", i, s.Name, s.End.Sub(t0), w.end)
+		}
+		if s.ParentID != tr.Spans[0].ID {
+			t.Errorf("span %d parent = %q, want root %q", i, s.ParentID, tr.Spans[0].ID)
+		}
+	}
+}
+
+func TestBuildLifecycle(t *testing.T) {
+	tr := Build(testTrace, "gt-abc", lifecycle())
+
+	if tr.ID != testTrace || tr.Derived {
+		t.Errorf("ID = %q derived=%v, want %q", tr.ID, tr.Derived, testTrace)
+	}
+	checkSpans(t, tr, []spanWant{
+		{"dispatch", 0, 5 * time.Second, StatusOK},
+		{"work", 5 * time.Second, 40 * time.Minute, StatusOK},
+		{"queue", 40 * time.Minute, 45 * time.Minute, StatusOK},
+		{"merge", 45 * time.Minute, 47 * time.Minute, StatusError},
+		{"queue", 47 * time.Minute, 50 * time.Minute, StatusOK},
+		{"merge", 50 * time.Minute, 52 * time.Minute, StatusOK},
+		{"convoy", 52 * time.Minute, 53 * time.Minute, StatusOK},
+	})
+
+	root := tr.Root()
+	if root.Name != "bead gt-abc" || root.Status != StatusOK {
+		t.Errorf("root = %s %s, want bead gt-abc ok", root.Name, root.Status)
+	}
+	if got := root.Duration(time.Time{}); got != 53*time.Minute {
+		t.Errorf("root duration = %v, want 53m", got)
+	}
+	if got := tr.Spans[4].Attributes["reason"]; got != "tests: boom" {
+		t.Errorf("failed merge reason = %q", got)
+	}
+	if got := root.Actor; got != "mayor" {
+		t.Errorf("root actor = %q, want the dispatcher", got)
+	}
+	if got := tr.Spans[2].Actor; got != "gastown/polecats/nux" {
+		t.Errorf("work actor = %q, want the polecat", got)
+	}
+	if got := tr.Spans[3].Actor; got != "gastown/refinery" {
+		t.Errorf("queue actor = %q, want the refinery", got)
+	}
+}
+
+func TestBuildInProgress(t *testing.T) {
+	evts := lifecycle()[:3] // spawned, slung, done: waiting in the queue
+	tr := Build(testTrace, "gt-abc", evts)
+
+	checkSpans(t, tr, []spanWant{
+		{"dispatch", 0, 5 * time.Second, StatusOK},
+		{"work", 5 * time.Second, 40 * time.Minute, StatusOK},
+		{"queue", 40 * time.Minute, -1, StatusUnset},
+	})
+	if got := tr.Spans[3].Actor; got != "gastown/polecats/nux" {
+		t.Errorf("open queue actor = %q, want the polecat that queued it", got)
+	}
+	root := tr.Root()
+	if !root.InProgress() || root.Status != StatusUnset {
+		t.Errorf("root ended=%v status=%s, want in progress", root.End, root.Status)
+	}
+	if got := root.Duration(t0.Add(time.Hour)); got != time.Hour {
+		t.Errorf("root duration at +1h = %v", got)
+	}
+}
+
+func TestBuildWithoutConvoy(t *testing.T) {
+	evts := lifecycle()[:7] // merged, no convoy close
+	tr := Build(testTrace, "gt-abc", evts)
+
+	if last := tr.Spans[len(tr.Spans)-1]; last.Name != "merge" {
+		t.Errorf("last span = %s, want merge (convoy is optional)", last.Name)
+	}
+	if root := tr.Root(); root.InProgress() || !root.End.Equal(t0.Add(52*time.Minute)) {
+		t.Errorf("root end = %v, want the merge", root.End)
+	}
+}
+
+func TestBuildSlingWithoutSpawn(t *testing.T) {
+	evts := []events.Event{
+		ev(0, events.TypeSling, "mayor", traced(events.SlingPayload("gt-abc", "gastown/crew/joe"))),
+		ev(time.Hour, events.TypeDone, "gastown/crew/joe", traced(events.DonePayload("gt-abc", "main"))),
+	}
+	tr := Build(testTrace, "gt-abc", evts)
+
+	checkSpans(t, tr, []spanWant{
+		{"dispatch", 0, 0, StatusOK},
+		{"work", 0, time.Hour, StatusOK},
+		{"queue", time.Hour, -1, StatusUnset},
+	})
+}
+
+func TestBuildEmpty(t *testing.T) {
+	tr := Build("", "gt-none", nil)
+	if tr.Root() != nil {
+		t.Error("trace without events has a root span")
+	}
+	if !tr.Derived || !ValidID(tr.ID) {
+		t.Errorf("ID = %q derived=%v, want a valid derived ID", tr.ID, tr.Derived)
+	}
+}
+
+func writeEvents(t *testing.T, evts []events.Event) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), events.EventsFile)
+	var b strings.Builder
+	for _, e := range evts {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.Write(data)
+		b.WriteString("\n")
+	}
+	b.WriteString("not json\n")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	other := "0af7651916cd43dd8448eb211c80319c"
+	evts := lifecycle()
+	evts = append(evts,
+		// Another bead's trace, same rig
+		ev(time.Minute, events.TypeSling, "mayor", events.WithTrace(events.SlingPayload("gt-xyz", "gastown/polecats/ace"), other)),
+		ev(2*time.Minute, events.TypeSpawn, "gt", events.WithTrace(events.SpawnPayload("gastown", "ace"), other)),
+		ev(3*time.Minute, events.TypeMail, "mayor", events.MailPayload("gastown/witness", "hello")),
+	)
+	path := writeEvents(t, evts)
+
+	tr, err := Load(path, "gt-abc")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tr.ID != testTrace {
+		t.Errorf("ID = %q, want %q", tr.ID, testTrace)
+	}
+	if len(tr.Events) != len(lifecycle()) {
+		t.Errorf("matched %d events, want %d", len(tr.Events), len(lifecycle()))
+	}
+	if len(tr.Spans) != 8 {
+		t.Errorf("spans = %d, want root + 7", len(tr.Spans))
+	}
+}
+
+func TestLoadUntraced(t *testing.T) {
+	// Work slung before tracing: merge events only name the branch
+	evts := []events.Event{
+		ev(0, events.TypeSling, "mayor", events.SlingPayload("gt-old", "gastown/polecats/nux")),
+		ev(time.Hour, events.TypeDone, "gastown/polecats/nux", events.DonePayload("gt-old", "polecat/nux/gt-old")),
+		ev(61*time.Minute, events.TypeMergeStarted, "gastown/refinery", events.MergePayload("gt-mr2", "nux", "polecat/nux/gt-old", "")),
+		ev(62*time.Minute, events.TypeMerged, "gastown/refinery", events.MergePayload("gt-mr2", "nux", "polecat/nux/gt-old", "")),
+		ev(63*time.Minute, events.TypeMerged, "gastown/refinery", events.MergePayload("gt-mr3", "ace", "polecat/ace/gt-other", "")),
+	}
+	tr, err := Load(writeEvents(t, evts), "gt-old")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !tr.Derived {
+		t.Error("untraced bead should have a derived trace ID")
+	}
+	if len(tr.Events) != 4 {
+		t.Errorf("matched %d events, want 4 (other branch excluded)", len(tr.Events))
+	}
+	if root := tr.Root(); root == nil || root.Status != StatusOK {
+		t.Errorf("root = %+v, want merged", root)
+	}
+}
+
+func TestLoadMissingLog(t *testing.T) {
+	tr, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"), "gt-abc")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if tr.Root() != nil {
+		t.Error("missing log produced spans")
+	}
+}
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if !ValidID(a) || !ValidID(b) {
+		t.Fatalf("NewID() = %q, %q: not valid trace IDs", a, b)
+	}
+	if a == b {
+		t.Error("NewID() returned the same ID twice")
+	}
+
+	for _, id := range []string{"", "abc", strings.Repeat("0", 32), strings.ToUpper(testTrace), strings.Repeat("g", 32)} {
+		if ValidID(id) {
+			t.Errorf("ValidID(%q) = true", id)
+		}
+	}
+}
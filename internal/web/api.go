@@ -0,0 +1,242 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// APIPrefix is the path prefix for the versioned JSON API.
+const APIPrefix = "/api/v1/"
+
+// apiResource is one endpoint under APIPrefix.
+type apiResource struct {
+	// fetch returns the resource data, filtered to rig when rig != "".
+	fetch func(f ConvoyFetcher, rig string) (interface{}, error)
+
+	// townLevel resources have no rig and reject the rig filter.
+	townLevel bool
+}
+
+// apiResources maps endpoint names to their fetchers. Each request runs only
+// the fetch it needs, unlike the HTML dashboard which fetches everything.
+var apiResources = map[string]apiResource{
+	"convoys": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchConvoys()
+		return filterRows(rows, rig, func(c ConvoyRow) bool {
+			for _, t := range c.TrackedIssues {
+				if addressInRig(t.Assignee, rig) {
+					return true
+				}
+			}
+			return false
+		}), err
+	}},
+	"workers": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchWorkers()
+		return filterRows(rows, rig, func(w WorkerRow) bool { return w.Rig == rig }), err
+	}},
+	"merge-queue": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchMergeQueue()
+		return filterRows(rows, rig, func(m MergeQueueRow) bool { return m.Repo == rig }), err
+	}},
+	"mail": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchMail()
+		return filterRows(rows, rig, func(m MailRow) bool {
+			return addressInRig(m.FromRaw, rig) || addressInRig(m.ToRaw, rig)
+		}), err
+	}},
+	"rigs": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchRigs()
+		return filterRows(rows, rig, func(r RigRow) bool { return r.Name == rig }), err
+	}},
+	"dogs": {townLevel: true, fetch: func(f ConvoyFetcher, _ string) (interface{}, error) {
+		rows, err := f.FetchDogs()
+		return filterRows(rows, "", nil), err
+	}},
+	"escalations": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchEscalations()
+		return filterRows(rows, rig, func(e EscalationRow) bool { return addressInRig(e.EscalatedByRaw, rig) }), err
+	}},
+	"health": {townLevel: true, fetch: func(f ConvoyFetcher, _ string) (interface{}, error) {
+		health, err := f.FetchHealth()
+		if health == nil {
+			health = &HealthRow{}
+		}
+		return health, err
+	}},
+	"hooks": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchHooks()
+		return filterRows(rows, rig, func(h HookRow) bool { return addressInRig(h.Assignee, rig) }), err
+	}},
+	"sessions": {fetch: func(f ConvoyFetcher, rig string) (interface{}, error) {
+		rows, err := f.FetchSessions()
+		return filterRows(rows, rig, func(s SessionRow) bool { return s.Rig == rig }), err
+	}},
+}
+
+// APIHandler serves dashboard data as JSON under APIPrefix.
+//
+//	GET /api/v1/                 list of resources
+//	GET /api/v1/<resource>       resource data
+//	GET /api/v1/<resource>?rig=x only rows belonging to rig x
+//
+// Responses carry an ETag; requests with a matching If-None-Match get
+// 304 Not Modified so polling scripts don't re-download unchanged data.
+type APIHandler struct {
+	fetcher ConvoyFetcher
+}
+
+// NewAPIHandler creates a JSON API handler backed by fetcher.
+func NewAPIHandler(fetcher ConvoyFetcher) *APIHandler {
+	return &APIHandler{fetcher: fetcher}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeAPIError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, APIPrefix), "/")
+	if name == "" {
+		h.serveIndex(w, r)
+		return
+	}
+
+	res, ok := apiResources[name]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "unknown resource %q", name)
+		return
+	}
+
+	rig := r.URL.Query().Get("rig")
+	if rig != "" && res.townLevel {
+		writeAPIError(w, http.StatusBadRequest, "%s is town-level and cannot be filtered by rig", name)
+		return
+	}
+
+	data, err := h.fetch(r.Context(), res, rig)
+	if err != nil {
+		if err == context.DeadlineExceeded {
+			writeAPIError(w, http.StatusGatewayTimeout, "fetching %s timed out after %v", name, fetchTimeout)
+			return
+		}
+		log.Printf("api: fetching %s failed: %v", name, err)
+		writeAPIError(w, http.StatusInternalServerError, "fetching %s: %v", name, err)
+		return
+	}
+
+	writeAPIJSON(w, r, data)
+}
+
+// serveIndex lists the available resources.
+func (h *APIHandler) serveIndex(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(apiResources))
+	for name := range apiResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeAPIJSON(w, r, map[string]interface{}{
+		"version":   "v1",
+		"resources": names,
+	})
+}
+
+// fetch runs the resource fetch, giving up after fetchTimeout.
+func (h *APIHandler) fetch(ctx context.Context, res apiResource, rig string) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	type result struct {
+		data interface{}
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := res.fetch(h.fetcher, rig)
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// writeAPIJSON writes data with an ETag derived from its encoding,
+// answering 304 when the client already has it.
+func writeAPIJSON(w http.ResponseWriter, r *http.Request, data interface{}) {
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "encoding response: %v", err)
+		return
+	}
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+// Weak validators compare equal to their strong form.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAPIError writes a JSON error body: {"error": "..."}.
+func writeAPIError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+// filterRows returns the rows for which keep is true, or all rows when rig
+// is empty. The result is never nil, so empty lists encode as [].
+func filterRows[T any](rows []T, rig string, keep func(T) bool) []T {
+	out := make([]T, 0, len(rows))
+	for _, row := range rows {
+		if rig == "" || keep(row) {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+// addressInRig reports whether an agent address (e.g., "gastown/polecats/nux")
+// belongs to rig.
+func addressInRig(addr, rig string) bool {
+	return addr == rig || strings.HasPrefix(addr, rig+"/")
+}
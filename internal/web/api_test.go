@@ -0,0 +1,175 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newAPITestFetcher() *MockConvoyFetcher {
+	return &MockConvoyFetcher{
+		Convoys: []ConvoyRow{
+			{ID: "hq-cv-1", TrackedIssues: []TrackedIssue{{ID: "gt-1", Assignee: "gastown/polecats/nux"}}},
+			{ID: "hq-cv-2", TrackedIssues: []TrackedIssue{{ID: "bd-1", Assignee: "beads/polecats/toast"}}},
+		},
+		Workers: []WorkerRow{
+			{Name: "nux", Rig: "gastown"},
+			{Name: "toast", Rig: "beads"},
+		},
+		Hooks: []HookRow{
+			{ID: "gt-1", Assignee: "gastown/polecats/nux"},
+			{ID: "bd-1", Assignee: "beads/polecats/toast"},
+		},
+		Mail: []MailRow{
+			{ID: "hq-msg-1", FromRaw: "mayor/", ToRaw: "gastown/witness"},
+			{ID: "hq-msg-2", FromRaw: "beads/witness", ToRaw: "mayor/"},
+		},
+		Health: &HealthRow{DeaconCycle: 42, HealthyAgents: 3},
+	}
+}
+
+func getAPI(t *testing.T, h http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIHandler_Workers(t *testing.T) {
+	h := NewAPIHandler(newAPITestFetcher())
+
+	w := getAPI(t, h, "/api/v1/workers", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var workers []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &workers); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if len(workers) != 2 || workers[0]["name"] != "nux" || workers[0]["rig"] != "gastown" {
+		t.Errorf("workers = %v", workers)
+	}
+}
+
+func TestAPIHandler_RigFilter(t *testing.T) {
+	h := NewAPIHandler(newAPITestFetcher())
+
+	tests := []struct {
+		path string
+		want []string // IDs or names expected, in order
+		key  string
+	}{
+		{"/api/v1/workers?rig=gastown", []string{"nux"}, "name"},
+		{"/api/v1/hooks?rig=beads", []string{"bd-1"}, "id"},
+		{"/api/v1/convoys?rig=gastown", []string{"hq-cv-1"}, "id"},
+		{"/api/v1/mail?rig=gastown", []string{"hq-msg-1"}, "id"},
+		{"/api/v1/mail?rig=beads", []string{"hq-msg-2"}, "id"},
+		{"/api/v1/workers?rig=nope", []string{}, "name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := getAPI(t, h, tt.path, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			var rows []map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &rows); err != nil {
+				t.Fatalf("decoding %s: %v", w.Body, err)
+			}
+			if rows == nil {
+				t.Fatalf("body = %s, want a JSON array", w.Body)
+			}
+			var got []string
+			for _, row := range rows {
+				got = append(got, row[tt.key].(string))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAPIHandler_TownLevelRejectsRigFilter(t *testing.T) {
+	h := NewAPIHandler(newAPITestFetcher())
+
+	if w := getAPI(t, h, "/api/v1/health?rig=gastown", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+
+	w := getAPI(t, h, "/api/v1/health", nil)
+	var health HealthRow
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if health.DeaconCycle != 42 || health.HealthyAgents != 3 {
+		t.Errorf("health = %+v", health)
+	}
+}
+
+func TestAPIHandler_ETag(t *testing.T) {
+	fetcher := newAPITestFetcher()
+	h := NewAPIHandler(fetcher)
+
+	first := getAPI(t, h, "/api/v1/workers", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag on response")
+	}
+
+	cached := getAPI(t, h, "/api/v1/workers", http.Header{"If-None-Match": {etag}})
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("status = %d, body = %q; want empty 304", cached.Code, cached.Body)
+	}
+
+	fetcher.Workers = append(fetcher.Workers, WorkerRow{Name: "slit", Rig: "gastown"})
+	changed := getAPI(t, h, "/api/v1/workers", http.Header{"If-None-Match": {etag}})
+	if changed.Code != http.StatusOK || changed.Header().Get("ETag") == etag {
+		t.Errorf("status = %d, etag = %s; want fresh 200 after data changed", changed.Code, changed.Header().Get("ETag"))
+	}
+}
+
+func TestAPIHandler_Errors(t *testing.T) {
+	h := NewAPIHandler(&MockConvoyFetcher{Error: errFetchFailed})
+
+	if w := getAPI(t, h, "/api/v1/convoys", nil); w.Code != http.StatusInternalServerError {
+		t.Errorf("fetch error status = %d, want 500", w.Code)
+	}
+	if w := getAPI(t, h, "/api/v1/bogus", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown resource status = %d, want 404", w.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/workers", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", w.Code)
+	}
+}
+
+func TestAPIHandler_Index(t *testing.T) {
+	w := getAPI(t, NewAPIHandler(newAPITestFetcher()), "/api/v1/", nil)
+	var index struct {
+		Version   string   `json:"version"`
+		Resources []string `json:"resources"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &index); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if index.Version != "v1" || len(index.Resources) != len(apiResources) {
+		t.Errorf("index = %+v", index)
+	}
+}
@@ -977,6 +977,7 @@ func (f *LiveConvoyFetcher) FetchMail() ([]MailRow, error) {
 			From:      from,
 			FromRaw:   m.CreatedBy,
 			To:        to,
+			ToRaw:     m.Assignee,
 			Subject:   m.Title,
 			Timestamp: timestamp.Format("15:04"),
 			Age:       age,
@@ -1174,10 +1175,11 @@ func (f *LiveConvoyFetcher) FetchEscalations() ([]EscalationRow, error) {
 	var rows []EscalationRow
 	for _, issue := range issues {
 		row := EscalationRow{
-			ID:          issue.ID,
-			Title:       issue.Title,
-			EscalatedBy: formatAgentAddress(issue.CreatedBy),
-			Severity:    "medium", // default
+			ID:             issue.ID,
+			Title:          issue.Title,
+			EscalatedBy:    formatAgentAddress(issue.CreatedBy),
+			EscalatedByRaw: issue.CreatedBy,
+			Severity:       "medium", // default
 		}
 
 		// Parse severity from labels
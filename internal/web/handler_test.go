@@ -738,7 +738,8 @@ func TestE2E_Server_FullDashboard(t *testing.T) {
 		{"PR repo", "roxas"},
 		{"Polecat section", "Polecats"},
 		{"Polecat name", "furiosa"},
-		{"HTMX auto-refresh", `hx-trigger="every 10s"`},
+		{"HTMX auto-refresh", `hx-trigger="every 10s [!window.gtLive]`},
+		{"Live updates", `new EventSource('/events')`},
 	}
 
 	for _, check := range checks {
@@ -0,0 +1,335 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// StreamPath is where the dashboard's server-sent events stream is served.
+const StreamPath = "/events"
+
+// Streamed panels. Each names an SSE event type, the "<name>-panel" template
+// that renders it, and the element "panel-<name>" the page patches.
+const (
+	panelConvoys    = "convoys"
+	panelWorkers    = "workers"
+	panelMergeQueue = "merge-queue"
+)
+
+// panelsByEvent maps event types to the panels they can change.
+var panelsByEvent = map[string][]string{
+	events.TypeSling:        {panelWorkers, panelConvoys},
+	events.TypeHook:         {panelWorkers, panelConvoys},
+	events.TypeUnhook:       {panelWorkers, panelConvoys},
+	events.TypeDone:         {panelWorkers, panelConvoys, panelMergeQueue},
+	events.TypeHandoff:      {panelWorkers},
+	events.TypeSpawn:        {panelWorkers},
+	events.TypeKill:         {panelWorkers},
+	events.TypeNudge:        {panelWorkers},
+	events.TypeBoot:         {panelWorkers},
+	events.TypeHalt:         {panelWorkers},
+	events.TypeSessionStart: {panelWorkers},
+	events.TypeSessionEnd:   {panelWorkers},
+	events.TypeSessionDeath: {panelWorkers},
+	events.TypeMassDeath:    {panelWorkers},
+	events.TypeMergeStarted: {panelMergeQueue, panelWorkers},
+	events.TypeMerged:       {panelMergeQueue, panelWorkers, panelConvoys},
+	events.TypeMergeFailed:  {panelMergeQueue, panelWorkers},
+	events.TypeMergeSkipped: {panelMergeQueue},
+	events.TypeConvoyClosed: {panelConvoys},
+	events.TypeUnblocked:    {panelConvoys},
+}
+
+// Stream timing.
+const (
+	// streamPollInterval matches the feed curator's tail interval.
+	streamPollInterval = 100 * time.Millisecond
+
+	// streamDebounce batches bursts of events (e.g., a convoy sling to ten
+	// polecats) into one refetch per panel.
+	streamDebounce = 500 * time.Millisecond
+
+	// streamKeepAlive keeps idle connections open through proxies.
+	streamKeepAlive = 30 * time.Second
+)
+
+// streamUpdate is one rendered panel sent to clients.
+type streamUpdate struct {
+	panel string
+	html  string
+}
+
+// EventStream pushes re-rendered dashboard panels to browsers over
+// server-sent events. It tails .events.jsonl the way feed.Curator does and,
+// when an event touches workers, convoys or the merge queue, refetches only
+// that panel - once per debounce window, however many clients are connected.
+type EventStream struct {
+	eventsPath string
+	fetcher    ConvoyFetcher
+	template   *template.Template
+	debounce   time.Duration
+
+	mu       sync.Mutex
+	clients  map[chan streamUpdate]struct{}
+	rendered map[string]string // last HTML sent per panel
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEventStream creates a stream for the town at townRoot.
+func NewEventStream(townRoot string, fetcher ConvoyFetcher) (*EventStream, error) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &EventStream{
+		eventsPath: filepath.Join(townRoot, events.EventsFile),
+		fetcher:    fetcher,
+		template:   tmpl,
+		debounce:   streamDebounce,
+		clients:    make(map[chan streamUpdate]struct{}),
+		rendered:   make(map[string]string),
+		ctx:        ctx,
+		cancel:     cancel,
+	}, nil
+}
+
+// Start begins tailing the events file.
+func (s *EventStream) Start() error {
+	// Open events file, creating if needed
+	file, err := os.OpenFile(s.eventsPath, os.O_RDONLY|os.O_CREATE, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return fmt.Errorf("opening events file: %w", err)
+	}
+
+	// Seek to end to only process new events
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		_ = file.Close() //nolint:gosec // G104: best effort cleanup on error
+		return fmt.Errorf("seeking to end: %w", err)
+	}
+
+	s.wg.Add(1)
+	go s.run(file)
+	return nil
+}
+
+// Stop stops tailing and ends all client streams.
+func (s *EventStream) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run tails the events file and refreshes dirty panels after each debounce.
+func (s *EventStream) run(file *os.File) {
+	defer s.wg.Done()
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	ticker := time.NewTicker(streamPollInterval)
+	defer ticker.Stop()
+
+	var partial string
+	dirty := make(map[string]bool)
+	var flushAt time.Time
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					// Keep an incomplete trailing line for the next tick
+					partial += line
+					break
+				}
+				line, partial = partial+line, ""
+				for _, panel := range panelsForLine(line) {
+					if len(dirty) == 0 {
+						flushAt = now.Add(s.debounce)
+					}
+					dirty[panel] = true
+				}
+			}
+
+			if len(dirty) > 0 && !now.Before(flushAt) {
+				s.refresh(dirty)
+				dirty = make(map[string]bool)
+			}
+		}
+	}
+}
+
+// panelsForLine returns the panels an events.jsonl line may have changed.
+func panelsForLine(line string) []string {
+	var e events.Event
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		return nil // Skip malformed lines
+	}
+	return panelsByEvent[e.Type]
+}
+
+// refresh refetches and renders the given panels and broadcasts the ones
+// whose HTML changed. Nothing is fetched while no client is connected.
+func (s *EventStream) refresh(panels map[string]bool) {
+	if s.clientCount() == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for panel := range panels {
+		wg.Add(1)
+		go func(panel string) {
+			defer wg.Done()
+			html, err := s.render(panel)
+			if err != nil {
+				log.Printf("dashboard: refreshing %s panel failed: %v", panel, err)
+				return
+			}
+			s.broadcast(streamUpdate{panel: panel, html: html})
+		}(panel)
+	}
+	wg.Wait()
+}
+
+// render fetches one panel's data and executes its template.
+func (s *EventStream) render(panel string) (string, error) {
+	var data ConvoyData
+	var err error
+	switch panel {
+	case panelConvoys:
+		data.Convoys, err = s.fetcher.FetchConvoys()
+	case panelWorkers:
+		data.Workers, err = s.fetcher.FetchWorkers()
+	case panelMergeQueue:
+		data.MergeQueue, err = s.fetcher.FetchMergeQueue()
+	default:
+		return "", fmt.Errorf("unknown panel %q", panel)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := s.template.ExecuteTemplate(&buf, panel+"-panel", data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// broadcast sends an update to every client, unless it matches what was
+// last sent for that panel. Slow clients miss updates rather than block.
+func (s *EventStream) broadcast(u streamUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rendered[u.panel] == u.html {
+		return
+	}
+	s.rendered[u.panel] = u.html
+
+	for ch := range s.clients {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
+
+func (s *EventStream) clientCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+func (s *EventStream) subscribe() chan streamUpdate {
+	ch := make(chan streamUpdate, 16)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[ch] = struct{}{}
+	return ch
+}
+
+func (s *EventStream) unsubscribe(ch chan streamUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, ch)
+	// The page that connected rendered everything fresh; forget what we
+	// sent so the next client doesn't miss an update that matches stale HTML.
+	if len(s.clients) == 0 {
+		s.rendered = make(map[string]string)
+	}
+}
+
+// ServeHTTP streams panel updates to one client until it disconnects.
+func (s *EventStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	// The dashboard server has a write timeout; streams must outlive it.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case u := <-ch:
+			writeSSE(w, u.panel, u.html)
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeSSE writes one server-sent event. Multi-line data is split across
+// data: fields, which EventSource rejoins with newlines.
+func writeSSE(w io.Writer, event, data string) {
+	var buf strings.Builder
+	buf.WriteString("event: ")
+	buf.WriteString(event)
+	buf.WriteByte('\n')
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: ")
+		buf.WriteString(strings.TrimSuffix(line, "\r"))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	_, _ = io.WriteString(w, buf.String())
+}
@@ -0,0 +1,174 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// countingFetcher counts fetches so tests can check only dirty panels refetch.
+type countingFetcher struct {
+	MockConvoyFetcher
+	convoys, workers, mergeQueue int32
+}
+
+func (c *countingFetcher) FetchConvoys() ([]ConvoyRow, error) {
+	atomic.AddInt32(&c.convoys, 1)
+	return c.MockConvoyFetcher.FetchConvoys()
+}
+
+func (c *countingFetcher) FetchWorkers() ([]WorkerRow, error) {
+	atomic.AddInt32(&c.workers, 1)
+	return c.MockConvoyFetcher.FetchWorkers()
+}
+
+func (c *countingFetcher) FetchMergeQueue() ([]MergeQueueRow, error) {
+	atomic.AddInt32(&c.mergeQueue, 1)
+	return c.MockConvoyFetcher.FetchMergeQueue()
+}
+
+func appendEvents(t *testing.T, path string, evs ...events.Event) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatalf("opening events file: %v", err)
+	}
+	defer f.Close()
+	for _, e := range evs {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readSSE reads one event from an SSE stream, skipping comments and retry hints.
+func readSSE(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if event != "" {
+				return event, strings.Join(lines, "\n")
+			}
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			lines = append(lines, strings.TrimPrefix(line, "data: "))
+		}
+	}
+}
+
+func TestEventStream_PushesChangedPanels(t *testing.T) {
+	townRoot := t.TempDir()
+	eventsPath := filepath.Join(townRoot, events.EventsFile)
+
+	fetcher := &countingFetcher{MockConvoyFetcher: MockConvoyFetcher{
+		MergeQueue: []MergeQueueRow{{Number: 101, Repo: "gastown", Title: "Fix the widget"}},
+	}}
+	stream, err := NewEventStream(townRoot, fetcher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.debounce = 10 * time.Millisecond
+	if err := stream.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Stop()
+
+	srv := httptest.NewServer(stream)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// Wait for the subscription to register before emitting
+	for i := 0; stream.clientCount() == 0 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	appendEvents(t, eventsPath,
+		events.Event{Type: events.TypeMergeSkipped, Actor: "gastown/refinery"},
+		events.Event{Type: events.TypeMergeSkipped, Actor: "gastown/refinery"},
+		events.Event{Type: "patrol_started", Actor: "gastown/witness"},
+	)
+
+	done := make(chan struct{})
+	var event, data string
+	go func() {
+		defer close(done)
+		event, data = readSSE(t, bufio.NewReader(resp.Body))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update received")
+	}
+
+	if event != panelMergeQueue {
+		t.Errorf("event = %q, want %q", event, panelMergeQueue)
+	}
+	if !strings.Contains(data, "Fix the widget") || !strings.Contains(data, "#101") {
+		t.Errorf("merge queue panel missing PR:\n%s", data)
+	}
+
+	// Two merge_skipped events in one window: one fetch; other panels untouched
+	if got := atomic.LoadInt32(&fetcher.mergeQueue); got != 1 {
+		t.Errorf("merge queue fetched %d times, want 1", got)
+	}
+	if atomic.LoadInt32(&fetcher.workers) != 0 || atomic.LoadInt32(&fetcher.convoys) != 0 {
+		t.Errorf("unrelated panels fetched: workers=%d convoys=%d", fetcher.workers, fetcher.convoys)
+	}
+}
+
+func TestEventStream_NoFetchWithoutClients(t *testing.T) {
+	townRoot := t.TempDir()
+	fetcher := &countingFetcher{}
+	stream, err := NewEventStream(townRoot, fetcher)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream.debounce = time.Millisecond
+	if err := stream.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Stop()
+
+	appendEvents(t, filepath.Join(townRoot, events.EventsFile), events.Event{Type: events.TypeSling})
+	time.Sleep(300 * time.Millisecond)
+
+	if atomic.LoadInt32(&fetcher.workers) != 0 || atomic.LoadInt32(&fetcher.convoys) != 0 {
+		t.Errorf("fetched with no clients: workers=%d convoys=%d", fetcher.workers, fetcher.convoys)
+	}
+}
+
+func TestWriteSSE_MultilineData(t *testing.T) {
+	var buf strings.Builder
+	writeSSE(&buf, "workers", "<div>\n  <p>nux</p>\n</div>")
+
+	want := "event: workers\ndata: <div>\ndata:   <p>nux</p>\ndata: </div>\n\n"
+	if buf.String() != want {
+		t.Errorf("writeSSE = %q, want %q", buf.String(), want)
+	}
+}
@@ -34,100 +34,101 @@ type ConvoyData struct {
 
 // RigRow represents a registered rig in the dashboard.
 type RigRow struct {
-	Name         string
-	GitURL       string
-	PolecatCount int
-	CrewCount    int
-	HasWitness   bool
-	HasRefinery  bool
+	Name         string `json:"name"`
+	GitURL       string `json:"git_url"`
+	PolecatCount int    `json:"polecat_count"`
+	CrewCount    int    `json:"crew_count"`
+	HasWitness   bool   `json:"has_witness"`
+	HasRefinery  bool   `json:"has_refinery"`
 }
 
 // DogRow represents a Deacon helper worker.
 type DogRow struct {
-	Name       string // Dog name (e.g., "alpha")
-	State      string // idle, working
-	Work       string // Current work assignment
-	LastActive string // Formatted age (e.g., "5m ago")
-	RigCount   int    // Number of worktrees
+	Name       string `json:"name"`        // Dog name (e.g., "alpha")
+	State      string `json:"state"`       // idle, working
+	Work       string `json:"work"`        // Current work assignment
+	LastActive string `json:"last_active"` // Formatted age (e.g., "5m ago")
+	RigCount   int    `json:"rig_count"`   // Number of worktrees
 }
 
 // EscalationRow represents an escalation needing attention.
 type EscalationRow struct {
-	ID          string
-	Title       string
-	Severity    string // critical, high, medium, low
-	EscalatedBy string
-	Age         string
-	Acked       bool
+	ID             string `json:"id"`
+	Title          string `json:"title"`
+	Severity       string `json:"severity"` // critical, high, medium, low
+	EscalatedBy    string `json:"escalated_by"`
+	EscalatedByRaw string `json:"escalated_by_address"` // Raw agent address (e.g., "gastown/witness")
+	Age            string `json:"age"`
+	Acked          bool   `json:"acked"`
 }
 
 // HealthRow represents system health status.
 type HealthRow struct {
-	DeaconHeartbeat string // Age of heartbeat (e.g., "2m ago")
-	DeaconCycle     int64
-	HealthyAgents   int
-	UnhealthyAgents int
-	IsPaused        bool
-	PauseReason     string
-	HeartbeatFresh  bool // true if < 5min old
+	DeaconHeartbeat string `json:"deacon_heartbeat"` // Age of heartbeat (e.g., "2m ago")
+	DeaconCycle     int64  `json:"deacon_cycle"`
+	HealthyAgents   int    `json:"healthy_agents"`
+	UnhealthyAgents int    `json:"unhealthy_agents"`
+	IsPaused        bool   `json:"is_paused"`
+	PauseReason     string `json:"pause_reason"`
+	HeartbeatFresh  bool   `json:"heartbeat_fresh"` // true if < 5min old
 }
 
 // QueueRow represents a work queue.
 type QueueRow struct {
-	Name       string
-	Status     string // active, paused, closed
-	Available  int
-	Processing int
-	Completed  int
-	Failed     int
+	Name       string `json:"name"`
+	Status     string `json:"status"` // active, paused, closed
+	Available  int    `json:"available"`
+	Processing int    `json:"processing"`
+	Completed  int    `json:"completed"`
+	Failed     int    `json:"failed"`
 }
 
 // SessionRow represents a tmux session.
 type SessionRow struct {
-	Name     string // Session name (e.g., "gt-gastown-witness")
-	Role     string // witness, refinery, polecat, crew, deacon
-	Rig      string // Rig name if applicable
-	Worker   string // Worker name for polecats/crew
-	Activity string // Age since last activity
-	IsAlive  bool   // Whether Claude is running in session
+	Name     string `json:"name"`     // Session name (e.g., "gt-gastown-witness")
+	Role     string `json:"role"`     // witness, refinery, polecat, crew, deacon
+	Rig      string `json:"rig"`      // Rig name if applicable
+	Worker   string `json:"worker"`   // Worker name for polecats/crew
+	Activity string `json:"activity"` // Age since last activity
+	IsAlive  bool   `json:"is_alive"` // Whether Claude is running in session
 }
 
 // HookRow represents a hooked bead (work pinned to an agent).
 type HookRow struct {
-	ID       string // Bead ID (e.g., "gt-abc12")
-	Title    string // Work item title
-	Assignee string // Agent address (e.g., "gastown/polecats/nux")
-	Agent    string // Formatted agent name
-	Age      string // Time since hooked
-	IsStale  bool   // True if hooked > 1 hour (potentially stuck)
+	ID       string `json:"id"`       // Bead ID (e.g., "gt-abc12")
+	Title    string `json:"title"`    // Work item title
+	Assignee string `json:"assignee"` // Agent address (e.g., "gastown/polecats/nux")
+	Agent    string `json:"agent"`    // Formatted agent name
+	Age      string `json:"age"`      // Time since hooked
+	IsStale  bool   `json:"is_stale"` // True if hooked > 1 hour (potentially stuck)
 }
 
 // MayorStatus represents the Mayor's current state.
 type MayorStatus struct {
-	IsAttached   bool   // True if gt-mayor tmux session exists
-	SessionName  string // Tmux session name
-	LastActivity string // Age since last activity
-	IsActive     bool   // True if activity < 5 min (likely working)
-	Runtime      string // Which runtime (claude, codex, etc.)
+	IsAttached   bool   `json:"is_attached"`   // True if gt-mayor tmux session exists
+	SessionName  string `json:"session_name"`  // Tmux session name
+	LastActivity string `json:"last_activity"` // Age since last activity
+	IsActive     bool   `json:"is_active"`     // True if activity < 5 min (likely working)
+	Runtime      string `json:"runtime"`       // Which runtime (claude, codex, etc.)
 }
 
 // IssueRow represents an open issue in the backlog.
 type IssueRow struct {
-	ID       string // Bead ID (e.g., "gt-abc12")
-	Title    string // Issue title
-	Type     string // issue, bug, feature, task
-	Priority int    // 1=critical, 2=high, 3=medium, 4=low
-	Age      string // Time since created
-	Labels   string // Comma-separated labels
+	ID       string `json:"id"`       // Bead ID (e.g., "gt-abc12")
+	Title    string `json:"title"`    // Issue title
+	Type     string `json:"type"`     // issue, bug, feature, task
+	Priority int    `json:"priority"` // 1=critical, 2=high, 3=medium, 4=low
+	Age      string `json:"age"`      // Time since created
+	Labels   string `json:"labels"`   // Comma-separated labels
 }
 
 // ActivityRow represents an event in the activity feed.
 type ActivityRow struct {
-	Time    string // Formatted time (e.g., "2m ago")
-	Icon    string // Emoji for event type
-	Type    string // Event type (sling, done, mail, etc.)
-	Actor   string // Who did it
-	Summary string // Human-readable description
+	Time    string `json:"time"`    // Formatted time (e.g., "2m ago")
+	Icon    string `json:"icon"`    // Emoji for event type
+	Type    string `json:"type"`    // Event type (sling, done, mail, etc.)
+	Actor   string `json:"actor"`   // Who did it
+	Summary string `json:"summary"` // Human-readable description
 }
 
 // DashboardSummary provides at-a-glance stats and alerts.
@@ -140,10 +141,10 @@ type DashboardSummary struct {
 	EscalationCount int
 
 	// Alerts (things needing attention)
-	StuckPolecats     int // No activity > 5 min
-	StaleHooks        int // Hooked > 1 hour
+	StuckPolecats      int // No activity > 5 min
+	StaleHooks         int // Hooked > 1 hour
 	UnackedEscalations int
-	DeadSessions      int // Sessions that died recently
+	DeadSessions       int // Sessions that died recently
 	HighPriorityIssues int // P1/P2 issues
 
 	// Computed
@@ -152,77 +153,78 @@ type DashboardSummary struct {
 
 // MailRow represents a mail message in the dashboard.
 type MailRow struct {
-	ID          string // Message ID (e.g., "hq-msg-abc123")
-	From        string // Sender (e.g., "gastown/polecats/Toast")
-	FromRaw     string // Raw sender address for color hashing
-	To          string // Recipient (e.g., "mayor/")
-	Subject     string // Message subject
-	Timestamp   string // Formatted timestamp
-	Age         string // Human-readable age (e.g., "5m ago")
-	Priority    string // low, normal, high, urgent
-	Type        string // task, notification, reply
-	Read        bool   // Whether message has been read
-	SortKey     int64  // Unix timestamp for sorting
+	ID        string `json:"id"`           // Message ID (e.g., "hq-msg-abc123")
+	From      string `json:"from"`         // Sender (e.g., "gastown/polecats/Toast")
+	FromRaw   string `json:"from_address"` // Raw sender address for color hashing
+	To        string `json:"to"`           // Recipient (e.g., "mayor/")
+	ToRaw     string `json:"to_address"`   // Raw recipient address for rig filtering
+	Subject   string `json:"subject"`      // Message subject
+	Timestamp string `json:"timestamp"`    // Formatted timestamp
+	Age       string `json:"age"`          // Human-readable age (e.g., "5m ago")
+	Priority  string `json:"priority"`     // low, normal, high, urgent
+	Type      string `json:"type"`         // task, notification, reply
+	Read      bool   `json:"read"`         // Whether message has been read
+	SortKey   int64  `json:"-"`            // Unix timestamp for sorting
 }
 
 // WorkerRow represents a worker (polecat or refinery) in the dashboard.
 type WorkerRow struct {
-	Name         string        // e.g., "dag", "nux", "refinery"
-	Rig          string        // e.g., "roxas", "gastown"
-	SessionID    string        // e.g., "gt-roxas-dag"
-	LastActivity activity.Info // Colored activity display
-	StatusHint   string        // Last line from pane (optional)
-	IssueID      string        // Currently assigned issue ID (e.g., "hq-1234")
-	IssueTitle   string        // Issue title (truncated)
-	WorkStatus   string        // working, stale, stuck, idle
-	AgentType    string        // "polecat" (ephemeral) or "refinery" (permanent)
+	Name         string        `json:"name"`          // e.g., "dag", "nux", "refinery"
+	Rig          string        `json:"rig"`           // e.g., "roxas", "gastown"
+	SessionID    string        `json:"session_id"`    // e.g., "gt-roxas-dag"
+	LastActivity activity.Info `json:"last_activity"` // Colored activity display
+	StatusHint   string        `json:"status_hint"`   // Last line from pane (optional)
+	IssueID      string        `json:"issue_id"`      // Currently assigned issue ID (e.g., "hq-1234")
+	IssueTitle   string        `json:"issue_title"`   // Issue title (truncated)
+	WorkStatus   string        `json:"work_status"`   // working, stale, stuck, idle
+	AgentType    string        `json:"agent_type"`    // "polecat" (ephemeral) or "refinery" (permanent)
 }
 
 // MergeQueueRow represents a PR in the merge queue.
 type MergeQueueRow struct {
-	Number     int
-	Repo       string // Short repo name (e.g., "roxas", "gastown")
-	Title      string
-	URL        string
-	CIStatus   string // "pass", "fail", "pending"
-	Mergeable  string // "ready", "conflict", "pending"
-	ColorClass string // "mq-green", "mq-yellow", "mq-red"
+	Number     int    `json:"number"`
+	Repo       string `json:"repo"` // Short repo name (e.g., "roxas", "gastown")
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	CIStatus   string `json:"ci_status"` // "pass", "fail", "pending"
+	Mergeable  string `json:"mergeable"` // "ready", "conflict", "pending"
+	ColorClass string `json:"-"`         // "mq-green", "mq-yellow", "mq-red"
 }
 
 // ConvoyRow represents a single convoy in the dashboard.
 type ConvoyRow struct {
-	ID            string
-	Title         string
-	Status        string // "open" or "closed" (raw beads status)
-	WorkStatus    string // Computed: "complete", "active", "stale", "stuck", "waiting"
-	Progress      string // e.g., "2/5"
-	Completed     int
-	Total         int
-	LastActivity  activity.Info
-	TrackedIssues []TrackedIssue
+	ID            string         `json:"id"`
+	Title         string         `json:"title"`
+	Status        string         `json:"status"`      // "open" or "closed" (raw beads status)
+	WorkStatus    string         `json:"work_status"` // Computed: "complete", "active", "stale", "stuck", "waiting"
+	Progress      string         `json:"progress"`    // e.g., "2/5"
+	Completed     int            `json:"completed"`
+	Total         int            `json:"total"`
+	LastActivity  activity.Info  `json:"last_activity"`
+	TrackedIssues []TrackedIssue `json:"tracked_issues"`
 }
 
 // TrackedIssue represents an issue tracked by a convoy.
 type TrackedIssue struct {
-	ID       string
-	Title    string
-	Status   string
-	Assignee string
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
 }
 
 // LoadTemplates loads and parses all HTML templates.
 func LoadTemplates() (*template.Template, error) {
 	// Define template functions
 	funcMap := template.FuncMap{
-		"activityClass":       activityClass,
-		"statusClass":         statusClass,
-		"workStatusClass":     workStatusClass,
-		"progressPercent":     progressPercent,
-		"senderColorClass":    senderColorClass,
-		"severityClass":       severityClass,
-		"dogStateClass":       dogStateClass,
-		"queueStatusClass":    queueStatusClass,
-		"polecatStatusClass":  polecatStatusClass,
+		"activityClass":      activityClass,
+		"statusClass":        statusClass,
+		"workStatusClass":    workStatusClass,
+		"progressPercent":    progressPercent,
+		"senderColorClass":   senderColorClass,
+		"severityClass":      severityClass,
+		"dogStateClass":      dogStateClass,
+		"queueStatusClass":   queueStatusClass,
+		"polecatStatusClass": polecatStatusClass,
 	}
 
 	// Get the templates subdirectory
@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
@@ -687,18 +690,27 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	// session due to rig loading issues or race conditions with IsRunning checks.
 	// See: gt-g9ft5 - sessions were piling up because nuke wasn't killing them.
 	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
-	t := tmux.NewTmux()
-
-	// Check if session exists and kill it
-	if running, _ := t.HasSession(sessionName); running {
-		// Try graceful shutdown first (Ctrl-C), then force kill
-		_ = t.SendKeysRaw(sessionName, "C-c")
-		// Brief delay for graceful handling
-		time.Sleep(100 * time.Millisecond)
-		// Force kill the session
-		if err := t.KillSession(sessionName); err != nil {
-			// Log but continue - session might already be dead
-			// The important thing is we tried
+
+	if conn := remoteRigConnection(workDir, rigName); conn != nil {
+		// Remote rig: the session lives on the rig's machine, and the
+		// connection's kill already reaps pane descendants
+		if running, _ := conn.TmuxHasSession(sessionName); running {
+			_ = conn.TmuxKillSession(sessionName)
+		}
+	} else {
+		t := tmux.NewTmux()
+
+		// Check if session exists and kill it
+		if running, _ := t.HasSession(sessionName); running {
+			// Try graceful shutdown first (Ctrl-C), then force kill
+			_ = t.SendKeysRaw(sessionName, "C-c")
+			// Brief delay for graceful handling
+			time.Sleep(100 * time.Millisecond)
+			// Force kill the session
+			if err := t.KillSession(sessionName); err != nil {
+				// Log but continue - session might already be dead
+				// The important thing is we tried
+			}
 		}
 	}
 
@@ -712,6 +724,25 @@ func NukePolecat(workDir, rigName, polecatName string) error {
 	return nil
 }
 
+// remoteRigConnection returns the connection to a rig's machine if the rig
+// lives on another machine, or nil for local rigs and rigs that can't be
+// loaded.
+func remoteRigConnection(workDir, rigName string) connection.Connection {
+	townRoot, err := workspace.Find(workDir)
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	r, err := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot)).GetRig(rigName)
+	if err != nil || !r.IsRemote() {
+		return nil
+	}
+	return r.Connection()
+}
+
 // NukePolecatResult contains the result of an auto-nuke attempt.
 type NukePolecatResult struct {
 	Nuked   bool
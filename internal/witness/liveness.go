@@ -0,0 +1,102 @@
+package witness
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/monitoring"
+)
+
+// Liveness thresholds for polecats whose panes stop changing. They apply to
+// the daemon agent monitor's last observed activity.
+const (
+	// NudgeAfter is how long a polecat can go without pane output before the
+	// witness nudges it.
+	NudgeAfter = 5 * time.Minute
+
+	// DeadlineAfter is how long without output before the nudge comes with a
+	// deadline and the polecat is escalated if it stays silent.
+	DeadlineAfter = 15 * time.Minute
+)
+
+// LivenessAction is what the witness patrol should do about a polecat.
+type LivenessAction string
+
+const (
+	// ActionNone means the polecat is making progress.
+	ActionNone LivenessAction = "none"
+
+	// ActionNudge means the polecat has been quiet for NudgeAfter.
+	ActionNudge LivenessAction = "nudge"
+
+	// ActionDeadline means the polecat has been quiet for DeadlineAfter.
+	ActionDeadline LivenessAction = "deadline"
+
+	// ActionHelp means the monitor saw the polecat blocked or erroring.
+	ActionHelp LivenessAction = "help"
+
+	// ActionStalled means the polecat has no running session.
+	ActionStalled LivenessAction = "stalled"
+)
+
+// PolecatLiveness is the monitor's view of one polecat and the patrol action
+// it calls for.
+type PolecatLiveness struct {
+	Polecat      string                 `json:"polecat"`
+	Status       monitoring.AgentStatus `json:"status"`
+	LastActivity time.Time              `json:"last_activity,omitempty"`
+	Quiet        time.Duration          `json:"-"`
+	Action       LivenessAction         `json:"action"`
+}
+
+// CheckLiveness reads the daemon agent monitor's status file and decides an
+// action for each of a rig's polecats. This replaces reading panes and timing
+// silence by hand during patrol. Returns an error if the monitor isn't
+// running, so the patrol knows to fall back to looking at panes itself.
+func CheckLiveness(townRoot, rigName string, polecats []string, now time.Time) ([]PolecatLiveness, error) {
+	file, err := monitoring.LoadStatusFile(monitoring.StatusFile(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	if !file.Fresh(monitoring.StatusFileMaxAge) {
+		return nil, fmt.Errorf("agent monitor has not sampled in %s; is the daemon running?", monitoring.StatusFileMaxAge)
+	}
+
+	names := append([]string(nil), polecats...)
+	sort.Strings(names)
+
+	results := make([]PolecatLiveness, 0, len(names))
+	for _, name := range names {
+		activity, ok := file.Agents[fmt.Sprintf("%s/polecats/%s", rigName, name)]
+		if !ok {
+			results = append(results, PolecatLiveness{Polecat: name, Status: monitoring.StatusOffline, Action: ActionStalled})
+			continue
+		}
+		results = append(results, assessLiveness(name, activity, now))
+	}
+	return results, nil
+}
+
+// assessLiveness maps a polecat's monitored activity to a patrol action.
+func assessLiveness(name string, activity *monitoring.AgentActivity, now time.Time) PolecatLiveness {
+	l := PolecatLiveness{
+		Polecat:      name,
+		Status:       activity.CurrentStatus,
+		LastActivity: activity.LastActivity,
+		Action:       ActionNone,
+	}
+	if !activity.LastActivity.IsZero() {
+		l.Quiet = now.Sub(activity.LastActivity)
+	}
+
+	switch {
+	case activity.CurrentStatus == monitoring.StatusBlocked, activity.CurrentStatus == monitoring.StatusError:
+		l.Action = ActionHelp
+	case activity.LastActivity.IsZero(), l.Quiet >= DeadlineAfter:
+		l.Action = ActionDeadline
+	case l.Quiet >= NudgeAfter:
+		l.Action = ActionNudge
+	}
+	return l
+}
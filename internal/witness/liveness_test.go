@@ -0,0 +1,52 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/monitoring"
+)
+
+func TestCheckLiveness(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+
+	tracker := monitoring.NewStatusTracker()
+	tracker.Restore(map[string]*monitoring.AgentActivity{
+		"gastown/polecats/busy":    {AgentID: "gastown/polecats/busy", CurrentStatus: monitoring.StatusWorking, LastActivity: now.Add(-time.Minute)},
+		"gastown/polecats/quiet":   {AgentID: "gastown/polecats/quiet", CurrentStatus: monitoring.StatusIdle, LastActivity: now.Add(-7 * time.Minute)},
+		"gastown/polecats/silent":  {AgentID: "gastown/polecats/silent", CurrentStatus: monitoring.StatusIdle, LastActivity: now.Add(-20 * time.Minute)},
+		"gastown/polecats/blocked": {AgentID: "gastown/polecats/blocked", CurrentStatus: monitoring.StatusBlocked, LastActivity: now},
+		"other/polecats/gone":      {AgentID: "other/polecats/gone", CurrentStatus: monitoring.StatusWorking, LastActivity: now},
+	})
+	if err := monitoring.SaveStatusFile(monitoring.StatusFile(town), tracker); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := CheckLiveness(town, "gastown", []string{"silent", "busy", "quiet", "blocked", "gone"}, now)
+	if err != nil {
+		t.Fatalf("CheckLiveness: %v", err)
+	}
+
+	want := map[string]LivenessAction{
+		"busy":    ActionNone,
+		"quiet":   ActionNudge,
+		"silent":  ActionDeadline,
+		"blocked": ActionHelp,
+		"gone":    ActionStalled,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got), len(want))
+	}
+	for _, l := range got {
+		if l.Action != want[l.Polecat] {
+			t.Errorf("%s: action = %s, want %s", l.Polecat, l.Action, want[l.Polecat])
+		}
+	}
+}
+
+func TestCheckLivenessNeedsMonitor(t *testing.T) {
+	if _, err := CheckLiveness(t.TempDir(), "gastown", []string{"nux"}, time.Now()); err == nil {
+		t.Error("CheckLiveness succeeded without an agent status file")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/connection"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -40,10 +42,22 @@ func NewManager(r *rig.Rig) *Manager {
 // IsRunning checks if the witness session is active.
 // ZFC: tmux session existence is the source of truth.
 func (m *Manager) IsRunning() (bool, error) {
+	if conn := m.remote(); conn != nil {
+		return conn.TmuxHasSession(m.SessionName())
+	}
 	t := tmux.NewTmux()
 	return t.HasSession(m.SessionName())
 }
 
+// remote returns the rig's connection if the rig lives on another machine,
+// or nil for local rigs.
+func (m *Manager) remote() connection.Connection {
+	if m.rig.IsRemote() {
+		return m.rig.Connection()
+	}
+	return nil
+}
+
 // SessionName returns the tmux session name for this witness.
 func (m *Manager) SessionName() string {
 	return fmt.Sprintf("gt-%s-witness", m.rig.Name)
@@ -52,8 +66,19 @@ func (m *Manager) SessionName() string {
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	if conn := m.remote(); conn != nil {
+		running, err := conn.TmuxHasSession(sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("checking session on %s: %w", conn.Name(), err)
+		}
+		if !running {
+			return nil, ErrNotRunning
+		}
+		return &tmux.SessionInfo{Name: sessionID}, nil
+	}
+
+	t := tmux.NewTmux()
 
 	running, err := t.HasSession(sessionID)
 	if err != nil {
@@ -96,6 +121,9 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 		// Foreground mode is deprecated - patrol logic moved to mol-witness-patrol
 		return fmt.Errorf("foreground mode is deprecated; use background mode (remove --foreground flag)")
 	}
+	if m.rig.IsRemote() {
+		return m.startRemote(agentOverride, envOverrides)
+	}
 
 	// Check if session already exists
 	running, _ := t.HasSession(sessionID)
@@ -185,6 +213,45 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	return nil
 }
 
+// startRemote starts the witness on a remote rig's machine. Environment
+// is baked into the startup command and local-only steps (settings,
+// theme, readiness probes) are skipped, as for remote polecats.
+func (m *Manager) startRemote(agentOverride string, envOverrides []string) error {
+	conn := m.rig.Connection()
+	sessionID := m.SessionName()
+
+	if running, err := conn.TmuxHasSession(sessionID); err != nil {
+		return fmt.Errorf("checking session on %s: %w", conn.Name(), err)
+	} else if running {
+		return ErrAlreadyRunning
+	}
+
+	roleConfig, err := m.roleConfig()
+	if err != nil {
+		return err
+	}
+
+	// Runtime config is resolved from the local rig; GT_ROOT points remote.
+	remoteTown := path.Dir(m.rig.RemotePath)
+	command, err := buildWitnessStartCommand(m.rig.Path, m.rig.Name, remoteTown, agentOverride, roleConfig)
+	if err != nil {
+		return err
+	}
+	env := make(map[string]string)
+	for key, value := range roleConfigEnvVars(roleConfig, remoteTown, m.rig.Name) {
+		env[key] = value
+	}
+	for _, override := range envOverrides {
+		if key, value, ok := strings.Cut(override, "="); ok {
+			env[key] = value
+		}
+	}
+	command = config.PrependEnv(command, env)
+
+	workDir := m.rig.RemoteDir(path.Join("witness", "rig"), "witness")
+	return m.rig.StartRemoteSession(sessionID, workDir, command)
+}
+
 func (m *Manager) roleConfig() (*beads.RoleConfig, error) {
 	// Role beads use hq- prefix and live in town-level beads, not rig beads
 	townRoot := m.townRoot()
@@ -237,8 +304,15 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string,
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
 	sessionID := m.SessionName()
+	if conn := m.remote(); conn != nil {
+		if running, _ := conn.TmuxHasSession(sessionID); !running {
+			return ErrNotRunning
+		}
+		return conn.TmuxKillSession(sessionID)
+	}
+
+	t := tmux.NewTmux()
 
 	// Check if tmux session exists
 	running, _ := t.HasSession(sessionID)
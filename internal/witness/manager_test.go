@@ -1,10 +1,13 @@
 package witness
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/connection"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 func TestBuildWitnessStartCommand_UsesRoleConfig(t *testing.T) {
@@ -53,3 +56,69 @@ func TestBuildWitnessStartCommand_AgentOverrideWins(t *testing.T) {
 		t.Errorf("expected GT_ROLE=gastown/witness in command, got %q", got)
 	}
 }
+
+// fakeMachine is a remote connection that records tmux sessions. Methods
+// the manager doesn't use panic through the nil embedded interface.
+type fakeMachine struct {
+	connection.Connection
+	dirs     map[string]bool
+	sessions map[string]string // session -> workdir
+	sent     map[string]string
+}
+
+func (f *fakeMachine) Name() string                     { return "box" }
+func (f *fakeMachine) IsLocal() bool                    { return false }
+func (f *fakeMachine) Exists(path string) (bool, error) { return f.dirs[path], nil }
+func (f *fakeMachine) TmuxHasSession(name string) (bool, error) {
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+func (f *fakeMachine) TmuxNewSession(name, dir string) error {
+	f.sessions[name] = dir
+	return nil
+}
+func (f *fakeMachine) TmuxSendKeys(session, keys string) error {
+	f.sent[session] = keys
+	return nil
+}
+func (f *fakeMachine) TmuxKillSession(name string) error {
+	delete(f.sessions, name)
+	return nil
+}
+
+func TestManager_RemoteRigUsesConnection(t *testing.T) {
+	t.Setenv(beads.EnvStore, "jsonl")
+	town := t.TempDir()
+	r := &rig.Rig{Name: "gastown", Path: filepath.Join(town, "gastown")}
+	remote := &fakeMachine{
+		dirs:     map[string]bool{"/srv/gt/gastown/witness": true},
+		sessions: make(map[string]string),
+		sent:     make(map[string]string),
+	}
+	r.SetConnection(remote, "/srv/gt/gastown")
+	mgr := NewManager(r)
+
+	if err := mgr.Start(false, "", []string{"GT_DEBUG=1"}); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	sessionID := mgr.SessionName()
+	if dir := remote.sessions[sessionID]; dir != "/srv/gt/gastown/witness" {
+		t.Errorf("remote session dir = %q, want the witness dir on box", dir)
+	}
+	if cmd := remote.sent[sessionID]; !strings.Contains(cmd, "GT_ROOT=/srv/gt") || !strings.Contains(cmd, "GT_DEBUG=1") {
+		t.Errorf("startup command = %q, want remote GT_ROOT and the env override", cmd)
+	}
+
+	if running, err := mgr.IsRunning(); err != nil || !running {
+		t.Errorf("IsRunning = %v, %v; want true from the remote session", running, err)
+	}
+	if err := mgr.Start(false, "", nil); err != ErrAlreadyRunning {
+		t.Errorf("second Start = %v, want ErrAlreadyRunning", err)
+	}
+	if err := mgr.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if _, err := mgr.Status(); err != ErrNotRunning {
+		t.Errorf("Status after Stop = %v, want ErrNotRunning", err)
+	}
+}
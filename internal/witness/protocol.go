@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 // Protocol message patterns for Witness inbox routing.
@@ -48,40 +50,44 @@ const (
 
 // PolecatDonePayload contains parsed data from a POLECAT_DONE message.
 type PolecatDonePayload struct {
-	PolecatName string
-	Exit        string // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
-	IssueID     string
-	MRID        string
-	Branch      string
-	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+	PolecatName string `json:"polecat"`
+	Exit        string `json:"exit"` // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
+	IssueID     string `json:"issue,omitempty"`
+	MRID        string `json:"mr,omitempty"`
+	Branch      string `json:"branch,omitempty"`
+	Gate        string `json:"gate,omitempty"`     // Gate ID when Exit is PHASE_COMPLETE
+	TraceID     string `json:"trace_id,omitempty"` // Lifecycle trace of the issue (see gt trace)
 }
 
 // HelpPayload contains parsed data from a HELP message.
 type HelpPayload struct {
-	Topic       string
-	Agent       string
-	IssueID     string
-	Problem     string
-	Tried       string
-	RequestedAt time.Time
+	Topic       string    `json:"topic"`
+	Agent       string    `json:"agent,omitempty"`
+	IssueID     string    `json:"issue,omitempty"`
+	Problem     string    `json:"problem,omitempty"`
+	Tried       string    `json:"tried,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
 }
 
 // MergedPayload contains parsed data from a MERGED message.
+// The JSON names match the Refinery's MERGED envelope (protocol.MergedPayload).
 type MergedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	MergedAt    time.Time
+	PolecatName string    `json:"polecat"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue"`
+	MergedAt    time.Time `json:"merged_at"`
 }
 
 // MergeFailedPayload contains parsed data from a MERGE_FAILED message.
+// The JSON names match the Refinery's MERGE_FAILED envelope
+// (protocol.MergeFailedPayload).
 type MergeFailedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	FailureType string // "build", "test", "lint", etc.
-	Error       string
-	FailedAt    time.Time
+	PolecatName string    `json:"polecat"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue"`
+	FailureType string    `json:"failure_type"` // "build", "test", "lint", etc.
+	Error       string    `json:"error"`
+	FailedAt    time.Time `json:"failed_at"`
 }
 
 // SwarmStartPayload contains parsed data from a SWARM_START message.
@@ -99,7 +105,7 @@ func ClassifyMessage(subject string) ProtocolType {
 		return ProtoPolecatDone
 	case PatternLifecycleShutdown.MatchString(subject):
 		return ProtoLifecycleShutdown
-	case PatternHelp.MatchString(subject):
+	case isHelpSubject(subject):
 		return ProtoHelp
 	case PatternMerged.MatchString(subject):
 		return ProtoMerged
@@ -123,12 +129,33 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//	Trace: <trace-id>
+//
+// followed by the payload's envelope (see FormatPolecatDone). Bodies without
+// an envelope are parsed from the text.
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid POLECAT_DONE subject: %s", subject)
 	}
 
+	payload, err := polecatDoneSchema.Decode(subject, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload.PolecatName == "" {
+		payload.PolecatName = matches[1]
+	}
+	return payload, nil
+}
+
+// parsePolecatDoneText parses the "Key: value" text of a POLECAT_DONE message.
+func parsePolecatDoneText(subject, body string) (*PolecatDonePayload, error) {
+	matches := PatternPolecatDone.FindStringSubmatch(subject)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("invalid POLECAT_DONE subject: %s", subject)
+	}
+
 	payload := &PolecatDonePayload{
 		PolecatName: matches[1],
 	}
@@ -146,6 +173,8 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 			payload.Gate = strings.TrimSpace(strings.TrimPrefix(line, "Gate:"))
 		} else if strings.HasPrefix(line, "Branch:") {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
+		} else if strings.HasPrefix(line, "Trace:") {
+			payload.TraceID = strings.TrimSpace(strings.TrimPrefix(line, "Trace:"))
 		}
 	}
 
@@ -160,14 +189,59 @@ func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
 //	Issue: <issue-id>
 //	Problem: <description>
 //	Tried: <what was attempted>
+//
+// or the same fields in an envelope.
 func ParseHelp(subject, body string) (*HelpPayload, error) {
-	matches := PatternHelp.FindStringSubmatch(subject)
-	if len(matches) < 2 {
+	payload, err := helpSchema.Decode(subject, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Topic == "" {
+		payload.Topic, _ = helpTopic(subject)
+	}
+	if payload.RequestedAt.IsZero() {
+		payload.RequestedAt = time.Now()
+	}
+	return payload, nil
+}
+
+// defaultHelpTopic is the topic of a HELP message whose subject names none.
+const defaultHelpTopic = "(no topic)"
+
+// helpTopic returns the topic of a HELP subject. Subjects that don't follow
+// "HELP: <topic>" ("HELP", "HELP:", "HELP me with ...") get the rest of the
+// subject, or defaultHelpTopic, so a badly formed plea still gets through.
+// Returns false if the subject isn't a HELP subject at all.
+func helpTopic(subject string) (string, bool) {
+	if matches := PatternHelp.FindStringSubmatch(subject); len(matches) >= 2 {
+		return matches[1], true
+	}
+	fields := strings.Fields(subject)
+	if len(fields) == 0 || strings.TrimSuffix(fields[0], ":") != "HELP" {
+		return "", false
+	}
+	topic := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(subject), fields[0]))
+	if topic == "" {
+		return defaultHelpTopic, true
+	}
+	return topic, true
+}
+
+// isHelpSubject reports whether subject is a HELP subject, well formed or not.
+func isHelpSubject(subject string) bool {
+	_, ok := helpTopic(subject)
+	return ok
+}
+
+// parseHelpText parses the "Key: value" text of a HELP message.
+func parseHelpText(subject, body string) (*HelpPayload, error) {
+	topic, ok := helpTopic(subject)
+	if !ok {
 		return nil, fmt.Errorf("invalid HELP subject: %s", subject)
 	}
 
 	payload := &HelpPayload{
-		Topic:       matches[1],
+		Topic:       topic,
 		RequestedAt: time.Now(),
 	}
 
@@ -195,12 +269,31 @@ func ParseHelp(subject, body string) (*HelpPayload, error) {
 //	Branch: <branch>
 //	Issue: <issue-id>
 //	Merged-At: <timestamp>
+//
+// or the Refinery's envelope.
 func ParseMerged(subject, body string) (*MergedPayload, error) {
 	matches := PatternMerged.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGED subject: %s", subject)
 	}
 
+	payload, err := mergedSchema.Decode(subject, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload.PolecatName == "" {
+		payload.PolecatName = matches[1]
+	}
+	return payload, nil
+}
+
+// parseMergedText parses the "Key: value" text of a MERGED message.
+func parseMergedText(subject, body string) (*MergedPayload, error) {
+	matches := PatternMerged.FindStringSubmatch(subject)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("invalid MERGED subject: %s", subject)
+	}
+
 	payload := &MergedPayload{
 		PolecatName: matches[1],
 	}
@@ -231,12 +324,34 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 //	Issue: <issue-id>
 //	FailureType: <type>
 //	Error: <error-message>
+//
+// or the Refinery's envelope.
 func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 	matches := PatternMergeFailed.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGE_FAILED subject: %s", subject)
 	}
 
+	payload, err := mergeFailedSchema.Decode(subject, body)
+	if err != nil {
+		return nil, err
+	}
+	if payload.PolecatName == "" {
+		payload.PolecatName = matches[1]
+	}
+	if payload.FailedAt.IsZero() {
+		payload.FailedAt = time.Now()
+	}
+	return payload, nil
+}
+
+// parseMergeFailedText parses the "Key: value" text of a MERGE_FAILED message.
+func parseMergeFailedText(subject, body string) (*MergeFailedPayload, error) {
+	matches := PatternMergeFailed.FindStringSubmatch(subject)
+	if len(matches) < 2 {
+		return nil, fmt.Errorf("invalid MERGE_FAILED subject: %s", subject)
+	}
+
 	payload := &MergeFailedPayload{
 		PolecatName: matches[1],
 		FailedAt:    time.Now(),
@@ -260,6 +375,77 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 	return payload, nil
 }
 
+// Payload schemas for messages the Witness receives. POLECAT_DONE and HELP
+// are registered so mail.Router.Send rejects malformed ones; MERGED and
+// MERGE_FAILED belong to the protocol package, so the Witness only decodes
+// them with its own view of the payload.
+var (
+	polecatDoneSchema = mail.RegisterSchema(&mail.Schema[PolecatDonePayload]{
+		Type:     "POLECAT_DONE",
+		Version:  1,
+		Legacy:   parsePolecatDoneText,
+		Validate: validatePolecatDone,
+	})
+
+	helpSchema = mail.RegisterSchema(&mail.Schema[HelpPayload]{
+		Type:    "HELP",
+		Version: 1,
+		Legacy:  parseHelpText,
+	})
+
+	mergedSchema      = &mail.Schema[MergedPayload]{Type: "MERGED", Version: 1, Legacy: parseMergedText}
+	mergeFailedSchema = &mail.Schema[MergeFailedPayload]{Type: "MERGE_FAILED", Version: 1, Legacy: parseMergeFailedText}
+)
+
+// validatePolecatDone checks the fields the Witness acts on.
+func validatePolecatDone(p *PolecatDonePayload) error {
+	switch p.Exit {
+	case "COMPLETED", "ESCALATED", "DEFERRED":
+	case "PHASE_COMPLETE":
+		if p.Gate == "" {
+			return fmt.Errorf("exit PHASE_COMPLETE needs a gate")
+		}
+	case "":
+		return fmt.Errorf("missing exit")
+	default:
+		return fmt.Errorf("unknown exit %q (want COMPLETED, ESCALATED, DEFERRED or PHASE_COMPLETE)", p.Exit)
+	}
+	return nil
+}
+
+// FormatPolecatDone returns the body of a POLECAT_DONE message: the
+// "Key: value" lines ParsePolecatDone documents, then the payload's envelope.
+func FormatPolecatDone(p *PolecatDonePayload) string {
+	text := FormatPolecatDoneText(p)
+	body, err := polecatDoneSchema.Encode(text, p)
+	if err != nil {
+		return text
+	}
+	return body
+}
+
+// FormatPolecatDoneText returns just the "Key: value" lines of a
+// POLECAT_DONE body, for notifications about the same work that aren't
+// POLECAT_DONE messages and so mustn't carry its envelope.
+func FormatPolecatDoneText(p *PolecatDonePayload) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Exit: %s", p.Exit))
+	if p.IssueID != "" {
+		lines = append(lines, fmt.Sprintf("Issue: %s", p.IssueID))
+	}
+	if p.MRID != "" {
+		lines = append(lines, fmt.Sprintf("MR: %s", p.MRID))
+	}
+	if p.Gate != "" {
+		lines = append(lines, fmt.Sprintf("Gate: %s", p.Gate))
+	}
+	lines = append(lines, fmt.Sprintf("Branch: %s", p.Branch))
+	if p.TraceID != "" {
+		lines = append(lines, fmt.Sprintf("Trace: %s", p.TraceID))
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ParseSwarmStart extracts payload from a SWARM_START message.
 // Body format is JSON: {"swarm_id": "batch-123", "beads": ["bd-a", "bd-b"]}
 func ParseSwarmStart(body string) (*SwarmStartPayload, error) {
@@ -1,7 +1,10 @@
 package witness
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 func TestClassifyMessage(t *testing.T) {
@@ -14,6 +17,8 @@ func TestClassifyMessage(t *testing.T) {
 		{"LIFECYCLE:Shutdown nux", ProtoLifecycleShutdown},
 		{"HELP: Tests failing", ProtoHelp},
 		{"HELP: Git conflict", ProtoHelp},
+		{"HELP me with rebasing", ProtoHelp},
+		{"HELPFUL tips", ProtoUnknown},
 		{"MERGED nux", ProtoMerged},
 		{"MERGED valkyrie", ProtoMerged},
 		{"MERGE_FAILED nux", ProtoMergeFailed},
@@ -40,7 +45,8 @@ func TestParsePolecatDone(t *testing.T) {
 	body := `Exit: MERGED
 Issue: gt-abc123
 MR: gt-mr-xyz
-Branch: feature-branch`
+Branch: feature-branch
+Trace: 4bf92f3577b34da6a3ce929d0e0e4736`
 
 	payload, err := ParsePolecatDone(subject, body)
 	if err != nil {
@@ -62,6 +68,9 @@ Branch: feature-branch`
 	if payload.Branch != "feature-branch" {
 		t.Errorf("Branch = %q, want %q", payload.Branch, "feature-branch")
 	}
+	if payload.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID = %q, want %q", payload.TraceID, "4bf92f3577b34da6a3ce929d0e0e4736")
+	}
 }
 
 func TestParsePolecatDone_MinimalBody(t *testing.T) {
@@ -91,6 +100,75 @@ func TestParsePolecatDone_InvalidSubject(t *testing.T) {
 	}
 }
 
+func TestFormatPolecatDone(t *testing.T) {
+	body := FormatPolecatDone(&PolecatDonePayload{
+		PolecatName: "nux",
+		Exit:        "PHASE_COMPLETE",
+		IssueID:     "gt-abc",
+		Branch:      "polecat/nux/gt-abc",
+		Gate:        "gt-gate1",
+		TraceID:     "4bf92f3577b34da6a3ce929d0e0e4736",
+	})
+
+	// Readable text stays for agents and older witnesses
+	if !strings.HasPrefix(body, "Exit: PHASE_COMPLETE\nIssue: gt-abc\nGate: gt-gate1\n") {
+		t.Errorf("body text = %q", body)
+	}
+	text := FormatPolecatDoneText(&PolecatDonePayload{PolecatName: "nux", Exit: "COMPLETED", Branch: "polecat/nux"})
+	if text != "Exit: COMPLETED\nBranch: polecat/nux" {
+		t.Errorf("FormatPolecatDoneText = %q, want text without an envelope", text)
+	}
+
+	payload, err := ParsePolecatDone("POLECAT_DONE nux", body)
+	if err != nil {
+		t.Fatalf("ParsePolecatDone: %v", err)
+	}
+	if payload.PolecatName != "nux" || payload.Exit != "PHASE_COMPLETE" || payload.Gate != "gt-gate1" ||
+		payload.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	// The envelope wins over drifted text
+	drifted := strings.Replace(body, "Exit: PHASE_COMPLETE", "Exit: done, I think", 1)
+	if payload, _ := ParsePolecatDone("POLECAT_DONE nux", drifted); payload.Exit != "PHASE_COMPLETE" {
+		t.Errorf("Exit = %q, want envelope value", payload.Exit)
+	}
+}
+
+func TestPolecatDoneSendValidation(t *testing.T) {
+	tests := []struct {
+		body    string
+		wantErr bool
+	}{
+		{"Exit: COMPLETED\nBranch: polecat/nux", false},
+		{"Exit: done\nBranch: polecat/nux", true},
+		{"Branch: polecat/nux", true},
+		{"Exit: PHASE_COMPLETE", true}, // Needs a gate
+		{FormatPolecatDone(&PolecatDonePayload{PolecatName: "nux", Exit: "DEFERRED"}), false},
+	}
+	for _, tt := range tests {
+		err := mail.ValidateProtocolBody("POLECAT_DONE nux", tt.body)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("body %q: err = %v, wantErr %v", tt.body, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseMerged_Envelope(t *testing.T) {
+	// As sent by the Refinery (protocol.NewMergedMessage)
+	body := "Branch: polecat/nux\n\n```gt-envelope\n" +
+		`{"type": "MERGED", "version": 1, "payload": {"branch": "polecat/nux", "issue": "gt-abc", "polecat": "nux", "rig": "gastown", "merged_at": "2026-03-15T12:00:00Z", "target_branch": "main"}}` +
+		"\n```\n"
+
+	payload, err := ParseMerged("MERGED nux", body)
+	if err != nil {
+		t.Fatalf("ParseMerged: %v", err)
+	}
+	if payload.IssueID != "gt-abc" || payload.Branch != "polecat/nux" || payload.MergedAt.IsZero() {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
 func TestParseHelp(t *testing.T) {
 	subject := "HELP: Tests failing on CI"
 	body := `Agent: gastown/polecats/nux
@@ -309,3 +387,27 @@ func TestAssessHelpRequest_BuildIssues(t *testing.T) {
 		t.Error("Should be able to help with build issues")
 	}
 }
+
+func TestParseHelp_LooseSubjects(t *testing.T) {
+	tests := []struct {
+		subject string
+		topic   string
+	}{
+		{"HELP", defaultHelpTopic},
+		{"HELP:", defaultHelpTopic},
+		{"HELP me with rebasing", "me with rebasing"},
+	}
+	for _, tt := range tests {
+		payload, err := ParseHelp(tt.subject, "Agent: gastown/polecats/nux")
+		if err != nil {
+			t.Errorf("ParseHelp(%q) error = %v", tt.subject, err)
+			continue
+		}
+		if payload.Topic != tt.topic {
+			t.Errorf("ParseHelp(%q).Topic = %q, want %q", tt.subject, payload.Topic, tt.topic)
+		}
+		if err := mail.ValidateProtocolBody(tt.subject, "Agent: gastown/polecats/nux"); err != nil {
+			t.Errorf("ValidateProtocolBody(%q) = %v, want accepted", tt.subject, err)
+		}
+	}
+}